@@ -19,6 +19,20 @@ func main() {
 		log.Fatalf("failed to initialize app: %v", err)
 	}
 
+	if cfg.RebuildBalances {
+		if err := app.RebuildBalances(context.Background()); err != nil {
+			log.Fatalf("failed to rebuild balances: %v", err)
+		}
+		return
+	}
+
+	if cfg.PromoteAdmin != "" {
+		if err := app.PromoteAdmin(context.Background(), cfg.PromoteAdmin); err != nil {
+			log.Fatalf("failed to promote admin: %v", err)
+		}
+		return
+	}
+
 	if err := app.Run(); err != nil {
 		log.Fatalf("app failed: %v", err)
 	}
@@ -14,8 +14,11 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/application/usecase"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/bootstrap"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/infrastructure/datastorage/postgres"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/infrastructure/encryption"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/infrastructure/jwt"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/infrastructure/notifier"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/presentation/handler"
 )
 
@@ -37,16 +40,75 @@ func main() {
 	}
 
 	userRepo := postgres.NewUserRepository(pool)
-	jwtService := jwt.NewJWTService(cfg.JWTSecret, cfg.JWTExpiry)
-
-	registerUseCase := usecase.NewRegisterUseCase(userRepo, jwtService)
-	loginUseCase := usecase.NewLoginUseCase(userRepo, jwtService)
-	validateUseCase := usecase.NewValidateTokenUseCase(jwtService)
+	refreshTokenRepo := postgres.NewRefreshTokenRepository(pool)
+	revokedTokenRepo := postgres.NewRevokedTokenRepository(pool)
+	loginAttemptRepo := postgres.NewLoginAttemptRepository(pool)
+	passwordResetRepo := postgres.NewPasswordResetTokenRepository(pool)
+	twoFactorRepo := postgres.NewTwoFactorRepository(pool)
+	twoFactorChallengeRepo := postgres.NewTwoFactorChallengeRepository(pool)
+	keys, err := jwt.LoadKeySet(cfg.JWTSecret, cfg.JWTSigningKeyFile, cfg.JWTVerificationKeyFiles)
+	if err != nil {
+		log.Fatalf("failed to load jwt keys: %v", err)
+	}
+	if cfg.JWTSigningKeyFile == "" {
+		log.Println("JWT_SIGNING_KEY_FILE is not set, tokens are signed with HS256 JWT_SECRET")
+	}
+	jwtService := jwt.NewJWTServiceWithKeys(keys, cfg.JWTExpiry)
+	tokenIssuer := usecase.NewTokenIssuer(jwtService, refreshTokenRepo, cfg.RefreshTTL)
+
+	var secretCipher service.SecretCipher
+	if cfg.TOTPEncryptionKey != "" {
+		secretCipher, err = encryption.NewAESGCMCipher(cfg.TOTPEncryptionKey)
+		if err != nil {
+			log.Fatalf("invalid TOTP_ENCRYPTION_KEY: %v", err)
+		}
+	} else {
+		log.Println("TOTP_ENCRYPTION_KEY is not set, two-factor authentication is unavailable")
+	}
+	totp := service.TOTP{Issuer: cfg.TOTPIssuer, Skew: 1}
+
+	registerUseCase := usecase.NewRegisterUseCase(userRepo, tokenIssuer, cfg.PasswordPolicy)
+	loginThrottle := usecase.NewLoginThrottle(loginAttemptRepo, usecase.LoginThrottlePolicy{
+		MaxFailures:   cfg.LoginMaxFailures,
+		MaxIPFailures: cfg.LoginIPMaxFailures,
+		Lockout:       cfg.LoginLockout,
+		BaseDelay:     cfg.LoginFailureDelay,
+	})
+	twoFactorChallenges := usecase.NewTwoFactorChallenges(twoFactorRepo, twoFactorChallengeRepo, cfg.TwoFactorChallengeTTL)
+	loginUseCase := usecase.NewLoginUseCase(userRepo, tokenIssuer, loginThrottle, twoFactorChallenges)
+	refreshTokenUseCase := usecase.NewRefreshTokenUseCase(userRepo, refreshTokenRepo, tokenIssuer)
+	validateUseCase := usecase.NewValidateTokenUseCase(jwtService, revokedTokenRepo)
+	logoutUseCase := usecase.NewLogoutUseCase(jwtService, revokedTokenRepo, refreshTokenRepo)
+	changePasswordUseCase := usecase.NewChangePasswordUseCase(userRepo, validateUseCase, revokedTokenRepo, refreshTokenRepo, tokenIssuer, cfg.PasswordPolicy)
+	requestPasswordResetUseCase := usecase.NewRequestPasswordResetUseCase(
+		userRepo,
+		passwordResetRepo,
+		notifier.NewPasswordResetNotifier(cfg.PasswordResetNotifyFile),
+		cfg.PasswordResetTTL,
+	)
+	confirmPasswordResetUseCase := usecase.NewConfirmPasswordResetUseCase(userRepo, passwordResetRepo, revokedTokenRepo, refreshTokenRepo, cfg.PasswordPolicy)
+	setupTwoFactorUseCase := usecase.NewSetupTwoFactorUseCase(validateUseCase, twoFactorRepo, secretCipher, totp)
+	enableTwoFactorUseCase := usecase.NewEnableTwoFactorUseCase(validateUseCase, twoFactorRepo, secretCipher, totp)
+	verifyTwoFactorUseCase := usecase.NewVerifyTwoFactorUseCase(userRepo, twoFactorRepo, twoFactorChallengeRepo, secretCipher, totp, tokenIssuer, loginThrottle)
+	// Отдельно от gophermart баланс недоступен, профиль отдаётся без него.
+	getProfileUseCase := usecase.NewGetProfileUseCase(validateUseCase, userRepo, nil)
+	updateProfileUseCase := usecase.NewUpdateProfileUseCase(validateUseCase, userRepo)
 
 	registerHandler := handler.NewRegisterHandler(registerUseCase)
 	loginHandler := handler.NewLoginHandler(loginUseCase)
+	refreshHandler := handler.NewRefreshHandler(refreshTokenUseCase)
+	logoutHandler := handler.NewLogoutHandler(logoutUseCase)
+	changePasswordHandler := handler.NewChangePasswordHandler(changePasswordUseCase)
+	requestPasswordResetHandler := handler.NewRequestPasswordResetHandler(requestPasswordResetUseCase)
+	confirmPasswordResetHandler := handler.NewConfirmPasswordResetHandler(confirmPasswordResetUseCase)
+	setupTwoFactorHandler := handler.NewSetupTwoFactorHandler(setupTwoFactorUseCase)
+	enableTwoFactorHandler := handler.NewEnableTwoFactorHandler(enableTwoFactorUseCase)
+	verifyTwoFactorHandler := handler.NewVerifyTwoFactorHandler(verifyTwoFactorUseCase)
+	getProfileHandler := handler.NewGetProfileHandler(getProfileUseCase)
+	updateProfileHandler := handler.NewUpdateProfileHandler(updateProfileUseCase)
 	validateHandler := handler.NewValidateHandler(validateUseCase)
 	healthHandler := handler.NewHealthHandler()
+	jwksHandler := handler.NewJWKSHandler(keys)
 
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
@@ -55,8 +117,19 @@ func main() {
 
 	r.Post("/api/user/register", registerHandler.ServeHTTP)
 	r.Post("/api/user/login", loginHandler.ServeHTTP)
+	r.Post("/api/user/login/2fa", verifyTwoFactorHandler.ServeHTTP)
+	r.Post("/api/user/token/refresh", refreshHandler.ServeHTTP)
+	r.Post("/api/user/logout", logoutHandler.ServeHTTP)
+	r.Put("/api/user/password", changePasswordHandler.ServeHTTP)
+	r.Post("/api/user/password/reset", requestPasswordResetHandler.ServeHTTP)
+	r.Post("/api/user/password/reset/confirm", confirmPasswordResetHandler.ServeHTTP)
+	r.Post("/api/user/2fa/setup", setupTwoFactorHandler.ServeHTTP)
+	r.Post("/api/user/2fa/enable", enableTwoFactorHandler.ServeHTTP)
+	r.Get("/api/user/profile", getProfileHandler.ServeHTTP)
+	r.Patch("/api/user/profile", updateProfileHandler.ServeHTTP)
 	r.Post("/api/auth/validate", validateHandler.ServeHTTP)
 	r.Get("/api/auth/health", healthHandler.ServeHTTP)
+	r.Get("/.well-known/jwks.json", jwksHandler.ServeHTTP)
 
 	srv := &http.Server{
 		Addr:    cfg.RunAddress,
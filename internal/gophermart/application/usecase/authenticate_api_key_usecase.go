@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
+)
+
+type AuthenticateAPIKeyUseCase struct {
+	apiKeyRepo repository.APIKeyRepository
+	accounts   service.AccountDirectory
+	now        func() time.Time
+}
+
+func NewAuthenticateAPIKeyUseCase(apiKeyRepo repository.APIKeyRepository, accounts service.AccountDirectory) *AuthenticateAPIKeyUseCase {
+	return &AuthenticateAPIKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+		accounts:   accounts,
+		now:        time.Now,
+	}
+}
+
+// AuthenticateAPIKeyRequest — запрос внешней системы от имени пользователя ActingUserID.
+type AuthenticateAPIKeyRequest struct {
+	Key          string
+	Scope        model.APIKeyScope
+	ActingUserID int64
+}
+
+// APIKeyPrincipal — субъект запроса, выполненного по API-ключу.
+type APIKeyPrincipal struct {
+	KeyID        int64
+	Owner        string
+	ActingUserID int64
+}
+
+// Execute проверяет ключ, его scope и то, что пользователь, от имени которого действует ключ,
+// существует и дал ключу разрешение. Неизвестный, отозванный и истёкший ключ одинаково дают
+// ErrInvalidAPIKey.
+func (uc *AuthenticateAPIKeyUseCase) Execute(ctx context.Context, req AuthenticateAPIKeyRequest) (*APIKeyPrincipal, error) {
+	if req.Key == "" {
+		return nil, domainerrors.ErrInvalidAPIKey
+	}
+
+	key, err := uc.apiKeyRepo.FindByHash(ctx, model.HashAPIKey(req.Key))
+	if err != nil {
+		return nil, err
+	}
+	now := uc.now()
+	if key == nil || !key.IsActive(now) {
+		return nil, domainerrors.ErrInvalidAPIKey
+	}
+	if !key.HasScope(req.Scope) {
+		return nil, domainerrors.ErrAPIKeyScopeDenied
+	}
+
+	if req.ActingUserID <= 0 {
+		return nil, domainerrors.ErrActingUserRequired
+	}
+	// Разрешение проверяется до поиска пользователя, чтобы ключ без разрешения
+	// не мог узнать, существует ли пользователь с таким ID.
+	granted, err := uc.apiKeyRepo.IsGranted(ctx, key.ID, req.ActingUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !granted {
+		return nil, domainerrors.ErrAPIKeyNotGranted
+	}
+	if _, err := uc.accounts.GetProfile(ctx, req.ActingUserID); err != nil {
+		if domainerrors.Is(err, domainerrors.ErrAccountNotFound) {
+			return nil, domainerrors.ErrActingUserNotFound
+		}
+		return nil, err
+	}
+
+	if key.LastUsedOutdated(now) {
+		if err := uc.apiKeyRepo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			log.Printf("failed to update last use of api key %d: %v", key.ID, err)
+		}
+	}
+
+	return &APIKeyPrincipal{
+		KeyID:        key.ID,
+		Owner:        key.Owner,
+		ActingUserID: req.ActingUserID,
+	}, nil
+}
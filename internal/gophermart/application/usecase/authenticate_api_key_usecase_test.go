@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
+)
+
+func TestAuthenticateAPIKeyUseCase_Execute(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	const plain = "gm_secret"
+
+	activeKey := func() *model.APIKey {
+		return &model.APIKey{
+			ID:        5,
+			Owner:     "coffee-shop",
+			KeyHash:   model.HashAPIKey(plain),
+			Scopes:    []model.APIKeyScope{model.APIKeyScopeOrdersWrite},
+			ExpiresAt: &future,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		req        AuthenticateAPIKeyRequest
+		setupMocks func(*MockAPIKeyRepository, *MockAccountDirectory)
+		wantErr    error
+	}{
+		{
+			name: "authenticates key for acting user",
+			req:  AuthenticateAPIKeyRequest{Key: plain, Scope: model.APIKeyScopeOrdersWrite, ActingUserID: 42},
+			setupMocks: func(repo *MockAPIKeyRepository, accounts *MockAccountDirectory) {
+				repo.On("FindByHash", mock.Anything, model.HashAPIKey(plain)).Return(activeKey(), nil)
+				repo.On("IsGranted", mock.Anything, int64(5), int64(42)).Return(true, nil)
+				accounts.On("GetProfile", mock.Anything, int64(42)).Return(&service.AccountProfile{Login: "user"}, nil)
+				repo.On("TouchLastUsed", mock.Anything, int64(5), now).Return(nil)
+			},
+		},
+		{
+			name: "recent last use is not updated again",
+			req:  AuthenticateAPIKeyRequest{Key: plain, Scope: model.APIKeyScopeOrdersWrite, ActingUserID: 42},
+			setupMocks: func(repo *MockAPIKeyRepository, accounts *MockAccountDirectory) {
+				key := activeKey()
+				lastUsed := now.Add(-model.APIKeyLastUsedResolution / 2)
+				key.LastUsedAt = &lastUsed
+				repo.On("FindByHash", mock.Anything, model.HashAPIKey(plain)).Return(key, nil)
+				repo.On("IsGranted", mock.Anything, int64(5), int64(42)).Return(true, nil)
+				accounts.On("GetProfile", mock.Anything, int64(42)).Return(&service.AccountProfile{Login: "user"}, nil)
+			},
+		},
+		{
+			name: "failed last-use update does not reject request",
+			req:  AuthenticateAPIKeyRequest{Key: plain, Scope: model.APIKeyScopeOrdersWrite, ActingUserID: 42},
+			setupMocks: func(repo *MockAPIKeyRepository, accounts *MockAccountDirectory) {
+				repo.On("FindByHash", mock.Anything, model.HashAPIKey(plain)).Return(activeKey(), nil)
+				repo.On("IsGranted", mock.Anything, int64(5), int64(42)).Return(true, nil)
+				accounts.On("GetProfile", mock.Anything, int64(42)).Return(&service.AccountProfile{Login: "user"}, nil)
+				repo.On("TouchLastUsed", mock.Anything, int64(5), now).Return(errors.New("db error"))
+			},
+		},
+		{
+			name:       "empty key",
+			req:        AuthenticateAPIKeyRequest{Scope: model.APIKeyScopeOrdersWrite, ActingUserID: 42},
+			setupMocks: func(*MockAPIKeyRepository, *MockAccountDirectory) {},
+			wantErr:    domainerrors.ErrInvalidAPIKey,
+		},
+		{
+			name: "unknown key",
+			req:  AuthenticateAPIKeyRequest{Key: plain, Scope: model.APIKeyScopeOrdersWrite, ActingUserID: 42},
+			setupMocks: func(repo *MockAPIKeyRepository, _ *MockAccountDirectory) {
+				repo.On("FindByHash", mock.Anything, model.HashAPIKey(plain)).Return(nil, nil)
+			},
+			wantErr: domainerrors.ErrInvalidAPIKey,
+		},
+		{
+			name: "revoked key",
+			req:  AuthenticateAPIKeyRequest{Key: plain, Scope: model.APIKeyScopeOrdersWrite, ActingUserID: 42},
+			setupMocks: func(repo *MockAPIKeyRepository, _ *MockAccountDirectory) {
+				key := activeKey()
+				key.RevokedAt = &past
+				repo.On("FindByHash", mock.Anything, model.HashAPIKey(plain)).Return(key, nil)
+			},
+			wantErr: domainerrors.ErrInvalidAPIKey,
+		},
+		{
+			name: "expired key",
+			req:  AuthenticateAPIKeyRequest{Key: plain, Scope: model.APIKeyScopeOrdersWrite, ActingUserID: 42},
+			setupMocks: func(repo *MockAPIKeyRepository, _ *MockAccountDirectory) {
+				key := activeKey()
+				key.ExpiresAt = &past
+				repo.On("FindByHash", mock.Anything, model.HashAPIKey(plain)).Return(key, nil)
+			},
+			wantErr: domainerrors.ErrInvalidAPIKey,
+		},
+		{
+			name: "scope not granted",
+			req:  AuthenticateAPIKeyRequest{Key: plain, Scope: model.APIKeyScopeBalanceRead, ActingUserID: 42},
+			setupMocks: func(repo *MockAPIKeyRepository, _ *MockAccountDirectory) {
+				repo.On("FindByHash", mock.Anything, model.HashAPIKey(plain)).Return(activeKey(), nil)
+			},
+			wantErr: domainerrors.ErrAPIKeyScopeDenied,
+		},
+		{
+			name: "acting user missing",
+			req:  AuthenticateAPIKeyRequest{Key: plain, Scope: model.APIKeyScopeOrdersWrite},
+			setupMocks: func(repo *MockAPIKeyRepository, _ *MockAccountDirectory) {
+				repo.On("FindByHash", mock.Anything, model.HashAPIKey(plain)).Return(activeKey(), nil)
+			},
+			wantErr: domainerrors.ErrActingUserRequired,
+		},
+		{
+			name: "acting user not found",
+			req:  AuthenticateAPIKeyRequest{Key: plain, Scope: model.APIKeyScopeOrdersWrite, ActingUserID: 42},
+			setupMocks: func(repo *MockAPIKeyRepository, accounts *MockAccountDirectory) {
+				repo.On("FindByHash", mock.Anything, model.HashAPIKey(plain)).Return(activeKey(), nil)
+				repo.On("IsGranted", mock.Anything, int64(5), int64(42)).Return(true, nil)
+				accounts.On("GetProfile", mock.Anything, int64(42)).Return(nil, domainerrors.ErrAccountNotFound)
+			},
+			wantErr: domainerrors.ErrActingUserNotFound,
+		},
+		{
+			name: "acting user has not granted access",
+			req:  AuthenticateAPIKeyRequest{Key: plain, Scope: model.APIKeyScopeOrdersWrite, ActingUserID: 42},
+			setupMocks: func(repo *MockAPIKeyRepository, _ *MockAccountDirectory) {
+				repo.On("FindByHash", mock.Anything, model.HashAPIKey(plain)).Return(activeKey(), nil)
+				repo.On("IsGranted", mock.Anything, int64(5), int64(42)).Return(false, nil)
+			},
+			wantErr: domainerrors.ErrAPIKeyNotGranted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockAPIKeyRepository)
+			accounts := new(MockAccountDirectory)
+			tt.setupMocks(repo, accounts)
+			uc := NewAuthenticateAPIKeyUseCase(repo, accounts)
+			uc.now = func() time.Time { return now }
+
+			principal, err := uc.Execute(context.Background(), tt.req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, principal)
+				repo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &APIKeyPrincipal{KeyID: 5, Owner: "coffee-shop", ActingUserID: 42}, principal)
+			repo.AssertExpectations(t)
+			accounts.AssertExpectations(t)
+		})
+	}
+}
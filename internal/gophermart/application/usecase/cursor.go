@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+)
+
+// encodeCursor упаковывает позицию keyset-пагинации (время записи и её id) в строку,
+// которую клиент передаёт обратно без разбора.
+func encodeCursor(at time.Time, id int64) string {
+	raw := at.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatInt(id, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (time.Time, int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, domainerrors.ErrInvalidCursor
+	}
+	atPart, idPart, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, 0, domainerrors.ErrInvalidCursor
+	}
+	at, err := time.Parse(time.RFC3339Nano, atPart)
+	if err != nil {
+		return time.Time{}, 0, domainerrors.ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(idPart, 10, 64)
+	if err != nil || id <= 0 {
+		return time.Time{}, 0, domainerrors.ErrInvalidCursor
+	}
+	return at, id, nil
+}
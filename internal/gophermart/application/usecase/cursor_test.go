@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 30, 0, 123456000, time.UTC)
+
+	gotAt, gotID, err := decodeCursor(encodeCursor(at, 42))
+
+	require.NoError(t, err)
+	assert.True(t, at.Equal(gotAt))
+	assert.Equal(t, int64(42), gotID)
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, cursor := range []string{
+		"not base64!",
+		base64.RawURLEncoding.EncodeToString([]byte("no separator")),
+		base64.RawURLEncoding.EncodeToString([]byte("yesterday|1")),
+		base64.RawURLEncoding.EncodeToString([]byte("2024-01-01T00:00:00Z|abc")),
+		base64.RawURLEncoding.EncodeToString([]byte("2024-01-01T00:00:00Z|0")),
+	} {
+		_, _, err := decodeCursor(cursor)
+		assert.ErrorIs(t, err, domainerrors.ErrInvalidCursor, cursor)
+	}
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
+)
+
+type DeleteAccountUseCase struct {
+	unitOfWork repository.UnitOfWork
+	accounts   service.AccountDirectory
+}
+
+func NewDeleteAccountUseCase(unitOfWork repository.UnitOfWork, accounts service.AccountDirectory) *DeleteAccountUseCase {
+	return &DeleteAccountUseCase{
+		unitOfWork: unitOfWork,
+		accounts:   accounts,
+	}
+}
+
+type DeleteAccountRequest struct {
+	UserID int64
+}
+
+// Execute удаляет персональные данные пользователя. Заказы, списания и журнал проводок
+// остаются привязаны к обезличенной учётной записи. Пока начисление по заказу не завершено,
+// удаление запрещено: иначе баллы начислились бы уже удалённому пользователю. Проверка
+// и удаление выполняются под блокировкой приёма заказов пользователя, которую берёт
+// и UploadOrderUseCase, поэтому новый заказ не может появиться между ними.
+func (uc *DeleteAccountUseCase) Execute(ctx context.Context, req DeleteAccountRequest) error {
+	tx, err := uc.unitOfWork.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	orderRepo := tx.OrderRepository()
+	if err := orderRepo.LockUser(ctx, req.UserID); err != nil {
+		return err
+	}
+
+	pending, err := orderRepo.HasPendingByUserID(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if pending {
+		return domainerrors.ErrAccountHasPendingOrders
+	}
+
+	if err := uc.accounts.Delete(ctx, req.UserID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
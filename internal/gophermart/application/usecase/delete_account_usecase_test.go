@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+)
+
+func TestDeleteAccountUseCase_Execute(t *testing.T) {
+	errDatabase := errors.New("db error")
+
+	tests := []struct {
+		name        string
+		pending     bool
+		pendingErr  error
+		setupMocks  func(*MockAccountDirectory)
+		wantErr     error
+		wantDeleted bool
+	}{
+		{
+			name: "deletes account without pending orders",
+			setupMocks: func(accounts *MockAccountDirectory) {
+				accounts.On("Delete", mock.Anything, int64(1)).Return(nil)
+			},
+			wantDeleted: true,
+		},
+		{
+			name:       "refuses while accrual is not finished",
+			pending:    true,
+			setupMocks: func(*MockAccountDirectory) {},
+			wantErr:    domainerrors.ErrAccountHasPendingOrders,
+		},
+		{
+			name:       "pending check fails",
+			pendingErr: errDatabase,
+			setupMocks: func(*MockAccountDirectory) {},
+			wantErr:    errDatabase,
+		},
+		{
+			name: "account already deleted",
+			setupMocks: func(accounts *MockAccountDirectory) {
+				accounts.On("Delete", mock.Anything, int64(1)).Return(domainerrors.ErrAccountNotFound)
+			},
+			wantErr: domainerrors.ErrAccountNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accounts := new(MockAccountDirectory)
+			orderRepo := new(MockOrderRepository)
+			orderRepo.On("LockUser", mock.Anything, int64(1)).Return(nil)
+			orderRepo.On("HasPendingByUserID", mock.Anything, int64(1)).Return(tt.pending, tt.pendingErr)
+			tt.setupMocks(accounts)
+
+			tx := &MockTransaction{orderRepo: orderRepo}
+			tx.On("Rollback", mock.Anything).Return(nil)
+			tx.On("Commit", mock.Anything).Return(nil).Maybe()
+			uow := new(MockUnitOfWork)
+			uow.On("Begin", mock.Anything).Return(tx, nil)
+
+			err := NewDeleteAccountUseCase(uow, accounts).Execute(context.Background(), DeleteAccountRequest{UserID: 1})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			orderRepo.AssertCalled(t, "LockUser", mock.Anything, int64(1))
+			if tt.wantDeleted {
+				accounts.AssertCalled(t, "Delete", mock.Anything, int64(1))
+				tx.AssertCalled(t, "Commit", mock.Anything)
+			} else if tt.pending || tt.pendingErr != nil {
+				accounts.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
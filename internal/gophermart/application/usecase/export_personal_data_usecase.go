@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
+)
+
+type ExportPersonalDataUseCase struct {
+	accounts       service.AccountDirectory
+	orderRepo      repository.OrderRepository
+	withdrawalRepo repository.WithdrawalRepository
+	ledgerRepo     repository.LedgerRepository
+	balanceRepo    repository.BalanceRepository
+	now            func() time.Time
+}
+
+func NewExportPersonalDataUseCase(
+	accounts service.AccountDirectory,
+	orderRepo repository.OrderRepository,
+	withdrawalRepo repository.WithdrawalRepository,
+	ledgerRepo repository.LedgerRepository,
+	balanceRepo repository.BalanceRepository,
+) *ExportPersonalDataUseCase {
+	return &ExportPersonalDataUseCase{
+		accounts:       accounts,
+		orderRepo:      orderRepo,
+		withdrawalRepo: withdrawalRepo,
+		ledgerRepo:     ledgerRepo,
+		balanceRepo:    balanceRepo,
+		now:            time.Now,
+	}
+}
+
+type ExportPersonalDataRequest struct {
+	UserID int64
+}
+
+// PersonalDataExport — все данные пользователя по запросу субъекта данных.
+type PersonalDataExport struct {
+	ExportedAt     time.Time              `json:"exported_at"`
+	Profile        ProfileExport          `json:"profile"`
+	Balance        GetBalanceResponse     `json:"balance"`
+	Orders         []*OrderResponse       `json:"orders"`
+	Withdrawals    []*WithdrawalResponse  `json:"withdrawals"`
+	BalanceHistory []*BalanceHistoryEntry `json:"balance_history"`
+}
+
+type ProfileExport struct {
+	Login        string    `json:"login"`
+	FirstName    string    `json:"first_name"`
+	LastName     string    `json:"last_name"`
+	Email        string    `json:"email,omitempty"`
+	RegisteredAt time.Time `json:"registered_at"`
+}
+
+// BalanceHistoryEntry — проводка по счёту пользователя в журнале.
+type BalanceHistoryEntry struct {
+	Kind        string       `json:"kind"`
+	Amount      model.Points `json:"amount"`
+	Description string       `json:"description,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+func (uc *ExportPersonalDataUseCase) Execute(ctx context.Context, req ExportPersonalDataRequest) (*PersonalDataExport, error) {
+	profile, err := uc.accounts.GetProfile(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := uc.balanceRepo.GetByUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := uc.orderRepo.FindByUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	withdrawals, err := uc.withdrawalRepo.FindByUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := uc.ledgerRepo.FindByUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &PersonalDataExport{
+		ExportedAt: uc.now(),
+		Profile: ProfileExport{
+			Login:        profile.Login,
+			FirstName:    profile.FirstName,
+			LastName:     profile.LastName,
+			Email:        profile.Email,
+			RegisteredAt: profile.RegisteredAt,
+		},
+		Balance: GetBalanceResponse{
+			Current:   balance.Current(),
+			Withdrawn: balance.Withdrawn(),
+		},
+		Orders:         make([]*OrderResponse, 0, len(orders)),
+		Withdrawals:    make([]*WithdrawalResponse, 0, len(withdrawals)),
+		BalanceHistory: make([]*BalanceHistoryEntry, 0, len(entries)),
+	}
+	for _, order := range orders {
+		export.Orders = append(export.Orders, newOrderResponse(order))
+	}
+	for _, withdrawal := range withdrawals {
+		export.Withdrawals = append(export.Withdrawals, newWithdrawalResponse(withdrawal))
+	}
+	for _, entry := range entries {
+		// Встречные проводки относятся к служебным счетам, а не к балансу пользователя.
+		if entry.Account() != model.LedgerAccountUser {
+			continue
+		}
+		export.BalanceHistory = append(export.BalanceHistory, &BalanceHistoryEntry{
+			Kind:        string(entry.Kind()),
+			Amount:      entry.Amount(),
+			Description: entry.Description(),
+			CreatedAt:   entry.CreatedAt(),
+		})
+	}
+
+	return export, nil
+}
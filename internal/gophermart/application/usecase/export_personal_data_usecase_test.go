@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
+)
+
+func TestExportPersonalDataUseCase_Execute(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	accrual := model.MustParsePoints("500")
+
+	t.Run("collects profile, balance and history", func(t *testing.T) {
+		accounts := new(MockAccountDirectory)
+		orderRepo := new(MockOrderRepository)
+		withdrawalRepo := new(MockWithdrawalRepository)
+		ledgerRepo := new(MockLedgerRepository)
+		balanceRepo := new(MockBalanceRepository)
+
+		accounts.On("GetProfile", mock.Anything, int64(1)).Return(&service.AccountProfile{
+			Login: "user", FirstName: "John", Email: "john@example.com", RegisteredAt: now.Add(-time.Hour),
+		}, nil)
+		balanceRepo.On("GetByUserID", mock.Anything, int64(1)).Return(
+			model.RestoreBalance(1, model.MustParsePoints("400"), model.MustParsePoints("100")), nil)
+		orderRepo.On("FindByUserID", mock.Anything, int64(1)).Return([]*model.Order{
+			model.RestoreOrder(1, 1, "12345678903", model.OrderStatusProcessed, &accrual, now),
+		}, nil)
+		withdrawalRepo.On("FindByUserID", mock.Anything, int64(1)).Return([]*model.Withdrawal{
+			model.RestoreWithdrawal(1, 1, "79927398713", model.MustParsePoints("100"), now),
+		}, nil)
+		ledgerRepo.On("FindByUserID", mock.Anything, int64(1)).Return([]*model.LedgerEntry{
+			model.RestoreLedgerEntry(1, 1, model.LedgerEntryKindAccrual, model.LedgerAccountUser, 1, accrual, nil, nil, nil, "", now),
+			model.RestoreLedgerEntry(2, 1, model.LedgerEntryKindAccrual, model.LedgerAccountAccrualSource, 1, accrual.Neg(), nil, nil, nil, "", now),
+			model.RestoreLedgerEntry(3, 2, model.LedgerEntryKindWithdrawal, model.LedgerAccountUser, 1, model.MustParsePoints("-100"), nil, nil, nil, "", now),
+		}, nil)
+
+		uc := NewExportPersonalDataUseCase(accounts, orderRepo, withdrawalRepo, ledgerRepo, balanceRepo)
+		uc.now = func() time.Time { return now }
+
+		export, err := uc.Execute(context.Background(), ExportPersonalDataRequest{UserID: 1})
+		require.NoError(t, err)
+
+		assert.Equal(t, now, export.ExportedAt)
+		assert.Equal(t, "user", export.Profile.Login)
+		assert.Equal(t, "john@example.com", export.Profile.Email)
+		assert.Equal(t, model.MustParsePoints("400"), export.Balance.Current)
+		require.Len(t, export.Orders, 1)
+		assert.Equal(t, "12345678903", export.Orders[0].Number)
+		require.Len(t, export.Withdrawals, 1)
+		assert.Equal(t, "79927398713", export.Withdrawals[0].Order)
+		require.Len(t, export.BalanceHistory, 2, "only entries on the user account are exported")
+		assert.Equal(t, model.MustParsePoints("-100"), export.BalanceHistory[1].Amount)
+	})
+
+	t.Run("deleted account", func(t *testing.T) {
+		accounts := new(MockAccountDirectory)
+		accounts.On("GetProfile", mock.Anything, int64(1)).Return(nil, domainerrors.ErrAccountNotFound)
+
+		uc := NewExportPersonalDataUseCase(accounts, new(MockOrderRepository), new(MockWithdrawalRepository),
+			new(MockLedgerRepository), new(MockBalanceRepository))
+
+		_, err := uc.Execute(context.Background(), ExportPersonalDataRequest{UserID: 1})
+		assert.ErrorIs(t, err, domainerrors.ErrAccountNotFound)
+	})
+}
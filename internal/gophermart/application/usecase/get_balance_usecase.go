@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
 )
 
@@ -21,8 +22,8 @@ type GetBalanceRequest struct {
 }
 
 type GetBalanceResponse struct {
-	Current   float64 `json:"current"`
-	Withdrawn float64 `json:"withdrawn"`
+	Current   model.Points `json:"current"`
+	Withdrawn model.Points `json:"withdrawn"`
 }
 
 func (uc *GetBalanceUseCase) Execute(ctx context.Context, req GetBalanceRequest) (*GetBalanceResponse, error) {
@@ -16,19 +16,19 @@ func TestGetBalanceUseCase_Execute(t *testing.T) {
 		name          string
 		userID        int64
 		setupMock     func(*MockBalanceRepository)
-		wantCurrent   float64
-		wantWithdrawn float64
+		wantCurrent   model.Points
+		wantWithdrawn model.Points
 		wantErr       bool
 	}{
 		{
 			name:   "successful get balance",
 			userID: 1,
 			setupMock: func(m *MockBalanceRepository) {
-				balance := model.RestoreBalance(1, 100.5, 50.0)
+				balance := model.RestoreBalance(1, model.MustParsePoints("100.5"), model.MustParsePoints("50"))
 				m.On("GetByUserID", mock.Anything, int64(1)).Return(balance, nil)
 			},
-			wantCurrent:   100.5,
-			wantWithdrawn: 50.0,
+			wantCurrent:   model.MustParsePoints("100.5"),
+			wantWithdrawn: model.MustParsePoints("50"),
 			wantErr:       false,
 		},
 		{
@@ -38,8 +38,8 @@ func TestGetBalanceUseCase_Execute(t *testing.T) {
 				balance := model.NewBalance(2)
 				m.On("GetByUserID", mock.Anything, int64(2)).Return(balance, nil)
 			},
-			wantCurrent:   0.0,
-			wantWithdrawn: 0.0,
+			wantCurrent:   0,
+			wantWithdrawn: 0,
 			wantErr:       false,
 		},
 		{
@@ -48,8 +48,8 @@ func TestGetBalanceUseCase_Execute(t *testing.T) {
 			setupMock: func(m *MockBalanceRepository) {
 				m.On("GetByUserID", mock.Anything, int64(3)).Return(nil, errors.New("database error"))
 			},
-			wantCurrent:   0.0,
-			wantWithdrawn: 0.0,
+			wantCurrent:   0,
+			wantWithdrawn: 0,
 			wantErr:       true,
 		},
 	}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+)
+
+type GetOrderUseCase struct {
+	orderRepo  repository.OrderRepository
+	outboxRepo repository.OutboxRepository
+}
+
+func NewGetOrderUseCase(orderRepo repository.OrderRepository, outboxRepo repository.OutboxRepository) *GetOrderUseCase {
+	return &GetOrderUseCase{
+		orderRepo:  orderRepo,
+		outboxRepo: outboxRepo,
+	}
+}
+
+type GetOrderRequest struct {
+	UserID int64
+	Number string
+}
+
+type OrderDetailsResponse struct {
+	OrderResponse
+	History    []*OrderStatusChangeResponse `json:"history"`
+	Processing *OrderProcessingResponse     `json:"processing,omitempty"`
+}
+
+type OrderStatusChangeResponse struct {
+	PreviousStatus string        `json:"previous_status,omitempty"`
+	Status         string        `json:"status"`
+	Accrual        *model.Points `json:"accrual,omitempty"`
+	AccrualStatus  string        `json:"accrual_status,omitempty"`
+	Reason         string        `json:"reason,omitempty"`
+	ChangedAt      time.Time     `json:"changed_at"`
+}
+
+// OrderProcessingResponse — состояние опроса системы начислений по заказу, который ещё
+// не получил финальный статус: сколько было неудачных попыток, последняя ошибка
+// и когда будет следующий запрос.
+type OrderProcessingResponse struct {
+	Status        model.OutboxStatus `json:"status"`
+	Retries       int                `json:"retries"`
+	LastError     string             `json:"last_error,omitempty"`
+	NextAttemptAt time.Time          `json:"next_attempt_at"`
+}
+
+// Execute возвращает заказ пользователя с историей статусов. Чужой заказ неотличим от
+// несуществующего, чтобы по номеру нельзя было узнать о заказах других пользователей.
+func (uc *GetOrderUseCase) Execute(ctx context.Context, req GetOrderRequest) (*OrderDetailsResponse, error) {
+	order, err := uc.orderRepo.FindByNumber(ctx, req.Number)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil || order.UserID() != req.UserID {
+		return nil, fmt.Errorf("order %s: %w", req.Number, domainerrors.ErrOrderNotFound)
+	}
+
+	history, err := uc.orderRepo.FindStatusHistory(ctx, order.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	response := &OrderDetailsResponse{
+		OrderResponse: *newOrderResponse(order),
+		History:       make([]*OrderStatusChangeResponse, 0, len(history)),
+	}
+	for _, change := range history {
+		response.History = append(response.History, &OrderStatusChangeResponse{
+			PreviousStatus: string(change.PreviousStatus),
+			Status:         string(change.Status),
+			Accrual:        change.Accrual,
+			AccrualStatus:  change.AccrualStatus,
+			Reason:         change.Reason,
+			ChangedAt:      change.CreatedAt,
+		})
+	}
+
+	if !order.Status().IsFinal() {
+		outboxes, err := uc.outboxRepo.List(ctx, repository.OutboxFilter{OrderNumber: order.Number(), Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+		if len(outboxes) > 0 {
+			outbox := outboxes[0]
+			response.Processing = &OrderProcessingResponse{
+				Status:        outbox.Status,
+				Retries:       outbox.Retries,
+				LastError:     outbox.LastError,
+				NextAttemptAt: outbox.NextAttemptAt,
+			}
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+)
+
+func TestGetOrderUseCase_Execute(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	accrual := model.MustParsePoints("500")
+
+	t.Run("processed order with history", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		outboxRepo := new(MockOutboxRepository)
+		orderRepo.On("FindByNumber", mock.Anything, "12345678903").
+			Return(model.RestoreOrder(7, 1, "12345678903", model.OrderStatusProcessed, &accrual, now), nil)
+		orderRepo.On("FindStatusHistory", mock.Anything, int64(7)).Return([]*model.OrderStatusChange{
+			{OrderID: 7, Status: model.OrderStatusNew, CreatedAt: now},
+			{OrderID: 7, PreviousStatus: model.OrderStatusNew, Status: model.OrderStatusProcessing, AccrualStatus: "PROCESSING", CreatedAt: now.Add(time.Minute)},
+			{OrderID: 7, PreviousStatus: model.OrderStatusProcessing, Status: model.OrderStatusProcessed, Accrual: &accrual, AccrualStatus: "PROCESSED", CreatedAt: now.Add(2 * time.Minute)},
+		}, nil)
+
+		resp, err := NewGetOrderUseCase(orderRepo, outboxRepo).Execute(context.Background(), GetOrderRequest{UserID: 1, Number: "12345678903"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "PROCESSED", resp.Status)
+		require.Len(t, resp.History, 3)
+		assert.Empty(t, resp.History[0].PreviousStatus)
+		assert.Equal(t, "PROCESSING", resp.History[2].PreviousStatus)
+		assert.Equal(t, &accrual, resp.History[2].Accrual)
+		assert.Nil(t, resp.Processing)
+		outboxRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+	})
+
+	t.Run("pending order includes processing state", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		outboxRepo := new(MockOutboxRepository)
+		orderRepo.On("FindByNumber", mock.Anything, "12345678903").
+			Return(model.RestoreOrder(7, 1, "12345678903", model.OrderStatusProcessing, nil, now), nil)
+		orderRepo.On("FindStatusHistory", mock.Anything, int64(7)).Return([]*model.OrderStatusChange{}, nil)
+		outboxRepo.On("List", mock.Anything, repository.OutboxFilter{OrderNumber: "12345678903", Limit: 1}).Return([]*model.Outbox{
+			{ID: 3, Status: model.OutboxStatusInFlight, Retries: 2, LastError: "accrual unavailable", NextAttemptAt: now.Add(time.Minute)},
+		}, nil)
+
+		resp, err := NewGetOrderUseCase(orderRepo, outboxRepo).Execute(context.Background(), GetOrderRequest{UserID: 1, Number: "12345678903"})
+
+		require.NoError(t, err)
+		require.NotNil(t, resp.Processing)
+		assert.Equal(t, model.OutboxStatusInFlight, resp.Processing.Status)
+		assert.Equal(t, 2, resp.Processing.Retries)
+		assert.Equal(t, "accrual unavailable", resp.Processing.LastError)
+		assert.NotNil(t, resp.History)
+	})
+
+	t.Run("order of another user is not found", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		orderRepo.On("FindByNumber", mock.Anything, "12345678903").
+			Return(model.RestoreOrder(7, 2, "12345678903", model.OrderStatusNew, nil, now), nil)
+
+		resp, err := NewGetOrderUseCase(orderRepo, new(MockOutboxRepository)).Execute(context.Background(), GetOrderRequest{UserID: 1, Number: "12345678903"})
+
+		assert.ErrorIs(t, err, domainerrors.ErrOrderNotFound)
+		assert.Nil(t, resp)
+		orderRepo.AssertNotCalled(t, "FindStatusHistory", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown order", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		orderRepo.On("FindByNumber", mock.Anything, "12345678903").Return(nil, nil)
+
+		_, err := NewGetOrderUseCase(orderRepo, new(MockOutboxRepository)).Execute(context.Background(), GetOrderRequest{UserID: 1, Number: "12345678903"})
+
+		assert.ErrorIs(t, err, domainerrors.ErrOrderNotFound)
+	})
+
+	t.Run("history error", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		orderRepo.On("FindByNumber", mock.Anything, "12345678903").
+			Return(model.RestoreOrder(7, 1, "12345678903", model.OrderStatusNew, nil, now), nil)
+		orderRepo.On("FindStatusHistory", mock.Anything, int64(7)).Return(nil, errors.New("db error"))
+
+		resp, err := NewGetOrderUseCase(orderRepo, new(MockOutboxRepository)).Execute(context.Background(), GetOrderRequest{UserID: 1, Number: "12345678903"})
+
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+	})
+}
@@ -2,8 +2,13 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"time"
 
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
 )
 
@@ -33,20 +38,105 @@ func (uc *GetOrdersUseCase) Execute(ctx context.Context, req GetOrdersRequest) (
 
 	response := make([]*OrderResponse, 0, len(orders))
 	for _, order := range orders {
-		response = append(response, &OrderResponse{
-			Number:     order.Number(),
-			Status:     string(order.Status()),
-			Accrual:    order.Accrual(),
-			UploadedAt: order.UploadedAt(),
-		})
+		response = append(response, newOrderResponse(order))
 	}
 
 	return response, nil
 }
 
+const (
+	defaultOrdersPageLimit = 100
+	maxOrdersPageLimit     = 1000
+)
+
+// GetOrdersPageRequest — запрос страницы заказов. Cursor — NextCursor предыдущей страницы;
+// From включается в интервал дат загрузки, To — нет.
+type GetOrdersPageRequest struct {
+	UserID int64
+	Status model.OrderStatus
+	From   time.Time
+	To     time.Time
+	Cursor string
+	Limit  int
+}
+
+type OrdersPage struct {
+	Orders []*OrderResponse
+	// NextCursor пуст на последней странице.
+	NextCursor string
+}
+
+// ExecutePage возвращает страницу заказов от новых к старым. Заказы читаются из базы
+// итератором по одному; лишняя строка сверх limit показывает, что есть следующая страница.
+func (uc *GetOrdersUseCase) ExecutePage(ctx context.Context, req GetOrdersPageRequest) (*OrdersPage, error) {
+	if req.Status != "" && !req.Status.IsValid() {
+		return nil, fmt.Errorf("status %s: %w", req.Status, domainerrors.ErrInvalidOrderStatus)
+	}
+	if !req.From.IsZero() && !req.To.IsZero() && !req.From.Before(req.To) {
+		return nil, domainerrors.ErrInvalidDateRange
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultOrdersPageLimit
+	}
+	if limit > maxOrdersPageLimit {
+		limit = maxOrdersPageLimit
+	}
+
+	filter := repository.OrderFilter{
+		Status: req.Status,
+		From:   req.From,
+		To:     req.To,
+		Limit:  limit + 1,
+	}
+	if req.Cursor != "" {
+		uploadedAt, id, err := decodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = &repository.OrderCursor{UploadedAt: uploadedAt, ID: id}
+	}
+
+	it, err := uc.orderRepo.FindByUserIDIterator(ctx, req.UserID, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	page := &OrdersPage{Orders: []*OrderResponse{}}
+	var last *model.Order
+	for {
+		order, err := it.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(page.Orders) == limit {
+			page.NextCursor = encodeCursor(last.UploadedAt(), last.ID())
+			break
+		}
+		page.Orders = append(page.Orders, newOrderResponse(order))
+		last = order
+	}
+
+	return page, nil
+}
+
 type OrderResponse struct {
-	Number     string    `json:"number"`
-	Status     string    `json:"status"`
-	Accrual    *float64  `json:"accrual,omitempty"`
-	UploadedAt time.Time `json:"uploaded_at"`
+	Number     string        `json:"number"`
+	Status     string        `json:"status"`
+	Accrual    *model.Points `json:"accrual,omitempty"`
+	UploadedAt time.Time     `json:"uploaded_at"`
+}
+
+func newOrderResponse(order *model.Order) *OrderResponse {
+	return &OrderResponse{
+		Number:     order.Number(),
+		Status:     string(order.Status()),
+		Accrual:    order.Accrual(),
+		UploadedAt: order.UploadedAt(),
+	}
 }
@@ -8,14 +8,17 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
 )
 
 func TestGetOrdersUseCase_Execute(t *testing.T) {
 	now := time.Now()
-	accrual1 := 100.5
-	accrual2 := 200.0
+	accrual1 := model.MustParsePoints("100.5")
+	accrual2 := model.MustParsePoints("200")
 
 	tests := []struct {
 		name      string
@@ -95,3 +98,103 @@ func TestGetOrdersUseCase_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestGetOrdersUseCase_ExecutePage(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	orders := []*model.Order{
+		model.RestoreOrder(3, 1, "4532015112830366", model.OrderStatusProcessed, nil, now),
+		model.RestoreOrder(2, 1, "79927398713", model.OrderStatusProcessed, nil, now.Add(-time.Hour)),
+		model.RestoreOrder(1, 1, "12345678903", model.OrderStatusProcessed, nil, now.Add(-2*time.Hour)),
+	}
+
+	t.Run("returns page with next cursor", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		it := &sliceIterator[*model.Order]{items: orders}
+		mockRepo.On("FindByUserIDIterator", mock.Anything, int64(1), repository.OrderFilter{
+			Status: model.OrderStatusProcessed,
+			Limit:  3,
+		}).Return(it, nil)
+
+		page, err := NewGetOrdersUseCase(mockRepo).ExecutePage(context.Background(), GetOrdersPageRequest{
+			UserID: 1,
+			Status: model.OrderStatusProcessed,
+			Limit:  2,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, page.Orders, 2)
+		assert.Equal(t, "4532015112830366", page.Orders[0].Number)
+		assert.Equal(t, "79927398713", page.Orders[1].Number)
+		assert.Equal(t, encodeCursor(now.Add(-time.Hour), 2), page.NextCursor)
+		assert.True(t, it.closed)
+	})
+
+	t.Run("continues after cursor", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockRepo.On("FindByUserIDIterator", mock.Anything, int64(1), repository.OrderFilter{
+			After: &repository.OrderCursor{UploadedAt: now.Add(-time.Hour), ID: 2},
+			Limit: 3,
+		}).Return(&sliceIterator[*model.Order]{items: orders[2:]}, nil)
+
+		page, err := NewGetOrdersUseCase(mockRepo).ExecutePage(context.Background(), GetOrdersPageRequest{
+			UserID: 1,
+			Cursor: encodeCursor(now.Add(-time.Hour), 2),
+			Limit:  2,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, page.Orders, 1)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("applies default and maximum limit", func(t *testing.T) {
+		for _, tt := range []struct{ limit, want int }{{0, defaultOrdersPageLimit + 1}, {5000, maxOrdersPageLimit + 1}} {
+			mockRepo := new(MockOrderRepository)
+			mockRepo.On("FindByUserIDIterator", mock.Anything, int64(1), repository.OrderFilter{Limit: tt.want}).
+				Return(&sliceIterator[*model.Order]{}, nil)
+
+			page, err := NewGetOrdersUseCase(mockRepo).ExecutePage(context.Background(), GetOrdersPageRequest{UserID: 1, Limit: tt.limit})
+
+			require.NoError(t, err)
+			assert.Empty(t, page.Orders)
+			mockRepo.AssertExpectations(t)
+		}
+	})
+
+	t.Run("iterator error", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockRepo.On("FindByUserIDIterator", mock.Anything, int64(1), mock.Anything).
+			Return(&sliceIterator[*model.Order]{items: orders[:1], err: errors.New("connection lost")}, nil)
+
+		page, err := NewGetOrdersUseCase(mockRepo).ExecutePage(context.Background(), GetOrdersPageRequest{UserID: 1})
+
+		assert.Error(t, err)
+		assert.Nil(t, page)
+	})
+}
+
+func TestGetOrdersUseCase_ExecutePage_Validation(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		req     GetOrdersPageRequest
+		wantErr error
+	}{
+		{name: "unknown status", req: GetOrdersPageRequest{UserID: 1, Status: "DONE"}, wantErr: domainerrors.ErrInvalidOrderStatus},
+		{name: "from after to", req: GetOrdersPageRequest{UserID: 1, From: now, To: now.Add(-time.Hour)}, wantErr: domainerrors.ErrInvalidDateRange},
+		{name: "malformed cursor", req: GetOrdersPageRequest{UserID: 1, Cursor: "???"}, wantErr: domainerrors.ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockOrderRepository)
+
+			page, err := NewGetOrdersUseCase(mockRepo).ExecutePage(context.Background(), tt.req)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, page)
+			mockRepo.AssertNotCalled(t, "FindByUserIDIterator", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
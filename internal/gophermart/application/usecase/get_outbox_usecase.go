@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+)
+
+type GetOutboxUseCase struct {
+	outboxRepo      repository.OutboxRepository
+	outboxAuditRepo repository.OutboxAuditRepository
+}
+
+func NewGetOutboxUseCase(outboxRepo repository.OutboxRepository, outboxAuditRepo repository.OutboxAuditRepository) *GetOutboxUseCase {
+	return &GetOutboxUseCase{
+		outboxRepo:      outboxRepo,
+		outboxAuditRepo: outboxAuditRepo,
+	}
+}
+
+type GetOutboxRequest struct {
+	ID int64
+}
+
+func (uc *GetOutboxUseCase) Execute(ctx context.Context, req GetOutboxRequest) (*OutboxDetailsResponse, error) {
+	outbox, err := uc.outboxRepo.FindByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if outbox == nil {
+		return nil, fmt.Errorf("outbox %d: %w", req.ID, domainerrors.ErrOutboxNotFound)
+	}
+
+	entries, err := uc.outboxAuditRepo.FindByOutboxID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := make([]*OutboxAuditResponse, 0, len(entries))
+	for _, entry := range entries {
+		audit = append(audit, &OutboxAuditResponse{
+			Action:          entry.Action,
+			ActorUserID:     entry.ActorUserID,
+			PreviousStatus:  entry.PreviousStatus,
+			PreviousRetries: entry.PreviousRetries,
+			PreviousError:   entry.PreviousError,
+			CreatedAt:       entry.CreatedAt,
+		})
+	}
+
+	return &OutboxDetailsResponse{
+		OutboxResponse: newOutboxResponse(outbox),
+		Audit:          audit,
+	}, nil
+}
+
+type OutboxDetailsResponse struct {
+	*OutboxResponse
+	Audit []*OutboxAuditResponse `json:"audit"`
+}
+
+type OutboxAuditResponse struct {
+	Action          model.OutboxAuditAction `json:"action"`
+	ActorUserID     int64                   `json:"actor_user_id"`
+	PreviousStatus  model.OutboxStatus      `json:"previous_status"`
+	PreviousRetries int                     `json:"previous_retries"`
+	PreviousError   string                  `json:"previous_error,omitempty"`
+	CreatedAt       time.Time               `json:"created_at"`
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+)
+
+func TestGetOutboxUseCase_Execute(t *testing.T) {
+	now := time.Now()
+
+	t.Run("returns outbox with audit trail", func(t *testing.T) {
+		outboxRepo := new(MockOutboxRepository)
+		auditRepo := new(MockOutboxAuditRepository)
+		outboxRepo.On("FindByID", mock.Anything, int64(1)).Return(&model.Outbox{
+			ID: 1, OrderID: 7, OrderNumber: "79927398713", Status: model.OutboxStatusDead, Retries: 10, LastError: "timeout", CreatedAt: now,
+		}, nil)
+		auditRepo.On("FindByOutboxID", mock.Anything, int64(1)).Return([]*model.OutboxAuditEntry{
+			{ID: 1, OutboxID: 1, Action: model.OutboxAuditActionRequeue, ActorUserID: 42, PreviousStatus: model.OutboxStatusDead, PreviousRetries: 10, CreatedAt: now},
+		}, nil)
+
+		uc := NewGetOutboxUseCase(outboxRepo, auditRepo)
+		resp, err := uc.Execute(context.Background(), GetOutboxRequest{ID: 1})
+
+		require.NoError(t, err)
+		assert.Equal(t, "timeout", resp.LastError)
+		assert.Equal(t, "79927398713", resp.OrderNumber)
+		require.Len(t, resp.Audit, 1)
+		assert.Equal(t, int64(42), resp.Audit[0].ActorUserID)
+		outboxRepo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		outboxRepo := new(MockOutboxRepository)
+		auditRepo := new(MockOutboxAuditRepository)
+		outboxRepo.On("FindByID", mock.Anything, int64(2)).Return(nil, nil)
+
+		uc := NewGetOutboxUseCase(outboxRepo, auditRepo)
+		resp, err := uc.Execute(context.Background(), GetOutboxRequest{ID: 2})
+
+		assert.ErrorIs(t, err, domainerrors.ErrOutboxNotFound)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		outboxRepo := new(MockOutboxRepository)
+		auditRepo := new(MockOutboxAuditRepository)
+		outboxRepo.On("FindByID", mock.Anything, int64(3)).Return(nil, errors.New("database error"))
+
+		uc := NewGetOutboxUseCase(outboxRepo, auditRepo)
+		_, err := uc.Execute(context.Background(), GetOutboxRequest{ID: 3})
+
+		assert.Error(t, err)
+	})
+}
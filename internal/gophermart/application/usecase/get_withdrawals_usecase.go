@@ -2,11 +2,17 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"io"
 	"time"
 
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
 )
 
+const maxWithdrawalsPageLimit = 1000
+
 type GetWithdrawalsUseCase struct {
 	withdrawalRepo repository.WithdrawalRepository
 }
@@ -17,34 +23,113 @@ func NewGetWithdrawalsUseCase(withdrawalRepo repository.WithdrawalRepository) *G
 	}
 }
 
+// GetWithdrawalsRequest — выборка списаний пользователя. Без Limit отдаются все подходящие
+// списания, с Limit — страница, продолжение которой передаётся в Cursor.
+// From включается в интервал дат списания, To — нет; MinSum и MaxSum — включительно.
 type GetWithdrawalsRequest struct {
 	UserID int64
+	From   time.Time
+	To     time.Time
+	MinSum *model.Points
+	MaxSum *model.Points
+	Cursor string
+	Limit  int
 }
 
-func (uc *GetWithdrawalsUseCase) Execute(ctx context.Context, req GetWithdrawalsRequest) ([]*WithdrawalResponse, error) {
-	withdrawals, err := uc.withdrawalRepo.FindByUserID(ctx, req.UserID)
+// Execute открывает поток списаний от новых к старым. Поток читает строки из базы по одной
+// и не держит историю в памяти, поэтому вызывающий обязан его закрыть.
+func (uc *GetWithdrawalsUseCase) Execute(ctx context.Context, req GetWithdrawalsRequest) (*WithdrawalStream, error) {
+	if !req.From.IsZero() && !req.To.IsZero() && !req.From.Before(req.To) {
+		return nil, domainerrors.ErrInvalidDateRange
+	}
+	if req.MinSum != nil && req.MaxSum != nil && req.MaxSum.Sub(*req.MinSum).IsNegative() {
+		return nil, domainerrors.ErrInvalidSumRange
+	}
+
+	limit := req.Limit
+	if limit > maxWithdrawalsPageLimit {
+		limit = maxWithdrawalsPageLimit
+	}
+
+	filter := repository.WithdrawalFilter{
+		From:   req.From,
+		To:     req.To,
+		MinSum: req.MinSum,
+		MaxSum: req.MaxSum,
+	}
+	if limit > 0 {
+		filter.Limit = limit + 1
+	}
+	if req.Cursor != "" {
+		processedAt, id, err := decodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = &repository.WithdrawalCursor{ProcessedAt: processedAt, ID: id}
+	}
+
+	it, err := uc.withdrawalRepo.FindByUserIDIterator(ctx, req.UserID, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(withdrawals) == 0 {
-		return []*WithdrawalResponse{}, nil
+	return &WithdrawalStream{it: it, limit: limit}, nil
+}
+
+// WithdrawalStream отдаёт списания по одному. При заданном limit лишняя строка сверх него
+// не возвращается, а только показывает, что есть следующая страница.
+type WithdrawalStream struct {
+	it         repository.Iterator[*model.Withdrawal]
+	limit      int
+	count      int
+	last       *model.Withdrawal
+	nextCursor string
+	done       bool
+}
+
+// Next возвращает очередное списание или io.EOF, когда поток исчерпан.
+func (s *WithdrawalStream) Next() (*WithdrawalResponse, error) {
+	if s.done {
+		return nil, io.EOF
 	}
 
-	response := make([]*WithdrawalResponse, 0, len(withdrawals))
-	for _, withdrawal := range withdrawals {
-		response = append(response, &WithdrawalResponse{
-			Order:       withdrawal.OrderNumber(),
-			Sum:         withdrawal.Sum(),
-			ProcessedAt: withdrawal.ProcessedAt(),
-		})
+	withdrawal, err := s.it.Next()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			s.done = true
+		}
+		return nil, err
+	}
+	if s.limit > 0 && s.count == s.limit {
+		s.nextCursor = encodeCursor(s.last.ProcessedAt(), s.last.ID())
+		s.done = true
+		return nil, io.EOF
 	}
 
-	return response, nil
+	s.count++
+	s.last = withdrawal
+	return newWithdrawalResponse(withdrawal), nil
+}
+
+// NextCursor известен после того, как Next вернул io.EOF, и пуст на последней странице.
+func (s *WithdrawalStream) NextCursor() string {
+	return s.nextCursor
+}
+
+func (s *WithdrawalStream) Close() error {
+	return s.it.Close()
 }
 
 type WithdrawalResponse struct {
-	Order       string    `json:"order"`
-	Sum         float64   `json:"sum"`
-	ProcessedAt time.Time `json:"processed_at"`
+	Order       string       `json:"order"`
+	Sum         model.Points `json:"sum"`
+	ProcessedAt time.Time    `json:"processed_at"`
+}
+
+func newWithdrawalResponse(withdrawal *model.Withdrawal) *WithdrawalResponse {
+	return &WithdrawalResponse{
+		Order:       withdrawal.OrderNumber(),
+		Sum:         withdrawal.Sum(),
+		ProcessedAt: withdrawal.ProcessedAt(),
+	}
 }
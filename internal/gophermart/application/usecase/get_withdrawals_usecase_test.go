@@ -3,93 +3,171 @@ package usecase
 import (
 	"context"
 	"errors"
+	"io"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
 )
 
+func collectWithdrawals(t *testing.T, stream *WithdrawalStream) ([]*WithdrawalResponse, error) {
+	t.Helper()
+	var items []*WithdrawalResponse
+	for {
+		item, err := stream.Next()
+		if errors.Is(err, io.EOF) {
+			return items, nil
+		}
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+}
+
 func TestGetWithdrawalsUseCase_Execute(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	withdrawals := []*model.Withdrawal{
+		model.RestoreWithdrawal(3, 1, "4532015112830366", model.MustParsePoints("250.75"), now),
+		model.RestoreWithdrawal(2, 1, "79927398713", model.MustParsePoints("50.5"), now.Add(-time.Hour)),
+		model.RestoreWithdrawal(1, 1, "12345678903", model.MustParsePoints("100"), now.Add(-2*time.Hour)),
+	}
+
+	t.Run("streams all withdrawals without limit", func(t *testing.T) {
+		mockRepo := new(MockWithdrawalRepository)
+		it := &sliceIterator[*model.Withdrawal]{items: withdrawals}
+		mockRepo.On("FindByUserIDIterator", mock.Anything, int64(1), repository.WithdrawalFilter{}).Return(it, nil)
+
+		stream, err := NewGetWithdrawalsUseCase(mockRepo).Execute(context.Background(), GetWithdrawalsRequest{UserID: 1})
+		require.NoError(t, err)
+		items, err := collectWithdrawals(t, stream)
+		require.NoError(t, stream.Close())
+
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+		assert.Equal(t, "4532015112830366", items[0].Order)
+		assert.Equal(t, model.MustParsePoints("250.75"), items[0].Sum)
+		assert.Empty(t, stream.NextCursor())
+		assert.True(t, it.closed)
+	})
+
+	t.Run("passes filters and stops at limit", func(t *testing.T) {
+		minSum, maxSum := model.MustParsePoints("10"), model.MustParsePoints("500")
+		from, to := now.Add(-24*time.Hour), now.Add(time.Hour)
+		mockRepo := new(MockWithdrawalRepository)
+		mockRepo.On("FindByUserIDIterator", mock.Anything, int64(1), repository.WithdrawalFilter{
+			From:   from,
+			To:     to,
+			MinSum: &minSum,
+			MaxSum: &maxSum,
+			Limit:  3,
+		}).Return(&sliceIterator[*model.Withdrawal]{items: withdrawals}, nil)
+
+		stream, err := NewGetWithdrawalsUseCase(mockRepo).Execute(context.Background(), GetWithdrawalsRequest{
+			UserID: 1,
+			From:   from,
+			To:     to,
+			MinSum: &minSum,
+			MaxSum: &maxSum,
+			Limit:  2,
+		})
+		require.NoError(t, err)
+		items, err := collectWithdrawals(t, stream)
+
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, "79927398713", items[1].Order)
+		assert.Equal(t, encodeCursor(now.Add(-time.Hour), 2), stream.NextCursor())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("continues after cursor", func(t *testing.T) {
+		mockRepo := new(MockWithdrawalRepository)
+		mockRepo.On("FindByUserIDIterator", mock.Anything, int64(1), repository.WithdrawalFilter{
+			After: &repository.WithdrawalCursor{ProcessedAt: now.Add(-time.Hour), ID: 2},
+			Limit: 3,
+		}).Return(&sliceIterator[*model.Withdrawal]{items: withdrawals[2:]}, nil)
+
+		stream, err := NewGetWithdrawalsUseCase(mockRepo).Execute(context.Background(), GetWithdrawalsRequest{
+			UserID: 1,
+			Cursor: encodeCursor(now.Add(-time.Hour), 2),
+			Limit:  2,
+		})
+		require.NoError(t, err)
+		items, err := collectWithdrawals(t, stream)
+
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Empty(t, stream.NextCursor())
+	})
+
+	t.Run("caps limit", func(t *testing.T) {
+		mockRepo := new(MockWithdrawalRepository)
+		mockRepo.On("FindByUserIDIterator", mock.Anything, int64(1), repository.WithdrawalFilter{Limit: maxWithdrawalsPageLimit + 1}).
+			Return(&sliceIterator[*model.Withdrawal]{}, nil)
+
+		stream, err := NewGetWithdrawalsUseCase(mockRepo).Execute(context.Background(), GetWithdrawalsRequest{UserID: 1, Limit: 5000})
+		require.NoError(t, err)
+		items, err := collectWithdrawals(t, stream)
+
+		require.NoError(t, err)
+		assert.Empty(t, items)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("iterator error", func(t *testing.T) {
+		mockRepo := new(MockWithdrawalRepository)
+		mockRepo.On("FindByUserIDIterator", mock.Anything, int64(1), mock.Anything).
+			Return(&sliceIterator[*model.Withdrawal]{items: withdrawals[:1], err: errors.New("connection lost")}, nil)
+
+		stream, err := NewGetWithdrawalsUseCase(mockRepo).Execute(context.Background(), GetWithdrawalsRequest{UserID: 1})
+		require.NoError(t, err)
+		items, err := collectWithdrawals(t, stream)
+
+		assert.Error(t, err)
+		assert.Len(t, items, 1)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo := new(MockWithdrawalRepository)
+		mockRepo.On("FindByUserIDIterator", mock.Anything, int64(1), mock.Anything).Return(nil, errors.New("database error"))
+
+		stream, err := NewGetWithdrawalsUseCase(mockRepo).Execute(context.Background(), GetWithdrawalsRequest{UserID: 1})
+
+		assert.Error(t, err)
+		assert.Nil(t, stream)
+	})
+}
+
+func TestGetWithdrawalsUseCase_Execute_Validation(t *testing.T) {
 	now := time.Now()
+	low, high := model.MustParsePoints("10"), model.MustParsePoints("20")
 
 	tests := []struct {
-		name      string
-		userID    int64
-		setupMock func(*MockWithdrawalRepository)
-		wantCount int
-		wantErr   bool
+		name    string
+		req     GetWithdrawalsRequest
+		wantErr error
 	}{
-		{
-			name:   "successful get withdrawals",
-			userID: 1,
-			setupMock: func(m *MockWithdrawalRepository) {
-				withdrawals := []*model.Withdrawal{
-					model.RestoreWithdrawal(1, 1, "12345678903", 100.0, now),
-					model.RestoreWithdrawal(2, 1, "79927398713", 50.5, now.Add(time.Hour)),
-				}
-				m.On("FindByUserID", mock.Anything, int64(1)).Return(withdrawals, nil)
-			},
-			wantCount: 2,
-			wantErr:   false,
-		},
-		{
-			name:   "empty withdrawals list",
-			userID: 2,
-			setupMock: func(m *MockWithdrawalRepository) {
-				m.On("FindByUserID", mock.Anything, int64(2)).Return([]*model.Withdrawal{}, nil)
-			},
-			wantCount: 0,
-			wantErr:   false,
-		},
-		{
-			name:   "repository error",
-			userID: 3,
-			setupMock: func(m *MockWithdrawalRepository) {
-				m.On("FindByUserID", mock.Anything, int64(3)).Return(nil, errors.New("database error"))
-			},
-			wantCount: 0,
-			wantErr:   true,
-		},
-		{
-			name:   "single withdrawal",
-			userID: 4,
-			setupMock: func(m *MockWithdrawalRepository) {
-				withdrawals := []*model.Withdrawal{
-					model.RestoreWithdrawal(1, 4, "4532015112830366", 250.75, now),
-				}
-				m.On("FindByUserID", mock.Anything, int64(4)).Return(withdrawals, nil)
-			},
-			wantCount: 1,
-			wantErr:   false,
-		},
+		{name: "from after to", req: GetWithdrawalsRequest{UserID: 1, From: now, To: now.Add(-time.Hour)}, wantErr: domainerrors.ErrInvalidDateRange},
+		{name: "min sum above max sum", req: GetWithdrawalsRequest{UserID: 1, MinSum: &high, MaxSum: &low}, wantErr: domainerrors.ErrInvalidSumRange},
+		{name: "malformed cursor", req: GetWithdrawalsRequest{UserID: 1, Cursor: "???"}, wantErr: domainerrors.ErrInvalidCursor},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(MockWithdrawalRepository)
-			tt.setupMock(mockRepo)
-
-			uc := NewGetWithdrawalsUseCase(mockRepo)
-			resp, err := uc.Execute(context.Background(), GetWithdrawalsRequest{UserID: tt.userID})
-
-			if tt.wantErr {
-				assert.Error(t, err)
-				assert.Nil(t, resp)
-			} else {
-				assert.NoError(t, err)
-				assert.NotNil(t, resp)
-				assert.Len(t, resp, tt.wantCount)
-
-				if tt.wantCount > 0 {
-					assert.NotEmpty(t, resp[0].Order)
-					assert.Greater(t, resp[0].Sum, 0.0)
-				}
-			}
-
-			mockRepo.AssertExpectations(t)
+
+			stream, err := NewGetWithdrawalsUseCase(mockRepo).Execute(context.Background(), tt.req)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, stream)
+			mockRepo.AssertNotCalled(t, "FindByUserIDIterator", mock.Anything, mock.Anything, mock.Anything)
 		})
 	}
 }
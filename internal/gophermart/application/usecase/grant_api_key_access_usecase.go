@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+)
+
+type GrantAPIKeyAccessUseCase struct {
+	apiKeyRepo repository.APIKeyRepository
+	now        func() time.Time
+}
+
+func NewGrantAPIKeyAccessUseCase(apiKeyRepo repository.APIKeyRepository) *GrantAPIKeyAccessUseCase {
+	return &GrantAPIKeyAccessUseCase{
+		apiKeyRepo: apiKeyRepo,
+		now:        time.Now,
+	}
+}
+
+// GrantAPIKeyAccessRequest — согласие пользователя UserID на запросы ключа KeyID от его имени.
+type GrantAPIKeyAccessRequest struct {
+	UserID int64
+	KeyID  int64
+}
+
+// Execute разрешает ключу действовать от имени пользователя. Отозванный, истёкший
+// или несуществующий ключ даёт ErrAPIKeyNotFound.
+func (uc *GrantAPIKeyAccessUseCase) Execute(ctx context.Context, req GrantAPIKeyAccessRequest) error {
+	granted, err := uc.apiKeyRepo.Grant(ctx, req.KeyID, req.UserID, uc.now())
+	if err != nil {
+		return err
+	}
+	if !granted {
+		return fmt.Errorf("api key %d: %w", req.KeyID, domainerrors.ErrAPIKeyNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+)
+
+func TestGrantAPIKeyAccessUseCase_Execute(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		granted bool
+		wantErr error
+	}{
+		{name: "grants access to active key", granted: true},
+		{name: "unknown or inactive key", granted: false, wantErr: domainerrors.ErrAPIKeyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockAPIKeyRepository)
+			repo.On("Grant", mock.Anything, int64(3), int64(42), now).Return(tt.granted, nil)
+			uc := NewGrantAPIKeyAccessUseCase(repo)
+			uc.now = func() time.Time { return now }
+
+			err := uc.Execute(context.Background(), GrantAPIKeyAccessRequest{UserID: 42, KeyID: 3})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
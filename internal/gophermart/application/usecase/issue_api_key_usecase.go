@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+)
+
+const (
+	apiKeyPrefix      = "gm_"
+	apiKeyRandomBytes = 32
+	// apiKeyDisplayLen — сколько первых символов ключа сохраняется открыто, чтобы администратор
+	// мог отличить ключи в списке.
+	apiKeyDisplayLen = len(apiKeyPrefix) + 8
+)
+
+type IssueAPIKeyUseCase struct {
+	apiKeyRepo repository.APIKeyRepository
+	random     io.Reader
+	now        func() time.Time
+}
+
+func NewIssueAPIKeyUseCase(apiKeyRepo repository.APIKeyRepository) *IssueAPIKeyUseCase {
+	return &IssueAPIKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+		random:     rand.Reader,
+		now:        time.Now,
+	}
+}
+
+// IssueAPIKeyRequest описывает новый ключ. Нулевой TTL означает бессрочный ключ.
+type IssueAPIKeyRequest struct {
+	ActorUserID int64
+	Name        string
+	Owner       string
+	Scopes      []model.APIKeyScope
+	TTL         time.Duration
+}
+
+type IssueAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// Execute создаёт ключ и возвращает его в открытом виде. Это единственный момент,
+// когда ключ можно узнать: в базе остаётся только хеш.
+func (uc *IssueAPIKeyUseCase) Execute(ctx context.Context, req IssueAPIKeyRequest) (*IssueAPIKeyResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	owner := strings.TrimSpace(req.Owner)
+	if name == "" || owner == "" {
+		return nil, domainerrors.ErrAPIKeyNameRequired
+	}
+	if req.TTL < 0 {
+		return nil, domainerrors.ErrInvalidAPIKeyExpiry
+	}
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, apiKeyRandomBytes)
+	if _, err := io.ReadFull(uc.random, secret); err != nil {
+		return nil, fmt.Errorf("generate api key: %w", err)
+	}
+	plain := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	key := &model.APIKey{
+		Name:      name,
+		Owner:     owner,
+		Prefix:    plain[:apiKeyDisplayLen],
+		KeyHash:   model.HashAPIKey(plain),
+		Scopes:    scopes,
+		CreatedBy: req.ActorUserID,
+	}
+	if req.TTL > 0 {
+		expiresAt := uc.now().Add(req.TTL)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := uc.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &IssueAPIKeyResponse{
+		APIKeyResponse: *newAPIKeyResponse(key),
+		Key:            plain,
+	}, nil
+}
+
+func normalizeScopes(scopes []model.APIKeyScope) ([]model.APIKeyScope, error) {
+	if len(scopes) == 0 {
+		return nil, domainerrors.ErrInvalidAPIKeyScope
+	}
+	result := make([]model.APIKeyScope, 0, len(scopes))
+	seen := make(map[model.APIKeyScope]bool, len(scopes))
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return nil, fmt.Errorf("scope %q: %w", scope, domainerrors.ErrInvalidAPIKeyScope)
+		}
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		result = append(result, scope)
+	}
+	return result, nil
+}
+
+type APIKeyResponse struct {
+	ID         int64               `json:"id"`
+	Name       string              `json:"name"`
+	Owner      string              `json:"owner"`
+	Prefix     string              `json:"prefix"`
+	Scopes     []model.APIKeyScope `json:"scopes"`
+	CreatedBy  int64               `json:"created_by"`
+	ExpiresAt  *time.Time          `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time          `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time          `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
+
+func newAPIKeyResponse(key *model.APIKey) *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Owner:      key.Owner,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		CreatedBy:  key.CreatedBy,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
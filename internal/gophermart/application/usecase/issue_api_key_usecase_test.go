@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+)
+
+func TestIssueAPIKeyUseCase_Execute(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := new(MockAPIKeyRepository)
+	uc := NewIssueAPIKeyUseCase(repo)
+	uc.random = bytes.NewReader(bytes.Repeat([]byte{0xab}, apiKeyRandomBytes))
+	uc.now = func() time.Time { return now }
+
+	var stored *model.APIKey
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*model.APIKey")).
+		Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*model.APIKey)
+			stored.ID = 7
+			stored.CreatedAt = now
+		}).
+		Return(nil)
+
+	resp, err := uc.Execute(context.Background(), IssueAPIKeyRequest{
+		ActorUserID: 1,
+		Name:        " pos terminal ",
+		Owner:       "coffee-shop",
+		Scopes:      []model.APIKeyScope{model.APIKeyScopeOrdersWrite, model.APIKeyScopeOrdersWrite, model.APIKeyScopeBalanceRead},
+		TTL:         24 * time.Hour,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(resp.Key, "gm_"))
+	assert.Equal(t, int64(7), resp.ID)
+	assert.Equal(t, resp.Key[:apiKeyDisplayLen], resp.Prefix)
+	assert.Equal(t, "pos terminal", resp.Name)
+	assert.Equal(t, []model.APIKeyScope{model.APIKeyScopeOrdersWrite, model.APIKeyScopeBalanceRead}, resp.Scopes)
+	require.NotNil(t, resp.ExpiresAt)
+	assert.Equal(t, now.Add(24*time.Hour), *resp.ExpiresAt)
+
+	require.NotNil(t, stored)
+	assert.Equal(t, model.HashAPIKey(resp.Key), stored.KeyHash)
+	assert.Equal(t, int64(1), stored.CreatedBy)
+}
+
+func TestIssueAPIKeyUseCase_Execute_NoExpiry(t *testing.T) {
+	repo := new(MockAPIKeyRepository)
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*model.APIKey")).Return(nil)
+
+	resp, err := NewIssueAPIKeyUseCase(repo).Execute(context.Background(), IssueAPIKeyRequest{
+		Name:   "pos",
+		Owner:  "shop",
+		Scopes: []model.APIKeyScope{model.APIKeyScopeOrdersWrite},
+	})
+
+	require.NoError(t, err)
+	assert.Nil(t, resp.ExpiresAt)
+}
+
+func TestIssueAPIKeyUseCase_Execute_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     IssueAPIKeyRequest
+		wantErr error
+	}{
+		{
+			name:    "missing name",
+			req:     IssueAPIKeyRequest{Owner: "shop", Scopes: []model.APIKeyScope{model.APIKeyScopeOrdersWrite}},
+			wantErr: domainerrors.ErrAPIKeyNameRequired,
+		},
+		{
+			name:    "missing owner",
+			req:     IssueAPIKeyRequest{Name: "pos", Owner: "  ", Scopes: []model.APIKeyScope{model.APIKeyScopeOrdersWrite}},
+			wantErr: domainerrors.ErrAPIKeyNameRequired,
+		},
+		{
+			name:    "no scopes",
+			req:     IssueAPIKeyRequest{Name: "pos", Owner: "shop"},
+			wantErr: domainerrors.ErrInvalidAPIKeyScope,
+		},
+		{
+			name:    "unknown scope",
+			req:     IssueAPIKeyRequest{Name: "pos", Owner: "shop", Scopes: []model.APIKeyScope{"balance:write"}},
+			wantErr: domainerrors.ErrInvalidAPIKeyScope,
+		},
+		{
+			name:    "negative ttl",
+			req:     IssueAPIKeyRequest{Name: "pos", Owner: "shop", Scopes: []model.APIKeyScope{model.APIKeyScopeOrdersWrite}, TTL: -time.Hour},
+			wantErr: domainerrors.ErrInvalidAPIKeyExpiry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockAPIKeyRepository)
+
+			resp, err := NewIssueAPIKeyUseCase(repo).Execute(context.Background(), tt.req)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, resp)
+			repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
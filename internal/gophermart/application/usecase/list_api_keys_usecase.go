@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+)
+
+type ListAPIKeysUseCase struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+func NewListAPIKeysUseCase(apiKeyRepo repository.APIKeyRepository) *ListAPIKeysUseCase {
+	return &ListAPIKeysUseCase{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Execute возвращает все ключи, включая отозванные и истёкшие, без самих секретов.
+func (uc *ListAPIKeysUseCase) Execute(ctx context.Context) ([]*APIKeyResponse, error) {
+	keys, err := uc.apiKeyRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, newAPIKeyResponse(key))
+	}
+
+	return response, nil
+}
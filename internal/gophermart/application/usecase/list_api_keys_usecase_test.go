@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+)
+
+func TestListAPIKeysUseCase_Execute(t *testing.T) {
+	now := time.Now()
+	repo := new(MockAPIKeyRepository)
+	repo.On("List", mock.Anything).Return([]*model.APIKey{
+		{ID: 2, Name: "pos", Owner: "shop", Prefix: "gm_abcd1234", KeyHash: "hash", RevokedAt: &now},
+		{ID: 1, Name: "web", Owner: "shop", Prefix: "gm_efgh5678", KeyHash: "hash"},
+	}, nil)
+
+	keys, err := NewListAPIKeysUseCase(repo).Execute(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, int64(2), keys[0].ID)
+	assert.Equal(t, "gm_abcd1234", keys[0].Prefix)
+	assert.NotNil(t, keys[0].RevokedAt)
+}
+
+func TestListAPIKeysUseCase_Execute_Error(t *testing.T) {
+	repo := new(MockAPIKeyRepository)
+	repo.On("List", mock.Anything).Return(nil, errors.New("db error"))
+
+	keys, err := NewListAPIKeysUseCase(repo).Execute(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, keys)
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+)
+
+const (
+	defaultOutboxListLimit = 50
+	maxOutboxListLimit     = 500
+)
+
+type ListOutboxUseCase struct {
+	outboxRepo repository.OutboxRepository
+}
+
+func NewListOutboxUseCase(outboxRepo repository.OutboxRepository) *ListOutboxUseCase {
+	return &ListOutboxUseCase{
+		outboxRepo: outboxRepo,
+	}
+}
+
+type ListOutboxRequest struct {
+	Status      model.OutboxStatus
+	OrderNumber string
+	OlderThan   time.Duration
+	Limit       int
+}
+
+func (uc *ListOutboxUseCase) Execute(ctx context.Context, req ListOutboxRequest) ([]*OutboxResponse, error) {
+	if req.Status != "" && !req.Status.IsValid() {
+		return nil, domainerrors.ErrInvalidOutboxStatus
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultOutboxListLimit
+	}
+	if limit > maxOutboxListLimit {
+		limit = maxOutboxListLimit
+	}
+
+	outboxes, err := uc.outboxRepo.List(ctx, repository.OutboxFilter{
+		Status:      req.Status,
+		OrderNumber: req.OrderNumber,
+		OlderThan:   req.OlderThan,
+		Limit:       limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*OutboxResponse, 0, len(outboxes))
+	for _, outbox := range outboxes {
+		response = append(response, newOutboxResponse(outbox))
+	}
+
+	return response, nil
+}
+
+type OutboxResponse struct {
+	ID             int64              `json:"id"`
+	OrderID        int64              `json:"order_id"`
+	OrderNumber    string             `json:"order_number"`
+	Status         model.OutboxStatus `json:"status"`
+	Retries        int                `json:"retries"`
+	LastError      string             `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time          `json:"next_attempt_at"`
+	LeaseOwner     string             `json:"lease_owner,omitempty"`
+	LeaseExpiresAt *time.Time         `json:"lease_expires_at,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+}
+
+func newOutboxResponse(outbox *model.Outbox) *OutboxResponse {
+	return &OutboxResponse{
+		ID:             outbox.ID,
+		OrderID:        outbox.OrderID,
+		OrderNumber:    outbox.OrderNumber,
+		Status:         outbox.Status,
+		Retries:        outbox.Retries,
+		LastError:      outbox.LastError,
+		NextAttemptAt:  outbox.NextAttemptAt,
+		LeaseOwner:     outbox.LeaseOwner,
+		LeaseExpiresAt: outbox.LeaseExpiresAt,
+		CreatedAt:      outbox.CreatedAt,
+		UpdatedAt:      outbox.UpdatedAt,
+	}
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+)
+
+func TestListOutboxUseCase_Execute(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		req       ListOutboxRequest
+		setupMock func(*MockOutboxRepository)
+		wantCount int
+		wantErr   error
+	}{
+		{
+			name: "filters are passed to repository",
+			req:  ListOutboxRequest{Status: model.OutboxStatusDead, OrderNumber: "79927398713", OlderThan: time.Hour, Limit: 10},
+			setupMock: func(m *MockOutboxRepository) {
+				filter := repository.OutboxFilter{Status: model.OutboxStatusDead, OrderNumber: "79927398713", OlderThan: time.Hour, Limit: 10}
+				m.On("List", mock.Anything, filter).Return([]*model.Outbox{
+					{ID: 1, OrderID: 1, OrderNumber: "79927398713", Status: model.OutboxStatusDead, Retries: 10, LastError: "timeout", CreatedAt: now},
+				}, nil)
+			},
+			wantCount: 1,
+		},
+		{
+			name: "default limit",
+			req:  ListOutboxRequest{},
+			setupMock: func(m *MockOutboxRepository) {
+				m.On("List", mock.Anything, repository.OutboxFilter{Limit: defaultOutboxListLimit}).Return([]*model.Outbox{}, nil)
+			},
+			wantCount: 0,
+		},
+		{
+			name: "limit is capped",
+			req:  ListOutboxRequest{Limit: 100000},
+			setupMock: func(m *MockOutboxRepository) {
+				m.On("List", mock.Anything, repository.OutboxFilter{Limit: maxOutboxListLimit}).Return([]*model.Outbox{}, nil)
+			},
+			wantCount: 0,
+		},
+		{
+			name:      "unknown status",
+			req:       ListOutboxRequest{Status: "BROKEN"},
+			setupMock: func(m *MockOutboxRepository) {},
+			wantErr:   domainerrors.ErrInvalidOutboxStatus,
+		},
+		{
+			name: "repository error",
+			req:  ListOutboxRequest{},
+			setupMock: func(m *MockOutboxRepository) {
+				m.On("List", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+			},
+			wantErr: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockOutboxRepository)
+			tt.setupMock(mockRepo)
+
+			uc := NewListOutboxUseCase(mockRepo)
+			resp, err := uc.Execute(context.Background(), tt.req)
+
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, resp, tt.wantCount)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
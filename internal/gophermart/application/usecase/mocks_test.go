@@ -2,11 +2,14 @@ package usecase
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
 )
 
 type MockOrderNumberValidator struct {
@@ -30,12 +33,30 @@ func (m *MockUnitOfWork) Begin(ctx context.Context) (repository.Transaction, err
 	return args.Get(0).(repository.Transaction), args.Error(1)
 }
 
+// Do повторяет поведение настоящей единицы работы без повторов: транзакция берётся из Begin,
+// фиксируется при успехе fn и откатывается в любом случае.
+func (m *MockUnitOfWork) Do(ctx context.Context, fn func(tx repository.Transaction) error) error {
+	tx, err := m.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
 type MockTransaction struct {
 	mock.Mock
 	balanceRepo    *MockBalanceRepository
 	withdrawalRepo *MockWithdrawalRepository
 	orderRepo      *MockOrderRepository
 	outboxRepo     *MockOutboxRepository
+	ledgerRepo     *MockLedgerRepository
+	auditRepo      *MockOutboxAuditRepository
+	idempotency    *MockIdempotencyRepository
 }
 
 func (m *MockTransaction) BalanceRepository() repository.BalanceRepository {
@@ -54,6 +75,18 @@ func (m *MockTransaction) OutboxRepository() repository.OutboxRepository {
 	return m.outboxRepo
 }
 
+func (m *MockTransaction) LedgerRepository() repository.LedgerRepository {
+	return m.ledgerRepo
+}
+
+func (m *MockTransaction) OutboxAuditRepository() repository.OutboxAuditRepository {
+	return m.auditRepo
+}
+
+func (m *MockTransaction) IdempotencyRepository() repository.IdempotencyRepository {
+	return m.idempotency
+}
+
 func (m *MockTransaction) Commit(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
@@ -76,16 +109,49 @@ func (m *MockBalanceRepository) GetByUserID(ctx context.Context, userID int64) (
 	return args.Get(0).(*model.Balance), args.Error(1)
 }
 
-func (m *MockBalanceRepository) Withdraw(ctx context.Context, userID int64, amount float64) error {
-	args := m.Called(ctx, userID, amount)
+func (m *MockBalanceRepository) GetByUserIDForUpdate(ctx context.Context, userID int64) (*model.Balance, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Balance), args.Error(1)
+}
+
+func (m *MockBalanceRepository) Rebuild(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
 	return args.Error(0)
 }
 
-func (m *MockBalanceRepository) Accrue(ctx context.Context, userID int64, amount float64) error {
-	args := m.Called(ctx, userID, amount)
+func (m *MockBalanceRepository) RebuildAll(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockLedgerRepository struct {
+	mock.Mock
+}
+
+func (m *MockLedgerRepository) Post(ctx context.Context, posting *model.LedgerPosting) error {
+	args := m.Called(ctx, posting)
 	return args.Error(0)
 }
 
+func (m *MockLedgerRepository) FindByUserID(ctx context.Context, userID int64) ([]*model.LedgerEntry, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.LedgerEntry), args.Error(1)
+}
+
+func (m *MockLedgerRepository) FindByTransactionID(ctx context.Context, transactionID int64) ([]*model.LedgerEntry, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.LedgerEntry), args.Error(1)
+}
+
 type MockWithdrawalRepository struct {
 	mock.Mock
 }
@@ -103,6 +169,31 @@ func (m *MockWithdrawalRepository) FindByUserID(ctx context.Context, userID int6
 	return args.Get(0).([]*model.Withdrawal), args.Error(1)
 }
 
+func (m *MockWithdrawalRepository) FindByUserIDIterator(ctx context.Context, userID int64, filter repository.WithdrawalFilter) (repository.Iterator[*model.Withdrawal], error) {
+	args := m.Called(ctx, userID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(repository.Iterator[*model.Withdrawal]), args.Error(1)
+}
+
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) Reserve(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+	args := m.Called(ctx, record)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.IdempotencyRecord), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) SaveResponse(ctx context.Context, userID int64, key string, response []byte) error {
+	args := m.Called(ctx, userID, key, response)
+	return args.Error(0)
+}
+
 type MockOrderRepository struct {
 	mock.Mock
 }
@@ -112,6 +203,14 @@ func (m *MockOrderRepository) Create(ctx context.Context, order *model.Order) er
 	return args.Error(0)
 }
 
+func (m *MockOrderRepository) FindByUserIDIterator(ctx context.Context, userID int64, filter repository.OrderFilter) (repository.Iterator[*model.Order], error) {
+	args := m.Called(ctx, userID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(repository.Iterator[*model.Order]), args.Error(1)
+}
+
 func (m *MockOrderRepository) FindByNumber(ctx context.Context, number string) (*model.Order, error) {
 	args := m.Called(ctx, number)
 	if args.Get(0) == nil {
@@ -136,11 +235,29 @@ func (m *MockOrderRepository) FindByID(ctx context.Context, id int64) (*model.Or
 	return args.Get(0).(*model.Order), args.Error(1)
 }
 
-func (m *MockOrderRepository) UpdateStatus(ctx context.Context, orderID int64, status model.OrderStatus, accrual *float64) error {
-	args := m.Called(ctx, orderID, status, accrual)
+func (m *MockOrderRepository) UpdateStatus(ctx context.Context, orderID int64, status model.OrderStatus, accrual *model.Points, note model.OrderStatusNote) (bool, error) {
+	args := m.Called(ctx, orderID, status, accrual, note)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOrderRepository) FindStatusHistory(ctx context.Context, orderID int64) ([]*model.OrderStatusChange, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.OrderStatusChange), args.Error(1)
+}
+
+func (m *MockOrderRepository) LockUser(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
 	return args.Error(0)
 }
 
+func (m *MockOrderRepository) HasPendingByUserID(ctx context.Context, userID int64) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockOrderRepository) FindPending(ctx context.Context, limit int) ([]*model.Order, error) {
 	args := m.Called(ctx, limit)
 	if args.Get(0) == nil {
@@ -166,12 +283,176 @@ func (m *MockOutboxRepository) FindPending(ctx context.Context, limit int) ([]*m
 	return args.Get(0).([]*model.Outbox), args.Error(1)
 }
 
-func (m *MockOutboxRepository) UpdateStatus(ctx context.Context, id int64, status model.OutboxStatus) error {
-	args := m.Called(ctx, id, status)
+func (m *MockOutboxRepository) UpdateStatus(ctx context.Context, id int64, owner string, status model.OutboxStatus) error {
+	args := m.Called(ctx, id, owner, status)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) ClaimBatch(ctx context.Context, owner string, limit int, leaseTTL time.Duration) ([]*model.Outbox, error) {
+	args := m.Called(ctx, owner, limit, leaseTTL)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Outbox), args.Error(1)
+}
+
+func (m *MockOutboxRepository) ScheduleRetry(ctx context.Context, id int64, owner string, delay time.Duration, lastError string) error {
+	args := m.Called(ctx, id, owner, delay, lastError)
 	return args.Error(0)
 }
 
-func (m *MockOutboxRepository) IncrementRetries(ctx context.Context, id int64) error {
+func (m *MockOutboxRepository) FindByID(ctx context.Context, id int64) (*model.Outbox, error) {
 	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Outbox), args.Error(1)
+}
+
+func (m *MockOutboxRepository) List(ctx context.Context, filter repository.OutboxFilter) ([]*model.Outbox, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Outbox), args.Error(1)
+}
+
+func (m *MockOutboxRepository) Requeue(ctx context.Context, ids []int64) ([]*model.Outbox, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Outbox), args.Error(1)
+}
+
+func (m *MockOutboxRepository) RequeueByStatus(ctx context.Context, status model.OutboxStatus) ([]*model.Outbox, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Outbox), args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkDead(ctx context.Context, id int64, owner string, lastError string) error {
+	args := m.Called(ctx, id, owner, lastError)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) ScheduleNextAttempt(ctx context.Context, id int64, owner string, delay time.Duration) error {
+	args := m.Called(ctx, id, owner, delay)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) Postpone(ctx context.Context, id int64, owner string, delay time.Duration) error {
+	args := m.Called(ctx, id, owner, delay)
+	return args.Error(0)
+}
+
+type MockOutboxAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxAuditRepository) Record(ctx context.Context, entry *model.OutboxAuditEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockOutboxAuditRepository) FindByOutboxID(ctx context.Context, outboxID int64) ([]*model.OutboxAuditEntry, error) {
+	args := m.Called(ctx, outboxID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.OutboxAuditEntry), args.Error(1)
+}
+
+type MockAccountDirectory struct {
+	mock.Mock
+}
+
+func (m *MockAccountDirectory) GetProfile(ctx context.Context, userID int64) (*service.AccountProfile, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.AccountProfile), args.Error(1)
+}
+
+func (m *MockAccountDirectory) Delete(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
 	return args.Error(0)
 }
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	args := m.Called(ctx, keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) List(ctx context.Context) ([]*model.APIKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id int64, at time.Time) (bool, error) {
+	args := m.Called(ctx, id, at)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) Grant(ctx context.Context, id, userID int64, at time.Time) (bool, error) {
+	args := m.Called(ctx, id, userID, at)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) RevokeGrant(ctx context.Context, id, userID int64) (bool, error) {
+	args := m.Called(ctx, id, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) IsGranted(ctx context.Context, id, userID int64) (bool, error) {
+	args := m.Called(ctx, id, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+// sliceIterator — Iterator поверх заранее заданного среза для моков репозиториев.
+type sliceIterator[T any] struct {
+	items  []T
+	err    error
+	closed bool
+}
+
+func (it *sliceIterator[T]) Next() (T, error) {
+	var zero T
+	if len(it.items) == 0 {
+		if it.err != nil {
+			return zero, it.err
+		}
+		return zero, io.EOF
+	}
+	item := it.items[0]
+	it.items = it.items[1:]
+	return item, nil
+}
+
+func (it *sliceIterator[T]) Close() error {
+	it.closed = true
+	return nil
+}
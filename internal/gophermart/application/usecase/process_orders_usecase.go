@@ -3,47 +3,75 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"math/rand/v2"
 	"time"
 
 	"golang.org/x/sync/errgroup"
 
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
 )
 
+// orderRejectedReason записывается в историю заказа, отклонённого системой начислений.
+const orderRejectedReason = "rejected by accrual system"
+
+// ProcessOrdersConfig задаёт расписание опроса заказов в нефинальном статусе.
+type ProcessOrdersConfig struct {
+	// PollInterval — пауза перед повторным опросом заказа со статусом REGISTERED или PROCESSING.
+	PollInterval time.Duration
+	// MaxOrderAge — длительность опроса заказа, после которой опрос прекращается и заказ
+	// передаётся на проверку. Отсчитывается от Outbox.PollingSince, поэтому ручной перезапуск
+	// записи из REVIEW даёт заказу новый срок.
+	MaxOrderAge time.Duration
+	// WorkerID — идентификатор экземпляра сервиса, от имени которого захватываются записи outbox.
+	WorkerID string
+	// LeaseTTL — время аренды записи outbox; по его истечении запись может захватить другой экземпляр.
+	LeaseTTL time.Duration
+	// Retry — политика повторов при ошибках обработки.
+	Retry RetryPolicy
+}
+
 type ProcessOrdersUseCase struct {
+	unitOfWork     repository.UnitOfWork
 	outboxRepo     repository.OutboxRepository
 	orderRepo      repository.OrderRepository
-	balanceRepo    repository.BalanceRepository
 	accrualService service.AccrualService
+	config         ProcessOrdersConfig
+	now            func() time.Time
+	random         func(n int64) int64
 }
 
 func NewProcessOrdersUseCase(
+	unitOfWork repository.UnitOfWork,
 	outboxRepo repository.OutboxRepository,
 	orderRepo repository.OrderRepository,
-	balanceRepo repository.BalanceRepository,
 	accrualService service.AccrualService,
+	config ProcessOrdersConfig,
 ) *ProcessOrdersUseCase {
 	return &ProcessOrdersUseCase{
+		unitOfWork:     unitOfWork,
 		outboxRepo:     outboxRepo,
 		orderRepo:      orderRepo,
-		balanceRepo:    balanceRepo,
 		accrualService: accrualService,
+		config:         config,
+		now:            time.Now,
+		random:         rand.Int64N,
 	}
 }
 
 const (
-	maxRetries     = 3
 	batchSize      = 10
 	maxConcurrency = 5
 )
 
 func (uc *ProcessOrdersUseCase) ProcessPendingOrders(ctx context.Context) error {
-	outboxes, err := uc.outboxRepo.FindPending(ctx, batchSize)
+	outboxes, err := uc.outboxRepo.ClaimBatch(ctx, uc.config.WorkerID, batchSize, uc.config.LeaseTTL)
 	if err != nil {
-		slog.ErrorContext(ctx, "failed to find pending outboxes", "error", err)
+		slog.ErrorContext(ctx, "failed to claim pending outboxes", "error", err)
 		return err
 	}
 
@@ -65,6 +93,34 @@ func (uc *ProcessOrdersUseCase) ProcessPendingOrders(ctx context.Context) error
 			defer func() { <-sem }()
 
 			if err := uc.processOrder(gCtx, outbox); err != nil {
+				// Запись, аренду которой перехватил другой обработчик, больше не наша:
+				// её результат уже откатан, а повторы планирует новый владелец.
+				if errors.Is(err, domainerrors.ErrOutboxLeaseLost) {
+					slog.WarnContext(gCtx, "outbox lease lost, result discarded",
+						"order_id", outbox.OrderID,
+						"outbox_id", outbox.ID,
+					)
+					return nil
+				}
+
+				// Пауза из-за лимита запросов не считается неудачной попыткой:
+				// запись откладывается до окончания паузы и освобождается из аренды.
+				var rateLimitErr *service.RateLimitError
+				if errors.As(err, &rateLimitErr) {
+					slog.InfoContext(gCtx, "accrual system rate limit reached, order postponed",
+						"order_id", outbox.OrderID,
+						"outbox_id", outbox.ID,
+						"retry_after", rateLimitErr.RetryAfter,
+					)
+					if updateErr := uc.outboxRepo.Postpone(gCtx, outbox.ID, uc.config.WorkerID, rateLimitErr.RetryAfter); updateErr != nil {
+						slog.ErrorContext(gCtx, "failed to postpone outbox",
+							"outbox_id", outbox.ID,
+							"error", updateErr,
+						)
+					}
+					return nil
+				}
+
 				slog.WarnContext(gCtx, "failed to process order",
 					"order_id", outbox.OrderID,
 					"outbox_id", outbox.ID,
@@ -72,21 +128,22 @@ func (uc *ProcessOrdersUseCase) ProcessPendingOrders(ctx context.Context) error
 					"error", err,
 				)
 
-				if outbox.Retries >= maxRetries {
-					if updateErr := uc.outboxRepo.UpdateStatus(gCtx, outbox.ID, model.OutboxStatusFailed); updateErr != nil {
-						slog.ErrorContext(gCtx, "failed to update outbox status to failed",
+				if outbox.Retries >= uc.config.Retry.MaxRetries {
+					if updateErr := uc.outboxRepo.MarkDead(gCtx, outbox.ID, uc.config.WorkerID, err.Error()); updateErr != nil {
+						slog.ErrorContext(gCtx, "failed to mark outbox as dead",
 							"outbox_id", outbox.ID,
 							"error", updateErr,
 						)
 					} else {
-						slog.InfoContext(gCtx, "outbox marked as failed",
+						slog.InfoContext(gCtx, "outbox marked as dead",
 							"outbox_id", outbox.ID,
 							"retries", outbox.Retries,
 						)
 					}
 				} else {
-					if updateErr := uc.outboxRepo.IncrementRetries(gCtx, outbox.ID); updateErr != nil {
-						slog.ErrorContext(gCtx, "failed to increment retries",
+					delay := uc.config.Retry.Backoff(outbox.Retries, uc.random)
+					if updateErr := uc.outboxRepo.ScheduleRetry(gCtx, outbox.ID, uc.config.WorkerID, delay, err.Error()); updateErr != nil {
+						slog.ErrorContext(gCtx, "failed to schedule retry",
 							"outbox_id", outbox.ID,
 							"error", updateErr,
 						)
@@ -95,17 +152,10 @@ func (uc *ProcessOrdersUseCase) ProcessPendingOrders(ctx context.Context) error
 				return nil
 			}
 
-			if err := uc.outboxRepo.UpdateStatus(gCtx, outbox.ID, model.OutboxStatusProcessed); err != nil {
-				slog.ErrorContext(gCtx, "failed to update outbox status to processed",
-					"outbox_id", outbox.ID,
-					"error", err,
-				)
-			} else {
-				slog.InfoContext(gCtx, "order processed successfully",
-					"order_id", outbox.OrderID,
-					"outbox_id", outbox.ID,
-				)
-			}
+			slog.InfoContext(gCtx, "order processed successfully",
+				"order_id", outbox.OrderID,
+				"outbox_id", outbox.ID,
+			)
 			return nil
 		})
 	}
@@ -125,27 +175,20 @@ func (uc *ProcessOrdersUseCase) processOrder(ctx context.Context, outbox *model.
 		return err
 	}
 	if order == nil {
-		return errors.New("order not found")
+		return fmt.Errorf("outbox %d: %w", outbox.ID, domainerrors.ErrOrderNotFound)
 	}
 
 	accrualResp, err := uc.accrualService.GetOrderInfo(ctx, order.Number())
 	if err != nil {
-		if err.Error() == "rate limited" || err.Error() == "order not found in accrual system" {
-			if err.Error() == "order not found in accrual system" {
-				if updateErr := order.UpdateStatus(model.OrderStatusInvalid, nil); updateErr != nil {
-					return updateErr
-				}
-				if updateErr := uc.orderRepo.UpdateStatus(ctx, order.ID(), model.OrderStatusInvalid, nil); updateErr != nil {
-					return updateErr
-				}
-				return nil
-			}
-			return err
+		if errors.Is(err, domainerrors.ErrAccrualOrderNotRegistered) {
+			note := model.OrderStatusNote{Reason: err.Error()}
+			return uc.applyAccrualStatus(ctx, outbox, order, model.OrderStatusInvalid, nil, note)
 		}
 		return err
 	}
 
 	var newStatus model.OrderStatus
+	note := model.OrderStatusNote{AccrualStatus: accrualResp.Status}
 	switch accrualResp.Status {
 	case "REGISTERED":
 		newStatus = model.OrderStatusNew
@@ -153,26 +196,82 @@ func (uc *ProcessOrdersUseCase) processOrder(ctx context.Context, outbox *model.
 		newStatus = model.OrderStatusProcessing
 	case "INVALID":
 		newStatus = model.OrderStatusInvalid
+		note.Reason = orderRejectedReason
 	case "PROCESSED":
 		newStatus = model.OrderStatusProcessed
-		if accrualResp.Accrual != nil {
-			if err := uc.balanceRepo.Accrue(ctx, order.UserID(), *accrualResp.Accrual); err != nil {
-				return err
-			}
-		}
 	default:
-		return errors.New("unknown accrual status")
+		return fmt.Errorf("%w: %q", domainerrors.ErrUnknownAccrualStatus, accrualResp.Status)
+	}
+
+	return uc.applyAccrualStatus(ctx, outbox, order, newStatus, accrualResp.Accrual, note)
+}
+
+// applyAccrualStatus в одной транзакции меняет статус заказа, зачисляет начисление в журнал
+// и обновляет запись outbox. Если заказ уже финализирован другим обработчиком,
+// повторного зачисления не происходит. Если аренда записи outbox потеряна, транзакция
+// откатывается вместе с начислением.
+func (uc *ProcessOrdersUseCase) applyAccrualStatus(ctx context.Context, outbox *model.Outbox, order *model.Order, newStatus model.OrderStatus, accrual *model.Points, note model.OrderStatusNote) error {
+	if order.Status().IsFinal() {
+		return uc.outboxRepo.UpdateStatus(ctx, outbox.ID, uc.config.WorkerID, model.OutboxStatusProcessed)
 	}
 
-	if err := order.UpdateStatus(newStatus, accrualResp.Accrual); err != nil {
+	if err := order.UpdateStatus(newStatus, accrual); err != nil {
 		return err
 	}
 
-	if err := uc.orderRepo.UpdateStatus(ctx, order.ID(), newStatus, accrualResp.Accrual); err != nil {
+	tx, err := uc.unitOfWork.Begin(ctx)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback(ctx)
 
-	return nil
+	updated, err := tx.OrderRepository().UpdateStatus(ctx, order.ID(), newStatus, accrual, note)
+	if err != nil {
+		return err
+	}
+
+	if updated && newStatus == model.OrderStatusProcessed && accrual != nil && accrual.IsPositive() {
+		posting, err := model.NewAccrualPosting(order.UserID(), order.ID(), *accrual)
+		if err != nil {
+			return err
+		}
+		if err := tx.LedgerRepository().Post(ctx, posting); err != nil {
+			return err
+		}
+	}
+
+	if !updated {
+		slog.InfoContext(ctx, "order already finalized, skipping accrual",
+			"order_id", order.ID(),
+			"outbox_id", outbox.ID,
+		)
+	}
+
+	if err := uc.updateOutbox(ctx, tx.OutboxRepository(), outbox, order, updated); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// updateOutbox закрывает запись outbox для заказа в финальном статусе. Заказ в нефинальном
+// статусе ставится на повторный опрос, а слишком старый — передаётся на ручную проверку.
+func (uc *ProcessOrdersUseCase) updateOutbox(ctx context.Context, outboxRepo repository.OutboxRepository, outbox *model.Outbox, order *model.Order, updated bool) error {
+	if !updated || order.Status().IsFinal() {
+		return outboxRepo.UpdateStatus(ctx, outbox.ID, uc.config.WorkerID, model.OutboxStatusProcessed)
+	}
+
+	if age := uc.now().Sub(outbox.PollingSince); age >= uc.config.MaxOrderAge {
+		slog.WarnContext(ctx, "order did not reach final status in time, flagged for review",
+			"order_id", order.ID(),
+			"outbox_id", outbox.ID,
+			"status", order.Status(),
+			"age", age,
+		)
+		return outboxRepo.UpdateStatus(ctx, outbox.ID, uc.config.WorkerID, model.OutboxStatusReview)
+	}
+
+	return outboxRepo.ScheduleNextAttempt(ctx, outbox.ID, uc.config.WorkerID, uc.config.PollInterval)
 }
 
 func (uc *ProcessOrdersUseCase) StartWorker(ctx context.Context, interval time.Duration) {
@@ -9,7 +9,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
 )
 
 type MockAccrualService struct {
@@ -24,15 +26,31 @@ func (m *MockAccrualService) GetOrderInfo(ctx context.Context, orderNumber strin
 	return args.Get(0).(*model.AccrualResponse), args.Error(1)
 }
 
+var testProcessOrdersConfig = ProcessOrdersConfig{
+	PollInterval: time.Minute,
+	MaxOrderAge:  24 * time.Hour,
+	WorkerID:     "worker-1",
+	LeaseTTL:     5 * time.Minute,
+	Retry: RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  time.Second,
+		MaxDelay:   time.Minute,
+	},
+}
+
+func fixedRandom(n int64) int64 {
+	return n / 2
+}
+
 func TestProcessOrdersUseCase_ProcessPendingOrders(t *testing.T) {
 	now := time.Now()
-	accrual := 100.5
+	accrual := model.MustParsePoints("100.5")
 
 	tests := []struct {
 		name         string
 		setupOutbox  func(*MockOutboxRepository)
 		setupOrder   func(*MockOrderRepository)
-		setupBalance func(*MockBalanceRepository)
+		setupLedger  func(*MockLedgerRepository)
 		setupAccrual func(*MockAccrualService)
 		wantErr      bool
 	}{
@@ -40,18 +58,18 @@ func TestProcessOrdersUseCase_ProcessPendingOrders(t *testing.T) {
 			name: "successful process single order with PROCESSED status",
 			setupOutbox: func(m *MockOutboxRepository) {
 				outboxes := []*model.Outbox{
-					{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, CreatedAt: now, UpdatedAt: now},
+					{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now, CreatedAt: now, UpdatedAt: now},
 				}
-				m.On("FindPending", mock.Anything, 10).Return(outboxes, nil)
-				m.On("UpdateStatus", mock.Anything, int64(1), model.OutboxStatusProcessed).Return(nil)
+				m.On("ClaimBatch", mock.Anything, testProcessOrdersConfig.WorkerID, 10, testProcessOrdersConfig.LeaseTTL).Return(outboxes, nil)
+				m.On("UpdateStatus", mock.Anything, int64(1), testProcessOrdersConfig.WorkerID, model.OutboxStatusProcessed).Return(nil)
 			},
 			setupOrder: func(m *MockOrderRepository) {
 				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusNew, nil, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
-				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusProcessed, &accrual).Return(nil)
+				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusProcessed, &accrual, model.OrderStatusNote{AccrualStatus: "PROCESSED"}).Return(true, nil)
 			},
-			setupBalance: func(m *MockBalanceRepository) {
-				m.On("Accrue", mock.Anything, int64(1), accrual).Return(nil)
+			setupLedger: func(m *MockLedgerRepository) {
+				m.On("Post", mock.Anything, mock.MatchedBy(accrualPostingOf(1, 1, accrual))).Return(nil)
 			},
 			setupAccrual: func(m *MockAccrualService) {
 				resp := &model.AccrualResponse{
@@ -66,11 +84,11 @@ func TestProcessOrdersUseCase_ProcessPendingOrders(t *testing.T) {
 		{
 			name: "empty pending orders",
 			setupOutbox: func(m *MockOutboxRepository) {
-				m.On("FindPending", mock.Anything, 10).Return([]*model.Outbox{}, nil)
+				m.On("ClaimBatch", mock.Anything, testProcessOrdersConfig.WorkerID, 10, testProcessOrdersConfig.LeaseTTL).Return([]*model.Outbox{}, nil)
 			},
 			setupOrder: func(m *MockOrderRepository) {
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
 			},
@@ -79,103 +97,124 @@ func TestProcessOrdersUseCase_ProcessPendingOrders(t *testing.T) {
 		{
 			name: "outbox repository find error",
 			setupOutbox: func(m *MockOutboxRepository) {
-				m.On("FindPending", mock.Anything, 10).Return(nil, errors.New("database error"))
+				m.On("ClaimBatch", mock.Anything, testProcessOrdersConfig.WorkerID, 10, testProcessOrdersConfig.LeaseTTL).Return(nil, errors.New("database error"))
 			},
 			setupOrder: func(m *MockOrderRepository) {
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
 			},
 			wantErr: true,
 		},
 		{
-			name: "process order error with retries increment",
+			name: "process order error schedules retry with backoff",
 			setupOutbox: func(m *MockOutboxRepository) {
 				outboxes := []*model.Outbox{
 					{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 1, CreatedAt: now, UpdatedAt: now},
 				}
-				m.On("FindPending", mock.Anything, 10).Return(outboxes, nil)
-				m.On("IncrementRetries", mock.Anything, int64(1)).Return(nil)
+				m.On("ClaimBatch", mock.Anything, testProcessOrdersConfig.WorkerID, 10, testProcessOrdersConfig.LeaseTTL).Return(outboxes, nil)
+				m.On("ScheduleRetry", mock.Anything, int64(1), testProcessOrdersConfig.WorkerID, testProcessOrdersConfig.Retry.Backoff(1, fixedRandom), "order not found").Return(nil)
 			},
 			setupOrder: func(m *MockOrderRepository) {
 				m.On("FindByID", mock.Anything, int64(1)).Return(nil, errors.New("order not found"))
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
+			},
+			setupAccrual: func(m *MockAccrualService) {
+			},
+			wantErr: false,
+		},
+		{
+			name: "rate limited - retries are not consumed",
+			setupOutbox: func(m *MockOutboxRepository) {
+				outboxes := []*model.Outbox{
+					{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 3, CreatedAt: now, UpdatedAt: now},
+				}
+				m.On("ClaimBatch", mock.Anything, testProcessOrdersConfig.WorkerID, 10, testProcessOrdersConfig.LeaseTTL).Return(outboxes, nil)
+				m.On("Postpone", mock.Anything, int64(1), testProcessOrdersConfig.WorkerID, time.Minute).Return(nil)
+			},
+			setupOrder: func(m *MockOrderRepository) {
+				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusNew, nil, now)
+				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
+			},
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
+				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(nil, &service.RateLimitError{RetryAfter: time.Minute})
 			},
 			wantErr: false,
 		},
 		{
-			name: "process order error with max retries - mark as failed",
+			name: "process order error with exhausted retries - mark as dead",
 			setupOutbox: func(m *MockOutboxRepository) {
 				outboxes := []*model.Outbox{
 					{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 3, CreatedAt: now, UpdatedAt: now},
 				}
-				m.On("FindPending", mock.Anything, 10).Return(outboxes, nil)
-				m.On("UpdateStatus", mock.Anything, int64(1), model.OutboxStatusFailed).Return(nil)
+				m.On("ClaimBatch", mock.Anything, testProcessOrdersConfig.WorkerID, 10, testProcessOrdersConfig.LeaseTTL).Return(outboxes, nil)
+				m.On("MarkDead", mock.Anything, int64(1), testProcessOrdersConfig.WorkerID, "order not found").Return(nil)
 			},
 			setupOrder: func(m *MockOrderRepository) {
 				m.On("FindByID", mock.Anything, int64(1)).Return(nil, errors.New("order not found"))
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
 			},
 			wantErr: false,
 		},
 		{
-			name: "error updating outbox status to failed",
+			name: "error marking outbox as dead",
 			setupOutbox: func(m *MockOutboxRepository) {
 				outboxes := []*model.Outbox{
 					{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 3, CreatedAt: now, UpdatedAt: now},
 				}
-				m.On("FindPending", mock.Anything, 10).Return(outboxes, nil)
-				m.On("UpdateStatus", mock.Anything, int64(1), model.OutboxStatusFailed).Return(errors.New("update failed error"))
+				m.On("ClaimBatch", mock.Anything, testProcessOrdersConfig.WorkerID, 10, testProcessOrdersConfig.LeaseTTL).Return(outboxes, nil)
+				m.On("MarkDead", mock.Anything, int64(1), testProcessOrdersConfig.WorkerID, "order not found").Return(errors.New("mark dead error"))
 			},
 			setupOrder: func(m *MockOrderRepository) {
 				m.On("FindByID", mock.Anything, int64(1)).Return(nil, errors.New("order not found"))
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
 			},
 			wantErr: false,
 		},
 		{
-			name: "error incrementing retries",
+			name: "error scheduling retry",
 			setupOutbox: func(m *MockOutboxRepository) {
 				outboxes := []*model.Outbox{
 					{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 1, CreatedAt: now, UpdatedAt: now},
 				}
-				m.On("FindPending", mock.Anything, 10).Return(outboxes, nil)
-				m.On("IncrementRetries", mock.Anything, int64(1)).Return(errors.New("increment error"))
+				m.On("ClaimBatch", mock.Anything, testProcessOrdersConfig.WorkerID, 10, testProcessOrdersConfig.LeaseTTL).Return(outboxes, nil)
+				m.On("ScheduleRetry", mock.Anything, int64(1), testProcessOrdersConfig.WorkerID, mock.Anything, "order not found").Return(errors.New("schedule retry error"))
 			},
 			setupOrder: func(m *MockOrderRepository) {
 				m.On("FindByID", mock.Anything, int64(1)).Return(nil, errors.New("order not found"))
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
 			},
 			wantErr: false,
 		},
 		{
-			name: "error updating outbox status to processed",
+			name: "error scheduling next attempt",
 			setupOutbox: func(m *MockOutboxRepository) {
 				outboxes := []*model.Outbox{
-					{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, CreatedAt: now, UpdatedAt: now},
+					{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now, CreatedAt: now, UpdatedAt: now},
 				}
-				m.On("FindPending", mock.Anything, 10).Return(outboxes, nil)
-				m.On("UpdateStatus", mock.Anything, int64(1), model.OutboxStatusProcessed).Return(errors.New("update processed error"))
+				m.On("ClaimBatch", mock.Anything, testProcessOrdersConfig.WorkerID, 10, testProcessOrdersConfig.LeaseTTL).Return(outboxes, nil)
+				m.On("ScheduleNextAttempt", mock.Anything, int64(1), testProcessOrdersConfig.WorkerID, testProcessOrdersConfig.PollInterval).Return(errors.New("schedule error"))
+				m.On("ScheduleRetry", mock.Anything, int64(1), testProcessOrdersConfig.WorkerID, mock.Anything, "schedule error").Return(nil)
 			},
 			setupOrder: func(m *MockOrderRepository) {
 				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusNew, nil, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
-				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusNew, (*float64)(nil)).Return(nil)
+				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusNew, (*model.Points)(nil), model.OrderStatusNote{AccrualStatus: "REGISTERED"}).Return(true, nil)
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
 				resp := &model.AccrualResponse{
@@ -190,21 +229,21 @@ func TestProcessOrdersUseCase_ProcessPendingOrders(t *testing.T) {
 			name: "multiple orders - some succeed, some fail",
 			setupOutbox: func(m *MockOutboxRepository) {
 				outboxes := []*model.Outbox{
-					{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, CreatedAt: now, UpdatedAt: now},
-					{ID: 2, OrderID: 2, Status: model.OutboxStatusPending, Retries: 0, CreatedAt: now, UpdatedAt: now},
+					{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now, CreatedAt: now, UpdatedAt: now},
+					{ID: 2, OrderID: 2, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now, CreatedAt: now, UpdatedAt: now},
 				}
-				m.On("FindPending", mock.Anything, 10).Return(outboxes, nil)
-				m.On("UpdateStatus", mock.Anything, int64(1), model.OutboxStatusProcessed).Return(nil)
-				m.On("IncrementRetries", mock.Anything, int64(2)).Return(nil)
+				m.On("ClaimBatch", mock.Anything, testProcessOrdersConfig.WorkerID, 10, testProcessOrdersConfig.LeaseTTL).Return(outboxes, nil)
+				m.On("UpdateStatus", mock.Anything, int64(1), testProcessOrdersConfig.WorkerID, model.OutboxStatusProcessed).Return(nil)
+				m.On("ScheduleRetry", mock.Anything, int64(2), testProcessOrdersConfig.WorkerID, mock.Anything, "order not found").Return(nil)
 			},
 			setupOrder: func(m *MockOrderRepository) {
 				order1 := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusNew, nil, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order1, nil).Once()
-				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusProcessed, &accrual).Return(nil)
+				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusProcessed, &accrual, model.OrderStatusNote{AccrualStatus: "PROCESSED"}).Return(true, nil)
 				m.On("FindByID", mock.Anything, int64(2)).Return(nil, errors.New("order not found")).Once()
 			},
-			setupBalance: func(m *MockBalanceRepository) {
-				m.On("Accrue", mock.Anything, int64(1), accrual).Return(nil)
+			setupLedger: func(m *MockLedgerRepository) {
+				m.On("Post", mock.Anything, mock.MatchedBy(accrualPostingOf(1, 1, accrual))).Return(nil)
 			},
 			setupAccrual: func(m *MockAccrualService) {
 				resp := &model.AccrualResponse{
@@ -222,15 +261,19 @@ func TestProcessOrdersUseCase_ProcessPendingOrders(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockOutboxRepo := new(MockOutboxRepository)
 			mockOrderRepo := new(MockOrderRepository)
-			mockBalanceRepo := new(MockBalanceRepository)
+			mockLedgerRepo := new(MockLedgerRepository)
 			mockAccrualService := new(MockAccrualService)
 
 			tt.setupOutbox(mockOutboxRepo)
 			tt.setupOrder(mockOrderRepo)
-			tt.setupBalance(mockBalanceRepo)
+			tt.setupLedger(mockLedgerRepo)
 			tt.setupAccrual(mockAccrualService)
 
-			uc := NewProcessOrdersUseCase(mockOutboxRepo, mockOrderRepo, mockBalanceRepo, mockAccrualService)
+			mockUOW := newProcessOrdersUnitOfWork(mockOrderRepo, mockOutboxRepo, mockLedgerRepo)
+			mockUOW.tx.On("Commit", mock.Anything).Return(nil).Maybe()
+
+			uc := NewProcessOrdersUseCase(mockUOW, mockOutboxRepo, mockOrderRepo, mockAccrualService, testProcessOrdersConfig)
+			uc.random = fixedRandom
 			err := uc.ProcessPendingOrders(context.Background())
 
 			if tt.wantErr {
@@ -241,34 +284,76 @@ func TestProcessOrdersUseCase_ProcessPendingOrders(t *testing.T) {
 
 			mockOutboxRepo.AssertExpectations(t)
 			mockOrderRepo.AssertExpectations(t)
-			mockBalanceRepo.AssertExpectations(t)
+			mockLedgerRepo.AssertExpectations(t)
 			mockAccrualService.AssertExpectations(t)
 		})
 	}
 }
 
+func TestProcessOrdersUseCase_ProcessPendingOrders_LeaseLost(t *testing.T) {
+	now := time.Now()
+	accrual := model.MustParsePoints("100.5")
+
+	mockOutboxRepo := new(MockOutboxRepository)
+	mockOrderRepo := new(MockOrderRepository)
+	mockLedgerRepo := new(MockLedgerRepository)
+	mockAccrualService := new(MockAccrualService)
+
+	outboxes := []*model.Outbox{
+		{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now, CreatedAt: now, UpdatedAt: now},
+	}
+	mockOutboxRepo.On("ClaimBatch", mock.Anything, testProcessOrdersConfig.WorkerID, 10, testProcessOrdersConfig.LeaseTTL).Return(outboxes, nil)
+	mockOutboxRepo.On("UpdateStatus", mock.Anything, int64(1), testProcessOrdersConfig.WorkerID, model.OutboxStatusProcessed).
+		Return(domainerrors.ErrOutboxLeaseLost)
+
+	order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusNew, nil, now)
+	mockOrderRepo.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
+	mockOrderRepo.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusProcessed, &accrual, model.OrderStatusNote{AccrualStatus: "PROCESSED"}).Return(true, nil)
+	mockLedgerRepo.On("Post", mock.Anything, mock.MatchedBy(accrualPostingOf(1, 1, accrual))).Return(nil)
+	mockAccrualService.On("GetOrderInfo", mock.Anything, "79927398713").
+		Return(&model.AccrualResponse{Order: "79927398713", Status: "PROCESSED", Accrual: &accrual}, nil)
+
+	mockUOW := newProcessOrdersUnitOfWork(mockOrderRepo, mockOutboxRepo, mockLedgerRepo)
+
+	uc := NewProcessOrdersUseCase(mockUOW, mockOutboxRepo, mockOrderRepo, mockAccrualService, testProcessOrdersConfig)
+	err := uc.ProcessPendingOrders(context.Background())
+
+	assert.NoError(t, err)
+	mockUOW.tx.AssertCalled(t, "Rollback", mock.Anything)
+	mockUOW.tx.AssertNotCalled(t, "Commit", mock.Anything)
+	mockOutboxRepo.AssertNotCalled(t, "ScheduleRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockOutboxRepo.AssertNotCalled(t, "MarkDead", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockOutboxRepo.AssertExpectations(t)
+	mockLedgerRepo.AssertExpectations(t)
+}
+
 func TestProcessOrdersUseCase_processOrder(t *testing.T) {
 	now := time.Now()
-	accrual := 150.75
+	accrual := model.MustParsePoints("150.75")
 
 	tests := []struct {
-		name         string
-		outbox       *model.Outbox
-		setupOrder   func(*MockOrderRepository)
-		setupBalance func(*MockBalanceRepository)
-		setupAccrual func(*MockAccrualService)
-		wantErr      bool
-		errMsg       string
+		name          string
+		outbox        *model.Outbox
+		setupOrder    func(*MockOrderRepository)
+		setupLedger   func(*MockLedgerRepository)
+		setupAccrual  func(*MockAccrualService)
+		wantCompleted bool
+		wantScheduled bool
+		wantReview    bool
+		wantCommit    bool
+		commitErr     error
+		wantErr       bool
+		errMsg        string
 	}{
 		{
 			name:   "successful process REGISTERED status",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
 			setupOrder: func(m *MockOrderRepository) {
 				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusNew, nil, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
-				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusNew, (*float64)(nil)).Return(nil)
+				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusNew, (*model.Points)(nil), model.OrderStatusNote{AccrualStatus: "REGISTERED"}).Return(true, nil)
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
 				resp := &model.AccrualResponse{
@@ -277,17 +362,19 @@ func TestProcessOrdersUseCase_processOrder(t *testing.T) {
 				}
 				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(resp, nil)
 			},
-			wantErr: false,
+			wantScheduled: true,
+			wantCommit:    true,
+			wantErr:       false,
 		},
 		{
 			name:   "successful process PROCESSING status",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
 			setupOrder: func(m *MockOrderRepository) {
 				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusNew, nil, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
-				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusProcessing, (*float64)(nil)).Return(nil)
+				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusProcessing, (*model.Points)(nil), model.OrderStatusNote{AccrualStatus: "PROCESSING"}).Return(true, nil)
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
 				resp := &model.AccrualResponse{
@@ -296,17 +383,61 @@ func TestProcessOrdersUseCase_processOrder(t *testing.T) {
 				}
 				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(resp, nil)
 			},
-			wantErr: false,
+			wantScheduled: true,
+			wantCommit:    true,
+			wantErr:       false,
+		},
+		{
+			name:   "PROCESSING status past max age - flagged for review",
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusInFlight, Retries: 0, PollingSince: now.Add(-48 * time.Hour)},
+			setupOrder: func(m *MockOrderRepository) {
+				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusProcessing, nil, now.Add(-48*time.Hour))
+				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
+				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusProcessing, (*model.Points)(nil), model.OrderStatusNote{AccrualStatus: "PROCESSING"}).Return(true, nil)
+			},
+			setupLedger: func(m *MockLedgerRepository) {
+			},
+			setupAccrual: func(m *MockAccrualService) {
+				resp := &model.AccrualResponse{
+					Order:  "79927398713",
+					Status: "PROCESSING",
+				}
+				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(resp, nil)
+			},
+			wantReview: true,
+			wantCommit: true,
+			wantErr:    false,
+		},
+		{
+			name:   "requeued old order is polled again instead of returning to review",
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
+			setupOrder: func(m *MockOrderRepository) {
+				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusProcessing, nil, now.Add(-48*time.Hour))
+				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
+				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusProcessing, (*model.Points)(nil), model.OrderStatusNote{AccrualStatus: "PROCESSING"}).Return(true, nil)
+			},
+			setupLedger: func(m *MockLedgerRepository) {
+			},
+			setupAccrual: func(m *MockAccrualService) {
+				resp := &model.AccrualResponse{
+					Order:  "79927398713",
+					Status: "PROCESSING",
+				}
+				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(resp, nil)
+			},
+			wantScheduled: true,
+			wantCommit:    true,
+			wantErr:       false,
 		},
 		{
 			name:   "successful process INVALID status",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
 			setupOrder: func(m *MockOrderRepository) {
 				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusNew, nil, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
-				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusInvalid, (*float64)(nil)).Return(nil)
+				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusInvalid, (*model.Points)(nil), model.OrderStatusNote{AccrualStatus: "INVALID", Reason: orderRejectedReason}).Return(true, nil)
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
 				resp := &model.AccrualResponse{
@@ -315,18 +446,20 @@ func TestProcessOrdersUseCase_processOrder(t *testing.T) {
 				}
 				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(resp, nil)
 			},
-			wantErr: false,
+			wantCompleted: true,
+			wantCommit:    true,
+			wantErr:       false,
 		},
 		{
 			name:   "successful process PROCESSED status with accrual",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
 			setupOrder: func(m *MockOrderRepository) {
 				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusProcessing, nil, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
-				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusProcessed, &accrual).Return(nil)
+				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusProcessed, &accrual, model.OrderStatusNote{AccrualStatus: "PROCESSED"}).Return(true, nil)
 			},
-			setupBalance: func(m *MockBalanceRepository) {
-				m.On("Accrue", mock.Anything, int64(1), accrual).Return(nil)
+			setupLedger: func(m *MockLedgerRepository) {
+				m.On("Post", mock.Anything, mock.MatchedBy(accrualPostingOf(1, 1, accrual))).Return(nil)
 			},
 			setupAccrual: func(m *MockAccrualService) {
 				resp := &model.AccrualResponse{
@@ -336,17 +469,19 @@ func TestProcessOrdersUseCase_processOrder(t *testing.T) {
 				}
 				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(resp, nil)
 			},
-			wantErr: false,
+			wantCompleted: true,
+			wantCommit:    true,
+			wantErr:       false,
 		},
 		{
 			name:   "PROCESSED status without accrual",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
 			setupOrder: func(m *MockOrderRepository) {
 				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusProcessing, nil, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
-				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusProcessed, (*float64)(nil)).Return(nil)
+				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusProcessed, (*model.Points)(nil), model.OrderStatusNote{AccrualStatus: "PROCESSED"}).Return(true, nil)
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
 				resp := &model.AccrualResponse{
@@ -355,15 +490,86 @@ func TestProcessOrdersUseCase_processOrder(t *testing.T) {
 				}
 				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(resp, nil)
 			},
-			wantErr: false,
+			wantCompleted: true,
+			wantCommit:    true,
+			wantErr:       false,
+		},
+		{
+			name:   "order finalized concurrently - accrual is not credited twice",
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
+			setupOrder: func(m *MockOrderRepository) {
+				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusProcessing, nil, now)
+				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
+				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusProcessed, &accrual, model.OrderStatusNote{AccrualStatus: "PROCESSED"}).Return(false, nil)
+			},
+			setupLedger: func(m *MockLedgerRepository) {
+			},
+			setupAccrual: func(m *MockAccrualService) {
+				resp := &model.AccrualResponse{
+					Order:   "79927398713",
+					Status:  "PROCESSED",
+					Accrual: &accrual,
+				}
+				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(resp, nil)
+			},
+			wantCompleted: true,
+			wantCommit:    true,
+			wantErr:       false,
+		},
+		{
+			name:   "order already credited - transaction rolled back",
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
+			setupOrder: func(m *MockOrderRepository) {
+				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusProcessing, nil, now)
+				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
+				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusProcessed, &accrual, model.OrderStatusNote{AccrualStatus: "PROCESSED"}).Return(true, nil)
+			},
+			setupLedger: func(m *MockLedgerRepository) {
+				m.On("Post", mock.Anything, mock.Anything).Return(model.ErrLedgerOrderCredited)
+			},
+			setupAccrual: func(m *MockAccrualService) {
+				resp := &model.AccrualResponse{
+					Order:   "79927398713",
+					Status:  "PROCESSED",
+					Accrual: &accrual,
+				}
+				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(resp, nil)
+			},
+			wantErr: true,
+			errMsg:  "order already credited",
+		},
+		{
+			name:   "commit error",
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
+			setupOrder: func(m *MockOrderRepository) {
+				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusProcessing, nil, now)
+				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
+				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusProcessed, &accrual, model.OrderStatusNote{AccrualStatus: "PROCESSED"}).Return(true, nil)
+			},
+			setupLedger: func(m *MockLedgerRepository) {
+				m.On("Post", mock.Anything, mock.Anything).Return(nil)
+			},
+			setupAccrual: func(m *MockAccrualService) {
+				resp := &model.AccrualResponse{
+					Order:   "79927398713",
+					Status:  "PROCESSED",
+					Accrual: &accrual,
+				}
+				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(resp, nil)
+			},
+			wantCompleted: true,
+			wantCommit:    true,
+			commitErr:     errors.New("commit error"),
+			wantErr:       true,
+			errMsg:        "commit error",
 		},
 		{
 			name:   "order not found",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
 			setupOrder: func(m *MockOrderRepository) {
 				m.On("FindByID", mock.Anything, int64(1)).Return(nil, nil)
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
 			},
@@ -372,11 +578,11 @@ func TestProcessOrdersUseCase_processOrder(t *testing.T) {
 		},
 		{
 			name:   "order repository find error",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
 			setupOrder: func(m *MockOrderRepository) {
 				m.On("FindByID", mock.Anything, int64(1)).Return(nil, errors.New("database error"))
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
 			},
@@ -384,56 +590,59 @@ func TestProcessOrdersUseCase_processOrder(t *testing.T) {
 		},
 		{
 			name:   "order not found in accrual system",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
 			setupOrder: func(m *MockOrderRepository) {
 				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusNew, nil, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
-				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusInvalid, (*float64)(nil)).Return(nil)
+				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusInvalid, (*model.Points)(nil), model.OrderStatusNote{Reason: domainerrors.ErrAccrualOrderNotRegistered.Error()}).Return(true, nil)
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
-				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(nil, errors.New("order not found in accrual system"))
+				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(nil, domainerrors.ErrAccrualOrderNotRegistered)
 			},
-			wantErr: false,
+			wantCompleted: true,
+			wantCommit:    true,
+			wantErr:       false,
 		},
 		{
-			name:   "order not found in accrual system - order update status error",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
+			name:   "order not found in accrual system - order already final",
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
 			setupOrder: func(m *MockOrderRepository) {
 				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusProcessed, nil, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
-				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(nil, errors.New("order not found in accrual system"))
+				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(nil, domainerrors.ErrAccrualOrderNotRegistered)
 			},
-			wantErr: true,
+			wantCompleted: true,
+			wantErr:       false,
 		},
 		{
 			name:   "order not found in accrual system - repository update error",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
 			setupOrder: func(m *MockOrderRepository) {
 				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusNew, nil, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
-				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusInvalid, (*float64)(nil)).Return(errors.New("repository error"))
+				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusInvalid, (*model.Points)(nil), model.OrderStatusNote{Reason: domainerrors.ErrAccrualOrderNotRegistered.Error()}).Return(false, errors.New("repository error"))
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
-				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(nil, errors.New("order not found in accrual system"))
+				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(nil, domainerrors.ErrAccrualOrderNotRegistered)
 			},
 			wantErr: true,
 		},
 		{
 			name:   "rate limited error",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
 			setupOrder: func(m *MockOrderRepository) {
 				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusNew, nil, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
 				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(nil, errors.New("rate limited"))
@@ -443,12 +652,12 @@ func TestProcessOrdersUseCase_processOrder(t *testing.T) {
 		},
 		{
 			name:   "unknown accrual status",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
 			setupOrder: func(m *MockOrderRepository) {
 				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusNew, nil, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
 				resp := &model.AccrualResponse{
@@ -461,14 +670,15 @@ func TestProcessOrdersUseCase_processOrder(t *testing.T) {
 			errMsg:  "unknown accrual status",
 		},
 		{
-			name:   "balance accrue error",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
+			name:   "ledger post error",
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
 			setupOrder: func(m *MockOrderRepository) {
 				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusProcessing, nil, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
+				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusProcessed, &accrual, model.OrderStatusNote{AccrualStatus: "PROCESSED"}).Return(true, nil)
 			},
-			setupBalance: func(m *MockBalanceRepository) {
-				m.On("Accrue", mock.Anything, int64(1), accrual).Return(errors.New("balance error"))
+			setupLedger: func(m *MockLedgerRepository) {
+				m.On("Post", mock.Anything, mock.MatchedBy(accrualPostingOf(1, 1, accrual))).Return(errors.New("ledger error"))
 			},
 			setupAccrual: func(m *MockAccrualService) {
 				resp := &model.AccrualResponse{
@@ -481,33 +691,35 @@ func TestProcessOrdersUseCase_processOrder(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:   "order UpdateStatus error",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
+			name:   "negative accrual rejected by order",
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
 			setupOrder: func(m *MockOrderRepository) {
-				// Order with status PROCESSED cannot be updated to NEW
-				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusProcessed, &accrual, now)
+				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusProcessing, nil, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
+				negative := model.MustParsePoints("-1")
 				resp := &model.AccrualResponse{
-					Order:  "79927398713",
-					Status: "NEW",
+					Order:   "79927398713",
+					Status:  "PROCESSED",
+					Accrual: &negative,
 				}
 				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(resp, nil)
 			},
 			wantErr: true,
+			errMsg:  "accrual cannot be negative",
 		},
 		{
 			name:   "order repository UpdateStatus error",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
 			setupOrder: func(m *MockOrderRepository) {
 				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusNew, nil, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
-				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusNew, (*float64)(nil)).Return(errors.New("repository update error"))
+				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusNew, (*model.Points)(nil), model.OrderStatusNote{AccrualStatus: "REGISTERED"}).Return(false, errors.New("repository update error"))
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
 				resp := &model.AccrualResponse{
@@ -520,12 +732,12 @@ func TestProcessOrdersUseCase_processOrder(t *testing.T) {
 		},
 		{
 			name:   "accrual service generic error (not rate limited, not order not found)",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
 			setupOrder: func(m *MockOrderRepository) {
 				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusNew, nil, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
 				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(nil, errors.New("accrual service internal error"))
@@ -534,13 +746,13 @@ func TestProcessOrdersUseCase_processOrder(t *testing.T) {
 			errMsg:  "accrual service internal error",
 		},
 		{
-			name:   "order update status error",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
+			name:   "order already final - outbox completed without update",
+			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0, PollingSince: now},
 			setupOrder: func(m *MockOrderRepository) {
-				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusProcessed, nil, now)
+				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusProcessed, &accrual, now)
 				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
 			},
-			setupBalance: func(m *MockBalanceRepository) {
+			setupLedger: func(m *MockLedgerRepository) {
 			},
 			setupAccrual: func(m *MockAccrualService) {
 				resp := &model.AccrualResponse{
@@ -549,26 +761,8 @@ func TestProcessOrdersUseCase_processOrder(t *testing.T) {
 				}
 				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(resp, nil)
 			},
-			wantErr: true,
-		},
-		{
-			name:   "order repository update status error",
-			outbox: &model.Outbox{ID: 1, OrderID: 1, Status: model.OutboxStatusPending, Retries: 0},
-			setupOrder: func(m *MockOrderRepository) {
-				order := model.RestoreOrder(1, 1, "79927398713", model.OrderStatusNew, nil, now)
-				m.On("FindByID", mock.Anything, int64(1)).Return(order, nil)
-				m.On("UpdateStatus", mock.Anything, int64(1), model.OrderStatusNew, (*float64)(nil)).Return(errors.New("update error"))
-			},
-			setupBalance: func(m *MockBalanceRepository) {
-			},
-			setupAccrual: func(m *MockAccrualService) {
-				resp := &model.AccrualResponse{
-					Order:  "79927398713",
-					Status: "REGISTERED",
-				}
-				m.On("GetOrderInfo", mock.Anything, "79927398713").Return(resp, nil)
-			},
-			wantErr: true,
+			wantCompleted: true,
+			wantErr:       false,
 		},
 	}
 
@@ -576,14 +770,28 @@ func TestProcessOrdersUseCase_processOrder(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockOutboxRepo := new(MockOutboxRepository)
 			mockOrderRepo := new(MockOrderRepository)
-			mockBalanceRepo := new(MockBalanceRepository)
+			mockLedgerRepo := new(MockLedgerRepository)
 			mockAccrualService := new(MockAccrualService)
 
 			tt.setupOrder(mockOrderRepo)
-			tt.setupBalance(mockBalanceRepo)
+			tt.setupLedger(mockLedgerRepo)
 			tt.setupAccrual(mockAccrualService)
 
-			uc := NewProcessOrdersUseCase(mockOutboxRepo, mockOrderRepo, mockBalanceRepo, mockAccrualService)
+			mockUOW := newProcessOrdersUnitOfWork(mockOrderRepo, mockOutboxRepo, mockLedgerRepo)
+			if tt.wantCompleted {
+				mockOutboxRepo.On("UpdateStatus", mock.Anything, tt.outbox.ID, testProcessOrdersConfig.WorkerID, model.OutboxStatusProcessed).Return(nil).Once()
+			}
+			if tt.wantScheduled {
+				mockOutboxRepo.On("ScheduleNextAttempt", mock.Anything, tt.outbox.ID, testProcessOrdersConfig.WorkerID, testProcessOrdersConfig.PollInterval).Return(nil).Once()
+			}
+			if tt.wantReview {
+				mockOutboxRepo.On("UpdateStatus", mock.Anything, tt.outbox.ID, testProcessOrdersConfig.WorkerID, model.OutboxStatusReview).Return(nil).Once()
+			}
+			if tt.wantCommit {
+				mockUOW.tx.On("Commit", mock.Anything).Return(tt.commitErr).Once()
+			}
+
+			uc := NewProcessOrdersUseCase(mockUOW, mockOutboxRepo, mockOrderRepo, mockAccrualService, testProcessOrdersConfig)
 			err := uc.processOrder(context.Background(), tt.outbox)
 
 			if tt.wantErr {
@@ -595,9 +803,41 @@ func TestProcessOrdersUseCase_processOrder(t *testing.T) {
 				assert.NoError(t, err)
 			}
 
+			mockOutboxRepo.AssertExpectations(t)
 			mockOrderRepo.AssertExpectations(t)
-			mockBalanceRepo.AssertExpectations(t)
+			mockLedgerRepo.AssertExpectations(t)
 			mockAccrualService.AssertExpectations(t)
+			mockUOW.tx.AssertExpectations(t)
 		})
 	}
 }
+
+type processOrdersUnitOfWork struct {
+	*MockUnitOfWork
+	tx *MockTransaction
+}
+
+// newProcessOrdersUnitOfWork возвращает транзакцию, репозитории которой — те же моки,
+// что и у пула, чтобы ожидания задавались в одном месте.
+func newProcessOrdersUnitOfWork(orderRepo *MockOrderRepository, outboxRepo *MockOutboxRepository, ledgerRepo *MockLedgerRepository) *processOrdersUnitOfWork {
+	tx := &MockTransaction{
+		orderRepo:  orderRepo,
+		outboxRepo: outboxRepo,
+		ledgerRepo: ledgerRepo,
+	}
+	tx.On("Rollback", mock.Anything).Return(nil).Maybe()
+
+	uow := new(MockUnitOfWork)
+	uow.On("Begin", mock.Anything).Return(tx, nil).Maybe()
+
+	return &processOrdersUnitOfWork{MockUnitOfWork: uow, tx: tx}
+}
+
+func accrualPostingOf(userID, orderID int64, amount model.Points) func(*model.LedgerPosting) bool {
+	return func(p *model.LedgerPosting) bool {
+		return p.Kind() == model.LedgerEntryKindAccrual &&
+			p.UserID() == userID &&
+			p.OrderID() != nil && *p.OrderID() == orderID &&
+			p.CurrentDelta() == amount
+	}
+}
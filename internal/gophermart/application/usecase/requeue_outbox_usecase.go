@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+)
+
+type RequeueOutboxUseCase struct {
+	unitOfWork repository.UnitOfWork
+	outboxRepo repository.OutboxRepository
+}
+
+func NewRequeueOutboxUseCase(unitOfWork repository.UnitOfWork, outboxRepo repository.OutboxRepository) *RequeueOutboxUseCase {
+	return &RequeueOutboxUseCase{
+		unitOfWork: unitOfWork,
+		outboxRepo: outboxRepo,
+	}
+}
+
+// RequeueOutboxRequest задаёт записи для перезапуска: либо списком IDs, либо всеми записями в статусе Status.
+type RequeueOutboxRequest struct {
+	ActorUserID int64
+	IDs         []int64
+	Status      model.OutboxStatus
+}
+
+type RequeueOutboxResponse struct {
+	Requeued []int64 `json:"requeued"`
+}
+
+// Execute возвращает записи DEAD и REVIEW в очередь и в той же транзакции записывает
+// каждое действие в журнал outbox_audit.
+func (uc *RequeueOutboxUseCase) Execute(ctx context.Context, req RequeueOutboxRequest) (*RequeueOutboxResponse, error) {
+	if len(req.IDs) == 0 && req.Status == "" {
+		return nil, domainerrors.ErrNothingToRequeue
+	}
+	if req.Status != "" && !req.Status.IsRequeueable() {
+		return nil, fmt.Errorf("status %s: %w", req.Status, domainerrors.ErrOutboxNotRequeueable)
+	}
+
+	tx, err := uc.unitOfWork.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var requeued []*model.Outbox
+	if len(req.IDs) > 0 {
+		requeued, err = tx.OutboxRepository().Requeue(ctx, req.IDs)
+	} else {
+		requeued, err = tx.OutboxRepository().RequeueByStatus(ctx, req.Status)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.IDs) == 1 && len(requeued) == 0 {
+		return nil, uc.explainNotRequeued(ctx, req.IDs[0])
+	}
+
+	auditRepo := tx.OutboxAuditRepository()
+	ids := make([]int64, 0, len(requeued))
+	for _, outbox := range requeued {
+		err := auditRepo.Record(ctx, &model.OutboxAuditEntry{
+			OutboxID:        outbox.ID,
+			Action:          model.OutboxAuditActionRequeue,
+			ActorUserID:     req.ActorUserID,
+			PreviousStatus:  outbox.Status,
+			PreviousRetries: outbox.Retries,
+			PreviousError:   outbox.LastError,
+		})
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, outbox.ID)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &RequeueOutboxResponse{Requeued: ids}, nil
+}
+
+func (uc *RequeueOutboxUseCase) explainNotRequeued(ctx context.Context, id int64) error {
+	outbox, err := uc.outboxRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if outbox == nil {
+		return fmt.Errorf("outbox %d: %w", id, domainerrors.ErrOutboxNotFound)
+	}
+	return fmt.Errorf("outbox %d in status %s: %w", id, outbox.Status, domainerrors.ErrOutboxNotRequeueable)
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+)
+
+func newRequeueUnitOfWork(outboxRepo *MockOutboxRepository, auditRepo *MockOutboxAuditRepository) (*MockUnitOfWork, *MockTransaction) {
+	tx := &MockTransaction{outboxRepo: outboxRepo, auditRepo: auditRepo}
+	tx.On("Rollback", mock.Anything).Return(nil).Maybe()
+
+	uow := new(MockUnitOfWork)
+	uow.On("Begin", mock.Anything).Return(tx, nil).Maybe()
+	return uow, tx
+}
+
+func auditEntryOf(outboxID, actorID int64, previous model.OutboxStatus) func(*model.OutboxAuditEntry) bool {
+	return func(e *model.OutboxAuditEntry) bool {
+		return e.OutboxID == outboxID &&
+			e.ActorUserID == actorID &&
+			e.Action == model.OutboxAuditActionRequeue &&
+			e.PreviousStatus == previous
+	}
+}
+
+func TestRequeueOutboxUseCase_Execute(t *testing.T) {
+	t.Run("requeues listed rows and records audit", func(t *testing.T) {
+		outboxRepo := new(MockOutboxRepository)
+		auditRepo := new(MockOutboxAuditRepository)
+		uow, tx := newRequeueUnitOfWork(outboxRepo, auditRepo)
+
+		outboxRepo.On("Requeue", mock.Anything, []int64{1, 2}).Return([]*model.Outbox{
+			{ID: 1, Status: model.OutboxStatusDead, Retries: 10, LastError: "timeout"},
+			{ID: 2, Status: model.OutboxStatusReview},
+		}, nil)
+		auditRepo.On("Record", mock.Anything, mock.MatchedBy(auditEntryOf(1, 42, model.OutboxStatusDead))).Return(nil)
+		auditRepo.On("Record", mock.Anything, mock.MatchedBy(auditEntryOf(2, 42, model.OutboxStatusReview))).Return(nil)
+		tx.On("Commit", mock.Anything).Return(nil)
+
+		uc := NewRequeueOutboxUseCase(uow, outboxRepo)
+		resp, err := uc.Execute(context.Background(), RequeueOutboxRequest{ActorUserID: 42, IDs: []int64{1, 2}})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 2}, resp.Requeued)
+		outboxRepo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("requeues by status", func(t *testing.T) {
+		outboxRepo := new(MockOutboxRepository)
+		auditRepo := new(MockOutboxAuditRepository)
+		uow, tx := newRequeueUnitOfWork(outboxRepo, auditRepo)
+
+		outboxRepo.On("RequeueByStatus", mock.Anything, model.OutboxStatusDead).Return([]*model.Outbox{}, nil)
+		tx.On("Commit", mock.Anything).Return(nil)
+
+		uc := NewRequeueOutboxUseCase(uow, outboxRepo)
+		resp, err := uc.Execute(context.Background(), RequeueOutboxRequest{ActorUserID: 42, Status: model.OutboxStatusDead})
+
+		require.NoError(t, err)
+		assert.Empty(t, resp.Requeued)
+	})
+
+	t.Run("single row not found", func(t *testing.T) {
+		outboxRepo := new(MockOutboxRepository)
+		auditRepo := new(MockOutboxAuditRepository)
+		uow, _ := newRequeueUnitOfWork(outboxRepo, auditRepo)
+
+		outboxRepo.On("Requeue", mock.Anything, []int64{5}).Return([]*model.Outbox{}, nil)
+		outboxRepo.On("FindByID", mock.Anything, int64(5)).Return(nil, nil)
+
+		uc := NewRequeueOutboxUseCase(uow, outboxRepo)
+		_, err := uc.Execute(context.Background(), RequeueOutboxRequest{ActorUserID: 42, IDs: []int64{5}})
+
+		assert.ErrorIs(t, err, domainerrors.ErrOutboxNotFound)
+	})
+
+	t.Run("single row in non-requeueable status", func(t *testing.T) {
+		outboxRepo := new(MockOutboxRepository)
+		auditRepo := new(MockOutboxAuditRepository)
+		uow, _ := newRequeueUnitOfWork(outboxRepo, auditRepo)
+
+		outboxRepo.On("Requeue", mock.Anything, []int64{5}).Return([]*model.Outbox{}, nil)
+		outboxRepo.On("FindByID", mock.Anything, int64(5)).Return(&model.Outbox{ID: 5, Status: model.OutboxStatusPending}, nil)
+
+		uc := NewRequeueOutboxUseCase(uow, outboxRepo)
+		_, err := uc.Execute(context.Background(), RequeueOutboxRequest{ActorUserID: 42, IDs: []int64{5}})
+
+		assert.ErrorIs(t, err, domainerrors.ErrOutboxNotRequeueable)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		uc := NewRequeueOutboxUseCase(new(MockUnitOfWork), new(MockOutboxRepository))
+
+		_, err := uc.Execute(context.Background(), RequeueOutboxRequest{ActorUserID: 42})
+		assert.ErrorIs(t, err, domainerrors.ErrNothingToRequeue)
+
+		_, err = uc.Execute(context.Background(), RequeueOutboxRequest{ActorUserID: 42, Status: model.OutboxStatusProcessed})
+		assert.ErrorIs(t, err, domainerrors.ErrOutboxNotRequeueable)
+	})
+
+	t.Run("audit error rolls back", func(t *testing.T) {
+		outboxRepo := new(MockOutboxRepository)
+		auditRepo := new(MockOutboxAuditRepository)
+		uow, tx := newRequeueUnitOfWork(outboxRepo, auditRepo)
+
+		outboxRepo.On("Requeue", mock.Anything, []int64{1}).Return([]*model.Outbox{{ID: 1, Status: model.OutboxStatusDead}}, nil)
+		auditRepo.On("Record", mock.Anything, mock.Anything).Return(errors.New("audit error"))
+
+		uc := NewRequeueOutboxUseCase(uow, outboxRepo)
+		_, err := uc.Execute(context.Background(), RequeueOutboxRequest{ActorUserID: 42, IDs: []int64{1}})
+
+		assert.Error(t, err)
+		tx.AssertNotCalled(t, "Commit", mock.Anything)
+	})
+}
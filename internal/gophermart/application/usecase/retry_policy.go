@@ -0,0 +1,29 @@
+package usecase
+
+import "time"
+
+// RetryPolicy задаёт число повторных попыток обработки записи outbox и паузы между ними.
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// Backoff возвращает паузу перед следующей попыткой после retries неудачных.
+// Пауза растёт экспоненциально от BaseDelay до MaxDelay; вторая её половина выбирается
+// случайно, чтобы записи, упавшие одновременно, не повторялись одной волной.
+func (p RetryPolicy) Backoff(retries int, random func(n int64) int64) time.Duration {
+	ceiling := p.BaseDelay
+	for i := 0; i < retries && ceiling < p.MaxDelay; i++ {
+		ceiling *= 2
+	}
+	if ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+
+	half := ceiling / 2
+	return half + time.Duration(random(int64(ceiling-half)+1))
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{
+		MaxRetries: 10,
+		BaseDelay:  time.Second,
+		MaxDelay:   time.Minute,
+	}
+	lowest := func(n int64) int64 { return 0 }
+	highest := func(n int64) int64 { return n - 1 }
+
+	tests := []struct {
+		retries int
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{0, 500 * time.Millisecond, time.Second},
+		{1, time.Second, 2 * time.Second},
+		{3, 4 * time.Second, 8 * time.Second},
+		{6, 30 * time.Second, time.Minute},
+		{100, 30 * time.Second, time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Backoff(tt.retries, lowest); got != tt.wantMin {
+			t.Errorf("Backoff(%d) min = %v, want %v", tt.retries, got, tt.wantMin)
+		}
+		if got := policy.Backoff(tt.retries, highest); got != tt.wantMax {
+			t.Errorf("Backoff(%d) max = %v, want %v", tt.retries, got, tt.wantMax)
+		}
+	}
+}
+
+func TestRetryPolicy_BackoffZeroDelay(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 1}
+
+	if got := policy.Backoff(3, func(n int64) int64 { return 0 }); got != 0 {
+		t.Errorf("Backoff() = %v, want 0", got)
+	}
+}
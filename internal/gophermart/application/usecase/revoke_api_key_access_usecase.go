@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+)
+
+type RevokeAPIKeyAccessUseCase struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+func NewRevokeAPIKeyAccessUseCase(apiKeyRepo repository.APIKeyRepository) *RevokeAPIKeyAccessUseCase {
+	return &RevokeAPIKeyAccessUseCase{apiKeyRepo: apiKeyRepo}
+}
+
+type RevokeAPIKeyAccessRequest struct {
+	UserID int64
+	KeyID  int64
+}
+
+// Execute отзывает согласие пользователя; следующий запрос ключа от его имени будет отклонён.
+func (uc *RevokeAPIKeyAccessUseCase) Execute(ctx context.Context, req RevokeAPIKeyAccessRequest) error {
+	revoked, err := uc.apiKeyRepo.RevokeGrant(ctx, req.KeyID, req.UserID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return fmt.Errorf("api key %d: %w", req.KeyID, domainerrors.ErrAPIKeyGrantNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+)
+
+func TestRevokeAPIKeyAccessUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name    string
+		revoked bool
+		wantErr error
+	}{
+		{name: "revokes granted access", revoked: true},
+		{name: "access was not granted", revoked: false, wantErr: domainerrors.ErrAPIKeyGrantNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockAPIKeyRepository)
+			repo.On("RevokeGrant", mock.Anything, int64(3), int64(42)).Return(tt.revoked, nil)
+
+			err := NewRevokeAPIKeyAccessUseCase(repo).Execute(context.Background(), RevokeAPIKeyAccessRequest{UserID: 42, KeyID: 3})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+)
+
+type RevokeAPIKeyUseCase struct {
+	apiKeyRepo repository.APIKeyRepository
+	now        func() time.Time
+}
+
+func NewRevokeAPIKeyUseCase(apiKeyRepo repository.APIKeyRepository) *RevokeAPIKeyUseCase {
+	return &RevokeAPIKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+		now:        time.Now,
+	}
+}
+
+type RevokeAPIKeyRequest struct {
+	ID int64
+}
+
+// Execute отзывает ключ. Ключ, который уже отозван или не существует, даёт ErrAPIKeyNotFound.
+func (uc *RevokeAPIKeyUseCase) Execute(ctx context.Context, req RevokeAPIKeyRequest) error {
+	revoked, err := uc.apiKeyRepo.Revoke(ctx, req.ID, uc.now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return fmt.Errorf("api key %d: %w", req.ID, domainerrors.ErrAPIKeyNotFound)
+	}
+	return nil
+}
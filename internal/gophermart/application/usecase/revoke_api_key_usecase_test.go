@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+)
+
+func TestRevokeAPIKeyUseCase_Execute(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		revoked bool
+		wantErr error
+	}{
+		{name: "revokes active key", revoked: true},
+		{name: "unknown or already revoked key", revoked: false, wantErr: domainerrors.ErrAPIKeyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockAPIKeyRepository)
+			repo.On("Revoke", mock.Anything, int64(3), now).Return(tt.revoked, nil)
+			uc := NewRevokeAPIKeyUseCase(repo)
+			uc.now = func() time.Time { return now }
+
+			err := uc.Execute(context.Background(), RevokeAPIKeyRequest{ID: 3})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
@@ -2,9 +2,10 @@ package usecase
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"time"
 
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
@@ -14,6 +15,7 @@ type UploadOrderUseCase struct {
 	unitOfWork     repository.UnitOfWork
 	orderRepo      repository.OrderRepository
 	outboxRepo     repository.OutboxRepository
+	accounts       service.AccountDirectory
 	orderValidator service.OrderNumberValidator
 }
 
@@ -21,12 +23,14 @@ func NewUploadOrderUseCase(
 	unitOfWork repository.UnitOfWork,
 	orderRepo repository.OrderRepository,
 	outboxRepo repository.OutboxRepository,
+	accounts service.AccountDirectory,
 	orderValidator service.OrderNumberValidator,
 ) *UploadOrderUseCase {
 	return &UploadOrderUseCase{
 		unitOfWork:     unitOfWork,
 		orderRepo:      orderRepo,
 		outboxRepo:     outboxRepo,
+		accounts:       accounts,
 		orderValidator: orderValidator,
 	}
 }
@@ -42,7 +46,7 @@ type UploadOrderResponse struct {
 
 func (uc *UploadOrderUseCase) Execute(ctx context.Context, req UploadOrderRequest) (*UploadOrderResponse, error) {
 	if !uc.orderValidator.Validate(req.Number) {
-		return nil, errors.New("invalid order number format")
+		return nil, fmt.Errorf("upload order %q: %w", req.Number, domainerrors.ErrInvalidOrderNumber)
 	}
 
 	existingOrder, err := uc.orderRepo.FindByNumber(ctx, req.Number)
@@ -54,7 +58,7 @@ func (uc *UploadOrderUseCase) Execute(ctx context.Context, req UploadOrderReques
 		if existingOrder.CanBeUploadedBy(req.UserID) {
 			return &UploadOrderResponse{Status: "already_uploaded"}, nil
 		}
-		return nil, errors.New("order number already exists for another user")
+		return nil, fmt.Errorf("upload order %q: %w", req.Number, domainerrors.ErrOrderOwnedByAnotherUser)
 	}
 
 	tx, err := uc.unitOfWork.Begin(ctx)
@@ -68,17 +72,28 @@ func (uc *UploadOrderUseCase) Execute(ctx context.Context, req UploadOrderReques
 		return nil, err
 	}
 
+	// Под блокировкой пользователя удаление учётной записи либо уже завершено и заказ
+	// отклоняется, либо дождётся этой транзакции и увидит новый заказ.
 	orderRepo := tx.OrderRepository()
+	if err := orderRepo.LockUser(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	if _, err := uc.accounts.GetProfile(ctx, req.UserID); err != nil {
+		return nil, fmt.Errorf("upload order %q: %w", req.Number, err)
+	}
+
 	if err := orderRepo.Create(ctx, order); err != nil {
 		return nil, err
 	}
 
+	now := time.Now()
 	outbox := &model.Outbox{
-		OrderID:   order.ID(),
-		Status:    model.OutboxStatusPending,
-		Retries:   0,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		OrderID:      order.ID(),
+		Status:       model.OutboxStatusPending,
+		Retries:      0,
+		PollingSince: now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
 	}
 
 	outboxRepo := tx.OutboxRepository()
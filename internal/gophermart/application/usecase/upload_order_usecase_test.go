@@ -9,7 +9,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
 )
 
 func TestUploadOrderUseCase_Execute(t *testing.T) {
@@ -19,6 +21,7 @@ func TestUploadOrderUseCase_Execute(t *testing.T) {
 		setupValidator func(*MockOrderNumberValidator)
 		setupOrderRepo func(*MockOrderRepository)
 		setupUOW       func(*MockUnitOfWork, *MockTransaction, *MockOrderRepository, *MockOutboxRepository)
+		accountErr     error
 		wantStatus     string
 		wantErr        bool
 		errMsg         string
@@ -201,6 +204,27 @@ func TestUploadOrderUseCase_Execute(t *testing.T) {
 			wantStatus: "",
 			wantErr:    true,
 		},
+		{
+			name: "deleted user is rejected",
+			req: UploadOrderRequest{
+				UserID: 1,
+				Number: "79927398713",
+			},
+			setupValidator: func(m *MockOrderNumberValidator) {
+				m.On("Validate", "79927398713").Return(true)
+			},
+			setupOrderRepo: func(m *MockOrderRepository) {
+				m.On("FindByNumber", mock.Anything, "79927398713").Return(nil, nil)
+			},
+			setupUOW: func(uow *MockUnitOfWork, tx *MockTransaction, orderRepo *MockOrderRepository, outboxRepo *MockOutboxRepository) {
+				orderRepo.On("LockUser", mock.Anything, int64(1)).Return(nil).Once()
+				tx.On("Rollback", mock.Anything).Return(nil)
+				uow.On("Begin", mock.Anything).Return(tx, nil)
+			},
+			accountErr: domainerrors.ErrAccountNotFound,
+			wantErr:    true,
+			errMsg:     "account not found",
+		},
 		{
 			name: "NewOrder validation error - invalid user ID",
 			req: UploadOrderRequest{
@@ -238,8 +262,16 @@ func TestUploadOrderUseCase_Execute(t *testing.T) {
 			tt.setupValidator(mockValidator)
 			tt.setupOrderRepo(mockOrderRepo)
 			tt.setupUOW(mockUOW, mockTx, mockTxOrderRepo, mockOutboxRepo)
+			mockTxOrderRepo.On("LockUser", mock.Anything, tt.req.UserID).Return(nil).Maybe()
 
-			uc := NewUploadOrderUseCase(mockUOW, mockOrderRepo, nil, mockValidator)
+			mockAccounts := new(MockAccountDirectory)
+			if tt.accountErr != nil {
+				mockAccounts.On("GetProfile", mock.Anything, tt.req.UserID).Return(nil, tt.accountErr)
+			} else {
+				mockAccounts.On("GetProfile", mock.Anything, tt.req.UserID).Return(&service.AccountProfile{}, nil).Maybe()
+			}
+
+			uc := NewUploadOrderUseCase(mockUOW, mockOrderRepo, nil, mockAccounts, mockValidator)
 			resp, err := uc.Execute(context.Background(), tt.req)
 
 			if tt.wantErr {
@@ -256,6 +288,10 @@ func TestUploadOrderUseCase_Execute(t *testing.T) {
 
 			mockValidator.AssertExpectations(t)
 			mockOrderRepo.AssertExpectations(t)
+			mockAccounts.AssertExpectations(t)
+			if tt.accountErr != nil {
+				mockTxOrderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			}
 			if !tt.wantErr {
 				mockUOW.AssertExpectations(t)
 			}
@@ -2,18 +2,27 @@ package usecase
 
 import (
 	"context"
-	"errors"
+	"encoding/json"
+	"fmt"
+	"time"
 
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
 )
 
+const (
+	withdrawOperation       = "withdraw"
+	maxIdempotencyKeyLength = 255
+)
+
 type WithdrawUseCase struct {
 	unitOfWork     repository.UnitOfWork
 	balanceRepo    repository.BalanceRepository
 	withdrawalRepo repository.WithdrawalRepository
 	orderValidator service.OrderNumberValidator
+	now            func() time.Time
 }
 
 func NewWithdrawUseCase(
@@ -27,38 +36,75 @@ func NewWithdrawUseCase(
 		balanceRepo:    balanceRepo,
 		withdrawalRepo: withdrawalRepo,
 		orderValidator: orderValidator,
+		now:            time.Now,
 	}
 }
 
+// WithdrawRequest — списание баллов в счёт заказа. IdempotencyKey необязателен: запрос
+// с уже использованным ключом не списывает баллы повторно.
 type WithdrawRequest struct {
-	UserID int64
-	Order  string
-	Sum    float64
+	UserID         int64
+	Order          string
+	Sum            model.Points
+	IdempotencyKey string
 }
 
 type WithdrawResponse struct {
-	Success bool
+	Success bool `json:"success"`
+	// Replayed — ответ взят из сохранённого результата первого запроса с тем же ключом.
+	Replayed bool `json:"-"`
 }
 
+// Execute списывает баллы. С IdempotencyKey ключ занимается в той же транзакции, что и списание:
+// повтор с тем же содержимым получает сохранённый ответ, с другим — ErrIdempotencyKeyReused.
+// Неудачное списание откатывает и ключ, поэтому его можно повторить, например, после пополнения баланса.
 func (uc *WithdrawUseCase) Execute(ctx context.Context, req WithdrawRequest) (*WithdrawResponse, error) {
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLength {
+		return nil, domainerrors.ErrInvalidIdempotencyKey
+	}
 	if !uc.orderValidator.Validate(req.Order) {
-		return nil, errors.New("invalid order number format")
+		return nil, fmt.Errorf("withdraw for order %q: %w", req.Order, domainerrors.ErrInvalidOrderNumber)
 	}
 
-	tx, err := uc.unitOfWork.Begin(ctx)
+	var response *WithdrawResponse
+	err := uc.unitOfWork.Do(ctx, func(tx repository.Transaction) error {
+		var err error
+		response, err = uc.withdraw(ctx, tx, req)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer tx.Rollback(ctx)
+	return response, nil
+}
 
-	balanceRepo := tx.BalanceRepository()
-	balance, err := balanceRepo.GetByUserID(ctx, req.UserID)
+// withdraw проверяет остаток по заблокированной строке баланса: параллельное списание
+// того же пользователя ждёт конца транзакции и видит уже уменьшенный баланс.
+func (uc *WithdrawUseCase) withdraw(ctx context.Context, tx repository.Transaction, req WithdrawRequest) (*WithdrawResponse, error) {
+	requestHash := model.IdempotencyFingerprint(withdrawOperation, req.Order, req.Sum.String())
+	if req.IdempotencyKey != "" {
+		stored, err := tx.IdempotencyRepository().Reserve(ctx, &model.IdempotencyRecord{
+			UserID:      req.UserID,
+			Key:         req.IdempotencyKey,
+			Operation:   withdrawOperation,
+			RequestHash: requestHash,
+			CreatedAt:   uc.now(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if stored != nil {
+			return replayWithdraw(stored, requestHash)
+		}
+	}
+
+	balance, err := tx.BalanceRepository().GetByUserIDForUpdate(ctx, req.UserID)
 	if err != nil {
 		return nil, err
 	}
 
 	if !balance.CanWithdraw(req.Sum) {
-		return nil, errors.New("insufficient funds")
+		return nil, fmt.Errorf("withdraw %s from user %d: %w", req.Sum, req.UserID, domainerrors.ErrInsufficientFunds)
 	}
 
 	withdrawal, err := model.NewWithdrawal(req.UserID, req.Order, req.Sum)
@@ -66,18 +112,42 @@ func (uc *WithdrawUseCase) Execute(ctx context.Context, req WithdrawRequest) (*W
 		return nil, err
 	}
 
-	withdrawalRepo := tx.WithdrawalRepository()
-	if err := withdrawalRepo.Create(ctx, withdrawal); err != nil {
+	if err := tx.WithdrawalRepository().Create(ctx, withdrawal); err != nil {
 		return nil, err
 	}
 
-	if err := balanceRepo.Withdraw(ctx, req.UserID, req.Sum); err != nil {
+	posting, err := model.NewWithdrawalPosting(req.UserID, withdrawal.ID(), withdrawal.Sum())
+	if err != nil {
 		return nil, err
 	}
 
-	if err := tx.Commit(ctx); err != nil {
+	if err := tx.LedgerRepository().Post(ctx, posting); err != nil {
 		return nil, err
 	}
 
-	return &WithdrawResponse{Success: true}, nil
+	response := &WithdrawResponse{Success: true}
+	if req.IdempotencyKey != "" {
+		data, err := json.Marshal(response)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.IdempotencyRepository().SaveResponse(ctx, req.UserID, req.IdempotencyKey, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+func replayWithdraw(stored *model.IdempotencyRecord, requestHash string) (*WithdrawResponse, error) {
+	if !stored.Matches(withdrawOperation, requestHash) {
+		return nil, fmt.Errorf("idempotency key %q: %w", stored.Key, domainerrors.ErrIdempotencyKeyReused)
+	}
+
+	var response WithdrawResponse
+	if err := json.Unmarshal(stored.Response, &response); err != nil {
+		return nil, fmt.Errorf("decode stored response for idempotency key %q: %w", stored.Key, err)
+	}
+	response.Replayed = true
+	return &response, nil
 }
@@ -3,11 +3,15 @@ package usecase
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 )
 
@@ -26,17 +30,17 @@ func TestWithdrawUseCase_Execute(t *testing.T) {
 			req: WithdrawRequest{
 				UserID: 1,
 				Order:  "79927398713",
-				Sum:    50.0,
+				Sum:    model.MustParsePoints("50"),
 			},
 			setupValidator: func(m *MockOrderNumberValidator) {
 				m.On("Validate", "79927398713").Return(true)
 			},
 			setupUOW: func(uow *MockUnitOfWork, tx *MockTransaction, balanceRepo *MockBalanceRepository, withdrawalRepo *MockWithdrawalRepository) {
-				balance := model.RestoreBalance(1, 100.0, 0.0)
-				balanceRepo.On("GetByUserID", mock.Anything, int64(1)).Return(balance, nil)
-				balanceRepo.On("Withdraw", mock.Anything, int64(1), 50.0).Return(nil)
+				balance := model.RestoreBalance(1, model.MustParsePoints("100"), 0)
+				balanceRepo.On("GetByUserIDForUpdate", mock.Anything, int64(1)).Return(balance, nil)
+				tx.ledgerRepo.On("Post", mock.Anything, mock.MatchedBy(withdrawalPostingOf(1, model.MustParsePoints("50")))).Return(nil)
 				withdrawalRepo.On("Create", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
-					return w.UserID() == 1 && w.OrderNumber() == "79927398713" && w.Sum() == 50.0
+					return w.UserID() == 1 && w.OrderNumber() == "79927398713" && w.Sum() == model.MustParsePoints("50")
 				})).Return(nil)
 				tx.On("Commit", mock.Anything).Return(nil)
 				tx.On("Rollback", mock.Anything).Return(nil)
@@ -50,7 +54,7 @@ func TestWithdrawUseCase_Execute(t *testing.T) {
 			req: WithdrawRequest{
 				UserID: 1,
 				Order:  "invalid",
-				Sum:    50.0,
+				Sum:    model.MustParsePoints("50"),
 			},
 			setupValidator: func(m *MockOrderNumberValidator) {
 				m.On("Validate", "invalid").Return(false)
@@ -66,14 +70,14 @@ func TestWithdrawUseCase_Execute(t *testing.T) {
 			req: WithdrawRequest{
 				UserID: 1,
 				Order:  "79927398713",
-				Sum:    150.0,
+				Sum:    model.MustParsePoints("150"),
 			},
 			setupValidator: func(m *MockOrderNumberValidator) {
 				m.On("Validate", "79927398713").Return(true)
 			},
 			setupUOW: func(uow *MockUnitOfWork, tx *MockTransaction, balanceRepo *MockBalanceRepository, withdrawalRepo *MockWithdrawalRepository) {
-				balance := model.RestoreBalance(1, 100.0, 0.0)
-				balanceRepo.On("GetByUserID", mock.Anything, int64(1)).Return(balance, nil)
+				balance := model.RestoreBalance(1, model.MustParsePoints("100"), 0)
+				balanceRepo.On("GetByUserIDForUpdate", mock.Anything, int64(1)).Return(balance, nil)
 				tx.On("Rollback", mock.Anything).Return(nil)
 				uow.On("Begin", mock.Anything).Return(tx, nil)
 			},
@@ -86,7 +90,7 @@ func TestWithdrawUseCase_Execute(t *testing.T) {
 			req: WithdrawRequest{
 				UserID: 1,
 				Order:  "79927398713",
-				Sum:    50.0,
+				Sum:    model.MustParsePoints("50"),
 			},
 			setupValidator: func(m *MockOrderNumberValidator) {
 				m.On("Validate", "79927398713").Return(true)
@@ -102,13 +106,13 @@ func TestWithdrawUseCase_Execute(t *testing.T) {
 			req: WithdrawRequest{
 				UserID: 1,
 				Order:  "79927398713",
-				Sum:    50.0,
+				Sum:    model.MustParsePoints("50"),
 			},
 			setupValidator: func(m *MockOrderNumberValidator) {
 				m.On("Validate", "79927398713").Return(true)
 			},
 			setupUOW: func(uow *MockUnitOfWork, tx *MockTransaction, balanceRepo *MockBalanceRepository, withdrawalRepo *MockWithdrawalRepository) {
-				balanceRepo.On("GetByUserID", mock.Anything, int64(1)).Return(nil, errors.New("database error"))
+				balanceRepo.On("GetByUserIDForUpdate", mock.Anything, int64(1)).Return(nil, errors.New("database error"))
 				tx.On("Rollback", mock.Anything).Return(nil)
 				uow.On("Begin", mock.Anything).Return(tx, nil)
 			},
@@ -120,14 +124,14 @@ func TestWithdrawUseCase_Execute(t *testing.T) {
 			req: WithdrawRequest{
 				UserID: 1,
 				Order:  "79927398713",
-				Sum:    50.0,
+				Sum:    model.MustParsePoints("50"),
 			},
 			setupValidator: func(m *MockOrderNumberValidator) {
 				m.On("Validate", "79927398713").Return(true)
 			},
 			setupUOW: func(uow *MockUnitOfWork, tx *MockTransaction, balanceRepo *MockBalanceRepository, withdrawalRepo *MockWithdrawalRepository) {
-				balance := model.RestoreBalance(1, 100.0, 0.0)
-				balanceRepo.On("GetByUserID", mock.Anything, int64(1)).Return(balance, nil)
+				balance := model.RestoreBalance(1, model.MustParsePoints("100"), 0)
+				balanceRepo.On("GetByUserIDForUpdate", mock.Anything, int64(1)).Return(balance, nil)
 				withdrawalRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("database error"))
 				tx.On("Rollback", mock.Anything).Return(nil)
 				uow.On("Begin", mock.Anything).Return(tx, nil)
@@ -140,15 +144,15 @@ func TestWithdrawUseCase_Execute(t *testing.T) {
 			req: WithdrawRequest{
 				UserID: 1,
 				Order:  "79927398713",
-				Sum:    50.0,
+				Sum:    model.MustParsePoints("50"),
 			},
 			setupValidator: func(m *MockOrderNumberValidator) {
 				m.On("Validate", "79927398713").Return(true)
 			},
 			setupUOW: func(uow *MockUnitOfWork, tx *MockTransaction, balanceRepo *MockBalanceRepository, withdrawalRepo *MockWithdrawalRepository) {
-				balance := model.RestoreBalance(1, 100.0, 0.0)
-				balanceRepo.On("GetByUserID", mock.Anything, int64(1)).Return(balance, nil)
-				balanceRepo.On("Withdraw", mock.Anything, int64(1), 50.0).Return(errors.New("database error"))
+				balance := model.RestoreBalance(1, model.MustParsePoints("100"), 0)
+				balanceRepo.On("GetByUserIDForUpdate", mock.Anything, int64(1)).Return(balance, nil)
+				tx.ledgerRepo.On("Post", mock.Anything, mock.MatchedBy(withdrawalPostingOf(1, model.MustParsePoints("50")))).Return(errors.New("database error"))
 				withdrawalRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
 				tx.On("Rollback", mock.Anything).Return(nil)
 				uow.On("Begin", mock.Anything).Return(tx, nil)
@@ -161,15 +165,15 @@ func TestWithdrawUseCase_Execute(t *testing.T) {
 			req: WithdrawRequest{
 				UserID: 1,
 				Order:  "79927398713",
-				Sum:    50.0,
+				Sum:    model.MustParsePoints("50"),
 			},
 			setupValidator: func(m *MockOrderNumberValidator) {
 				m.On("Validate", "79927398713").Return(true)
 			},
 			setupUOW: func(uow *MockUnitOfWork, tx *MockTransaction, balanceRepo *MockBalanceRepository, withdrawalRepo *MockWithdrawalRepository) {
-				balance := model.RestoreBalance(1, 100.0, 0.0)
-				balanceRepo.On("GetByUserID", mock.Anything, int64(1)).Return(balance, nil)
-				balanceRepo.On("Withdraw", mock.Anything, int64(1), 50.0).Return(nil)
+				balance := model.RestoreBalance(1, model.MustParsePoints("100"), 0)
+				balanceRepo.On("GetByUserIDForUpdate", mock.Anything, int64(1)).Return(balance, nil)
+				tx.ledgerRepo.On("Post", mock.Anything, mock.MatchedBy(withdrawalPostingOf(1, model.MustParsePoints("50")))).Return(nil)
 				withdrawalRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
 				tx.On("Commit", mock.Anything).Return(errors.New("commit error"))
 				tx.On("Rollback", mock.Anything).Return(nil)
@@ -183,14 +187,14 @@ func TestWithdrawUseCase_Execute(t *testing.T) {
 			req: WithdrawRequest{
 				UserID: 0,
 				Order:  "12345678903",
-				Sum:    50.0,
+				Sum:    model.MustParsePoints("50"),
 			},
 			setupValidator: func(m *MockOrderNumberValidator) {
 				m.On("Validate", "12345678903").Return(true)
 			},
 			setupUOW: func(uow *MockUnitOfWork, tx *MockTransaction, balanceRepo *MockBalanceRepository, withdrawalRepo *MockWithdrawalRepository) {
-				balance := model.RestoreBalance(0, 100.0, 0.0)
-				balanceRepo.On("GetByUserID", mock.Anything, int64(0)).Return(balance, nil)
+				balance := model.RestoreBalance(0, model.MustParsePoints("100"), 0)
+				balanceRepo.On("GetByUserIDForUpdate", mock.Anything, int64(0)).Return(balance, nil)
 				tx.On("Rollback", mock.Anything).Return(nil)
 				uow.On("Begin", mock.Anything).Return(tx, nil)
 			},
@@ -202,14 +206,14 @@ func TestWithdrawUseCase_Execute(t *testing.T) {
 			req: WithdrawRequest{
 				UserID: 1,
 				Order:  "",
-				Sum:    50.0,
+				Sum:    model.MustParsePoints("50"),
 			},
 			setupValidator: func(m *MockOrderNumberValidator) {
 				m.On("Validate", "").Return(true) // Validator passes, but NewWithdrawal will fail
 			},
 			setupUOW: func(uow *MockUnitOfWork, tx *MockTransaction, balanceRepo *MockBalanceRepository, withdrawalRepo *MockWithdrawalRepository) {
-				balance := model.RestoreBalance(1, 100.0, 0.0)
-				balanceRepo.On("GetByUserID", mock.Anything, int64(1)).Return(balance, nil)
+				balance := model.RestoreBalance(1, model.MustParsePoints("100"), 0)
+				balanceRepo.On("GetByUserIDForUpdate", mock.Anything, int64(1)).Return(balance, nil)
 				tx.On("Rollback", mock.Anything).Return(nil)
 				uow.On("Begin", mock.Anything).Return(tx, nil)
 			},
@@ -221,14 +225,14 @@ func TestWithdrawUseCase_Execute(t *testing.T) {
 			req: WithdrawRequest{
 				UserID: 1,
 				Order:  "12345678903",
-				Sum:    0.0,
+				Sum:    0,
 			},
 			setupValidator: func(m *MockOrderNumberValidator) {
 				m.On("Validate", "12345678903").Return(true)
 			},
 			setupUOW: func(uow *MockUnitOfWork, tx *MockTransaction, balanceRepo *MockBalanceRepository, withdrawalRepo *MockWithdrawalRepository) {
-				balance := model.RestoreBalance(1, 100.0, 0.0)
-				balanceRepo.On("GetByUserID", mock.Anything, int64(1)).Return(balance, nil)
+				balance := model.RestoreBalance(1, model.MustParsePoints("100"), 0)
+				balanceRepo.On("GetByUserIDForUpdate", mock.Anything, int64(1)).Return(balance, nil)
 				tx.On("Rollback", mock.Anything).Return(nil)
 				uow.On("Begin", mock.Anything).Return(tx, nil)
 			},
@@ -244,9 +248,11 @@ func TestWithdrawUseCase_Execute(t *testing.T) {
 			mockTx := new(MockTransaction)
 			mockBalanceRepo := new(MockBalanceRepository)
 			mockWithdrawalRepo := new(MockWithdrawalRepository)
+			mockLedgerRepo := new(MockLedgerRepository)
 
 			mockTx.balanceRepo = mockBalanceRepo
 			mockTx.withdrawalRepo = mockWithdrawalRepo
+			mockTx.ledgerRepo = mockLedgerRepo
 
 			tt.setupValidator(mockValidator)
 			tt.setupUOW(mockUOW, mockTx, mockBalanceRepo, mockWithdrawalRepo)
@@ -276,3 +282,127 @@ func TestWithdrawUseCase_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestWithdrawUseCase_Execute_Idempotency(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	req := WithdrawRequest{
+		UserID:         1,
+		Order:          "79927398713",
+		Sum:            model.MustParsePoints("50"),
+		IdempotencyKey: "retry-1",
+	}
+	requestHash := model.IdempotencyFingerprint(withdrawOperation, req.Order, req.Sum.String())
+
+	setup := func() (*WithdrawUseCase, *MockUnitOfWork, *MockTransaction) {
+		validator := new(MockOrderNumberValidator)
+		validator.On("Validate", req.Order).Return(true)
+		tx := &MockTransaction{
+			balanceRepo:    new(MockBalanceRepository),
+			withdrawalRepo: new(MockWithdrawalRepository),
+			ledgerRepo:     new(MockLedgerRepository),
+			idempotency:    new(MockIdempotencyRepository),
+		}
+		tx.On("Rollback", mock.Anything).Return(nil)
+		uow := new(MockUnitOfWork)
+		uow.On("Begin", mock.Anything).Return(tx, nil)
+
+		uc := NewWithdrawUseCase(uow, nil, nil, validator)
+		uc.now = func() time.Time { return now }
+		return uc, uow, tx
+	}
+
+	t.Run("first request reserves key and stores response", func(t *testing.T) {
+		uc, _, tx := setup()
+		tx.idempotency.On("Reserve", mock.Anything, &model.IdempotencyRecord{
+			UserID:      1,
+			Key:         "retry-1",
+			Operation:   withdrawOperation,
+			RequestHash: requestHash,
+			CreatedAt:   now,
+		}).Return(nil, nil)
+		tx.balanceRepo.On("GetByUserIDForUpdate", mock.Anything, int64(1)).Return(model.RestoreBalance(1, model.MustParsePoints("100"), 0), nil)
+		tx.withdrawalRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		tx.ledgerRepo.On("Post", mock.Anything, mock.MatchedBy(withdrawalPostingOf(1, req.Sum))).Return(nil)
+		tx.idempotency.On("SaveResponse", mock.Anything, int64(1), "retry-1", []byte(`{"success":true}`)).Return(nil)
+		tx.On("Commit", mock.Anything).Return(nil)
+
+		resp, err := uc.Execute(context.Background(), req)
+
+		require.NoError(t, err)
+		assert.True(t, resp.Success)
+		assert.False(t, resp.Replayed)
+		tx.AssertExpectations(t)
+		tx.idempotency.AssertExpectations(t)
+	})
+
+	t.Run("replay returns stored response without withdrawing", func(t *testing.T) {
+		uc, _, tx := setup()
+		tx.On("Commit", mock.Anything).Return(nil)
+		tx.idempotency.On("Reserve", mock.Anything, mock.Anything).Return(&model.IdempotencyRecord{
+			UserID:      1,
+			Key:         "retry-1",
+			Operation:   withdrawOperation,
+			RequestHash: requestHash,
+			Response:    []byte(`{"success":true}`),
+		}, nil)
+
+		resp, err := uc.Execute(context.Background(), req)
+
+		require.NoError(t, err)
+		assert.True(t, resp.Success)
+		assert.True(t, resp.Replayed)
+		tx.balanceRepo.AssertNotCalled(t, "GetByUserIDForUpdate", mock.Anything, mock.Anything)
+		tx.withdrawalRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("key reused for a different request", func(t *testing.T) {
+		uc, _, tx := setup()
+		tx.idempotency.On("Reserve", mock.Anything, mock.Anything).Return(&model.IdempotencyRecord{
+			UserID:      1,
+			Key:         "retry-1",
+			Operation:   withdrawOperation,
+			RequestHash: model.IdempotencyFingerprint(withdrawOperation, req.Order, "60.00"),
+			Response:    []byte(`{"success":true}`),
+		}, nil)
+
+		resp, err := uc.Execute(context.Background(), req)
+
+		assert.ErrorIs(t, err, domainerrors.ErrIdempotencyKeyReused)
+		assert.Nil(t, resp)
+		tx.withdrawalRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("failed withdrawal does not store response", func(t *testing.T) {
+		uc, _, tx := setup()
+		tx.idempotency.On("Reserve", mock.Anything, mock.Anything).Return(nil, nil)
+		tx.balanceRepo.On("GetByUserIDForUpdate", mock.Anything, int64(1)).Return(model.RestoreBalance(1, model.MustParsePoints("10"), 0), nil)
+
+		resp, err := uc.Execute(context.Background(), req)
+
+		assert.ErrorIs(t, err, domainerrors.ErrInsufficientFunds)
+		assert.Nil(t, resp)
+		tx.idempotency.AssertNotCalled(t, "SaveResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		tx.AssertCalled(t, "Rollback", mock.Anything)
+	})
+
+	t.Run("too long key", func(t *testing.T) {
+		uc, uow, _ := setup()
+		long := req
+		long.IdempotencyKey = strings.Repeat("k", maxIdempotencyKeyLength+1)
+
+		resp, err := uc.Execute(context.Background(), long)
+
+		assert.ErrorIs(t, err, domainerrors.ErrInvalidIdempotencyKey)
+		assert.Nil(t, resp)
+		uow.AssertNotCalled(t, "Begin", mock.Anything)
+	})
+}
+
+func withdrawalPostingOf(userID int64, sum model.Points) func(*model.LedgerPosting) bool {
+	return func(p *model.LedgerPosting) bool {
+		return p.Kind() == model.LedgerEntryKindWithdrawal &&
+			p.UserID() == userID &&
+			p.CurrentDelta() == sum.Neg() &&
+			p.WithdrawnDelta() == sum
+	}
+}
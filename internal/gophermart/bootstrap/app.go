@@ -9,12 +9,17 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	gophermartusecase "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/application/usecase"
+	gophermartrepository "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+	userserviceusecase "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/application/usecase"
+	userservicemodel "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
 )
 
 type App struct {
 	config          *Config
 	server          *http.Server
 	processOrdersUC *gophermartusecase.ProcessOrdersUseCase
+	grantRoleUC     *userserviceusecase.GrantRoleUseCase
+	balanceRepo     gophermartrepository.BalanceRepository
 	pool            *pgxpool.Pool
 	workerCtx       context.Context
 	workerCancel    context.CancelFunc
@@ -34,15 +39,21 @@ func (a *App) Initialize() error {
 	}
 
 	a.pool = infraResult.Pool
+	a.balanceRepo = infraResult.BalanceRepo
 
 	useCaseInitializer := NewUseCaseInitializer(a.config, infraResult)
 	useCaseResult := useCaseInitializer.Initialize()
 
-	handlerInitializer := NewHandlerInitializer(a.config, useCaseResult)
-	handlerResult := handlerInitializer.Initialize()
+	handlerInitializer := NewHandlerInitializer(a.config, infraResult, useCaseResult)
+	handlerResult, err := handlerInitializer.Initialize()
+	if err != nil {
+		a.pool.Close()
+		return err
+	}
 
 	a.server = handlerResult.Server
 	a.processOrdersUC = useCaseResult.ProcessOrdersUseCase
+	a.grantRoleUC = useCaseResult.GrantRoleUseCase
 
 	return nil
 }
@@ -61,6 +72,34 @@ func (a *App) Run() error {
 	return nil
 }
 
+// RebuildBalances пересчитывает проекцию balances по журналу проводок.
+func (a *App) RebuildBalances(ctx context.Context) error {
+	defer a.pool.Close()
+
+	count, err := a.balanceRepo.RebuildAll(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("rebuilt %d balances from ledger", count)
+	return nil
+}
+
+// PromoteAdmin выдаёт роль admin пользователю с указанным логином. Нужна для назначения
+// первого администратора, пока никто не может выдавать роли через API.
+func (a *App) PromoteAdmin(ctx context.Context, login string) error {
+	defer a.pool.Close()
+
+	err := a.grantRoleUC.Execute(ctx, userserviceusecase.GrantRoleRequest{
+		Login: login,
+		Role:  userservicemodel.RoleAdmin,
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("granted admin role to %q; it takes effect with the next issued token", login)
+	return nil
+}
+
 func (a *App) Shutdown(ctx context.Context) error {
 	log.Println("shutting down server...")
 
@@ -2,8 +2,20 @@ package bootstrap
 
 import (
 	"flag"
+	"fmt"
+	"log"
 	"os"
+	"strconv"
 	"time"
+
+	gophermartauth "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/infrastructure/auth"
+)
+
+// Режимы проверки токенов в AuthMiddleware: local — проверка подписи в процессе,
+// remote — запрос к /api/auth/validate сервиса пользователей с кэшированием ответов.
+const (
+	AuthModeLocal  = "local"
+	AuthModeRemote = "remote"
 )
 
 type Config struct {
@@ -12,6 +24,20 @@ type Config struct {
 	AccrualSystemAddress string
 	JWTSecret           string
 	JWTExpiry           time.Duration
+	RebuildBalances     bool
+	PromoteAdmin        string
+	AccrualPollInterval time.Duration
+	OrderMaxAge         time.Duration
+	WorkerID            string
+	OutboxLeaseTTL      time.Duration
+	OutboxMaxRetries    int
+	OutboxRetryBase     time.Duration
+	OutboxRetryMax      time.Duration
+	AuthMode            string
+	AuthServiceURL      string
+	AuthCacheTTL        time.Duration
+	AuthNegativeTTL     time.Duration
+	AuthCacheSize       int
 }
 
 func ConfigLoad() *Config {
@@ -21,6 +47,8 @@ func ConfigLoad() *Config {
 	flag.StringVar(&cfg.DatabaseURI, "d", getEnv("DATABASE_URI", ""), "database connection string")
 	flag.StringVar(&cfg.AccrualSystemAddress, "r", getEnv("ACCRUAL_SYSTEM_ADDRESS", ""), "accrual system address")
 	flag.StringVar(&cfg.JWTSecret, "j", getEnv("JWT_SECRET", "your-secret-key-change-in-production"), "JWT secret key")
+	flag.BoolVar(&cfg.RebuildBalances, "rebuild-balances", false, "rebuild balances from the ledger and exit")
+	flag.StringVar(&cfg.PromoteAdmin, "promote-admin", "", "grant the admin role to the user with this login and exit")
 	
 	expiryStr := getEnv("JWT_EXPIRY", "30m")
 	expiry, err := time.ParseDuration(expiryStr)
@@ -29,8 +57,29 @@ func ConfigLoad() *Config {
 	}
 	cfg.JWTExpiry = expiry
 
+	cfg.AccrualPollInterval = getEnvDuration("ACCRUAL_POLL_INTERVAL", 30*time.Second)
+	cfg.OrderMaxAge = getEnvDuration("ORDER_MAX_AGE", 24*time.Hour)
+	cfg.WorkerID = getEnv("WORKER_ID", defaultWorkerID())
+	cfg.OutboxLeaseTTL = getEnvDuration("OUTBOX_LEASE_TTL", 5*time.Minute)
+	cfg.OutboxMaxRetries = getEnvInt("OUTBOX_MAX_RETRIES", 10)
+	cfg.OutboxRetryBase = getEnvDuration("OUTBOX_RETRY_BASE_DELAY", 5*time.Second)
+	cfg.OutboxRetryMax = getEnvDuration("OUTBOX_RETRY_MAX_DELAY", 30*time.Minute)
+	cfg.AuthMode = getEnv("AUTH_MODE", AuthModeLocal)
+	cfg.AuthServiceURL = getEnv("AUTH_SERVICE_URL", "")
+	cfg.AuthCacheTTL = getEnvDuration("AUTH_CACHE_TTL", 10*time.Second)
+	cfg.AuthNegativeTTL = getEnvDuration("AUTH_NEGATIVE_CACHE_TTL", 10*time.Second)
+	cfg.AuthCacheSize = getEnvInt("AUTH_CACHE_SIZE", 10000)
+
 	flag.Parse()
 
+	if cfg.AuthServiceURL == "" {
+		cfg.AuthServiceURL = "http://" + cfg.RunAddress
+	}
+	// Больший срок кэша продлил бы жизнь токенам, отозванным на другом экземпляре.
+	if cfg.AuthMode == AuthModeRemote && cfg.AuthCacheTTL > gophermartauth.MaxPositiveTTL {
+		log.Fatalf("AUTH_CACHE_TTL must not exceed %v, got %v", gophermartauth.MaxPositiveTTL, cfg.AuthCacheTTL)
+	}
+
 	return cfg
 }
 
@@ -41,4 +90,30 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// defaultWorkerID различает экземпляры сервиса, запущенные на разных хостах
+// или в одном контейнере.
+func defaultWorkerID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "gophermart"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(getEnv(key, strconv.Itoa(defaultValue)))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, defaultValue.String()))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
 
@@ -1,11 +1,15 @@
 package bootstrap
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
+	gophermartmodel "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	gophermartservice "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
+	gophermartauth "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/infrastructure/auth"
 	gophermarthttpclient "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/infrastructure/httpclient"
 	gophermarthandler "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/presentation/handler"
 	gophermartmiddleware "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/presentation/middleware"
@@ -14,12 +18,14 @@ import (
 
 type HandlerInitializer struct {
 	config        *Config
+	infraResult   *InfrastructureResult
 	useCaseResult *UseCaseResult
 }
 
-func NewHandlerInitializer(cfg *Config, useCaseResult *UseCaseResult) *HandlerInitializer {
+func NewHandlerInitializer(cfg *Config, infraResult *InfrastructureResult, useCaseResult *UseCaseResult) *HandlerInitializer {
 	return &HandlerInitializer{
 		config:        cfg,
+		infraResult:   infraResult,
 		useCaseResult: useCaseResult,
 	}
 }
@@ -28,18 +34,61 @@ type HandlerResult struct {
 	Server *http.Server
 }
 
-func (h *HandlerInitializer) Initialize() *HandlerResult {
+func (h *HandlerInitializer) Initialize() (*HandlerResult, error) {
+	tokenVerifier, err := h.newTokenVerifier()
+	if err != nil {
+		return nil, err
+	}
+
 	registerHandler := userservicehandler.NewRegisterHandler(h.useCaseResult.RegisterUseCase)
 	loginHandler := userservicehandler.NewLoginHandler(h.useCaseResult.LoginUseCase)
+	refreshHandler := userservicehandler.NewRefreshHandler(h.useCaseResult.RefreshTokenUseCase)
+	logoutHandler := userservicehandler.NewLogoutHandler(h.useCaseResult.LogoutUseCase)
+	changePasswordHandler := userservicehandler.NewChangePasswordHandler(h.useCaseResult.ChangePasswordUseCase)
+	requestResetHandler := userservicehandler.NewRequestPasswordResetHandler(h.useCaseResult.RequestResetUseCase)
+	confirmResetHandler := userservicehandler.NewConfirmPasswordResetHandler(h.useCaseResult.ConfirmResetUseCase)
+	setupTwoFactorHandler := userservicehandler.NewSetupTwoFactorHandler(h.useCaseResult.SetupTwoFactorUseCase)
+	enableTwoFactorHandler := userservicehandler.NewEnableTwoFactorHandler(h.useCaseResult.EnableTwoFactorUseCase)
+	verifyTwoFactorHandler := userservicehandler.NewVerifyTwoFactorHandler(h.useCaseResult.VerifyTwoFactorUseCase)
+	getProfileHandler := userservicehandler.NewGetProfileHandler(h.useCaseResult.GetProfileUseCase)
+	updateProfileHandler := userservicehandler.NewUpdateProfileHandler(h.useCaseResult.UpdateProfileUseCase)
 	validateHandler := userservicehandler.NewValidateHandler(h.useCaseResult.ValidateUseCase)
 	healthHandler := userservicehandler.NewHealthHandler()
+	jwksHandler := userservicehandler.NewJWKSHandler(h.infraResult.PublicKeys)
+	unlockLoginHandler := userservicehandler.NewUnlockLoginHandler(h.useCaseResult.UnlockLoginUseCase)
 
-	orderHandler := gophermarthandler.NewOrderHandler(h.useCaseResult.UploadOrderUseCase, h.useCaseResult.GetOrdersUseCase)
+	orderHandler := gophermarthandler.NewOrderHandler(
+		h.useCaseResult.UploadOrderUseCase,
+		h.useCaseResult.GetOrdersUseCase,
+		h.useCaseResult.GetOrderUseCase,
+	)
 	balanceHandler := gophermarthandler.NewBalanceHandler(h.useCaseResult.GetBalanceUseCase, h.useCaseResult.WithdrawUseCase)
 	withdrawalHandler := gophermarthandler.NewWithdrawalHandler(h.useCaseResult.GetWithdrawalsUseCase)
+	accountHandler := gophermarthandler.NewAccountHandler(h.useCaseResult.ExportDataUseCase, h.useCaseResult.DeleteAccountUseCase)
+	adminOutboxHandler := gophermarthandler.NewAdminOutboxHandler(
+		h.useCaseResult.ListOutboxUseCase,
+		h.useCaseResult.GetOutboxUseCase,
+		h.useCaseResult.RequeueOutboxUseCase,
+	)
+	adminAPIKeyHandler := gophermarthandler.NewAdminAPIKeyHandler(
+		h.useCaseResult.IssueAPIKeyUseCase,
+		h.useCaseResult.ListAPIKeysUseCase,
+		h.useCaseResult.RevokeAPIKeyUseCase,
+	)
 
-	userServiceClient := gophermarthttpclient.NewUserServiceClient("http://" + h.config.RunAddress)
-	authMiddleware := gophermartmiddleware.NewAuthMiddleware(userServiceClient)
+	apiKeyAccessHandler := gophermarthandler.NewAPIKeyAccessHandler(
+		h.useCaseResult.GrantAPIKeyAccessUC,
+		h.useCaseResult.RevokeAPIKeyAccessUC,
+	)
+
+	authMiddleware := gophermartmiddleware.NewAuthMiddleware(tokenVerifier, h.useCaseResult.AuthAPIKeyUseCase)
+
+	// В режиме remote подтверждённые токены кэшируются: выход, смена пароля и удаление
+	// учётной записи на этом экземпляре сразу удаляют из кэша токены пользователя.
+	var revokesTokens []func(http.Handler) http.Handler
+	if cache, ok := tokenVerifier.(gophermartmiddleware.TokenCacheInvalidator); ok {
+		revokesTokens = append(revokesTokens, gophermartmiddleware.InvalidateTokenCache(cache))
+	}
 
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
@@ -48,14 +97,53 @@ func (h *HandlerInitializer) Initialize() *HandlerResult {
 
 	r.Post("/api/user/register", registerHandler.ServeHTTP)
 	r.Post("/api/user/login", loginHandler.ServeHTTP)
+	r.Post("/api/user/login/2fa", verifyTwoFactorHandler.ServeHTTP)
+	r.Post("/api/user/token/refresh", refreshHandler.ServeHTTP)
+	r.With(revokesTokens...).Post("/api/user/logout", logoutHandler.ServeHTTP)
+	r.With(revokesTokens...).Put("/api/user/password", changePasswordHandler.ServeHTTP)
+	r.Post("/api/user/password/reset", requestResetHandler.ServeHTTP)
+	r.Post("/api/user/password/reset/confirm", confirmResetHandler.ServeHTTP)
+	r.Post("/api/user/2fa/setup", setupTwoFactorHandler.ServeHTTP)
+	r.Post("/api/user/2fa/enable", enableTwoFactorHandler.ServeHTTP)
+	r.Get("/api/user/profile", getProfileHandler.ServeHTTP)
+	r.Patch("/api/user/profile", updateProfileHandler.ServeHTTP)
 	r.Post("/api/auth/validate", validateHandler.ServeHTTP)
 	r.Get("/api/auth/health", healthHandler.ServeHTTP)
+	r.Get("/.well-known/jwks.json", jwksHandler.ServeHTTP)
 
-	r.With(authMiddleware.Handle).Post("/api/user/orders", orderHandler.Upload)
-	r.With(authMiddleware.Handle).Get("/api/user/orders", orderHandler.GetList)
-	r.With(authMiddleware.Handle).Get("/api/user/balance", balanceHandler.Get)
+	r.With(authMiddleware.AllowAPIKey(gophermartmodel.APIKeyScopeOrdersWrite)).Post("/api/user/orders", orderHandler.Upload)
+	r.With(authMiddleware.AllowAPIKey(gophermartmodel.APIKeyScopeOrdersRead)).Get("/api/user/orders", orderHandler.GetList)
+	r.With(authMiddleware.AllowAPIKey(gophermartmodel.APIKeyScopeOrdersRead)).Get("/api/user/orders/{number}", orderHandler.Get)
+	r.With(authMiddleware.AllowAPIKey(gophermartmodel.APIKeyScopeBalanceRead)).Get("/api/user/balance", balanceHandler.Get)
 	r.With(authMiddleware.Handle).Post("/api/user/balance/withdraw", balanceHandler.Withdraw)
-	r.With(authMiddleware.Handle).Get("/api/user/withdrawals", withdrawalHandler.GetList)
+	r.With(authMiddleware.AllowAPIKey(gophermartmodel.APIKeyScopeWithdrawalsRead)).Get("/api/user/withdrawals", withdrawalHandler.GetList)
+	r.With(authMiddleware.Handle).Get("/api/user/export", accountHandler.Export)
+	r.With(append(revokesTokens, authMiddleware.Handle)...).Delete("/api/user", accountHandler.Delete)
+	r.With(authMiddleware.Handle).Put("/api/user/api-keys/{id}/access", apiKeyAccessHandler.Grant)
+	r.With(authMiddleware.Handle).Delete("/api/user/api-keys/{id}/access", apiKeyAccessHandler.Revoke)
+
+	r.Route("/api/admin", func(r chi.Router) {
+		r.Use(authMiddleware.Handle)
+
+		r.Group(func(r chi.Router) {
+			r.Use(gophermartmiddleware.RequireRole(gophermartservice.RoleAdmin))
+
+			r.Get("/outbox", adminOutboxHandler.List)
+			r.Post("/outbox/requeue", adminOutboxHandler.RequeueBulk)
+			r.Get("/outbox/{id}", adminOutboxHandler.Get)
+			r.Post("/outbox/{id}/requeue", adminOutboxHandler.Requeue)
+
+			r.Post("/api-keys", adminAPIKeyHandler.Issue)
+			r.Get("/api-keys", adminAPIKeyHandler.List)
+			r.Delete("/api-keys/{id}", adminAPIKeyHandler.Revoke)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(gophermartmiddleware.RequireRole(gophermartservice.RoleSupport, gophermartservice.RoleAdmin))
+
+			r.Post("/users/unlock", unlockLoginHandler.ServeHTTP)
+		})
+	})
 
 	server := &http.Server{
 		Addr:    h.config.RunAddress,
@@ -64,5 +152,21 @@ func (h *HandlerInitializer) Initialize() *HandlerResult {
 
 	return &HandlerResult{
 		Server: server,
+	}, nil
+}
+
+func (h *HandlerInitializer) newTokenVerifier() (gophermartservice.TokenVerifier, error) {
+	switch h.config.AuthMode {
+	case AuthModeLocal:
+		return gophermartauth.NewLocalTokenVerifier(h.useCaseResult.ValidateUseCase), nil
+	case AuthModeRemote:
+		client := gophermarthttpclient.NewUserServiceClient(h.config.AuthServiceURL)
+		return gophermartauth.NewCachingTokenVerifier(client, gophermartauth.CacheConfig{
+			PositiveTTL: h.config.AuthCacheTTL,
+			NegativeTTL: h.config.AuthNegativeTTL,
+			MaxEntries:  h.config.AuthCacheSize,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown AUTH_MODE %q", h.config.AuthMode)
 	}
 }
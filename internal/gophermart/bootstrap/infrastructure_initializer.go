@@ -12,6 +12,7 @@ import (
 	userservicerepository "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
 	userserviceservice "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
 	userservicepostgres "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/infrastructure/datastorage/postgres"
+	userserviceencryption "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/infrastructure/encryption"
 	userservicejwt "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/infrastructure/jwt"
 )
 
@@ -28,13 +29,24 @@ func NewInfrastructureInitializer(cfg *Config) *InfrastructureInitializer {
 type InfrastructureResult struct {
 	Pool           *pgxpool.Pool
 	UserRepo       userservicerepository.UserRepository
+	RefreshRepo    userservicerepository.RefreshTokenRepository
 	JWTService     userserviceservice.JWTService
+	PublicKeys     userserviceservice.PublicKeyProvider
 	OrderRepo      gophermartrepository.OrderRepository
 	BalanceRepo    gophermartrepository.BalanceRepository
 	WithdrawalRepo gophermartrepository.WithdrawalRepository
+	LedgerRepo     gophermartrepository.LedgerRepository
 	OutboxRepo     gophermartrepository.OutboxRepository
+	OutboxAudit    gophermartrepository.OutboxAuditRepository
+	APIKeyRepo     gophermartrepository.APIKeyRepository
 	UnitOfWork     gophermartrepository.UnitOfWork
 	UserServiceCfg *userservicebootstrap.Config
+	RevokedRepo    userservicerepository.RevokedTokenRepository
+	LoginAttempts  userservicerepository.LoginAttemptRepository
+	PasswordResets userservicerepository.PasswordResetTokenRepository
+	TwoFactorRepo  userservicerepository.TwoFactorRepository
+	Challenges     userservicerepository.TwoFactorChallengeRepository
+	SecretCipher   userserviceservice.SecretCipher
 }
 
 func (i *InfrastructureInitializer) Initialize() (*InfrastructureResult, error) {
@@ -58,22 +70,58 @@ func (i *InfrastructureInitializer) Initialize() (*InfrastructureResult, error)
 	userServiceCfg.JWTExpiry = i.config.JWTExpiry
 
 	userRepo := userservicepostgres.NewUserRepository(pool)
-	jwtService := userservicejwt.NewJWTService(userServiceCfg.JWTSecret, userServiceCfg.JWTExpiry)
+	refreshTokenRepo := userservicepostgres.NewRefreshTokenRepository(pool)
+	revokedTokenRepo := userservicepostgres.NewRevokedTokenRepository(pool)
+	loginAttemptRepo := userservicepostgres.NewLoginAttemptRepository(pool)
+	passwordResetRepo := userservicepostgres.NewPasswordResetTokenRepository(pool)
+	twoFactorRepo := userservicepostgres.NewTwoFactorRepository(pool)
+	challengeRepo := userservicepostgres.NewTwoFactorChallengeRepository(pool)
+	keys, err := userservicejwt.LoadKeySet(userServiceCfg.JWTSecret, userServiceCfg.JWTSigningKeyFile, userServiceCfg.JWTVerificationKeyFiles)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	jwtService := userservicejwt.NewJWTServiceWithKeys(keys, userServiceCfg.JWTExpiry)
+
+	var secretCipher userserviceservice.SecretCipher
+	if userServiceCfg.TOTPEncryptionKey != "" {
+		secretCipher, err = userserviceencryption.NewAESGCMCipher(userServiceCfg.TOTPEncryptionKey)
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+	} else {
+		log.Println("TOTP_ENCRYPTION_KEY is not set, two-factor authentication is unavailable")
+	}
 
 	orderRepo := gophermartpostgres.NewOrderRepository(pool)
 	balanceRepo := gophermartpostgres.NewBalanceRepository(pool)
 	withdrawalRepo := gophermartpostgres.NewWithdrawalRepository(pool)
+	ledgerRepo := gophermartpostgres.NewLedgerRepository(pool)
 	outboxRepo := gophermartpostgres.NewOutboxRepository(pool)
+	outboxAuditRepo := gophermartpostgres.NewOutboxAuditRepository(pool)
+	apiKeyRepo := gophermartpostgres.NewAPIKeyRepository(pool)
 	unitOfWork := gophermartpostgres.NewUnitOfWork(pool)
 
 	return &InfrastructureResult{
 		Pool:           pool,
 		UserRepo:       userRepo,
+		RefreshRepo:    refreshTokenRepo,
+		RevokedRepo:    revokedTokenRepo,
+		LoginAttempts:  loginAttemptRepo,
+		PasswordResets: passwordResetRepo,
+		TwoFactorRepo:  twoFactorRepo,
+		Challenges:     challengeRepo,
+		SecretCipher:   secretCipher,
 		JWTService:     jwtService,
+		PublicKeys:     keys,
 		OrderRepo:      orderRepo,
 		BalanceRepo:    balanceRepo,
 		WithdrawalRepo: withdrawalRepo,
+		LedgerRepo:     ledgerRepo,
 		OutboxRepo:     outboxRepo,
+		OutboxAudit:    outboxAuditRepo,
+		APIKeyRepo:     apiKeyRepo,
 		UnitOfWork:     unitOfWork,
 		UserServiceCfg: userServiceCfg,
 	}, nil
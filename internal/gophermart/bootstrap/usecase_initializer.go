@@ -3,8 +3,11 @@ package bootstrap
 import (
 	gophermartusecase "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/application/usecase"
 	gophermartservice "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
+	gophermartaccount "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/infrastructure/account"
 	gophermarthttpclient "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/infrastructure/httpclient"
 	userserviceusecase "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/application/usecase"
+	userserviceservice "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+	userservicenotifier "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/infrastructure/notifier"
 )
 
 type UseCaseInitializer struct {
@@ -20,49 +23,217 @@ func NewUseCaseInitializer(cfg *Config, infraResult *InfrastructureResult) *UseC
 }
 
 type UseCaseResult struct {
-	RegisterUseCase       *userserviceusecase.RegisterUseCase
-	LoginUseCase          *userserviceusecase.LoginUseCase
-	ValidateUseCase       *userserviceusecase.ValidateTokenUseCase
-	UploadOrderUseCase    *gophermartusecase.UploadOrderUseCase
-	GetOrdersUseCase      *gophermartusecase.GetOrdersUseCase
-	GetBalanceUseCase     *gophermartusecase.GetBalanceUseCase
-	WithdrawUseCase       *gophermartusecase.WithdrawUseCase
-	GetWithdrawalsUseCase *gophermartusecase.GetWithdrawalsUseCase
-	ProcessOrdersUseCase  *gophermartusecase.ProcessOrdersUseCase
+	RegisterUseCase        *userserviceusecase.RegisterUseCase
+	LoginUseCase           *userserviceusecase.LoginUseCase
+	RefreshTokenUseCase    *userserviceusecase.RefreshTokenUseCase
+	ValidateUseCase        *userserviceusecase.ValidateTokenUseCase
+	LogoutUseCase          *userserviceusecase.LogoutUseCase
+	UnlockLoginUseCase     *userserviceusecase.UnlockLoginUseCase
+	GrantRoleUseCase       *userserviceusecase.GrantRoleUseCase
+	ChangePasswordUseCase  *userserviceusecase.ChangePasswordUseCase
+	RequestResetUseCase    *userserviceusecase.RequestPasswordResetUseCase
+	ConfirmResetUseCase    *userserviceusecase.ConfirmPasswordResetUseCase
+	SetupTwoFactorUseCase  *userserviceusecase.SetupTwoFactorUseCase
+	EnableTwoFactorUseCase *userserviceusecase.EnableTwoFactorUseCase
+	VerifyTwoFactorUseCase *userserviceusecase.VerifyTwoFactorUseCase
+	GetProfileUseCase      *userserviceusecase.GetProfileUseCase
+	UpdateProfileUseCase   *userserviceusecase.UpdateProfileUseCase
+	UploadOrderUseCase     *gophermartusecase.UploadOrderUseCase
+	GetOrdersUseCase       *gophermartusecase.GetOrdersUseCase
+	GetOrderUseCase        *gophermartusecase.GetOrderUseCase
+	GetBalanceUseCase      *gophermartusecase.GetBalanceUseCase
+	WithdrawUseCase        *gophermartusecase.WithdrawUseCase
+	GetWithdrawalsUseCase  *gophermartusecase.GetWithdrawalsUseCase
+	ProcessOrdersUseCase   *gophermartusecase.ProcessOrdersUseCase
+	ListOutboxUseCase      *gophermartusecase.ListOutboxUseCase
+	GetOutboxUseCase       *gophermartusecase.GetOutboxUseCase
+	RequeueOutboxUseCase   *gophermartusecase.RequeueOutboxUseCase
+	ExportDataUseCase      *gophermartusecase.ExportPersonalDataUseCase
+	DeleteAccountUseCase   *gophermartusecase.DeleteAccountUseCase
+	IssueAPIKeyUseCase     *gophermartusecase.IssueAPIKeyUseCase
+	ListAPIKeysUseCase     *gophermartusecase.ListAPIKeysUseCase
+	RevokeAPIKeyUseCase    *gophermartusecase.RevokeAPIKeyUseCase
+	AuthAPIKeyUseCase      *gophermartusecase.AuthenticateAPIKeyUseCase
+	GrantAPIKeyAccessUC    *gophermartusecase.GrantAPIKeyAccessUseCase
+	RevokeAPIKeyAccessUC   *gophermartusecase.RevokeAPIKeyAccessUseCase
 }
 
 func (u *UseCaseInitializer) Initialize() *UseCaseResult {
+	tokenIssuer := userserviceusecase.NewTokenIssuer(
+		u.infraResult.JWTService,
+		u.infraResult.RefreshRepo,
+		u.infraResult.UserServiceCfg.RefreshTTL,
+	)
+	userServiceCfg := u.infraResult.UserServiceCfg
 	registerUseCase := userserviceusecase.NewRegisterUseCase(
 		u.infraResult.UserRepo,
-		u.infraResult.JWTService,
+		tokenIssuer,
+		userServiceCfg.PasswordPolicy,
+	)
+	loginThrottle := userserviceusecase.NewLoginThrottle(
+		u.infraResult.LoginAttempts,
+		userserviceusecase.LoginThrottlePolicy{
+			MaxFailures:   userServiceCfg.LoginMaxFailures,
+			MaxIPFailures: userServiceCfg.LoginIPMaxFailures,
+			Lockout:       userServiceCfg.LoginLockout,
+			BaseDelay:     userServiceCfg.LoginFailureDelay,
+		},
+	)
+	twoFactorChallenges := userserviceusecase.NewTwoFactorChallenges(
+		u.infraResult.TwoFactorRepo,
+		u.infraResult.Challenges,
+		userServiceCfg.TwoFactorChallengeTTL,
 	)
 	loginUseCase := userserviceusecase.NewLoginUseCase(
 		u.infraResult.UserRepo,
-		u.infraResult.JWTService,
+		tokenIssuer,
+		loginThrottle,
+		twoFactorChallenges,
+	)
+	unlockLoginUseCase := userserviceusecase.NewUnlockLoginUseCase(u.infraResult.LoginAttempts)
+	grantRoleUseCase := userserviceusecase.NewGrantRoleUseCase(u.infraResult.UserRepo)
+	refreshTokenUseCase := userserviceusecase.NewRefreshTokenUseCase(
+		u.infraResult.UserRepo,
+		u.infraResult.RefreshRepo,
+		tokenIssuer,
 	)
 	validateUseCase := userserviceusecase.NewValidateTokenUseCase(
 		u.infraResult.JWTService,
+		u.infraResult.RevokedRepo,
+	)
+	logoutUseCase := userserviceusecase.NewLogoutUseCase(
+		u.infraResult.JWTService,
+		u.infraResult.RevokedRepo,
+		u.infraResult.RefreshRepo,
+	)
+	changePasswordUseCase := userserviceusecase.NewChangePasswordUseCase(
+		u.infraResult.UserRepo,
+		validateUseCase,
+		u.infraResult.RevokedRepo,
+		u.infraResult.RefreshRepo,
+		tokenIssuer,
+		userServiceCfg.PasswordPolicy,
+	)
+	requestResetUseCase := userserviceusecase.NewRequestPasswordResetUseCase(
+		u.infraResult.UserRepo,
+		u.infraResult.PasswordResets,
+		userservicenotifier.NewPasswordResetNotifier(userServiceCfg.PasswordResetNotifyFile),
+		userServiceCfg.PasswordResetTTL,
+	)
+	confirmResetUseCase := userserviceusecase.NewConfirmPasswordResetUseCase(
+		u.infraResult.UserRepo,
+		u.infraResult.PasswordResets,
+		u.infraResult.RevokedRepo,
+		u.infraResult.RefreshRepo,
+		userServiceCfg.PasswordPolicy,
+	)
+	totp := userserviceservice.TOTP{Issuer: userServiceCfg.TOTPIssuer, Skew: 1}
+	setupTwoFactorUseCase := userserviceusecase.NewSetupTwoFactorUseCase(
+		validateUseCase,
+		u.infraResult.TwoFactorRepo,
+		u.infraResult.SecretCipher,
+		totp,
+	)
+	enableTwoFactorUseCase := userserviceusecase.NewEnableTwoFactorUseCase(
+		validateUseCase,
+		u.infraResult.TwoFactorRepo,
+		u.infraResult.SecretCipher,
+		totp,
+	)
+	verifyTwoFactorUseCase := userserviceusecase.NewVerifyTwoFactorUseCase(
+		u.infraResult.UserRepo,
+		u.infraResult.TwoFactorRepo,
+		u.infraResult.Challenges,
+		u.infraResult.SecretCipher,
+		totp,
+		tokenIssuer,
+		loginThrottle,
 	)
 
 	accrualClient := gophermarthttpclient.NewAccrualClient(u.config.AccrualSystemAddress)
 	orderValidator := gophermartservice.NewLuhnOrderNumberValidator()
 
-	uploadOrderUseCase := gophermartusecase.NewUploadOrderUseCase(u.infraResult.UnitOfWork, u.infraResult.OrderRepo, u.infraResult.OutboxRepo, orderValidator)
 	getOrdersUseCase := gophermartusecase.NewGetOrdersUseCase(u.infraResult.OrderRepo)
+	getOrderUseCase := gophermartusecase.NewGetOrderUseCase(u.infraResult.OrderRepo, u.infraResult.OutboxRepo)
 	getBalanceUseCase := gophermartusecase.NewGetBalanceUseCase(u.infraResult.BalanceRepo)
 	withdrawUseCase := gophermartusecase.NewWithdrawUseCase(u.infraResult.UnitOfWork, u.infraResult.BalanceRepo, u.infraResult.WithdrawalRepo, orderValidator)
 	getWithdrawalsUseCase := gophermartusecase.NewGetWithdrawalsUseCase(u.infraResult.WithdrawalRepo)
-	processOrdersUseCase := gophermartusecase.NewProcessOrdersUseCase(u.infraResult.OutboxRepo, u.infraResult.OrderRepo, u.infraResult.BalanceRepo, accrualClient)
+	processOrdersUseCase := gophermartusecase.NewProcessOrdersUseCase(u.infraResult.UnitOfWork, u.infraResult.OutboxRepo, u.infraResult.OrderRepo, accrualClient, gophermartusecase.ProcessOrdersConfig{
+		PollInterval: u.config.AccrualPollInterval,
+		MaxOrderAge:  u.config.OrderMaxAge,
+		WorkerID:     u.config.WorkerID,
+		LeaseTTL:     u.config.OutboxLeaseTTL,
+		Retry: gophermartusecase.RetryPolicy{
+			MaxRetries: u.config.OutboxMaxRetries,
+			BaseDelay:  u.config.OutboxRetryBase,
+			MaxDelay:   u.config.OutboxRetryMax,
+		},
+	})
+	listOutboxUseCase := gophermartusecase.NewListOutboxUseCase(u.infraResult.OutboxRepo)
+	getOutboxUseCase := gophermartusecase.NewGetOutboxUseCase(u.infraResult.OutboxRepo, u.infraResult.OutboxAudit)
+	requeueOutboxUseCase := gophermartusecase.NewRequeueOutboxUseCase(u.infraResult.UnitOfWork, u.infraResult.OutboxRepo)
+
+	getProfileUseCase := userserviceusecase.NewGetProfileUseCase(
+		validateUseCase,
+		u.infraResult.UserRepo,
+		gophermartaccount.NewLocalBalanceProvider(getBalanceUseCase),
+	)
+	updateProfileUseCase := userserviceusecase.NewUpdateProfileUseCase(validateUseCase, u.infraResult.UserRepo)
+
+	accounts := gophermartaccount.NewLocalAccountDirectory(
+		u.infraResult.UserRepo,
+		userserviceusecase.NewDeleteAccountUseCase(u.infraResult.UserRepo, u.infraResult.RevokedRepo, u.infraResult.RefreshRepo),
+	)
+	exportDataUseCase := gophermartusecase.NewExportPersonalDataUseCase(
+		accounts,
+		u.infraResult.OrderRepo,
+		u.infraResult.WithdrawalRepo,
+		u.infraResult.LedgerRepo,
+		u.infraResult.BalanceRepo,
+	)
+	deleteAccountUseCase := gophermartusecase.NewDeleteAccountUseCase(u.infraResult.UnitOfWork, accounts)
+	uploadOrderUseCase := gophermartusecase.NewUploadOrderUseCase(u.infraResult.UnitOfWork, u.infraResult.OrderRepo, u.infraResult.OutboxRepo, accounts, orderValidator)
+
+	issueAPIKeyUseCase := gophermartusecase.NewIssueAPIKeyUseCase(u.infraResult.APIKeyRepo)
+	listAPIKeysUseCase := gophermartusecase.NewListAPIKeysUseCase(u.infraResult.APIKeyRepo)
+	revokeAPIKeyUseCase := gophermartusecase.NewRevokeAPIKeyUseCase(u.infraResult.APIKeyRepo)
+	authAPIKeyUseCase := gophermartusecase.NewAuthenticateAPIKeyUseCase(u.infraResult.APIKeyRepo, accounts)
+	grantAPIKeyAccessUseCase := gophermartusecase.NewGrantAPIKeyAccessUseCase(u.infraResult.APIKeyRepo)
+	revokeAPIKeyAccessUseCase := gophermartusecase.NewRevokeAPIKeyAccessUseCase(u.infraResult.APIKeyRepo)
 
 	return &UseCaseResult{
-		RegisterUseCase:       registerUseCase,
-		LoginUseCase:          loginUseCase,
-		ValidateUseCase:       validateUseCase,
-		UploadOrderUseCase:    uploadOrderUseCase,
-		GetOrdersUseCase:      getOrdersUseCase,
-		GetBalanceUseCase:     getBalanceUseCase,
-		WithdrawUseCase:       withdrawUseCase,
-		GetWithdrawalsUseCase: getWithdrawalsUseCase,
-		ProcessOrdersUseCase:  processOrdersUseCase,
+		RegisterUseCase:        registerUseCase,
+		LoginUseCase:           loginUseCase,
+		RefreshTokenUseCase:    refreshTokenUseCase,
+		ValidateUseCase:        validateUseCase,
+		LogoutUseCase:          logoutUseCase,
+		UnlockLoginUseCase:     unlockLoginUseCase,
+		GrantRoleUseCase:       grantRoleUseCase,
+		ChangePasswordUseCase:  changePasswordUseCase,
+		RequestResetUseCase:    requestResetUseCase,
+		ConfirmResetUseCase:    confirmResetUseCase,
+		SetupTwoFactorUseCase:  setupTwoFactorUseCase,
+		EnableTwoFactorUseCase: enableTwoFactorUseCase,
+		VerifyTwoFactorUseCase: verifyTwoFactorUseCase,
+		GetProfileUseCase:      getProfileUseCase,
+		UpdateProfileUseCase:   updateProfileUseCase,
+		UploadOrderUseCase:     uploadOrderUseCase,
+		GetOrdersUseCase:       getOrdersUseCase,
+		GetOrderUseCase:        getOrderUseCase,
+		GetBalanceUseCase:      getBalanceUseCase,
+		WithdrawUseCase:        withdrawUseCase,
+		GetWithdrawalsUseCase:  getWithdrawalsUseCase,
+		ProcessOrdersUseCase:   processOrdersUseCase,
+		ListOutboxUseCase:      listOutboxUseCase,
+		GetOutboxUseCase:       getOutboxUseCase,
+		RequeueOutboxUseCase:   requeueOutboxUseCase,
+		ExportDataUseCase:      exportDataUseCase,
+		DeleteAccountUseCase:   deleteAccountUseCase,
+		IssueAPIKeyUseCase:     issueAPIKeyUseCase,
+		ListAPIKeysUseCase:     listAPIKeysUseCase,
+		RevokeAPIKeyUseCase:    revokeAPIKeyUseCase,
+		AuthAPIKeyUseCase:      authAPIKeyUseCase,
+		GrantAPIKeyAccessUC:    grantAPIKeyAccessUseCase,
+		RevokeAPIKeyAccessUC:   revokeAPIKeyAccessUseCase,
 	}
 }
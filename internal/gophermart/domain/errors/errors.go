@@ -0,0 +1,120 @@
+package errors
+
+import "errors"
+
+// Kind — категория бизнес-ошибки. По категории слой представления выбирает код ответа,
+// поэтому каждая ошибка домена создаётся через New с явной категорией.
+type Kind string
+
+const (
+	KindInvalidInput      Kind = "invalid_input"
+	KindValidation        Kind = "validation"
+	KindUnauthorized      Kind = "unauthorized"
+	KindForbidden         Kind = "forbidden"
+	KindNotFound          Kind = "not_found"
+	KindConflict          Kind = "conflict"
+	KindInsufficientFunds Kind = "insufficient_funds"
+	KindRateLimited       Kind = "rate_limited"
+	KindInternal          Kind = "internal"
+)
+
+type Error struct {
+	kind    Kind
+	message string
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+func (e *Error) Kind() Kind {
+	return e.kind
+}
+
+var registry []*Error
+
+// New создаёт ошибку домена и регистрирует её, чтобы тесты могли проверить,
+// что для каждой ошибки определён код ответа.
+func New(kind Kind, message string) *Error {
+	err := &Error{kind: kind, message: message}
+	registry = append(registry, err)
+	return err
+}
+
+// Registered возвращает все ошибки, созданные через New.
+func Registered() []*Error {
+	return append([]*Error(nil), registry...)
+}
+
+// As возвращает первую ошибку домена в цепочке err.
+func As(err error) (*Error, bool) {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+	return nil, false
+}
+
+// KindOf возвращает категорию ошибки. Ошибки вне домена относятся к KindInternal.
+func KindOf(err error) Kind {
+	if domainErr, ok := As(err); ok {
+		return domainErr.kind
+	}
+	return KindInternal
+}
+
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+var (
+	ErrInvalidUserID           = New(KindInvalidInput, "invalid user ID")
+	ErrOrderNumberRequired     = New(KindInvalidInput, "order number is required")
+	ErrInvalidOrderNumber      = New(KindValidation, "invalid order number format")
+	ErrOrderAlreadyExists      = New(KindConflict, "order number already exists")
+	ErrOrderOwnedByAnotherUser = New(KindConflict, "order number already exists for another user")
+	ErrOrderNotFound           = New(KindNotFound, "order not found")
+	ErrOrderStatusFinal        = New(KindConflict, "cannot change status of final order")
+	ErrNegativeAccrual         = New(KindValidation, "accrual cannot be negative")
+	ErrInvalidOrderAccrual     = New(KindValidation, "invalid order cannot have accrual")
+	ErrInvalidOrderStatus      = New(KindInvalidInput, "invalid order status")
+
+	ErrInvalidCursor    = New(KindInvalidInput, "invalid cursor")
+	ErrInvalidDateRange = New(KindInvalidInput, "from must be before to")
+	ErrInvalidSumRange  = New(KindInvalidInput, "min_sum must not exceed max_sum")
+
+	ErrAccrualNotPositive    = New(KindValidation, "accrual amount must be positive")
+	ErrWithdrawalNotPositive = New(KindValidation, "withdrawal amount must be positive")
+	ErrWithdrawalSumRequired = New(KindValidation, "withdrawal sum must be positive")
+	ErrWithdrawalOrderExists = New(KindConflict, "order has already been paid with points")
+	ErrInsufficientFunds     = New(KindInsufficientFunds, "insufficient funds")
+
+	ErrInvalidIdempotencyKey = New(KindInvalidInput, "idempotency key must be at most 255 characters")
+	ErrIdempotencyKeyReused  = New(KindValidation, "idempotency key was already used for a different request")
+
+	ErrAccrualOrderNotRegistered = New(KindNotFound, "order not found in accrual system")
+	ErrUnknownAccrualStatus      = New(KindInternal, "unknown accrual status")
+	ErrRateLimited               = New(KindRateLimited, "rate limited")
+
+	ErrInvalidToken = New(KindUnauthorized, "invalid token")
+
+	ErrInvalidAPIKey       = New(KindUnauthorized, "invalid api key")
+	ErrAPIKeyScopeDenied   = New(KindForbidden, "api key is not allowed to perform this action")
+	ErrAPIKeyNotFound      = New(KindNotFound, "api key not found")
+	ErrAPIKeyNameRequired  = New(KindInvalidInput, "api key name and owner are required")
+	ErrInvalidAPIKeyScope  = New(KindInvalidInput, "invalid api key scope")
+	ErrInvalidAPIKeyExpiry = New(KindInvalidInput, "api key expiry must be positive")
+	ErrActingUserRequired  = New(KindInvalidInput, "acting user is required for api key requests")
+	ErrActingUserNotFound  = New(KindInvalidInput, "acting user not found")
+	ErrAPIKeyNotGranted    = New(KindForbidden, "acting user has not granted access to this api key")
+	ErrAPIKeyGrantNotFound = New(KindNotFound, "api key access was not granted")
+
+	ErrAccountNotFound         = New(KindNotFound, "account not found")
+	ErrAccountHasPendingOrders = New(KindConflict, "account has orders that are still being processed")
+
+	ErrInvalidOutboxStatus  = New(KindInvalidInput, "invalid outbox status")
+	ErrOutboxNotFound       = New(KindNotFound, "outbox not found")
+	ErrOutboxNotRequeueable = New(KindConflict, "outbox cannot be requeued in its current status")
+	ErrNothingToRequeue     = New(KindInvalidInput, "outbox ids or status required")
+	ErrOutboxLeaseLost      = New(KindConflict, "outbox lease lost")
+)
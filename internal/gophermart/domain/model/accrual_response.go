@@ -1,9 +1,9 @@
 package model
 
 type AccrualResponse struct {
-	Order   string   `json:"order"`
-	Status  string   `json:"status"`
-	Accrual *float64 `json:"accrual,omitempty"`
+	Order   string  `json:"order"`
+	Status  string  `json:"status"`
+	Accrual *Points `json:"accrual,omitempty"`
 }
 
 
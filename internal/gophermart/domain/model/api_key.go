@@ -0,0 +1,75 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// APIKeyScope — право, выданное ключу. Ключ действует только в пределах своих scopes.
+type APIKeyScope string
+
+const (
+	APIKeyScopeOrdersWrite     APIKeyScope = "orders:write"
+	APIKeyScopeOrdersRead      APIKeyScope = "orders:read"
+	APIKeyScopeBalanceRead     APIKeyScope = "balance:read"
+	APIKeyScopeWithdrawalsRead APIKeyScope = "withdrawals:read"
+)
+
+func (s APIKeyScope) IsValid() bool {
+	switch s {
+	case APIKeyScopeOrdersWrite, APIKeyScopeOrdersRead, APIKeyScopeBalanceRead, APIKeyScopeWithdrawalsRead:
+		return true
+	}
+	return false
+}
+
+// APIKeyLastUsedResolution — не чаще этого интервала обновляется LastUsedAt, чтобы каждый
+// запрос по ключу не превращался в запись в базу.
+const APIKeyLastUsedResolution = time.Minute
+
+// APIKey — ключ доступа внешней системы (например, кассы партнёра). Сам ключ не хранится:
+// в базе лежит только его SHA-256 и короткий Prefix, по которому ключ можно узнать в списке.
+type APIKey struct {
+	ID         int64
+	Name       string
+	Owner      string
+	Prefix     string
+	KeyHash    string
+	Scopes     []APIKeyScope
+	CreatedBy  int64
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive сообщает, что ключ не отозван и не истёк к моменту now.
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// LastUsedOutdated сообщает, что LastUsedAt пора обновить: ключ ещё не использовался
+// или последняя отметка старше APIKeyLastUsedResolution.
+func (k *APIKey) LastUsedOutdated(now time.Time) bool {
+	return k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= APIKeyLastUsedResolution
+}
+
+// HashAPIKey возвращает hex-представление SHA-256 ключа. Ключи генерируются случайно
+// с достаточной энтропией, поэтому медленный хеш вроде bcrypt не нужен и поиск идёт по индексу.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAPIKeyScope_IsValid(t *testing.T) {
+	tests := []struct {
+		scope APIKeyScope
+		want  bool
+	}{
+		{APIKeyScopeOrdersWrite, true},
+		{APIKeyScopeOrdersRead, true},
+		{APIKeyScopeBalanceRead, true},
+		{APIKeyScopeWithdrawalsRead, true},
+		{"balance:write", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.scope.IsValid(); got != tt.want {
+			t.Errorf("APIKeyScope(%q).IsValid() = %v, want %v", tt.scope, got, tt.want)
+		}
+	}
+}
+
+func TestAPIKey_HasScope(t *testing.T) {
+	key := &APIKey{Scopes: []APIKeyScope{APIKeyScopeOrdersWrite, APIKeyScopeBalanceRead}}
+
+	if !key.HasScope(APIKeyScopeOrdersWrite) {
+		t.Error("HasScope(orders:write) = false, want true")
+	}
+	if key.HasScope(APIKeyScopeWithdrawalsRead) {
+		t.Error("HasScope(withdrawals:read) = true, want false")
+	}
+}
+
+func TestAPIKey_IsActive(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	tests := []struct {
+		name string
+		key  APIKey
+		want bool
+	}{
+		{"no expiry", APIKey{}, true},
+		{"expires later", APIKey{ExpiresAt: &future}, true},
+		{"expired", APIKey{ExpiresAt: &past}, false},
+		{"expires now", APIKey{ExpiresAt: &now}, false},
+		{"revoked", APIKey{RevokedAt: &past}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.key.IsActive(now); got != tt.want {
+				t.Errorf("IsActive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashAPIKey(t *testing.T) {
+	hash := HashAPIKey("gm_secret")
+
+	if len(hash) != 64 {
+		t.Errorf("len(HashAPIKey()) = %d, want 64", len(hash))
+	}
+	if hash != HashAPIKey("gm_secret") {
+		t.Error("HashAPIKey() is not deterministic")
+	}
+	if hash == HashAPIKey("gm_other") {
+		t.Error("HashAPIKey() returned the same hash for different keys")
+	}
+}
+
+func TestAPIKey_LastUsedOutdated(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-APIKeyLastUsedResolution / 2)
+	old := now.Add(-APIKeyLastUsedResolution)
+
+	tests := []struct {
+		name string
+		key  APIKey
+		want bool
+	}{
+		{"never used", APIKey{}, true},
+		{"used recently", APIKey{LastUsedAt: &recent}, false},
+		{"used a resolution ago", APIKey{LastUsedAt: &old}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.key.LastUsedOutdated(now); got != tt.want {
+				t.Errorf("LastUsedOutdated() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,13 +1,13 @@
 package model
 
 import (
-	"errors"
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 )
 
 type Balance struct {
 	userID    int64
-	current   float64
-	withdrawn float64
+	current   Points
+	withdrawn Points
 }
 
 func NewBalance(userID int64) *Balance {
@@ -22,41 +22,41 @@ func (b *Balance) UserID() int64 {
 	return b.userID
 }
 
-func (b *Balance) Current() float64 {
+func (b *Balance) Current() Points {
 	return b.current
 }
 
-func (b *Balance) Withdrawn() float64 {
+func (b *Balance) Withdrawn() Points {
 	return b.withdrawn
 }
 
-func (b *Balance) Accrue(amount float64) error {
-	if amount <= 0 {
-		return errors.New("accrual amount must be positive")
+func (b *Balance) Accrue(amount Points) error {
+	if !amount.IsPositive() {
+		return domainerrors.ErrAccrualNotPositive
 	}
-	b.current += amount
+	b.current = b.current.Add(amount)
 	return nil
 }
 
-func (b *Balance) Withdraw(amount float64) error {
-	if amount <= 0 {
-		return errors.New("withdrawal amount must be positive")
+func (b *Balance) Withdraw(amount Points) error {
+	if !amount.IsPositive() {
+		return domainerrors.ErrWithdrawalNotPositive
 	}
 
 	if b.current < amount {
-		return errors.New("insufficient funds")
+		return domainerrors.ErrInsufficientFunds
 	}
 
-	b.current -= amount
-	b.withdrawn += amount
+	b.current = b.current.Sub(amount)
+	b.withdrawn = b.withdrawn.Add(amount)
 	return nil
 }
 
-func (b *Balance) CanWithdraw(amount float64) bool {
-	return amount > 0 && b.current >= amount
+func (b *Balance) CanWithdraw(amount Points) bool {
+	return amount.IsPositive() && b.current >= amount
 }
 
-func RestoreBalance(userID int64, current, withdrawn float64) *Balance {
+func RestoreBalance(userID int64, current, withdrawn Points) *Balance {
 	return &Balance{
 		userID:    userID,
 		current:   current,
@@ -27,8 +27,8 @@ func TestNewBalance(t *testing.T) {
 
 func TestRestoreBalance(t *testing.T) {
 	userID := int64(123)
-	current := 100.5
-	withdrawn := 50.0
+	current := MustParsePoints("100.5")
+	withdrawn := MustParsePoints("50")
 
 	balance := RestoreBalance(userID, current, withdrawn)
 
@@ -50,14 +50,14 @@ func TestBalance_Accrue(t *testing.T) {
 
 	tests := []struct {
 		name        string
-		amount      float64
+		amount      Points
 		wantErr     bool
-		wantCurrent float64
+		wantCurrent Points
 	}{
-		{"positive amount", 100.0, false, 100.0},
-		{"another positive amount", 50.5, false, 150.5},
-		{"zero amount", 0.0, true, 150.5},
-		{"negative amount", -10.0, true, 150.5},
+		{"positive amount", MustParsePoints("100"), false, MustParsePoints("100")},
+		{"another positive amount", MustParsePoints("50.5"), false, MustParsePoints("150.5")},
+		{"zero amount", 0, true, MustParsePoints("150.5")},
+		{"negative amount", MustParsePoints("-10"), true, MustParsePoints("150.5")},
 	}
 
 	for _, tt := range tests {
@@ -76,23 +76,23 @@ func TestBalance_Accrue(t *testing.T) {
 func TestBalance_Withdraw(t *testing.T) {
 	tests := []struct {
 		name          string
-		initial       float64
-		amount        float64
+		initial       Points
+		amount        Points
 		wantErr       bool
-		wantCurrent   float64
-		wantWithdrawn float64
+		wantCurrent   Points
+		wantWithdrawn Points
 	}{
-		{"successful withdrawal", 100.0, 50.0, false, 50.0, 50.0},
-		{"withdraw all", 100.0, 100.0, false, 0.0, 100.0},
-		{"insufficient funds", 50.0, 100.0, true, 50.0, 0.0},
-		{"zero amount", 100.0, 0.0, true, 100.0, 0.0},
-		{"negative amount", 100.0, -10.0, true, 100.0, 0.0},
-		{"multiple withdrawals", 100.0, 30.0, false, 70.0, 30.0},
+		{"successful withdrawal", MustParsePoints("100"), MustParsePoints("50"), false, MustParsePoints("50"), MustParsePoints("50")},
+		{"withdraw all", MustParsePoints("100"), MustParsePoints("100"), false, 0, MustParsePoints("100")},
+		{"insufficient funds", MustParsePoints("50"), MustParsePoints("100"), true, MustParsePoints("50"), 0},
+		{"zero amount", MustParsePoints("100"), 0, true, MustParsePoints("100"), 0},
+		{"negative amount", MustParsePoints("100"), MustParsePoints("-10"), true, MustParsePoints("100"), 0},
+		{"multiple withdrawals", MustParsePoints("100"), MustParsePoints("30"), false, MustParsePoints("70"), MustParsePoints("30")},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			balance := RestoreBalance(1, tt.initial, 0.0)
+			balance := RestoreBalance(1, tt.initial, 0)
 			err := balance.Withdraw(tt.amount)
 
 			if (err != nil) != tt.wantErr {
@@ -118,22 +118,22 @@ func TestBalance_Withdraw(t *testing.T) {
 func TestBalance_CanWithdraw(t *testing.T) {
 	tests := []struct {
 		name    string
-		current float64
-		amount  float64
+		current Points
+		amount  Points
 		want    bool
 	}{
-		{"can withdraw", 100.0, 50.0, true},
-		{"can withdraw all", 100.0, 100.0, true},
-		{"cannot withdraw more", 50.0, 100.0, false},
-		{"cannot withdraw zero", 100.0, 0.0, false},
-		{"cannot withdraw negative", 100.0, -10.0, false},
-		{"exact amount", 100.0, 100.0, true},
-		{"small amount", 0.01, 0.01, true},
+		{"can withdraw", MustParsePoints("100"), MustParsePoints("50"), true},
+		{"can withdraw all", MustParsePoints("100"), MustParsePoints("100"), true},
+		{"cannot withdraw more", MustParsePoints("50"), MustParsePoints("100"), false},
+		{"cannot withdraw zero", MustParsePoints("100"), 0, false},
+		{"cannot withdraw negative", MustParsePoints("100"), MustParsePoints("-10"), false},
+		{"exact amount", MustParsePoints("100"), MustParsePoints("100"), true},
+		{"small amount", MustParsePoints("0.01"), MustParsePoints("0.01"), true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			balance := RestoreBalance(1, tt.current, 0.0)
+			balance := RestoreBalance(1, tt.current, 0)
 			if got := balance.CanWithdraw(tt.amount); got != tt.want {
 				t.Errorf("CanWithdraw(%v) = %v, want %v", tt.amount, got, tt.want)
 			}
@@ -142,15 +142,15 @@ func TestBalance_CanWithdraw(t *testing.T) {
 }
 
 func TestBalance_Current(t *testing.T) {
-	balance := RestoreBalance(1, 150.75, 0.0)
-	if balance.Current() != 150.75 {
+	balance := RestoreBalance(1, MustParsePoints("150.75"), 0)
+	if balance.Current() != MustParsePoints("150.75") {
 		t.Errorf("Current() = %v, want 150.75", balance.Current())
 	}
 }
 
 func TestBalance_Withdrawn(t *testing.T) {
-	balance := RestoreBalance(1, 100.0, 75.5)
-	if balance.Withdrawn() != 75.5 {
+	balance := RestoreBalance(1, MustParsePoints("100"), MustParsePoints("75.5"))
+	if balance.Withdrawn() != MustParsePoints("75.5") {
 		t.Errorf("Withdrawn() = %v, want 75.5", balance.Withdrawn())
 	}
 }
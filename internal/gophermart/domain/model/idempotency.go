@@ -0,0 +1,35 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// IdempotencyRecord — запрос, выполненный с заголовком Idempotency-Key, и его результат.
+// Повтор с тем же ключом и тем же содержимым получает сохранённый Response и не выполняется заново.
+type IdempotencyRecord struct {
+	UserID      int64
+	Key         string
+	Operation   string
+	RequestHash string
+	Response    []byte
+	CreatedAt   time.Time
+}
+
+// Matches сообщает, что запрос с отпечатком requestHash повторяет сохранённый.
+func (r *IdempotencyRecord) Matches(operation, requestHash string) bool {
+	return r.Operation == operation && r.RequestHash == requestHash
+}
+
+// IdempotencyFingerprint — отпечаток содержимого запроса: SHA-256 от операции и значимых полей.
+// Поля разделяются нулевым байтом, чтобы ("ab", "c") и ("a", "bc") давали разные отпечатки.
+func IdempotencyFingerprint(operation string, fields ...string) string {
+	h := sha256.New()
+	h.Write([]byte(operation))
+	for _, field := range fields {
+		h.Write([]byte{0})
+		h.Write([]byte(field))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,35 @@
+package model
+
+import "testing"
+
+func TestIdempotencyFingerprint(t *testing.T) {
+	base := IdempotencyFingerprint("withdraw", "79927398713", "100.00")
+
+	if got := IdempotencyFingerprint("withdraw", "79927398713", "100.00"); got != base {
+		t.Errorf("fingerprint is not stable: %s != %s", got, base)
+	}
+
+	for _, other := range []string{
+		IdempotencyFingerprint("withdraw", "79927398713", "100.01"),
+		IdempotencyFingerprint("withdraw", "7992739871", "3100.00"),
+		IdempotencyFingerprint("accrual", "79927398713", "100.00"),
+	} {
+		if other == base {
+			t.Errorf("different requests share fingerprint %s", base)
+		}
+	}
+}
+
+func TestIdempotencyRecord_Matches(t *testing.T) {
+	record := &IdempotencyRecord{Operation: "withdraw", RequestHash: "abc"}
+
+	if !record.Matches("withdraw", "abc") {
+		t.Error("expected match for the same operation and hash")
+	}
+	if record.Matches("withdraw", "abd") {
+		t.Error("expected mismatch for a different hash")
+	}
+	if record.Matches("upload", "abc") {
+		t.Error("expected mismatch for a different operation")
+	}
+}
@@ -0,0 +1,302 @@
+package model
+
+import (
+	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+)
+
+type LedgerEntryKind string
+
+const (
+	LedgerEntryKindAccrual    LedgerEntryKind = "ACCRUAL"
+	LedgerEntryKindWithdrawal LedgerEntryKind = "WITHDRAWAL"
+	LedgerEntryKindReversal   LedgerEntryKind = "REVERSAL"
+	LedgerEntryKindAdjustment LedgerEntryKind = "ADJUSTMENT"
+)
+
+// LedgerAccount — счёт, на который относится проводка. Счёт USER — баланс пользователя,
+// остальные счета — встречные стороны проводок.
+type LedgerAccount string
+
+const (
+	LedgerAccountUser          LedgerAccount = "USER"
+	LedgerAccountAccrualSource LedgerAccount = "ACCRUAL_SOURCE"
+	LedgerAccountRedemption    LedgerAccount = "REDEMPTION"
+	LedgerAccountAdjustment    LedgerAccount = "ADJUSTMENT"
+)
+
+var (
+	ErrLedgerAmountNotPositive   = domainerrors.New(domainerrors.KindValidation, "ledger amount must be positive")
+	ErrLedgerAmountZero          = domainerrors.New(domainerrors.KindValidation, "ledger amount must not be zero")
+	ErrLedgerDescriptionRequired = domainerrors.New(domainerrors.KindValidation, "adjustment description is required")
+	ErrLedgerUnbalanced          = domainerrors.New(domainerrors.KindInternal, "ledger posting is unbalanced")
+	ErrLedgerNothingToReverse    = domainerrors.New(domainerrors.KindNotFound, "ledger transaction has no entries to reverse")
+	ErrLedgerReverseReversal     = domainerrors.New(domainerrors.KindConflict, "reversal transaction cannot be reversed")
+	ErrLedgerMixedTransactions   = domainerrors.New(domainerrors.KindInternal, "entries belong to different ledger transactions")
+	ErrLedgerOrderCredited       = domainerrors.New(domainerrors.KindConflict, "order already credited")
+)
+
+type LedgerEntry struct {
+	id            int64
+	transactionID int64
+	kind          LedgerEntryKind
+	account       LedgerAccount
+	userID        int64
+	amount        Points
+	orderID       *int64
+	withdrawalID  *int64
+	reversalOf    *int64
+	description   string
+	createdAt     time.Time
+}
+
+func (e *LedgerEntry) ID() int64 {
+	return e.id
+}
+
+func (e *LedgerEntry) TransactionID() int64 {
+	return e.transactionID
+}
+
+func (e *LedgerEntry) Kind() LedgerEntryKind {
+	return e.kind
+}
+
+func (e *LedgerEntry) Account() LedgerAccount {
+	return e.account
+}
+
+func (e *LedgerEntry) UserID() int64 {
+	return e.userID
+}
+
+// Amount — сумма со знаком: положительная увеличивает счёт, отрицательная — уменьшает.
+func (e *LedgerEntry) Amount() Points {
+	return e.amount
+}
+
+func (e *LedgerEntry) OrderID() *int64 {
+	return e.orderID
+}
+
+func (e *LedgerEntry) WithdrawalID() *int64 {
+	return e.withdrawalID
+}
+
+func (e *LedgerEntry) ReversalOf() *int64 {
+	return e.reversalOf
+}
+
+func (e *LedgerEntry) Description() string {
+	return e.description
+}
+
+func (e *LedgerEntry) CreatedAt() time.Time {
+	return e.createdAt
+}
+
+func RestoreLedgerEntry(
+	id, transactionID int64,
+	kind LedgerEntryKind,
+	account LedgerAccount,
+	userID int64,
+	amount Points,
+	orderID, withdrawalID, reversalOf *int64,
+	description string,
+	createdAt time.Time,
+) *LedgerEntry {
+	return &LedgerEntry{
+		id:            id,
+		transactionID: transactionID,
+		kind:          kind,
+		account:       account,
+		userID:        userID,
+		amount:        amount,
+		orderID:       orderID,
+		withdrawalID:  withdrawalID,
+		reversalOf:    reversalOf,
+		description:   description,
+		createdAt:     createdAt,
+	}
+}
+
+// LedgerPosting — одна бухгалтерская операция: набор проводок с общим transaction_id,
+// сумма которых равна нулю.
+type LedgerPosting struct {
+	transactionID int64
+	kind          LedgerEntryKind
+	userID        int64
+	orderID       *int64
+	withdrawalID  *int64
+	reversalOf    *int64
+	description   string
+	entries       []*LedgerEntry
+}
+
+func NewAccrualPosting(userID, orderID int64, amount Points) (*LedgerPosting, error) {
+	if !amount.IsPositive() {
+		return nil, ErrLedgerAmountNotPositive
+	}
+	p := &LedgerPosting{
+		kind:    LedgerEntryKindAccrual,
+		userID:  userID,
+		orderID: &orderID,
+	}
+	p.add(LedgerAccountUser, amount)
+	p.add(LedgerAccountAccrualSource, amount.Neg())
+	return p, nil
+}
+
+func NewWithdrawalPosting(userID, withdrawalID int64, amount Points) (*LedgerPosting, error) {
+	if !amount.IsPositive() {
+		return nil, ErrLedgerAmountNotPositive
+	}
+	p := &LedgerPosting{
+		kind:         LedgerEntryKindWithdrawal,
+		userID:       userID,
+		withdrawalID: &withdrawalID,
+	}
+	p.add(LedgerAccountUser, amount.Neg())
+	p.add(LedgerAccountRedemption, amount)
+	return p, nil
+}
+
+// NewAdjustmentPosting — ручная корректировка баланса: положительная сумма начисляет баллы,
+// отрицательная — списывает.
+func NewAdjustmentPosting(userID int64, amount Points, description string) (*LedgerPosting, error) {
+	if amount.IsZero() {
+		return nil, ErrLedgerAmountZero
+	}
+	if description == "" {
+		return nil, ErrLedgerDescriptionRequired
+	}
+	p := &LedgerPosting{
+		kind:        LedgerEntryKindAdjustment,
+		userID:      userID,
+		description: description,
+	}
+	p.add(LedgerAccountUser, amount)
+	p.add(LedgerAccountAdjustment, amount.Neg())
+	return p, nil
+}
+
+// NewReversalPosting сторнирует ранее проведённую операцию: каждая проводка
+// повторяется с обратным знаком.
+func NewReversalPosting(original []*LedgerEntry, description string) (*LedgerPosting, error) {
+	if len(original) == 0 {
+		return nil, ErrLedgerNothingToReverse
+	}
+	first := original[0]
+	if first.kind == LedgerEntryKindReversal {
+		return nil, ErrLedgerReverseReversal
+	}
+	transactionID := first.transactionID
+	p := &LedgerPosting{
+		kind:         LedgerEntryKindReversal,
+		userID:       first.userID,
+		orderID:      first.orderID,
+		withdrawalID: first.withdrawalID,
+		reversalOf:   &transactionID,
+		description:  description,
+	}
+	for _, entry := range original {
+		if entry.transactionID != transactionID {
+			return nil, ErrLedgerMixedTransactions
+		}
+		p.add(entry.account, entry.amount.Neg())
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *LedgerPosting) add(account LedgerAccount, amount Points) {
+	p.entries = append(p.entries, &LedgerEntry{
+		kind:         p.kind,
+		account:      account,
+		userID:       p.userID,
+		amount:       amount,
+		orderID:      p.orderID,
+		withdrawalID: p.withdrawalID,
+		reversalOf:   p.reversalOf,
+		description:  p.description,
+	})
+}
+
+// Validate проверяет, что проводки операции сбалансированы.
+func (p *LedgerPosting) Validate() error {
+	if len(p.entries) < 2 {
+		return ErrLedgerUnbalanced
+	}
+	var total Points
+	for _, entry := range p.entries {
+		total = total.Add(entry.amount)
+	}
+	if !total.IsZero() {
+		return ErrLedgerUnbalanced
+	}
+	return nil
+}
+
+func (p *LedgerPosting) TransactionID() int64 {
+	return p.transactionID
+}
+
+func (p *LedgerPosting) Kind() LedgerEntryKind {
+	return p.kind
+}
+
+func (p *LedgerPosting) UserID() int64 {
+	return p.userID
+}
+
+func (p *LedgerPosting) OrderID() *int64 {
+	return p.orderID
+}
+
+func (p *LedgerPosting) WithdrawalID() *int64 {
+	return p.withdrawalID
+}
+
+func (p *LedgerPosting) ReversalOf() *int64 {
+	return p.reversalOf
+}
+
+func (p *LedgerPosting) Description() string {
+	return p.description
+}
+
+func (p *LedgerPosting) Entries() []*LedgerEntry {
+	return p.entries
+}
+
+// CurrentDelta — изменение текущего баланса пользователя после проведения операции.
+func (p *LedgerPosting) CurrentDelta() Points {
+	return p.accountTotal(LedgerAccountUser)
+}
+
+// WithdrawnDelta — изменение суммы списанных баллов после проведения операции.
+func (p *LedgerPosting) WithdrawnDelta() Points {
+	return p.accountTotal(LedgerAccountRedemption)
+}
+
+func (p *LedgerPosting) accountTotal(account LedgerAccount) Points {
+	var total Points
+	for _, entry := range p.entries {
+		if entry.account == account {
+			total = total.Add(entry.amount)
+		}
+	}
+	return total
+}
+
+// SetPosted фиксирует идентификаторы, присвоенные операции при записи в журнал.
+func (p *LedgerPosting) SetPosted(transactionID int64, createdAt time.Time) {
+	p.transactionID = transactionID
+	for _, entry := range p.entries {
+		entry.transactionID = transactionID
+		entry.createdAt = createdAt
+	}
+}
@@ -0,0 +1,137 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewAccrualPosting(t *testing.T) {
+	posting, err := NewAccrualPosting(1, 10, MustParsePoints("100.5"))
+	if err != nil {
+		t.Fatalf("NewAccrualPosting() error = %v", err)
+	}
+
+	if err := posting.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if posting.Kind() != LedgerEntryKindAccrual {
+		t.Errorf("Kind() = %v, want %v", posting.Kind(), LedgerEntryKindAccrual)
+	}
+	if posting.OrderID() == nil || *posting.OrderID() != 10 {
+		t.Errorf("OrderID() = %v, want 10", posting.OrderID())
+	}
+	if posting.CurrentDelta() != MustParsePoints("100.5") {
+		t.Errorf("CurrentDelta() = %v, want 100.50", posting.CurrentDelta())
+	}
+	if posting.WithdrawnDelta() != 0 {
+		t.Errorf("WithdrawnDelta() = %v, want 0", posting.WithdrawnDelta())
+	}
+
+	for _, amount := range []Points{0, MustParsePoints("-1")} {
+		if _, err := NewAccrualPosting(1, 10, amount); !errors.Is(err, ErrLedgerAmountNotPositive) {
+			t.Errorf("NewAccrualPosting(%v) error = %v, want %v", amount, err, ErrLedgerAmountNotPositive)
+		}
+	}
+}
+
+func TestNewWithdrawalPosting(t *testing.T) {
+	posting, err := NewWithdrawalPosting(1, 5, MustParsePoints("30"))
+	if err != nil {
+		t.Fatalf("NewWithdrawalPosting() error = %v", err)
+	}
+
+	if err := posting.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if posting.CurrentDelta() != MustParsePoints("-30") {
+		t.Errorf("CurrentDelta() = %v, want -30.00", posting.CurrentDelta())
+	}
+	if posting.WithdrawnDelta() != MustParsePoints("30") {
+		t.Errorf("WithdrawnDelta() = %v, want 30.00", posting.WithdrawnDelta())
+	}
+	if posting.WithdrawalID() == nil || *posting.WithdrawalID() != 5 {
+		t.Errorf("WithdrawalID() = %v, want 5", posting.WithdrawalID())
+	}
+}
+
+func TestNewAdjustmentPosting(t *testing.T) {
+	tests := []struct {
+		name        string
+		amount      Points
+		description string
+		wantErr     bool
+		wantDelta   Points
+	}{
+		{"credit", MustParsePoints("15"), "goodwill", false, MustParsePoints("15")},
+		{"debit", MustParsePoints("-7.5"), "correction", false, MustParsePoints("-7.5")},
+		{"zero amount", 0, "noop", true, 0},
+		{"missing description", MustParsePoints("1"), "", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posting, err := NewAdjustmentPosting(1, tt.amount, tt.description)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewAdjustmentPosting() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && posting.CurrentDelta() != tt.wantDelta {
+				t.Errorf("CurrentDelta() = %v, want %v", posting.CurrentDelta(), tt.wantDelta)
+			}
+		})
+	}
+}
+
+func TestNewReversalPosting(t *testing.T) {
+	withdrawalID := int64(5)
+	original := []*LedgerEntry{
+		RestoreLedgerEntry(1, 42, LedgerEntryKindWithdrawal, LedgerAccountUser, 1, MustParsePoints("-30"), nil, &withdrawalID, nil, "", time.Now()),
+		RestoreLedgerEntry(2, 42, LedgerEntryKindWithdrawal, LedgerAccountRedemption, 1, MustParsePoints("30"), nil, &withdrawalID, nil, "", time.Now()),
+	}
+
+	posting, err := NewReversalPosting(original, "cancelled")
+	if err != nil {
+		t.Fatalf("NewReversalPosting() error = %v", err)
+	}
+	if posting.ReversalOf() == nil || *posting.ReversalOf() != 42 {
+		t.Errorf("ReversalOf() = %v, want 42", posting.ReversalOf())
+	}
+	if posting.CurrentDelta() != MustParsePoints("30") {
+		t.Errorf("CurrentDelta() = %v, want 30.00", posting.CurrentDelta())
+	}
+	if posting.WithdrawnDelta() != MustParsePoints("-30") {
+		t.Errorf("WithdrawnDelta() = %v, want -30.00", posting.WithdrawnDelta())
+	}
+
+	if _, err := NewReversalPosting(nil, "empty"); !errors.Is(err, ErrLedgerNothingToReverse) {
+		t.Errorf("NewReversalPosting(nil) error = %v, want %v", err, ErrLedgerNothingToReverse)
+	}
+
+	reversalEntries := []*LedgerEntry{
+		RestoreLedgerEntry(3, 43, LedgerEntryKindReversal, LedgerAccountUser, 1, MustParsePoints("30"), nil, nil, nil, "", time.Now()),
+	}
+	if _, err := NewReversalPosting(reversalEntries, "again"); !errors.Is(err, ErrLedgerReverseReversal) {
+		t.Errorf("NewReversalPosting(reversal) error = %v, want %v", err, ErrLedgerReverseReversal)
+	}
+
+	unbalanced := original[:1]
+	if _, err := NewReversalPosting(unbalanced, "partial"); !errors.Is(err, ErrLedgerUnbalanced) {
+		t.Errorf("NewReversalPosting(partial) error = %v, want %v", err, ErrLedgerUnbalanced)
+	}
+}
+
+func TestLedgerPosting_SetPosted(t *testing.T) {
+	posting, _ := NewAccrualPosting(1, 10, MustParsePoints("1"))
+	now := time.Now()
+
+	posting.SetPosted(7, now)
+
+	if posting.TransactionID() != 7 {
+		t.Errorf("TransactionID() = %v, want 7", posting.TransactionID())
+	}
+	for _, entry := range posting.Entries() {
+		if entry.TransactionID() != 7 || !entry.CreatedAt().Equal(now) {
+			t.Errorf("entry not marked as posted: %v %v", entry.TransactionID(), entry.CreatedAt())
+		}
+	}
+}
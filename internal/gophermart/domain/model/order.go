@@ -1,8 +1,9 @@
 package model
 
 import (
-	"errors"
 	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 )
 
 type Order struct {
@@ -10,16 +11,16 @@ type Order struct {
 	userID     int64
 	number     string
 	status     OrderStatus
-	accrual    *float64
+	accrual    *Points
 	uploadedAt time.Time
 }
 
 func NewOrder(userID int64, number string) (*Order, error) {
 	if userID <= 0 {
-		return nil, errors.New("invalid user ID")
+		return nil, domainerrors.ErrInvalidUserID
 	}
 	if number == "" {
-		return nil, errors.New("order number is required")
+		return nil, domainerrors.ErrOrderNumberRequired
 	}
 
 	return &Order{
@@ -46,7 +47,7 @@ func (o *Order) Status() OrderStatus {
 	return o.status
 }
 
-func (o *Order) Accrual() *float64 {
+func (o *Order) Accrual() *Points {
 	return o.accrual
 }
 
@@ -54,20 +55,20 @@ func (o *Order) UploadedAt() time.Time {
 	return o.uploadedAt
 }
 
-func (o *Order) UpdateStatus(newStatus OrderStatus, accrual *float64) error {
-	// Бизнес-правило: нельзя перейти из PROCESSED в другой статус
-	if o.status == OrderStatusProcessed {
-		return errors.New("cannot change status of processed order")
+func (o *Order) UpdateStatus(newStatus OrderStatus, accrual *Points) error {
+	// Бизнес-правило: нельзя перейти из финального статуса (PROCESSED, INVALID) в другой статус
+	if o.status.IsFinal() {
+		return domainerrors.ErrOrderStatusFinal
 	}
 
 	// Бизнес-правило: только PROCESSED может иметь accrual, и он не может быть отрицательным
-	if newStatus == OrderStatusProcessed && accrual != nil && *accrual < 0 {
-		return errors.New("accrual cannot be negative")
+	if newStatus == OrderStatusProcessed && accrual != nil && accrual.IsNegative() {
+		return domainerrors.ErrNegativeAccrual
 	}
 
 	// Бизнес-правило: INVALID не может иметь accrual
 	if newStatus == OrderStatusInvalid && accrual != nil {
-		return errors.New("invalid order cannot have accrual")
+		return domainerrors.ErrInvalidOrderAccrual
 	}
 
 	o.status = newStatus
@@ -87,7 +88,7 @@ func (o *Order) SetID(id int64) {
 	o.id = id
 }
 
-func RestoreOrder(id, userID int64, number string, status OrderStatus, accrual *float64, uploadedAt time.Time) *Order {
+func RestoreOrder(id, userID int64, number string, status OrderStatus, accrual *Points, uploadedAt time.Time) *Order {
 	return &Order{
 		id:         id,
 		userID:     userID,
@@ -106,3 +107,35 @@ const (
 	OrderStatusInvalid    OrderStatus = "INVALID"
 	OrderStatusProcessed  OrderStatus = "PROCESSED"
 )
+
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderStatusNew, OrderStatusProcessing, OrderStatusInvalid, OrderStatusProcessed:
+		return true
+	}
+	return false
+}
+
+// IsFinal сообщает, что расчёт начисления по заказу завершён и статус больше не меняется.
+func (s OrderStatus) IsFinal() bool {
+	return s == OrderStatusProcessed || s == OrderStatusInvalid
+}
+
+// OrderStatusNote — подробности смены статуса заказа для истории: статус, который вернула
+// система начислений, и причина отказа.
+type OrderStatusNote struct {
+	AccrualStatus string
+	Reason        string
+}
+
+// OrderStatusChange — запись истории статусов заказа. PreviousStatus пуст у записи о загрузке заказа.
+type OrderStatusChange struct {
+	ID             int64
+	OrderID        int64
+	PreviousStatus OrderStatus
+	Status         OrderStatus
+	Accrual        *Points
+	AccrualStatus  string
+	Reason         string
+	CreatedAt      time.Time
+}
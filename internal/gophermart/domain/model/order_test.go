@@ -52,7 +52,7 @@ func TestRestoreOrder(t *testing.T) {
 	userID := int64(123)
 	number := "12345678903"
 	status := OrderStatusProcessed
-	accrual := 100.5
+	accrual := MustParsePoints("100.5")
 	uploadedAt := time.Now()
 
 	order := RestoreOrder(id, userID, number, status, &accrual, uploadedAt)
@@ -82,16 +82,17 @@ func TestOrder_UpdateStatus(t *testing.T) {
 		name          string
 		initialStatus OrderStatus
 		newStatus     OrderStatus
-		accrual       *float64
+		accrual       *Points
 		wantErr       bool
 	}{
 		{"new to processing", OrderStatusNew, OrderStatusProcessing, nil, false},
-		{"processing to processed", OrderStatusProcessing, OrderStatusProcessed, floatPtr(100.0), false},
+		{"processing to processed", OrderStatusProcessing, OrderStatusProcessed, pointsPtr(MustParsePoints("100")), false},
 		{"processing to invalid", OrderStatusProcessing, OrderStatusInvalid, nil, false},
 		{"cannot change processed", OrderStatusProcessed, OrderStatusNew, nil, true},
 		{"processed to processing", OrderStatusProcessed, OrderStatusProcessing, nil, true},
-		{"negative accrual", OrderStatusProcessing, OrderStatusProcessed, floatPtr(-10.0), true},
-		{"invalid with accrual", OrderStatusProcessing, OrderStatusInvalid, floatPtr(100.0), true},
+		{"cannot change invalid", OrderStatusInvalid, OrderStatusProcessed, pointsPtr(MustParsePoints("100")), true},
+		{"negative accrual", OrderStatusProcessing, OrderStatusProcessed, pointsPtr(MustParsePoints("-10")), true},
+		{"invalid with accrual", OrderStatusProcessing, OrderStatusInvalid, pointsPtr(MustParsePoints("100")), true},
 		{"processed with nil accrual", OrderStatusProcessing, OrderStatusProcessed, nil, false},
 	}
 
@@ -209,7 +210,7 @@ func TestOrder_Status(t *testing.T) {
 }
 
 func TestOrder_Accrual(t *testing.T) {
-	accrual := 150.75
+	accrual := MustParsePoints("150.75")
 	order := RestoreOrder(1, 1, "12345678903", OrderStatusProcessed, &accrual, time.Now())
 	if order.Accrual() == nil || *order.Accrual() != accrual {
 		t.Errorf("Accrual() = %v, want %v", order.Accrual(), accrual)
@@ -229,6 +230,26 @@ func TestOrder_UploadedAt(t *testing.T) {
 	}
 }
 
-func floatPtr(f float64) *float64 {
+func pointsPtr(f Points) *Points {
 	return &f
 }
+
+func TestOrderStatus_IsValid(t *testing.T) {
+	tests := []struct {
+		status OrderStatus
+		want   bool
+	}{
+		{OrderStatusNew, true},
+		{OrderStatusProcessing, true},
+		{OrderStatusInvalid, true},
+		{OrderStatusProcessed, true},
+		{"REGISTERED", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.status.IsValid(); got != tt.want {
+			t.Errorf("OrderStatus(%q).IsValid() = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
@@ -3,20 +3,65 @@ package model
 import "time"
 
 type Outbox struct {
-	ID        int64
-	OrderID   int64
-	Status    OutboxStatus
-	Retries   int
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID             int64
+	OrderID        int64
+	OrderNumber    string
+	Status         OutboxStatus
+	Retries        int
+	NextAttemptAt  time.Time
+	LeaseOwner     string
+	LeaseExpiresAt *time.Time
+	LastError      string
+	// PollingSince — начало текущего опроса: создание записи или последний ручной перезапуск.
+	PollingSince   time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
+// OutboxStatus — состояние опроса заказа в системе начислений. IN_FLIGHT означает,
+// что заказ ещё не получил финальный статус и будет опрошен повторно после NextAttemptAt;
+// REVIEW — финальный статус не получен за допустимое время и нужна ручная проверка;
+// DEAD — исчерпаны попытки обработки, причина сохранена в LastError.
 type OutboxStatus string
 
 const (
-	OutboxStatusPending  OutboxStatus = "PENDING"
+	OutboxStatusPending   OutboxStatus = "PENDING"
+	OutboxStatusInFlight  OutboxStatus = "IN_FLIGHT"
 	OutboxStatusProcessed OutboxStatus = "PROCESSED"
-	OutboxStatusFailed    OutboxStatus = "FAILED"
+	OutboxStatusDead      OutboxStatus = "DEAD"
+	OutboxStatusReview    OutboxStatus = "REVIEW"
 )
 
+func (s OutboxStatus) IsValid() bool {
+	switch s {
+	case OutboxStatusPending, OutboxStatusInFlight, OutboxStatusProcessed, OutboxStatusDead, OutboxStatusReview:
+		return true
+	}
+	return false
+}
+
+// IsRequeueable сообщает, можно ли вручную вернуть запись в очередь обработки.
+func (s OutboxStatus) IsRequeueable() bool {
+	return s == OutboxStatusDead || s == OutboxStatusReview
+}
+
+type OutboxAuditAction string
+
+const (
+	OutboxAuditActionRequeue OutboxAuditAction = "REQUEUE"
+)
+
+// OutboxAuditEntry — запись журнала ручных действий администратора с outbox.
+// Previous* хранят состояние записи до действия.
+type OutboxAuditEntry struct {
+	ID              int64
+	OutboxID        int64
+	Action          OutboxAuditAction
+	ActorUserID     int64
+	PreviousStatus  OutboxStatus
+	PreviousRetries int
+	PreviousError   string
+	CreatedAt       time.Time
+}
+
 
@@ -0,0 +1,213 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+)
+
+// Points — сумма баллов лояльности с фиксированной точностью до сотых
+// (1 балл = 1 рубль). Хранится в сотых долях, что совпадает с DECIMAL(10,2) в БД.
+type Points int64
+
+const (
+	pointsScale     = 100
+	pointsFracDigit = 2
+	maxExponent     = 64
+)
+
+// MaxPoints — наибольшая по модулю сумма, которую вмещают столбцы DECIMAL(10,2).
+// Большие суммы отклоняются при разборе, а не ошибкой PostgreSQL при записи.
+const MaxPoints Points = 99_999_999_99
+
+var (
+	ErrInvalidPoints   = domainerrors.New(domainerrors.KindInvalidInput, "invalid points amount")
+	ErrPointsPrecision = domainerrors.New(domainerrors.KindValidation, "points amount has more than two decimal places")
+	ErrPointsOverflow  = domainerrors.New(domainerrors.KindValidation, "points amount is out of range")
+)
+
+func PointsFromCents(cents int64) Points {
+	return Points(cents)
+}
+
+// ParsePoints разбирает десятичную запись суммы. Суммы с точностью выше сотых отклоняются.
+func ParsePoints(s string) (Points, error) {
+	return parsePoints(s, false)
+}
+
+// RoundPoints разбирает десятичную запись суммы и округляет её до сотых
+// (половина — от нуля, как при приведении к DECIMAL(10,2) в PostgreSQL).
+func RoundPoints(s string) (Points, error) {
+	return parsePoints(s, true)
+}
+
+func MustParsePoints(s string) Points {
+	p, err := ParsePoints(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func (p Points) Cents() int64 {
+	return int64(p)
+}
+
+func (p Points) Float64() float64 {
+	return float64(p) / pointsScale
+}
+
+func (p Points) IsZero() bool {
+	return p == 0
+}
+
+func (p Points) IsPositive() bool {
+	return p > 0
+}
+
+func (p Points) IsNegative() bool {
+	return p < 0
+}
+
+func (p Points) Add(other Points) Points {
+	return p + other
+}
+
+func (p Points) Sub(other Points) Points {
+	return p - other
+}
+
+func (p Points) Neg() Points {
+	return -p
+}
+
+// String возвращает сумму с двумя знаками после точки, например "100.50".
+func (p Points) String() string {
+	sign := ""
+	abs := uint64(p)
+	if p < 0 {
+		sign = "-"
+		abs = uint64(-p)
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, abs/pointsScale, abs%pointsScale)
+}
+
+// MarshalJSON кодирует сумму JSON-числом без лишних нулей: 500.5, 42, 0.01.
+func (p Points) MarshalJSON() ([]byte, error) {
+	s := p.String()
+	s = strings.TrimRight(s, "0")
+	s = strings.TrimSuffix(s, ".")
+	return []byte(s), nil
+}
+
+func (p *Points) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		return ErrInvalidPoints
+	}
+	parsed, err := ParsePoints(s)
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+func (p *Points) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*p = 0
+		return nil
+	case string:
+		parsed, err := RoundPoints(v)
+		if err != nil {
+			return err
+		}
+		*p = parsed
+		return nil
+	case []byte:
+		return p.Scan(string(v))
+	case int64:
+		return p.Scan(strconv.FormatInt(v, 10))
+	case float64:
+		return p.Scan(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		return fmt.Errorf("cannot scan %T into points", src)
+	}
+}
+
+func (p Points) Value() (driver.Value, error) {
+	return p.String(), nil
+}
+
+func parsePoints(s string, round bool) (Points, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidPoints
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	mantissa, exponent := s, 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		mantissa = s[:i]
+		exp, err := strconv.Atoi(s[i+1:])
+		if err != nil || exp > maxExponent || exp < -maxExponent {
+			return 0, ErrInvalidPoints
+		}
+		exponent = exp
+	}
+
+	intPart, fracPart, _ := strings.Cut(mantissa, ".")
+	if intPart == "" && fracPart == "" {
+		return 0, ErrInvalidPoints
+	}
+	digits := intPart + fracPart
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return 0, ErrInvalidPoints
+		}
+	}
+
+	value, _ := new(big.Int).SetString(digits, 10)
+	shift := exponent - len(fracPart) + pointsFracDigit
+
+	ten := big.NewInt(10)
+	if shift >= 0 {
+		value.Mul(value, new(big.Int).Exp(ten, big.NewInt(int64(shift)), nil))
+	} else {
+		divisor := new(big.Int).Exp(ten, big.NewInt(int64(-shift)), nil)
+		remainder := new(big.Int)
+		value.QuoRem(value, divisor, remainder)
+		if remainder.Sign() != 0 {
+			if !round {
+				return 0, ErrPointsPrecision
+			}
+			if remainder.Mul(remainder, big.NewInt(2)).Cmp(divisor) >= 0 {
+				value.Add(value, big.NewInt(1))
+			}
+		}
+	}
+
+	if negative {
+		value.Neg(value)
+	}
+	if value.CmpAbs(big.NewInt(int64(MaxPoints))) > 0 {
+		return 0, ErrPointsOverflow
+	}
+	return Points(value.Int64()), nil
+}
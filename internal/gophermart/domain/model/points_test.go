@@ -0,0 +1,167 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParsePoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Points
+		wantErr error
+	}{
+		{"integer", "42", 4200, nil},
+		{"one decimal", "500.5", 50050, nil},
+		{"two decimals", "0.01", 1, nil},
+		{"negative", "-10.25", -1025, nil},
+		{"exponent", "1.5e2", 15000, nil},
+		{"exponent with trailing zeros", "1.230e1", 1230, nil},
+		{"three decimals", "0.001", 0, ErrPointsPrecision},
+		{"float artifact", "0.30000000000000004", 0, ErrPointsPrecision},
+		{"empty", "", 0, ErrInvalidPoints},
+		{"letters", "12a", 0, ErrInvalidPoints},
+		{"dot only", ".", 0, ErrInvalidPoints},
+		{"overflow", "1e30", 0, ErrPointsOverflow},
+		{"max", "99999999.99", MaxPoints, nil},
+		{"above column precision", "100000000", 0, ErrPointsOverflow},
+		{"below column precision", "-100000000", 0, ErrPointsOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePoints(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParsePoints(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePoints(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundPoints(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Points
+	}{
+		{"729.985", 72999},
+		{"729.984", 72998},
+		{"-0.005", -1},
+		{"0.3333333", 33},
+		{"100", 10000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := RoundPoints(tt.input)
+			if err != nil {
+				t.Fatalf("RoundPoints(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("RoundPoints(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPoints_Arithmetic(t *testing.T) {
+	sum := Points(0)
+	for i := 0; i < 10; i++ {
+		sum = sum.Add(MustParsePoints("0.1"))
+	}
+	if sum != MustParsePoints("1") {
+		t.Errorf("sum = %v, want 1.00", sum)
+	}
+
+	if got := MustParsePoints("100").Sub(MustParsePoints("0.01")); got.String() != "99.99" {
+		t.Errorf("Sub() = %v, want 99.99", got)
+	}
+}
+
+func TestPoints_String(t *testing.T) {
+	tests := []struct {
+		value Points
+		want  string
+	}{
+		{0, "0.00"},
+		{1, "0.01"},
+		{50050, "500.50"},
+		{-1025, "-10.25"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.value.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestPoints_JSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		A Points  `json:"a"`
+		B Points  `json:"b"`
+		C Points  `json:"c"`
+		D *Points `json:"d,omitempty"`
+	}{A: 50050, B: 4200, C: 1})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(data) != `{"a":500.5,"b":42,"c":0.01}` {
+		t.Errorf("Marshal() = %s", data)
+	}
+
+	var req struct {
+		Sum Points `json:"sum"`
+	}
+	if err := json.Unmarshal([]byte(`{"sum": 751.25}`), &req); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if req.Sum != 75125 {
+		t.Errorf("Sum = %v, want 751.25", req.Sum)
+	}
+
+	if err := json.Unmarshal([]byte(`{"sum": 0.001}`), &req); !errors.Is(err, ErrPointsPrecision) {
+		t.Errorf("Unmarshal() error = %v, want %v", err, ErrPointsPrecision)
+	}
+	if err := json.Unmarshal([]byte(`{"sum": "10"}`), &req); !errors.Is(err, ErrInvalidPoints) {
+		t.Errorf("Unmarshal() error = %v, want %v", err, ErrInvalidPoints)
+	}
+}
+
+func TestPoints_ScanValue(t *testing.T) {
+	tests := []struct {
+		name string
+		src  any
+		want Points
+	}{
+		{"nil", nil, 0},
+		{"numeric text", "100.50", 10050},
+		{"bytes", []byte("0.01"), 1},
+		{"int64", int64(7), 700},
+		{"float64", 12.34, 1234},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p Points
+			if err := p.Scan(tt.src); err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if p != tt.want {
+				t.Errorf("Scan() = %v, want %v", p, tt.want)
+			}
+		})
+	}
+
+	v, err := MustParsePoints("100.5").Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	if v != "100.50" {
+		t.Errorf("Value() = %v, want 100.50", v)
+	}
+}
@@ -1,27 +1,28 @@
 package model
 
 import (
-	"errors"
 	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 )
 
 type Withdrawal struct {
 	id          int64
 	userID      int64
 	orderNumber string
-	sum         float64
+	sum         Points
 	processedAt time.Time
 }
 
-func NewWithdrawal(userID int64, orderNumber string, sum float64) (*Withdrawal, error) {
+func NewWithdrawal(userID int64, orderNumber string, sum Points) (*Withdrawal, error) {
 	if userID <= 0 {
-		return nil, errors.New("invalid user ID")
+		return nil, domainerrors.ErrInvalidUserID
 	}
 	if orderNumber == "" {
-		return nil, errors.New("order number is required")
+		return nil, domainerrors.ErrOrderNumberRequired
 	}
-	if sum <= 0 {
-		return nil, errors.New("withdrawal sum must be positive")
+	if !sum.IsPositive() {
+		return nil, domainerrors.ErrWithdrawalSumRequired
 	}
 
 	return &Withdrawal{
@@ -44,7 +45,7 @@ func (w *Withdrawal) OrderNumber() string {
 	return w.orderNumber
 }
 
-func (w *Withdrawal) Sum() float64 {
+func (w *Withdrawal) Sum() Points {
 	return w.sum
 }
 
@@ -56,7 +57,7 @@ func (w *Withdrawal) SetID(id int64) {
 	w.id = id
 }
 
-func RestoreWithdrawal(id, userID int64, orderNumber string, sum float64, processedAt time.Time) *Withdrawal {
+func RestoreWithdrawal(id, userID int64, orderNumber string, sum Points, processedAt time.Time) *Withdrawal {
 	return &Withdrawal{
 		id:          id,
 		userID:      userID,
@@ -10,17 +10,17 @@ func TestNewWithdrawal(t *testing.T) {
 		name        string
 		userID      int64
 		orderNumber string
-		sum         float64
+		sum         Points
 		wantErr     bool
 	}{
-		{"valid withdrawal", 1, "12345678903", 100.0, false},
-		{"another valid", 999, "79927398713", 50.5, false},
-		{"invalid user ID zero", 0, "12345678903", 100.0, true},
-		{"invalid user ID negative", -1, "12345678903", 100.0, true},
-		{"empty order number", 1, "", 100.0, true},
-		{"zero sum", 1, "12345678903", 0.0, true},
-		{"negative sum", 1, "12345678903", -10.0, true},
-		{"small positive sum", 1, "12345678903", 0.01, false},
+		{"valid withdrawal", 1, "12345678903", MustParsePoints("100"), false},
+		{"another valid", 999, "79927398713", MustParsePoints("50.5"), false},
+		{"invalid user ID zero", 0, "12345678903", MustParsePoints("100"), true},
+		{"invalid user ID negative", -1, "12345678903", MustParsePoints("100"), true},
+		{"empty order number", 1, "", MustParsePoints("100"), true},
+		{"zero sum", 1, "12345678903", 0, true},
+		{"negative sum", 1, "12345678903", MustParsePoints("-10"), true},
+		{"small positive sum", 1, "12345678903", MustParsePoints("0.01"), false},
 	}
 
 	for _, tt := range tests {
@@ -52,7 +52,7 @@ func TestRestoreWithdrawal(t *testing.T) {
 	id := int64(1)
 	userID := int64(123)
 	orderNumber := "12345678903"
-	sum := 150.75
+	sum := MustParsePoints("150.75")
 	processedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
 
 	withdrawal := RestoreWithdrawal(id, userID, orderNumber, sum, processedAt)
@@ -75,7 +75,7 @@ func TestRestoreWithdrawal(t *testing.T) {
 }
 
 func TestWithdrawal_SetID(t *testing.T) {
-	withdrawal := RestoreWithdrawal(1, 1, "12345678903", 100.0, time.Now())
+	withdrawal := RestoreWithdrawal(1, 1, "12345678903", MustParsePoints("100"), time.Now())
 	newID := int64(999)
 
 	withdrawal.SetID(newID)
@@ -87,7 +87,7 @@ func TestWithdrawal_SetID(t *testing.T) {
 
 func TestWithdrawal_ID(t *testing.T) {
 	id := int64(123)
-	withdrawal := RestoreWithdrawal(id, 1, "12345678903", 100.0, time.Now())
+	withdrawal := RestoreWithdrawal(id, 1, "12345678903", MustParsePoints("100"), time.Now())
 	if withdrawal.ID() != id {
 		t.Errorf("ID() = %v, want %v", withdrawal.ID(), id)
 	}
@@ -95,7 +95,7 @@ func TestWithdrawal_ID(t *testing.T) {
 
 func TestWithdrawal_UserID(t *testing.T) {
 	userID := int64(456)
-	withdrawal := RestoreWithdrawal(1, userID, "12345678903", 100.0, time.Now())
+	withdrawal := RestoreWithdrawal(1, userID, "12345678903", MustParsePoints("100"), time.Now())
 	if withdrawal.UserID() != userID {
 		t.Errorf("UserID() = %v, want %v", withdrawal.UserID(), userID)
 	}
@@ -103,14 +103,14 @@ func TestWithdrawal_UserID(t *testing.T) {
 
 func TestWithdrawal_OrderNumber(t *testing.T) {
 	orderNumber := "79927398713"
-	withdrawal := RestoreWithdrawal(1, 1, orderNumber, 100.0, time.Now())
+	withdrawal := RestoreWithdrawal(1, 1, orderNumber, MustParsePoints("100"), time.Now())
 	if withdrawal.OrderNumber() != orderNumber {
 		t.Errorf("OrderNumber() = %v, want %v", withdrawal.OrderNumber(), orderNumber)
 	}
 }
 
 func TestWithdrawal_Sum(t *testing.T) {
-	sum := 250.5
+	sum := MustParsePoints("250.5")
 	withdrawal := RestoreWithdrawal(1, 1, "12345678903", sum, time.Now())
 	if withdrawal.Sum() != sum {
 		t.Errorf("Sum() = %v, want %v", withdrawal.Sum(), sum)
@@ -119,7 +119,7 @@ func TestWithdrawal_Sum(t *testing.T) {
 
 func TestWithdrawal_ProcessedAt(t *testing.T) {
 	processedAt := time.Date(2024, 2, 20, 15, 45, 0, 0, time.UTC)
-	withdrawal := RestoreWithdrawal(1, 1, "12345678903", 100.0, processedAt)
+	withdrawal := RestoreWithdrawal(1, 1, "12345678903", MustParsePoints("100"), processedAt)
 	if !withdrawal.ProcessedAt().Equal(processedAt) {
 		t.Errorf("ProcessedAt() = %v, want %v", withdrawal.ProcessedAt(), processedAt)
 	}
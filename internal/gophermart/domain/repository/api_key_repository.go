@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *model.APIKey) error
+	// FindByHash возвращает nil, nil, если ключа с таким хешем нет.
+	FindByHash(ctx context.Context, keyHash string) (*model.APIKey, error)
+	List(ctx context.Context) ([]*model.APIKey, error)
+	// Revoke отзывает ключ и возвращает false, если ключ не найден или уже отозван.
+	Revoke(ctx context.Context, id int64, at time.Time) (bool, error)
+	TouchLastUsed(ctx context.Context, id int64, at time.Time) error
+	// Grant разрешает ключу id действовать от имени userID. Возвращает false, если ключ
+	// не найден, отозван или истёк к моменту at. Повторное разрешение ничего не меняет.
+	Grant(ctx context.Context, id, userID int64, at time.Time) (bool, error)
+	// RevokeGrant отменяет разрешение и возвращает false, если его не было.
+	RevokeGrant(ctx context.Context, id, userID int64) (bool, error)
+	IsGranted(ctx context.Context, id, userID int64) (bool, error)
+}
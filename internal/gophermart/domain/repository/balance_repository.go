@@ -5,10 +5,14 @@ import (
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 )
 
+// BalanceRepository — проекция балансов, построенная по журналу проводок.
+// Изменяется только через LedgerRepository.Post, Rebuild пересчитывает её из журнала.
 type BalanceRepository interface {
 	GetByUserID(ctx context.Context, userID int64) (*model.Balance, error)
-	Withdraw(ctx context.Context, userID int64, amount float64) error
-	Accrue(ctx context.Context, userID int64, amount float64) error
+	// GetByUserIDForUpdate читает баланс и блокирует его строку до конца транзакции, чтобы
+	// параллельные списания проверяли остаток по очереди. Имеет смысл только внутри Transaction.
+	GetByUserIDForUpdate(ctx context.Context, userID int64) (*model.Balance, error)
+	Rebuild(ctx context.Context, userID int64) error
+	RebuildAll(ctx context.Context) (int64, error)
 }
 
-
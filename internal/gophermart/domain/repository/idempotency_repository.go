@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+)
+
+type IdempotencyRepository interface {
+	// Reserve занимает ключ и возвращает nil, nil. Если ключ пользователя уже занят, возвращается
+	// сохранённая запись. Внутри транзакции конкурирующий запрос с тем же ключом ждёт,
+	// пока первая транзакция не завершится, и получает её результат.
+	Reserve(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error)
+	SaveResponse(ctx context.Context, userID int64, key string, response []byte) error
+}
@@ -0,0 +1,8 @@
+package repository
+
+// Iterator построчно читает результат запроса, не загружая его в память целиком.
+// Next возвращает io.EOF, когда строки закончились; Close обязателен в любом случае.
+type Iterator[T any] interface {
+	Next() (T, error)
+	Close() error
+}
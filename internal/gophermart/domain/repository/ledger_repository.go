@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+)
+
+type LedgerRepository interface {
+	Post(ctx context.Context, posting *model.LedgerPosting) error
+	FindByUserID(ctx context.Context, userID int64) ([]*model.LedgerEntry, error)
+	FindByTransactionID(ctx context.Context, transactionID int64) ([]*model.LedgerEntry, error)
+}
+
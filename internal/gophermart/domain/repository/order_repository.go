@@ -2,15 +2,46 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 )
 
+// OrderCursor — позиция в списке заказов, отсортированном по uploaded_at и id по убыванию.
+type OrderCursor struct {
+	UploadedAt time.Time
+	ID         int64
+}
+
+// OrderFilter ограничивает выборку заказов пользователя. Пустые поля не фильтруют:
+// From включается в интервал, To — нет; After возвращает заказы строго после курсора.
+type OrderFilter struct {
+	Status model.OrderStatus
+	From   time.Time
+	To     time.Time
+	After  *OrderCursor
+	Limit  int
+}
+
 type OrderRepository interface {
 	Create(ctx context.Context, order *model.Order) error
 	FindByUserID(ctx context.Context, userID int64) ([]*model.Order, error)
+	// FindByUserIDIterator отдаёт заказы пользователя по одному, от новых к старым.
+	FindByUserIDIterator(ctx context.Context, userID int64, filter OrderFilter) (Iterator[*model.Order], error)
 	FindByNumber(ctx context.Context, number string) (*model.Order, error)
 	FindByID(ctx context.Context, id int64) (*model.Order, error)
-	UpdateStatus(ctx context.Context, orderID int64, status model.OrderStatus, accrual *float64) error
+	// UpdateStatus меняет статус заказа, только если он ещё не финальный.
+	// Возвращает false, если заказ уже был финализирован ранее.
+	// Если статус действительно изменился, переход записывается в историю вместе с note.
+	UpdateStatus(ctx context.Context, orderID int64, status model.OrderStatus, accrual *model.Points, note model.OrderStatusNote) (bool, error)
+	// FindStatusHistory возвращает историю статусов заказа от старых записей к новым.
+	FindStatusHistory(ctx context.Context, orderID int64) ([]*model.OrderStatusChange, error)
 	FindPending(ctx context.Context, limit int) ([]*model.Order, error)
+	// HasPendingByUserID сообщает, есть ли у пользователя заказы, начисление по которым не завершено.
+	HasPendingByUserID(ctx context.Context, userID int64) (bool, error)
+	// LockUser блокирует до конца транзакции приём заказов пользователя и удаление его учётной
+	// записи, чтобы удаление не пропустило заказ, загруженный одновременно с ним.
+	// Имеет смысл только внутри Transaction.
+	LockUser(ctx context.Context, userID int64) error
 }
 
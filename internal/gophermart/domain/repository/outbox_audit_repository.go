@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+)
+
+type OutboxAuditRepository interface {
+	Record(ctx context.Context, entry *model.OutboxAuditEntry) error
+	FindByOutboxID(ctx context.Context, outboxID int64) ([]*model.OutboxAuditEntry, error)
+}
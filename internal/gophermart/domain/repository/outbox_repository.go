@@ -2,14 +2,42 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 )
 
+// OutboxFilter ограничивает выборку записей outbox. Пустые поля не фильтруют.
+type OutboxFilter struct {
+	Status      model.OutboxStatus
+	OrderNumber string
+	OlderThan   time.Duration
+	Limit       int
+}
+
 type OutboxRepository interface {
 	Create(ctx context.Context, outbox *model.Outbox) error
 	FindPending(ctx context.Context, limit int) ([]*model.Outbox, error)
-	UpdateStatus(ctx context.Context, outboxID int64, status model.OutboxStatus) error
-	IncrementRetries(ctx context.Context, outboxID int64) error
+	// ClaimBatch захватывает до limit готовых к обработке записей в аренду owner на leaseTTL.
+	// Записи, захваченные другими обработчиками, пропускаются до истечения их аренды.
+	ClaimBatch(ctx context.Context, owner string, limit int, leaseTTL time.Duration) ([]*model.Outbox, error)
+	// UpdateStatus, ScheduleRetry, MarkDead, ScheduleNextAttempt и Postpone освобождают аренду и меняют
+	// запись, только пока она арендована owner; иначе возвращается ErrOutboxLeaseLost.
+	UpdateStatus(ctx context.Context, outboxID int64, owner string, status model.OutboxStatus) error
+	// ScheduleRetry увеличивает счётчик попыток и откладывает следующую попытку на delay.
+	ScheduleRetry(ctx context.Context, outboxID int64, owner string, delay time.Duration, lastError string) error
+	// MarkDead прекращает обработку записи, сохраняя причину последней ошибки.
+	MarkDead(ctx context.Context, outboxID int64, owner string, lastError string) error
+	ScheduleNextAttempt(ctx context.Context, outboxID int64, owner string, delay time.Duration) error
+	// Postpone откладывает следующую попытку на delay, не меняя статус и счётчик попыток.
+	Postpone(ctx context.Context, outboxID int64, owner string, delay time.Duration) error
+	FindByID(ctx context.Context, outboxID int64) (*model.Outbox, error)
+	List(ctx context.Context, filter OutboxFilter) ([]*model.Outbox, error)
+	// Requeue возвращает в PENDING со сброшенным счётчиком попыток записи в статусах DEAD и REVIEW
+	// из ids и возвращает их состояние до перезапуска. Остальные записи не изменяются.
+	Requeue(ctx context.Context, ids []int64) ([]*model.Outbox, error)
+	// RequeueByStatus делает то же для всех записей в статусе status.
+	RequeueByStatus(ctx context.Context, status model.OutboxStatus) ([]*model.Outbox, error)
 }
 
 
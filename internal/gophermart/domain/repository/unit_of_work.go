@@ -6,6 +6,11 @@ import (
 
 type UnitOfWork interface {
 	Begin(ctx context.Context) (Transaction, error)
+	// Do выполняет fn в транзакции и фиксирует её, если fn не вернула ошибку. Транзакция,
+	// прерванная базой из-за конфликта сериализации или взаимной блокировки, повторяется
+	// целиком, поэтому fn может быть вызвана несколько раз и не должна иметь побочных
+	// эффектов вне транзакции.
+	Do(ctx context.Context, fn func(tx Transaction) error) error
 }
 
 type Transaction interface {
@@ -13,6 +18,9 @@ type Transaction interface {
 	OutboxRepository() OutboxRepository
 	WithdrawalRepository() WithdrawalRepository
 	BalanceRepository() BalanceRepository
+	LedgerRepository() LedgerRepository
+	OutboxAuditRepository() OutboxAuditRepository
+	IdempotencyRepository() IdempotencyRepository
 	Commit(ctx context.Context) error
 	Rollback(ctx context.Context) error
 }
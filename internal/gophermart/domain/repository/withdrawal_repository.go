@@ -2,12 +2,33 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 )
 
+// WithdrawalCursor — позиция в списке списаний, отсортированном по processed_at и id по убыванию.
+type WithdrawalCursor struct {
+	ProcessedAt time.Time
+	ID          int64
+}
+
+// WithdrawalFilter ограничивает выборку списаний пользователя. Пустые поля не фильтруют:
+// From включается в интервал, To — нет; MinSum и MaxSum — включительно.
+type WithdrawalFilter struct {
+	From   time.Time
+	To     time.Time
+	MinSum *model.Points
+	MaxSum *model.Points
+	After  *WithdrawalCursor
+	Limit  int
+}
+
 type WithdrawalRepository interface {
 	Create(ctx context.Context, withdrawal *model.Withdrawal) error
 	FindByUserID(ctx context.Context, userID int64) ([]*model.Withdrawal, error)
+	// FindByUserIDIterator отдаёт списания пользователя по одному, от новых к старым.
+	FindByUserIDIterator(ctx context.Context, userID int64, filter WithdrawalFilter) (Iterator[*model.Withdrawal], error)
 }
 
 
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// AccountProfile — персональные данные пользователя из сервиса пользователей.
+type AccountProfile struct {
+	Login        string
+	FirstName    string
+	LastName     string
+	Email        string
+	RegisteredAt time.Time
+}
+
+// AccountDirectory даёт доступ к учётным записям сервиса пользователей.
+// Для неизвестного или удалённого пользователя возвращается domainerrors.ErrAccountNotFound.
+type AccountDirectory interface {
+	GetProfile(ctx context.Context, userID int64) (*AccountProfile, error)
+	// Delete отзывает токены пользователя и обезличивает его учётную запись.
+	Delete(ctx context.Context, userID int64) error
+}
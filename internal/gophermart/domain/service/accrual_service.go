@@ -2,6 +2,10 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 )
 
@@ -9,3 +13,16 @@ type AccrualService interface {
 	GetOrderInfo(ctx context.Context, orderNumber string) (*model.AccrualResponse, error)
 }
 
+// RateLimitError возвращается, когда система начислений ответила 429. Запрос следует
+// повторить после RetryAfter; такая ошибка не считается неудачной попыткой обработки.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limited, retry after %d seconds", int(e.RetryAfter.Seconds()))
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return domainerrors.ErrRateLimited
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// Роли пользователя в claims токена; совпадают с ролями сервиса пользователей.
+const (
+	RoleUser    = "user"
+	RoleSupport = "support"
+	RoleAdmin   = "admin"
+)
+
+type TokenClaims struct {
+	UserID int64
+	Login  string
+	Roles  []string
+	// ExpiresAt — момент истечения токена; нулевое значение, если срок неизвестен.
+	ExpiresAt time.Time
+}
+
+// HasAnyRole сообщает, есть ли у пользователя хотя бы одна из перечисленных ролей.
+func (c *TokenClaims) HasAnyRole(roles ...string) bool {
+	for _, have := range c.Roles {
+		for _, want := range roles {
+			if have == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TokenVerifier проверяет токен доступа и возвращает данные пользователя.
+// Для недействительного токена возвращается ошибка domainerrors.ErrInvalidToken.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (*TokenClaims, error)
+}
@@ -0,0 +1,53 @@
+package account
+
+import (
+	"context"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
+	userserviceusecase "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/application/usecase"
+	userserviceerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	userservicerepository "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+)
+
+// LocalAccountDirectory обращается к учётным записям сервиса пользователей в процессе.
+type LocalAccountDirectory struct {
+	userRepo        userservicerepository.UserRepository
+	deleteAccountUC *userserviceusecase.DeleteAccountUseCase
+}
+
+func NewLocalAccountDirectory(
+	userRepo userservicerepository.UserRepository,
+	deleteAccountUseCase *userserviceusecase.DeleteAccountUseCase,
+) *LocalAccountDirectory {
+	return &LocalAccountDirectory{
+		userRepo:        userRepo,
+		deleteAccountUC: deleteAccountUseCase,
+	}
+}
+
+func (d *LocalAccountDirectory) GetProfile(ctx context.Context, userID int64) (*service.AccountProfile, error) {
+	user, err := d.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, mapUserError(err)
+	}
+	return &service.AccountProfile{
+		Login:        user.Login,
+		FirstName:    user.FirstName,
+		LastName:     user.LastName,
+		Email:        user.Email,
+		RegisteredAt: user.CreatedAt,
+	}, nil
+}
+
+func (d *LocalAccountDirectory) Delete(ctx context.Context, userID int64) error {
+	err := d.deleteAccountUC.Execute(ctx, userserviceusecase.DeleteAccountRequest{UserID: userID})
+	return mapUserError(err)
+}
+
+func mapUserError(err error) error {
+	if userserviceerrors.Is(err, userserviceerrors.ErrUserNotFound) {
+		return domainerrors.ErrAccountNotFound
+	}
+	return err
+}
@@ -0,0 +1,29 @@
+package account
+
+import (
+	"context"
+
+	gophermartusecase "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/application/usecase"
+	userservicemodel "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+)
+
+// LocalBalanceProvider отдаёт сервису пользователей баланс для профиля тем же use case,
+// что и GET /api/user/balance, без HTTP-запроса.
+type LocalBalanceProvider struct {
+	getBalanceUseCase *gophermartusecase.GetBalanceUseCase
+}
+
+func NewLocalBalanceProvider(getBalanceUseCase *gophermartusecase.GetBalanceUseCase) *LocalBalanceProvider {
+	return &LocalBalanceProvider{getBalanceUseCase: getBalanceUseCase}
+}
+
+func (p *LocalBalanceProvider) GetBalanceSummary(ctx context.Context, userID int64) (*userservicemodel.BalanceSummary, error) {
+	resp, err := p.getBalanceUseCase.Execute(ctx, gophermartusecase.GetBalanceRequest{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	return &userservicemodel.BalanceSummary{
+		Current:   resp.Current.String(),
+		Withdrawn: resp.Withdrawn.String(),
+	}, nil
+}
@@ -0,0 +1,44 @@
+package account
+
+import (
+	"context"
+	"testing"
+
+	gophermartusecase "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/application/usecase"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+)
+
+type fakeBalanceRepository struct {
+	balance *model.Balance
+}
+
+func (r *fakeBalanceRepository) GetByUserID(_ context.Context, userID int64) (*model.Balance, error) {
+	return r.balance, nil
+}
+
+func (r *fakeBalanceRepository) GetByUserIDForUpdate(_ context.Context, userID int64) (*model.Balance, error) {
+	return r.balance, nil
+}
+
+func (r *fakeBalanceRepository) Rebuild(context.Context, int64) error {
+	return nil
+}
+
+func (r *fakeBalanceRepository) RebuildAll(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func TestLocalBalanceProvider_GetBalanceSummary(t *testing.T) {
+	repo := &fakeBalanceRepository{
+		balance: model.RestoreBalance(1, model.MustParsePoints("500.5"), model.MustParsePoints("42")),
+	}
+	provider := NewLocalBalanceProvider(gophermartusecase.NewGetBalanceUseCase(repo))
+
+	summary, err := provider.GetBalanceSummary(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetBalanceSummary() error = %v", err)
+	}
+	if summary.Current != "500.50" || summary.Withdrawn != "42.00" {
+		t.Errorf("GetBalanceSummary() = %+v, want 500.50/42.00", summary)
+	}
+}
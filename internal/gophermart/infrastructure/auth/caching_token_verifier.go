@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
+)
+
+// MaxPositiveTTL ограничивает время хранения подтверждённого токена. Отзыв токена на другом
+// экземпляре (выход, смена пароля) кэш не видит, поэтому отозванный токен принимается
+// не дольше этого срока.
+const MaxPositiveTTL = 10 * time.Second
+
+type CacheConfig struct {
+	// PositiveTTL — сколько хранится результат для действительного токена, не больше
+	// MaxPositiveTTL; запись в любом случае удаляется не позже истечения самого токена.
+	PositiveTTL time.Duration
+	// NegativeTTL — сколько хранится отказ для недействительного токена.
+	NegativeTTL time.Duration
+	// MaxEntries ограничивает размер кэша; при переполнении вытесняются
+	// просроченные записи, а затем записи с ближайшим сроком истечения.
+	MaxEntries int
+}
+
+type cacheEntry struct {
+	claims    *service.TokenClaims
+	expiresAt time.Time
+}
+
+// CachingTokenVerifier кэширует результаты удалённой проверки токенов. Кэшируются
+// только ответы «действителен» и «недействителен»: сетевые ошибки и ответы 5xx
+// передаются вызывающему без сохранения.
+type CachingTokenVerifier struct {
+	next   service.TokenVerifier
+	config CacheConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]cacheEntry
+}
+
+func NewCachingTokenVerifier(next service.TokenVerifier, config CacheConfig) *CachingTokenVerifier {
+	if config.PositiveTTL > MaxPositiveTTL {
+		config.PositiveTTL = MaxPositiveTTL
+	}
+	return &CachingTokenVerifier{
+		next:    next,
+		config:  config,
+		now:     time.Now,
+		entries: make(map[[sha256.Size]byte]cacheEntry),
+	}
+}
+
+func (v *CachingTokenVerifier) Verify(ctx context.Context, token string) (*service.TokenClaims, error) {
+	// В кэше хранится хэш токена, а не сам токен.
+	key := sha256.Sum256([]byte(token))
+
+	if entry, ok := v.lookup(key); ok {
+		if entry.claims == nil {
+			return nil, domainerrors.ErrInvalidToken
+		}
+		claims := *entry.claims
+		return &claims, nil
+	}
+
+	claims, err := v.next.Verify(ctx, token)
+	switch {
+	case err == nil:
+		expiresAt := v.now().Add(v.config.PositiveTTL)
+		if !claims.ExpiresAt.IsZero() && claims.ExpiresAt.Before(expiresAt) {
+			expiresAt = claims.ExpiresAt
+		}
+		v.store(key, cacheEntry{claims: claims, expiresAt: expiresAt})
+	case errors.Is(err, domainerrors.ErrInvalidToken):
+		v.store(key, cacheEntry{expiresAt: v.now().Add(v.config.NegativeTTL)})
+	}
+	return claims, err
+}
+
+// ForgetUser удаляет из кэша все подтверждённые токены пользователя, которому принадлежит
+// token. Вызывается после выхода или смены пароля, обработанных этим экземпляром, чтобы
+// отозванные токены отклонялись сразу, а не по истечении PositiveTTL.
+func (v *CachingTokenVerifier) ForgetUser(token string) {
+	key := sha256.Sum256([]byte(token))
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entry, ok := v.entries[key]
+	if !ok || entry.claims == nil {
+		return
+	}
+	for k, e := range v.entries {
+		if e.claims != nil && e.claims.UserID == entry.claims.UserID {
+			delete(v.entries, k)
+		}
+	}
+}
+
+func (v *CachingTokenVerifier) lookup(key [sha256.Size]byte) (cacheEntry, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entry, ok := v.entries[key]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	if !v.now().Before(entry.expiresAt) {
+		delete(v.entries, key)
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (v *CachingTokenVerifier) store(key [sha256.Size]byte, entry cacheEntry) {
+	if v.config.MaxEntries <= 0 {
+		return
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if _, ok := v.entries[key]; !ok && len(v.entries) >= v.config.MaxEntries {
+		v.evict()
+	}
+	v.entries[key] = entry
+}
+
+// evict удаляет просроченные записи, а если таких нет — запись с ближайшим сроком истечения.
+func (v *CachingTokenVerifier) evict() {
+	now := v.now()
+	var (
+		oldestKey [sha256.Size]byte
+		oldest    time.Time
+		removed   bool
+	)
+	for key, entry := range v.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(v.entries, key)
+			removed = true
+			continue
+		}
+		if oldest.IsZero() || entry.expiresAt.Before(oldest) {
+			oldestKey, oldest = key, entry.expiresAt
+		}
+	}
+	if !removed && !oldest.IsZero() {
+		delete(v.entries, oldestKey)
+	}
+}
@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
+)
+
+type fakeVerifier struct {
+	calls     map[string]int
+	err       error
+	expiresAt time.Time
+}
+
+func (v *fakeVerifier) Verify(_ context.Context, token string) (*service.TokenClaims, error) {
+	v.calls[token]++
+	if v.err != nil {
+		return nil, v.err
+	}
+	if token == "bad" {
+		return nil, domainerrors.ErrInvalidToken
+	}
+	return &service.TokenClaims{UserID: 1, Login: token, ExpiresAt: v.expiresAt}, nil
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestVerifier(next service.TokenVerifier, maxEntries int) (*CachingTokenVerifier, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	v := NewCachingTokenVerifier(next, CacheConfig{
+		PositiveTTL: time.Minute,
+		NegativeTTL: 10 * time.Second,
+		MaxEntries:  maxEntries,
+	})
+	v.now = clock.Now
+	return v, clock
+}
+
+func TestCachingTokenVerifier_PositiveCache(t *testing.T) {
+	next := &fakeVerifier{calls: map[string]int{}}
+	v, clock := newTestVerifier(next, 10)
+
+	for i := 0; i < 3; i++ {
+		claims, err := v.Verify(context.Background(), "good")
+		if err != nil || claims.Login != "good" {
+			t.Fatalf("Verify() = %v, %v", claims, err)
+		}
+	}
+	if next.calls["good"] != 1 {
+		t.Errorf("remote calls = %d, want 1", next.calls["good"])
+	}
+
+	clock.Advance(time.Minute)
+	if _, err := v.Verify(context.Background(), "good"); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if next.calls["good"] != 2 {
+		t.Errorf("remote calls after TTL = %d, want 2", next.calls["good"])
+	}
+}
+
+func TestCachingTokenVerifier_PositiveCacheBoundedByTokenExpiry(t *testing.T) {
+	next := &fakeVerifier{calls: map[string]int{}, expiresAt: time.Unix(0, 0).Add(5 * time.Second)}
+	v, clock := newTestVerifier(next, 10)
+
+	v.Verify(context.Background(), "good")
+	clock.Advance(4 * time.Second)
+	v.Verify(context.Background(), "good")
+	if next.calls["good"] != 1 {
+		t.Fatalf("remote calls before token expiry = %d, want 1", next.calls["good"])
+	}
+
+	clock.Advance(time.Second)
+	v.Verify(context.Background(), "good")
+	if next.calls["good"] != 2 {
+		t.Errorf("remote calls after token expiry = %d, want 2", next.calls["good"])
+	}
+}
+
+// Отзыв токена на другом экземпляре кэш не видит: отозванный токен принимается
+// не дольше MaxPositiveTTL, даже если настроен больший PositiveTTL.
+func TestCachingTokenVerifier_RevocationBound(t *testing.T) {
+	next := &fakeVerifier{calls: map[string]int{}}
+	v, clock := newTestVerifier(next, 10)
+	v.Verify(context.Background(), "good")
+
+	next.err = domainerrors.ErrInvalidToken
+	clock.Advance(MaxPositiveTTL - time.Second)
+	if _, err := v.Verify(context.Background(), "good"); err != nil {
+		t.Fatalf("Verify() within bound error = %v", err)
+	}
+
+	clock.Advance(time.Second)
+	if _, err := v.Verify(context.Background(), "good"); !errors.Is(err, domainerrors.ErrInvalidToken) {
+		t.Errorf("Verify() after %v error = %v, want %v", MaxPositiveTTL, err, domainerrors.ErrInvalidToken)
+	}
+}
+
+func TestCachingTokenVerifier_ForgetUser(t *testing.T) {
+	next := &fakeVerifier{calls: map[string]int{}}
+	v, _ := newTestVerifier(next, 10)
+
+	v.Verify(context.Background(), "session-1")
+	v.Verify(context.Background(), "session-2")
+	v.ForgetUser("session-1")
+
+	next.err = domainerrors.ErrInvalidToken
+	for _, token := range []string{"session-1", "session-2"} {
+		if _, err := v.Verify(context.Background(), token); !errors.Is(err, domainerrors.ErrInvalidToken) {
+			t.Errorf("Verify(%q) after logout error = %v, want %v", token, err, domainerrors.ErrInvalidToken)
+		}
+	}
+}
+
+func TestCachingTokenVerifier_NegativeCache(t *testing.T) {
+	next := &fakeVerifier{calls: map[string]int{}}
+	v, clock := newTestVerifier(next, 10)
+
+	for i := 0; i < 3; i++ {
+		if _, err := v.Verify(context.Background(), "bad"); !errors.Is(err, domainerrors.ErrInvalidToken) {
+			t.Fatalf("Verify() error = %v, want %v", err, domainerrors.ErrInvalidToken)
+		}
+	}
+	if next.calls["bad"] != 1 {
+		t.Errorf("remote calls = %d, want 1", next.calls["bad"])
+	}
+
+	clock.Advance(10 * time.Second)
+	v.Verify(context.Background(), "bad")
+	if next.calls["bad"] != 2 {
+		t.Errorf("remote calls after negative TTL = %d, want 2", next.calls["bad"])
+	}
+}
+
+func TestCachingTokenVerifier_TransportErrorsNotCached(t *testing.T) {
+	next := &fakeVerifier{calls: map[string]int{}, err: fmt.Errorf("unexpected status code: 500")}
+	v, _ := newTestVerifier(next, 10)
+
+	v.Verify(context.Background(), "good")
+	v.Verify(context.Background(), "good")
+
+	if next.calls["good"] != 2 {
+		t.Errorf("remote calls = %d, want 2", next.calls["good"])
+	}
+	if len(v.entries) != 0 {
+		t.Errorf("cache size = %d, want 0", len(v.entries))
+	}
+}
+
+func TestCachingTokenVerifier_Bounded(t *testing.T) {
+	next := &fakeVerifier{calls: map[string]int{}}
+	v, clock := newTestVerifier(next, 2)
+
+	v.Verify(context.Background(), "first")
+	clock.Advance(time.Second)
+	v.Verify(context.Background(), "second")
+	clock.Advance(time.Second)
+	v.Verify(context.Background(), "third")
+
+	if len(v.entries) != 2 {
+		t.Fatalf("cache size = %d, want 2", len(v.entries))
+	}
+
+	v.Verify(context.Background(), "first")
+	if next.calls["first"] != 2 {
+		t.Errorf("oldest entry was not evicted: remote calls = %d, want 2", next.calls["first"])
+	}
+	v.Verify(context.Background(), "third")
+	if next.calls["third"] != 1 {
+		t.Errorf("recent entry was evicted: remote calls = %d, want 1", next.calls["third"])
+	}
+}
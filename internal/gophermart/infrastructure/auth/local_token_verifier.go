@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
+	userserviceusecase "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/application/usecase"
+	userserviceerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	userservicemodel "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+)
+
+// LocalTokenVerifier проверяет токен в процессе тем же use case, что и /api/auth/validate:
+// подпись, срок действия и отзыв, — без HTTP-запроса к сервису пользователей.
+type LocalTokenVerifier struct {
+	validateUseCase *userserviceusecase.ValidateTokenUseCase
+}
+
+func NewLocalTokenVerifier(validateUseCase *userserviceusecase.ValidateTokenUseCase) *LocalTokenVerifier {
+	return &LocalTokenVerifier{validateUseCase: validateUseCase}
+}
+
+func (v *LocalTokenVerifier) Verify(ctx context.Context, token string) (*service.TokenClaims, error) {
+	resp, err := v.validateUseCase.Execute(ctx, userserviceusecase.ValidateTokenRequest{Token: token})
+	if err != nil {
+		if userserviceerrors.Is(err, userserviceerrors.ErrTokenRequired) ||
+			userserviceerrors.Is(err, userserviceerrors.ErrInvalidToken) ||
+			userserviceerrors.Is(err, userserviceerrors.ErrTokenRevoked) {
+			return nil, fmt.Errorf("%w: %v", domainerrors.ErrInvalidToken, err)
+		}
+		return nil, err
+	}
+	claims := &service.TokenClaims{
+		UserID: resp.Claims.UserID,
+		Login:  resp.Claims.Login,
+		Roles:  userservicemodel.RoleStrings(resp.Claims.Roles),
+	}
+	if resp.Claims.ExpiresAt != nil {
+		claims.ExpiresAt = resp.Claims.ExpiresAt.Time
+	}
+	return claims, nil
+}
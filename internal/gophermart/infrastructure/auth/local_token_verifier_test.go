@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
+	userserviceusecase "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/application/usecase"
+	userservicemodel "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	userserviceservice "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+	userservicejwt "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/infrastructure/jwt"
+)
+
+type fakeRevokedTokens struct {
+	revoked map[string]bool
+	err     error
+}
+
+func (r *fakeRevokedTokens) Revoke(_ context.Context, jti string, _ int64, _ time.Time) error {
+	r.revoked[jti] = true
+	return nil
+}
+
+func (r *fakeRevokedTokens) RevokeAllForUser(context.Context, int64, time.Time) error {
+	return nil
+}
+
+func (r *fakeRevokedTokens) IsRevoked(_ context.Context, jti string, _ int64, _ time.Time) (bool, error) {
+	return r.revoked[jti], r.err
+}
+
+func newLocalVerifier(jwtService userserviceservice.JWTService, revoked *fakeRevokedTokens) *LocalTokenVerifier {
+	return NewLocalTokenVerifier(userserviceusecase.NewValidateTokenUseCase(jwtService, revoked))
+}
+
+func TestLocalTokenVerifier_Verify(t *testing.T) {
+	jwtService := userservicejwt.NewJWTService("secret", time.Minute)
+	revoked := &fakeRevokedTokens{revoked: map[string]bool{}}
+	verifier := newLocalVerifier(jwtService, revoked)
+
+	token, err := jwtService.GenerateToken(42, "user", []userservicemodel.Role{userservicemodel.RoleUser, userservicemodel.RoleAdmin})
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	claims, err := verifier.Verify(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if claims.UserID != 42 || claims.Login != "user" {
+		t.Errorf("Verify() = %+v, want user 42", claims)
+	}
+	if !claims.HasAnyRole(service.RoleAdmin) {
+		t.Errorf("Verify() roles = %v, want admin", claims.Roles)
+	}
+	if until := time.Until(claims.ExpiresAt); until <= 0 || until > time.Minute {
+		t.Errorf("Verify() expires at %v, want within a minute", claims.ExpiresAt)
+	}
+
+	otherKey := newLocalVerifier(userservicejwt.NewJWTService("other-secret", time.Minute), revoked)
+	if _, err := otherKey.Verify(context.Background(), token); !errors.Is(err, domainerrors.ErrInvalidToken) {
+		t.Errorf("Verify() with wrong key error = %v, want %v", err, domainerrors.ErrInvalidToken)
+	}
+
+	expired, _ := userservicejwt.NewJWTService("secret", -time.Minute).GenerateToken(42, "user", nil)
+	if _, err := verifier.Verify(context.Background(), expired); !errors.Is(err, domainerrors.ErrInvalidToken) {
+		t.Errorf("Verify() with expired token error = %v, want %v", err, domainerrors.ErrInvalidToken)
+	}
+
+	parsed, _ := jwtService.ValidateToken(token)
+	revoked.Revoke(context.Background(), parsed.ID, 42, parsed.ExpiresAt.Time)
+	if _, err := verifier.Verify(context.Background(), token); !errors.Is(err, domainerrors.ErrInvalidToken) {
+		t.Errorf("Verify() with revoked token error = %v, want %v", err, domainerrors.ErrInvalidToken)
+	}
+
+	storeErr := errors.New("database error")
+	failing := newLocalVerifier(jwtService, &fakeRevokedTokens{err: storeErr})
+	if _, err := failing.Verify(context.Background(), token); !errors.Is(err, storeErr) || errors.Is(err, domainerrors.ErrInvalidToken) {
+		t.Errorf("Verify() with store error = %v, want %v", err, storeErr)
+	}
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+)
+
+const apiKeyColumns = `id, name, owner, prefix, key_hash, scopes, created_by, expires_at, last_used_at, revoked_at, created_at`
+
+type apiKeyRepository struct {
+	querier Querier
+}
+
+func NewAPIKeyRepository(pool *pgxpool.Pool) repository.APIKeyRepository {
+	return &apiKeyRepository{querier: pool}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	query := `INSERT INTO api_keys (name, owner, prefix, key_hash, scopes, created_by, expires_at)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`
+	return r.querier.QueryRow(ctx, query,
+		key.Name, key.Owner, key.Prefix, key.KeyHash, scopes, key.CreatedBy, key.ExpiresAt,
+	).Scan(&key.ID, &key.CreatedAt)
+}
+
+func (r *apiKeyRepository) FindByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1`
+	key, err := scanAPIKey(r.querier.QueryRow(ctx, query, keyHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return key, nil
+}
+
+func (r *apiKeyRepository) List(ctx context.Context) ([]*model.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY created_at DESC, id DESC`
+	rows, err := r.querier.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRows(rows, func(rows pgx.Rows) (*model.APIKey, error) {
+		return scanAPIKey(rows)
+	})
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id int64, at time.Time) (bool, error) {
+	query := `UPDATE api_keys SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`
+	tag, err := r.querier.Exec(ctx, query, id, at)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	query := `UPDATE api_keys SET last_used_at = $2
+	          WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $3)`
+	_, err := r.querier.Exec(ctx, query, id, at, at.Add(-model.APIKeyLastUsedResolution))
+	return err
+}
+
+func (r *apiKeyRepository) Grant(ctx context.Context, id, userID int64, at time.Time) (bool, error) {
+	query := `INSERT INTO api_key_grants (api_key_id, user_id, created_at)
+	          SELECT id, $2, $3 FROM api_keys
+	          WHERE id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $3)
+	          ON CONFLICT (api_key_id, user_id) DO UPDATE SET created_at = api_key_grants.created_at`
+	tag, err := r.querier.Exec(ctx, query, id, userID, at)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *apiKeyRepository) RevokeGrant(ctx context.Context, id, userID int64) (bool, error) {
+	tag, err := r.querier.Exec(ctx, `DELETE FROM api_key_grants WHERE api_key_id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *apiKeyRepository) IsGranted(ctx context.Context, id, userID int64) (bool, error) {
+	var granted bool
+	err := r.querier.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM api_key_grants WHERE api_key_id = $1 AND user_id = $2)`, id, userID,
+	).Scan(&granted)
+	return granted, err
+}
+
+func scanAPIKey(row pgx.Row) (*model.APIKey, error) {
+	key := &model.APIKey{}
+	var scopes []string
+	err := row.Scan(&key.ID, &key.Name, &key.Owner, &key.Prefix, &key.KeyHash, &scopes,
+		&key.CreatedBy, &key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	for _, scope := range scopes {
+		key.Scopes = append(key.Scopes, model.APIKeyScope(scope))
+	}
+	return key, nil
+}
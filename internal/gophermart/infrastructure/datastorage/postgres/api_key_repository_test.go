@@ -0,0 +1,163 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/infrastructure/datastorage/postgres"
+)
+
+func TestAPIKeyRepository_CreateAndFindByHash(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewAPIKeyRepository(pool)
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(24 * time.Hour).Truncate(time.Microsecond)
+	key := &model.APIKey{
+		Name:      "pos terminal",
+		Owner:     "coffee-shop",
+		Prefix:    "gm_abcd1",
+		KeyHash:   model.HashAPIKey("gm_abcd1secret"),
+		Scopes:    []model.APIKeyScope{model.APIKeyScopeOrdersWrite, model.APIKeyScopeBalanceRead},
+		CreatedBy: 1,
+		ExpiresAt: &expiresAt,
+	}
+	require.NoError(t, repo.Create(ctx, key))
+	assert.NotZero(t, key.ID)
+	assert.False(t, key.CreatedAt.IsZero())
+
+	found, err := repo.FindByHash(ctx, model.HashAPIKey("gm_abcd1secret"))
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, key.ID, found.ID)
+	assert.Equal(t, "coffee-shop", found.Owner)
+	assert.Equal(t, key.Scopes, found.Scopes)
+	require.NotNil(t, found.ExpiresAt)
+	assert.True(t, expiresAt.Equal(*found.ExpiresAt))
+	assert.Nil(t, found.LastUsedAt)
+	assert.Nil(t, found.RevokedAt)
+
+	missing, err := repo.FindByHash(ctx, model.HashAPIKey("unknown"))
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestAPIKeyRepository_Revoke(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewAPIKeyRepository(pool)
+	ctx := context.Background()
+
+	key := &model.APIKey{
+		Name:    "pos",
+		Owner:   "shop",
+		Prefix:  "gm_rev00",
+		KeyHash: model.HashAPIKey("gm_rev00secret"),
+		Scopes:  []model.APIKeyScope{model.APIKeyScopeOrdersWrite},
+	}
+	require.NoError(t, repo.Create(ctx, key))
+
+	revoked, err := repo.Revoke(ctx, key.ID, time.Now())
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = repo.Revoke(ctx, key.ID, time.Now())
+	require.NoError(t, err)
+	assert.False(t, revoked, "already revoked key must not be revoked again")
+
+	revoked, err = repo.Revoke(ctx, 999, time.Now())
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	keys, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.NotNil(t, keys[0].RevokedAt)
+}
+
+func TestAPIKeyRepository_TouchLastUsed(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewAPIKeyRepository(pool)
+	ctx := context.Background()
+
+	key := &model.APIKey{
+		Name:    "pos",
+		Owner:   "shop",
+		Prefix:  "gm_use00",
+		KeyHash: model.HashAPIKey("gm_use00secret"),
+		Scopes:  []model.APIKeyScope{model.APIKeyScopeOrdersWrite},
+	}
+	require.NoError(t, repo.Create(ctx, key))
+
+	first := time.Now().Truncate(time.Microsecond)
+	require.NoError(t, repo.TouchLastUsed(ctx, key.ID, first))
+	require.NoError(t, repo.TouchLastUsed(ctx, key.ID, first.Add(10*time.Second)))
+
+	found, err := repo.FindByHash(ctx, key.KeyHash)
+	require.NoError(t, err)
+	require.NotNil(t, found.LastUsedAt)
+	assert.True(t, first.Equal(*found.LastUsedAt), "updates within a minute are skipped")
+
+	later := first.Add(2 * time.Minute)
+	require.NoError(t, repo.TouchLastUsed(ctx, key.ID, later))
+
+	found, err = repo.FindByHash(ctx, key.KeyHash)
+	require.NoError(t, err)
+	assert.True(t, later.Equal(*found.LastUsedAt))
+}
+
+func TestAPIKeyRepository_Grants(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewAPIKeyRepository(pool)
+	ctx := context.Background()
+
+	key := &model.APIKey{
+		Name:    "pos",
+		Owner:   "shop",
+		Prefix:  "gm_grt00",
+		KeyHash: model.HashAPIKey("gm_grt00secret"),
+		Scopes:  []model.APIKeyScope{model.APIKeyScopeBalanceRead},
+	}
+	require.NoError(t, repo.Create(ctx, key))
+
+	granted, err := repo.IsGranted(ctx, key.ID, 42)
+	require.NoError(t, err)
+	assert.False(t, granted)
+
+	ok, err := repo.Grant(ctx, key.ID, 42, time.Now())
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = repo.Grant(ctx, key.ID, 42, time.Now())
+	require.NoError(t, err)
+	assert.True(t, ok, "repeated grant is idempotent")
+
+	granted, err = repo.IsGranted(ctx, key.ID, 42)
+	require.NoError(t, err)
+	assert.True(t, granted)
+	granted, err = repo.IsGranted(ctx, key.ID, 43)
+	require.NoError(t, err)
+	assert.False(t, granted, "grant applies only to the user who gave it")
+
+	revoked, err := repo.RevokeGrant(ctx, key.ID, 42)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	revoked, err = repo.RevokeGrant(ctx, key.ID, 42)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	_, err = repo.Revoke(ctx, key.ID, time.Now())
+	require.NoError(t, err)
+	ok, err = repo.Grant(ctx, key.ID, 42, time.Now())
+	require.NoError(t, err)
+	assert.False(t, ok, "revoked key cannot be granted")
+
+	ok, err = repo.Grant(ctx, 999, 42, time.Now())
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"errors"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -22,30 +23,59 @@ func NewBalanceRepositoryTx(tx pgx.Tx) repository.BalanceRepository {
 }
 
 func (r *balanceRepository) GetByUserID(ctx context.Context, userID int64) (*model.Balance, error) {
-	query := `SELECT user_id, current, withdrawn FROM balances WHERE user_id = $1`
+	return r.getByUserID(ctx, `SELECT user_id, current, withdrawn FROM balances WHERE user_id = $1`, userID)
+}
+
+// GetByUserIDForUpdate не блокирует ничего, если строки баланса ещё нет: такой баланс нулевой,
+// и списание с него не пройдёт проверку остатка.
+func (r *balanceRepository) GetByUserIDForUpdate(ctx context.Context, userID int64) (*model.Balance, error) {
+	return r.getByUserID(ctx, `SELECT user_id, current, withdrawn FROM balances WHERE user_id = $1 FOR UPDATE`, userID)
+}
+
+func (r *balanceRepository) getByUserID(ctx context.Context, query string, userID int64) (*model.Balance, error) {
 	var uid int64
-	var current, withdrawn float64
+	var current, withdrawn model.Points
 	err := r.querier.QueryRow(ctx, query, userID).Scan(&uid, &current, &withdrawn)
 	if err != nil {
-		return model.NewBalance(userID), nil
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.NewBalance(userID), nil
+		}
+		return nil, err
 	}
 	return model.RestoreBalance(uid, current, withdrawn), nil
 }
 
-func (r *balanceRepository) Withdraw(ctx context.Context, userID int64, amount float64) error {
-	query := `INSERT INTO balances (user_id, current, withdrawn) 
-	          VALUES ($1, -$2::DECIMAL(10,2), $2::DECIMAL(10,2))
-	          ON CONFLICT (user_id) 
-	          DO UPDATE SET current = balances.current - $2::DECIMAL(10,2), withdrawn = balances.withdrawn + $2::DECIMAL(10,2)`
-	_, err := r.querier.Exec(ctx, query, userID, amount)
+func (r *balanceRepository) Rebuild(ctx context.Context, userID int64) error {
+	query := `INSERT INTO balances (user_id, current, withdrawn)
+	          SELECT $1,
+	                 COALESCE(SUM(amount) FILTER (WHERE account = 'USER'), 0),
+	                 COALESCE(SUM(amount) FILTER (WHERE account = 'REDEMPTION'), 0)
+	          FROM ledger_entries WHERE user_id = $1
+	          ON CONFLICT (user_id)
+	          DO UPDATE SET current = EXCLUDED.current, withdrawn = EXCLUDED.withdrawn`
+	_, err := r.querier.Exec(ctx, query, userID)
 	return err
 }
 
-func (r *balanceRepository) Accrue(ctx context.Context, userID int64, amount float64) error {
-	query := `INSERT INTO balances (user_id, current, withdrawn) 
-	          VALUES ($1, $2::DECIMAL(10,2), 0)
-	          ON CONFLICT (user_id) 
-	          DO UPDATE SET current = balances.current + $2::DECIMAL(10,2)`
-	_, err := r.querier.Exec(ctx, query, userID, amount)
-	return err
+func (r *balanceRepository) RebuildAll(ctx context.Context) (int64, error) {
+	query := `WITH totals AS (
+	              SELECT user_id,
+	                     SUM(amount) FILTER (WHERE account = 'USER') AS current,
+	                     SUM(amount) FILTER (WHERE account = 'REDEMPTION') AS withdrawn
+	              FROM ledger_entries GROUP BY user_id
+	          ), affected AS (
+	              SELECT user_id FROM balances
+	              UNION
+	              SELECT user_id FROM totals
+	          )
+	          INSERT INTO balances (user_id, current, withdrawn)
+	          SELECT a.user_id, COALESCE(t.current, 0), COALESCE(t.withdrawn, 0)
+	          FROM affected a LEFT JOIN totals t ON t.user_id = a.user_id
+	          ON CONFLICT (user_id)
+	          DO UPDATE SET current = EXCLUDED.current, withdrawn = EXCLUDED.withdrawn`
+	tag, err := r.querier.Exec(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/infrastructure/datastorage/postgres"
 )
 
@@ -23,8 +24,8 @@ func TestBalanceRepository_GetByUserID(t *testing.T) {
 		require.NoError(t, err)
 		assert.NotNil(t, balance)
 		assert.Equal(t, int64(999), balance.UserID())
-		assert.Equal(t, 0.0, balance.Current())
-		assert.Equal(t, 0.0, balance.Withdrawn())
+		assert.Equal(t, model.Points(0), balance.Current())
+		assert.Equal(t, model.Points(0), balance.Withdrawn())
 	})
 
 	t.Run("returns existing balance", func(t *testing.T) {
@@ -38,130 +39,113 @@ func TestBalanceRepository_GetByUserID(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, int64(1), balance.UserID())
-		assert.Equal(t, 100.5, balance.Current())
-		assert.Equal(t, 50.0, balance.Withdrawn())
+		assert.Equal(t, model.MustParsePoints("100.5"), balance.Current())
+		assert.Equal(t, model.MustParsePoints("50"), balance.Withdrawn())
 	})
 }
 
-func TestBalanceRepository_Withdraw(t *testing.T) {
+func TestBalanceRepository_GetByUserIDAfterPosting(t *testing.T) {
 	pool := setupTestDB(t)
 	repo := postgres.NewBalanceRepository(pool)
+	ledgerRepo := postgres.NewLedgerRepository(pool)
 	ctx := context.Background()
 
-	t.Run("successful withdrawal from existing balance", func(t *testing.T) {
-		_, err := pool.Exec(ctx,
-			"INSERT INTO balances (user_id, current, withdrawn) VALUES ($1, $2, $3)",
-			1, 100.0, 0.0,
-		)
+	t.Run("projection follows ledger postings", func(t *testing.T) {
+		err := ledgerRepo.Post(ctx, adjustmentPosting(t, 1, model.MustParsePoints("100")))
 		require.NoError(t, err)
 
-		err = repo.Withdraw(ctx, 1, 50.0)
-
+		withdrawalID := createWithdrawal(t, pool, 1, model.MustParsePoints("30.25"))
+		posting, err := model.NewWithdrawalPosting(1, withdrawalID, model.MustParsePoints("30.25"))
 		require.NoError(t, err)
-
-		var current, withdrawn float64
-		err = pool.QueryRow(ctx,
-			"SELECT current, withdrawn FROM balances WHERE user_id = $1", 1,
-		).Scan(&current, &withdrawn)
+		err = ledgerRepo.Post(ctx, posting)
 		require.NoError(t, err)
 
-		assert.Equal(t, 50.0, current)
-		assert.Equal(t, 50.0, withdrawn)
+		balance, err := repo.GetByUserID(ctx, 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.MustParsePoints("69.75"), balance.Current())
+		assert.Equal(t, model.MustParsePoints("30.25"), balance.Withdrawn())
 	})
+}
 
-	t.Run("creates balance if not exists", func(t *testing.T) {
-		err := repo.Withdraw(ctx, 2, 30.0)
+func TestBalanceRepository_Rebuild(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewBalanceRepository(pool)
+	ledgerRepo := postgres.NewLedgerRepository(pool)
+	ctx := context.Background()
 
+	t.Run("restores corrupted projection from ledger", func(t *testing.T) {
+		err := ledgerRepo.Post(ctx, adjustmentPosting(t, 1, model.MustParsePoints("150")))
 		require.NoError(t, err)
 
-		var current, withdrawn float64
-		err = pool.QueryRow(ctx,
-			"SELECT current, withdrawn FROM balances WHERE user_id = $1", 2,
-		).Scan(&current, &withdrawn)
+		withdrawalID := createWithdrawal(t, pool, 1, model.MustParsePoints("50"))
+		posting, err := model.NewWithdrawalPosting(1, withdrawalID, model.MustParsePoints("50"))
+		require.NoError(t, err)
+		err = ledgerRepo.Post(ctx, posting)
 		require.NoError(t, err)
 
-		assert.Equal(t, -30.0, current)
-		assert.Equal(t, 30.0, withdrawn)
-	})
+		_, err = pool.Exec(ctx, "UPDATE balances SET current = 999, withdrawn = 0 WHERE user_id = $1", 1)
+		require.NoError(t, err)
 
-	t.Run("multiple withdrawals accumulate", func(t *testing.T) {
-		_, err := pool.Exec(ctx,
-			"INSERT INTO balances (user_id, current, withdrawn) VALUES ($1, $2, $3)",
-			3, 200.0, 10.0,
-		)
+		err = repo.Rebuild(ctx, 1)
 		require.NoError(t, err)
 
-		err = repo.Withdraw(ctx, 3, 75.0)
+		balance, err := repo.GetByUserID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, model.MustParsePoints("100"), balance.Current())
+		assert.Equal(t, model.MustParsePoints("50"), balance.Withdrawn())
+	})
+
+	t.Run("creates zero balance for user without entries", func(t *testing.T) {
+		err := repo.Rebuild(ctx, 2)
 		require.NoError(t, err)
 
 		var current, withdrawn float64
 		err = pool.QueryRow(ctx,
-			"SELECT current, withdrawn FROM balances WHERE user_id = $1", 3,
+			"SELECT current, withdrawn FROM balances WHERE user_id = $1", 2,
 		).Scan(&current, &withdrawn)
 		require.NoError(t, err)
 
-		assert.Equal(t, 125.0, current)
-		assert.Equal(t, 85.0, withdrawn)
+		assert.Equal(t, 0.0, current)
+		assert.Equal(t, 0.0, withdrawn)
 	})
 }
 
-func TestBalanceRepository_Accrue(t *testing.T) {
+func TestBalanceRepository_RebuildAll(t *testing.T) {
 	pool := setupTestDB(t)
 	repo := postgres.NewBalanceRepository(pool)
+	ledgerRepo := postgres.NewLedgerRepository(pool)
 	ctx := context.Background()
 
-	t.Run("successful accrue to existing balance", func(t *testing.T) {
-		_, err := pool.Exec(ctx,
-			"INSERT INTO balances (user_id, current, withdrawn) VALUES ($1, $2, $3)",
-			1, 100.0, 0.0,
-		)
+	t.Run("rebuilds every balance", func(t *testing.T) {
+		err := ledgerRepo.Post(ctx, adjustmentPosting(t, 1, model.MustParsePoints("10")))
 		require.NoError(t, err)
-
-		err = repo.Accrue(ctx, 1, 50.0)
-
+		err = ledgerRepo.Post(ctx, adjustmentPosting(t, 2, model.MustParsePoints("20.5")))
 		require.NoError(t, err)
 
-		var current float64
-		err = pool.QueryRow(ctx,
-			"SELECT current FROM balances WHERE user_id = $1", 1,
-		).Scan(&current)
+		_, err = pool.Exec(ctx, "UPDATE balances SET current = 0")
 		require.NoError(t, err)
-
-		assert.Equal(t, 150.0, current)
-	})
-
-	t.Run("creates balance if not exists", func(t *testing.T) {
-		err := repo.Accrue(ctx, 2, 75.5)
-
-		require.NoError(t, err)
-
-		var current float64
-		err = pool.QueryRow(ctx,
-			"SELECT current FROM balances WHERE user_id = $1", 2,
-		).Scan(&current)
-		require.NoError(t, err)
-
-		assert.Equal(t, 75.5, current)
-	})
-
-	t.Run("multiple accruals accumulate", func(t *testing.T) {
-		_, err := pool.Exec(ctx,
+		_, err = pool.Exec(ctx,
 			"INSERT INTO balances (user_id, current, withdrawn) VALUES ($1, $2, $3)",
-			3, 50.0, 0.0,
+			3, 42.0, 1.0,
 		)
 		require.NoError(t, err)
 
-		err = repo.Accrue(ctx, 3, 25.0)
+		count, err := repo.RebuildAll(ctx)
 		require.NoError(t, err)
-		err = repo.Accrue(ctx, 3, 25.0)
+		assert.Equal(t, int64(3), count)
+
+		balance1, err := repo.GetByUserID(ctx, 1)
 		require.NoError(t, err)
+		assert.Equal(t, model.MustParsePoints("10"), balance1.Current())
 
-		var current float64
-		err = pool.QueryRow(ctx,
-			"SELECT current FROM balances WHERE user_id = $1", 3,
-		).Scan(&current)
+		balance2, err := repo.GetByUserID(ctx, 2)
 		require.NoError(t, err)
+		assert.Equal(t, model.MustParsePoints("20.5"), balance2.Current())
 
-		assert.Equal(t, 100.0, current)
+		balance3, err := repo.GetByUserID(ctx, 3)
+		require.NoError(t, err)
+		assert.Equal(t, model.Points(0), balance3.Current())
+		assert.Equal(t, model.Points(0), balance3.Withdrawn())
 	})
 }
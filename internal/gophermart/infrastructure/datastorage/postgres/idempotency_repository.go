@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+)
+
+type idempotencyRepository struct {
+	querier Querier
+}
+
+func NewIdempotencyRepository(pool *pgxpool.Pool) repository.IdempotencyRepository {
+	return &idempotencyRepository{querier: pool}
+}
+
+func NewIdempotencyRepositoryTx(tx pgx.Tx) repository.IdempotencyRepository {
+	return &idempotencyRepository{querier: tx}
+}
+
+// Reserve выполняет вставку и чтение отдельными запросами: при READ COMMITTED второй запрос
+// видит строку, которую закоммитила конкурирующая транзакция, пока вставка ждала её завершения.
+func (r *idempotencyRepository) Reserve(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+	insert := `INSERT INTO idempotency_keys (user_id, key, operation, request_hash, created_at) 
+	           VALUES ($1, $2, $3, $4, $5) ON CONFLICT (user_id, key) DO NOTHING`
+	tag, err := r.querier.Exec(ctx, insert, record.UserID, record.Key, record.Operation, record.RequestHash, record.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() > 0 {
+		return nil, nil
+	}
+
+	query := `SELECT user_id, key, operation, request_hash, response, created_at 
+	          FROM idempotency_keys WHERE user_id = $1 AND key = $2`
+	var stored model.IdempotencyRecord
+	err = r.querier.QueryRow(ctx, query, record.UserID, record.Key).Scan(
+		&stored.UserID, &stored.Key, &stored.Operation, &stored.RequestHash, &stored.Response, &stored.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("idempotency key disappeared after conflict")
+		}
+		return nil, err
+	}
+	return &stored, nil
+}
+
+func (r *idempotencyRepository) SaveResponse(ctx context.Context, userID int64, key string, response []byte) error {
+	query := `UPDATE idempotency_keys SET response = $3 WHERE user_id = $1 AND key = $2`
+	_, err := r.querier.Exec(ctx, query, userID, key, response)
+	return err
+}
@@ -0,0 +1,85 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/infrastructure/datastorage/postgres"
+)
+
+func TestIdempotencyRepository_Reserve(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewIdempotencyRepository(pool)
+	ctx := context.Background()
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	record := &model.IdempotencyRecord{UserID: 1, Key: "retry-1", Operation: "withdraw", RequestHash: "hash", CreatedAt: now}
+
+	t.Run("free key is reserved", func(t *testing.T) {
+		stored, err := repo.Reserve(ctx, record)
+
+		require.NoError(t, err)
+		assert.Nil(t, stored)
+		require.NoError(t, repo.SaveResponse(ctx, 1, "retry-1", []byte(`{"success":true}`)))
+	})
+
+	t.Run("taken key returns stored record", func(t *testing.T) {
+		stored, err := repo.Reserve(ctx, &model.IdempotencyRecord{UserID: 1, Key: "retry-1", Operation: "withdraw", RequestHash: "other", CreatedAt: now})
+
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+		assert.Equal(t, "hash", stored.RequestHash)
+		assert.JSONEq(t, `{"success":true}`, string(stored.Response))
+		assert.True(t, stored.CreatedAt.Equal(now))
+	})
+
+	t.Run("keys are scoped to user", func(t *testing.T) {
+		stored, err := repo.Reserve(ctx, &model.IdempotencyRecord{UserID: 2, Key: "retry-1", Operation: "withdraw", RequestHash: "hash", CreatedAt: now})
+
+		require.NoError(t, err)
+		assert.Nil(t, stored)
+	})
+
+	t.Run("concurrent reservation waits for the first transaction", func(t *testing.T) {
+		uow := postgres.NewUnitOfWork(pool)
+		first, err := uow.Begin(ctx)
+		require.NoError(t, err)
+		defer first.Rollback(ctx)
+
+		key := &model.IdempotencyRecord{UserID: 3, Key: "retry-2", Operation: "withdraw", RequestHash: "hash", CreatedAt: now}
+		stored, err := first.IdempotencyRepository().Reserve(ctx, key)
+		require.NoError(t, err)
+		require.Nil(t, stored)
+
+		result := make(chan *model.IdempotencyRecord, 1)
+		go func() {
+			stored, err := repo.Reserve(ctx, key)
+			assert.NoError(t, err)
+			result <- stored
+		}()
+
+		select {
+		case <-result:
+			t.Fatal("second reservation must wait for the first transaction")
+		case <-time.After(200 * time.Millisecond):
+		}
+
+		require.NoError(t, first.IdempotencyRepository().SaveResponse(ctx, 3, "retry-2", []byte(`{"success":true}`)))
+		require.NoError(t, first.Commit(ctx))
+
+		select {
+		case stored := <-result:
+			require.NotNil(t, stored)
+			assert.JSONEq(t, `{"success":true}`, string(stored.Response))
+		case <-time.After(5 * time.Second):
+			t.Fatal("second reservation did not finish")
+		}
+	})
+}
@@ -4,12 +4,10 @@ import (
 	"io"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
 )
 
-type Iterator[T any] interface {
-	Next() (T, error)
-	Close() error
-}
+type Iterator[T any] = repository.Iterator[T]
 
 type pgxIterator[T any] struct {
 	rows    pgx.Rows
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+)
+
+type ledgerRepository struct {
+	querier Querier
+}
+
+func NewLedgerRepository(pool *pgxpool.Pool) repository.LedgerRepository {
+	return &ledgerRepository{querier: pool}
+}
+
+func NewLedgerRepositoryTx(tx pgx.Tx) repository.LedgerRepository {
+	return &ledgerRepository{querier: tx}
+}
+
+// Post записывает проводки операции и обновляет проекцию balances одним запросом,
+// поэтому журнал и баланс не расходятся даже без внешней транзакции.
+func (r *ledgerRepository) Post(ctx context.Context, posting *model.LedgerPosting) error {
+	if err := posting.Validate(); err != nil {
+		return err
+	}
+
+	entries := posting.Entries()
+	accounts := make([]string, len(entries))
+	amounts := make([]string, len(entries))
+	for i, entry := range entries {
+		accounts[i] = string(entry.Account())
+		amounts[i] = entry.Amount().String()
+	}
+
+	query := `WITH tx AS (
+	              SELECT nextval('ledger_transaction_id_seq') AS id, NOW() AS created_at
+	          ), entries AS (
+	              INSERT INTO ledger_entries (transaction_id, kind, account, user_id, amount, order_id, withdrawal_id, reversal_of, description, created_at)
+	              SELECT tx.id, $1::VARCHAR, e.account, $2::BIGINT, e.amount, $3::BIGINT, $4::BIGINT, $5::BIGINT, NULLIF($6::VARCHAR, ''), tx.created_at
+	              FROM tx, unnest($7::TEXT[], $8::DECIMAL(12,2)[]) AS e(account, amount)
+	          ), projection AS (
+	              INSERT INTO balances (user_id, current, withdrawn)
+	              VALUES ($2, $9::DECIMAL(10,2), $10::DECIMAL(10,2))
+	              ON CONFLICT (user_id)
+	              DO UPDATE SET current = balances.current + EXCLUDED.current, withdrawn = balances.withdrawn + EXCLUDED.withdrawn
+	          )
+	          SELECT id, created_at FROM tx`
+	var transactionID int64
+	var createdAt time.Time
+	err := r.querier.QueryRow(ctx, query,
+		string(posting.Kind()), posting.UserID(), posting.OrderID(), posting.WithdrawalID(), posting.ReversalOf(), posting.Description(),
+		accounts, amounts, posting.CurrentDelta(), posting.WithdrawnDelta(),
+	).Scan(&transactionID, &createdAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "uq_ledger_entries_order_accrual" {
+			return model.ErrLedgerOrderCredited
+		}
+		// Нехватка баллов — ошибка клиента только при списании; для начисления, сторно
+		// и корректировки уход баланса в минус — ошибка данных, а не отказ пользователю.
+		if errors.As(err, &pgErr) && pgErr.Code == "23514" && pgErr.ConstraintName == "balances_current_non_negative" {
+			if posting.Kind() == model.LedgerEntryKindWithdrawal {
+				return fmt.Errorf("post %s for user %d: %w", posting.Kind(), posting.UserID(), domainerrors.ErrInsufficientFunds)
+			}
+			return fmt.Errorf("post %s for user %d: balance would become negative: %w", posting.Kind(), posting.UserID(), err)
+		}
+		return err
+	}
+	posting.SetPosted(transactionID, createdAt)
+	return nil
+}
+
+func (r *ledgerRepository) FindByUserID(ctx context.Context, userID int64) ([]*model.LedgerEntry, error) {
+	query := `SELECT id, transaction_id, kind, account, user_id, amount, order_id, withdrawal_id, reversal_of, description, created_at
+	          FROM ledger_entries WHERE user_id = $1 ORDER BY id`
+	rows, err := r.querier.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRows(rows, scanLedgerEntry)
+}
+
+func (r *ledgerRepository) FindByTransactionID(ctx context.Context, transactionID int64) ([]*model.LedgerEntry, error) {
+	query := `SELECT id, transaction_id, kind, account, user_id, amount, order_id, withdrawal_id, reversal_of, description, created_at
+	          FROM ledger_entries WHERE transaction_id = $1 ORDER BY id`
+	rows, err := r.querier.Query(ctx, query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRows(rows, scanLedgerEntry)
+}
+
+func scanLedgerEntry(rows pgx.Rows) (*model.LedgerEntry, error) {
+	var id, transactionID, userID int64
+	var kind model.LedgerEntryKind
+	var account model.LedgerAccount
+	var amount model.Points
+	var orderID, withdrawalID, reversalOf *int64
+	var description *string
+	var createdAt time.Time
+	err := rows.Scan(&id, &transactionID, &kind, &account, &userID, &amount, &orderID, &withdrawalID, &reversalOf, &description, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	var desc string
+	if description != nil {
+		desc = *description
+	}
+	return model.RestoreLedgerEntry(id, transactionID, kind, account, userID, amount, orderID, withdrawalID, reversalOf, desc, createdAt), nil
+}
@@ -0,0 +1,184 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/infrastructure/datastorage/postgres"
+)
+
+func adjustmentPosting(t *testing.T, userID int64, amount model.Points) *model.LedgerPosting {
+	t.Helper()
+	posting, err := model.NewAdjustmentPosting(userID, amount, "test adjustment")
+	require.NoError(t, err)
+	return posting
+}
+
+func createWithdrawal(t *testing.T, pool *pgxpool.Pool, userID int64, sum model.Points) int64 {
+	t.Helper()
+	withdrawal, err := model.NewWithdrawal(userID, "79927398713", sum)
+	require.NoError(t, err)
+	err = postgres.NewWithdrawalRepository(pool).Create(context.Background(), withdrawal)
+	require.NoError(t, err)
+	return withdrawal.ID()
+}
+
+func TestLedgerRepository_Post(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewLedgerRepository(pool)
+	ctx := context.Background()
+
+	t.Run("accrual writes balanced entry pair", func(t *testing.T) {
+		order, err := model.NewOrder(1, "79927398713")
+		require.NoError(t, err)
+		err = postgres.NewOrderRepository(pool).Create(ctx, order)
+		require.NoError(t, err)
+
+		posting, err := model.NewAccrualPosting(1, order.ID(), model.MustParsePoints("500.5"))
+		require.NoError(t, err)
+
+		err = repo.Post(ctx, posting)
+
+		require.NoError(t, err)
+		assert.Greater(t, posting.TransactionID(), int64(0))
+
+		entries, err := repo.FindByTransactionID(ctx, posting.TransactionID())
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, model.LedgerAccountUser, entries[0].Account())
+		assert.Equal(t, model.MustParsePoints("500.5"), entries[0].Amount())
+		assert.Equal(t, model.LedgerAccountAccrualSource, entries[1].Account())
+		assert.Equal(t, model.MustParsePoints("-500.5"), entries[1].Amount())
+		for _, entry := range entries {
+			assert.Equal(t, model.LedgerEntryKindAccrual, entry.Kind())
+			require.NotNil(t, entry.OrderID())
+			assert.Equal(t, order.ID(), *entry.OrderID())
+		}
+
+		var current float64
+		err = pool.QueryRow(ctx, "SELECT current FROM balances WHERE user_id = $1", 1).Scan(&current)
+		require.NoError(t, err)
+		assert.Equal(t, 500.5, current)
+	})
+
+	t.Run("order cannot be credited twice", func(t *testing.T) {
+		order, err := model.NewOrder(3, "12345678903")
+		require.NoError(t, err)
+		err = postgres.NewOrderRepository(pool).Create(ctx, order)
+		require.NoError(t, err)
+
+		first, err := model.NewAccrualPosting(3, order.ID(), model.MustParsePoints("10"))
+		require.NoError(t, err)
+		err = repo.Post(ctx, first)
+		require.NoError(t, err)
+
+		second, err := model.NewAccrualPosting(3, order.ID(), model.MustParsePoints("10"))
+		require.NoError(t, err)
+		err = repo.Post(ctx, second)
+
+		assert.ErrorIs(t, err, model.ErrLedgerOrderCredited)
+
+		var current float64
+		err = pool.QueryRow(ctx, "SELECT current FROM balances WHERE user_id = $1", 3).Scan(&current)
+		require.NoError(t, err)
+		assert.Equal(t, 10.0, current)
+	})
+
+	t.Run("reversal cancels original transaction", func(t *testing.T) {
+		withdrawalID := createWithdrawal(t, pool, 2, model.MustParsePoints("40"))
+		err := repo.Post(ctx, adjustmentPosting(t, 2, model.MustParsePoints("100")))
+		require.NoError(t, err)
+
+		withdrawal, err := model.NewWithdrawalPosting(2, withdrawalID, model.MustParsePoints("40"))
+		require.NoError(t, err)
+		err = repo.Post(ctx, withdrawal)
+		require.NoError(t, err)
+
+		original, err := repo.FindByTransactionID(ctx, withdrawal.TransactionID())
+		require.NoError(t, err)
+		reversal, err := model.NewReversalPosting(original, "withdrawal cancelled")
+		require.NoError(t, err)
+		err = repo.Post(ctx, reversal)
+		require.NoError(t, err)
+
+		var current, withdrawn float64
+		err = pool.QueryRow(ctx,
+			"SELECT current, withdrawn FROM balances WHERE user_id = $1", 2,
+		).Scan(&current, &withdrawn)
+		require.NoError(t, err)
+		assert.Equal(t, 100.0, current)
+		assert.Equal(t, 0.0, withdrawn)
+
+		entries, err := repo.FindByUserID(ctx, 2)
+		require.NoError(t, err)
+		require.Len(t, entries, 6)
+		last := entries[len(entries)-1]
+		assert.Equal(t, model.LedgerEntryKindReversal, last.Kind())
+		require.NotNil(t, last.ReversalOf())
+		assert.Equal(t, withdrawal.TransactionID(), *last.ReversalOf())
+		assert.Equal(t, "withdrawal cancelled", last.Description())
+	})
+}
+
+func TestLedgerRepository_Constraints(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewLedgerRepository(pool)
+	ctx := context.Background()
+
+	t.Run("entries cannot be updated or deleted", func(t *testing.T) {
+		err := repo.Post(ctx, adjustmentPosting(t, 1, model.MustParsePoints("10")))
+		require.NoError(t, err)
+
+		_, err = pool.Exec(ctx, "UPDATE ledger_entries SET amount = amount * 2")
+		assert.Error(t, err)
+
+		_, err = pool.Exec(ctx, "DELETE FROM ledger_entries")
+		assert.Error(t, err)
+	})
+
+	t.Run("balance cannot go negative", func(t *testing.T) {
+		withdrawalID := createWithdrawal(t, pool, 2, model.MustParsePoints("40"))
+		err := repo.Post(ctx, adjustmentPosting(t, 2, model.MustParsePoints("30")))
+		require.NoError(t, err)
+
+		posting, err := model.NewWithdrawalPosting(2, withdrawalID, model.MustParsePoints("40"))
+		require.NoError(t, err)
+		err = repo.Post(ctx, posting)
+
+		assert.ErrorIs(t, err, domainerrors.ErrInsufficientFunds)
+
+		var current float64
+		err = pool.QueryRow(ctx, "SELECT current FROM balances WHERE user_id = $1", 2).Scan(&current)
+		require.NoError(t, err)
+		assert.Equal(t, 30.0, current)
+	})
+
+	t.Run("negative balance from adjustment is not insufficient funds", func(t *testing.T) {
+		err := repo.Post(ctx, adjustmentPosting(t, 2, model.MustParsePoints("-100")))
+
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, domainerrors.ErrInsufficientFunds)
+
+		var current float64
+		err = pool.QueryRow(ctx, "SELECT current FROM balances WHERE user_id = $1", 2).Scan(&current)
+		require.NoError(t, err)
+		assert.Equal(t, 30.0, current)
+	})
+
+	t.Run("unbalanced transaction is rejected", func(t *testing.T) {
+		_, err := pool.Exec(ctx,
+			`INSERT INTO ledger_entries (transaction_id, kind, account, user_id, amount)
+			 VALUES (nextval('ledger_transaction_id_seq'), 'ADJUSTMENT', 'USER', $1, $2)`,
+			1, 10.0,
+		)
+		assert.Error(t, err)
+	})
+}
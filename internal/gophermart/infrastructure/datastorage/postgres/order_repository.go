@@ -8,6 +8,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
 )
@@ -24,15 +25,22 @@ func NewOrderRepositoryTx(tx pgx.Tx) repository.OrderRepository {
 	return &orderRepository{querier: tx}
 }
 
+// Create сохраняет заказ и первую запись его истории статусов.
 func (r *orderRepository) Create(ctx context.Context, order *model.Order) error {
-	query := `INSERT INTO orders (user_id, number, status, accrual, uploaded_at) 
-	          VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	query := `WITH inserted AS (
+	              INSERT INTO orders (user_id, number, status, accrual, uploaded_at) 
+	              VALUES ($1, $2, $3, $4, $5) RETURNING id, status, uploaded_at
+	          ), history AS (
+	              INSERT INTO order_status_history (order_id, status, created_at) 
+	              SELECT id, status, uploaded_at FROM inserted
+	          )
+	          SELECT id FROM inserted`
 	var id int64
 	err := r.querier.QueryRow(ctx, query, order.UserID(), order.Number(), order.Status(), order.Accrual(), order.UploadedAt()).Scan(&id)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			return errors.New("order number already exists")
+			return domainerrors.ErrOrderAlreadyExists
 		}
 		return err
 	}
@@ -52,7 +60,7 @@ func (r *orderRepository) FindByUserID(ctx context.Context, userID int64) ([]*mo
 		var id, uid int64
 		var number string
 		var status model.OrderStatus
-		var accrual *float64
+		var accrual *model.Points
 		var uploadedAt time.Time
 		err := rows.Scan(&id, &uid, &number, &status, &accrual, &uploadedAt)
 		if err != nil {
@@ -62,10 +70,22 @@ func (r *orderRepository) FindByUserID(ctx context.Context, userID int64) ([]*mo
 	})
 }
 
-func (r *orderRepository) FindByUserIDIterator(ctx context.Context, userID int64) (Iterator[*model.Order], error) {
+func (r *orderRepository) FindByUserIDIterator(ctx context.Context, userID int64, filter repository.OrderFilter) (Iterator[*model.Order], error) {
 	query := `SELECT id, user_id, number, status, accrual, uploaded_at 
-	          FROM orders WHERE user_id = $1 ORDER BY uploaded_at DESC`
-	rows, err := r.querier.Query(ctx, query, userID)
+	          FROM orders WHERE user_id = $1 
+	            AND ($2::TEXT = '' OR status = $2) 
+	            AND ($3::TIMESTAMP IS NULL OR uploaded_at >= $3) 
+	            AND ($4::TIMESTAMP IS NULL OR uploaded_at < $4) 
+	            AND ($5::TIMESTAMP IS NULL OR (uploaded_at, id) < ($5, $6)) 
+	          ORDER BY uploaded_at DESC, id DESC LIMIT NULLIF($7::INT, 0)`
+	var afterUploadedAt *time.Time
+	var afterID int64
+	if filter.After != nil {
+		afterUploadedAt = &filter.After.UploadedAt
+		afterID = filter.After.ID
+	}
+	rows, err := r.querier.Query(ctx, query, userID, string(filter.Status),
+		nullTime(filter.From), nullTime(filter.To), afterUploadedAt, afterID, filter.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +94,7 @@ func (r *orderRepository) FindByUserIDIterator(ctx context.Context, userID int64
 		var id, uid int64
 		var number string
 		var status model.OrderStatus
-		var accrual *float64
+		var accrual *model.Points
 		var uploadedAt time.Time
 		err := rows.Scan(&id, &uid, &number, &status, &accrual, &uploadedAt)
 		if err != nil {
@@ -90,7 +110,7 @@ func (r *orderRepository) FindByNumber(ctx context.Context, number string) (*mod
 	var id, userID int64
 	var num string
 	var status model.OrderStatus
-	var accrual *float64
+	var accrual *model.Points
 	var uploadedAt time.Time
 	err := r.querier.QueryRow(ctx, query, number).Scan(&id, &userID, &num, &status, &accrual, &uploadedAt)
 	if err != nil {
@@ -108,7 +128,7 @@ func (r *orderRepository) FindByID(ctx context.Context, id int64) (*model.Order,
 	var orderID, userID int64
 	var number string
 	var status model.OrderStatus
-	var accrual *float64
+	var accrual *model.Points
 	var uploadedAt time.Time
 	err := r.querier.QueryRow(ctx, query, id).Scan(&orderID, &userID, &number, &status, &accrual, &uploadedAt)
 	if err != nil {
@@ -120,12 +140,55 @@ func (r *orderRepository) FindByID(ctx context.Context, id int64) (*model.Order,
 	return model.RestoreOrder(orderID, userID, number, status, accrual, uploadedAt), nil
 }
 
-func (r *orderRepository) UpdateStatus(ctx context.Context, orderID int64, status model.OrderStatus, accrual *float64) error {
-	query := `UPDATE orders SET status = $1, accrual = $2 WHERE id = $3`
-	_, err := r.querier.Exec(ctx, query, status, accrual, orderID)
+func (r *orderRepository) UpdateStatus(ctx context.Context, orderID int64, status model.OrderStatus, accrual *model.Points, note model.OrderStatusNote) (bool, error) {
+	query := `WITH locked AS (
+	              SELECT id, status FROM orders 
+	              WHERE id = $3 AND status NOT IN ('PROCESSED', 'INVALID') 
+	              FOR UPDATE
+	          ), updated AS (
+	              UPDATE orders o SET status = $1, accrual = $2 
+	              FROM locked c WHERE o.id = c.id 
+	              RETURNING o.id
+	          ), history AS (
+	              INSERT INTO order_status_history (order_id, previous_status, status, accrual, accrual_status, reason) 
+	              SELECT c.id, c.status, $1, $2, $4, $5 FROM locked c WHERE c.status <> $1
+	          )
+	          SELECT COUNT(*) FROM updated`
+	var count int
+	err := r.querier.QueryRow(ctx, query, status, accrual, orderID, note.AccrualStatus, note.Reason).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+// LockUser берёт транзакционную advisory-блокировку: строки пользователя для неё не подходят,
+// потому что удаление учётной записи блокирует строку users в отдельной транзакции.
+func (r *orderRepository) LockUser(ctx context.Context, userID int64) error {
+	_, err := r.querier.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtextextended('orders:user:' || $1::BIGINT, 0))`, userID)
 	return err
 }
 
+func (r *orderRepository) FindStatusHistory(ctx context.Context, orderID int64) ([]*model.OrderStatusChange, error) {
+	query := `SELECT id, order_id, COALESCE(previous_status, ''), status, accrual, accrual_status, reason, created_at 
+	          FROM order_status_history WHERE order_id = $1 
+	          ORDER BY created_at ASC, id ASC`
+	rows, err := r.querier.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRows(rows, func(rows pgx.Rows) (*model.OrderStatusChange, error) {
+		change := &model.OrderStatusChange{}
+		err := rows.Scan(&change.ID, &change.OrderID, &change.PreviousStatus, &change.Status,
+			&change.Accrual, &change.AccrualStatus, &change.Reason, &change.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		return change, nil
+	})
+}
+
 func (r *orderRepository) FindPending(ctx context.Context, limit int) ([]*model.Order, error) {
 	query := `SELECT id, user_id, number, status, accrual, uploaded_at 
 	          FROM orders WHERE status IN ('NEW', 'PROCESSING') 
@@ -139,7 +202,7 @@ func (r *orderRepository) FindPending(ctx context.Context, limit int) ([]*model.
 		var id, userID int64
 		var number string
 		var status model.OrderStatus
-		var accrual *float64
+		var accrual *model.Points
 		var uploadedAt time.Time
 
 		err := rows.Scan(&id, &userID, &number, &status, &accrual, &uploadedAt)
@@ -151,6 +214,17 @@ func (r *orderRepository) FindPending(ctx context.Context, limit int) ([]*model.
 	})
 }
 
+func (r *orderRepository) HasPendingByUserID(ctx context.Context, userID int64) (bool, error) {
+	query := `SELECT EXISTS (
+	              SELECT 1 FROM orders WHERE user_id = $1 AND status IN ('NEW', 'PROCESSING')
+	          )`
+	var exists bool
+	if err := r.querier.QueryRow(ctx, query, userID).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
 func (r *orderRepository) FindPendingIterator(ctx context.Context, limit int) (Iterator[*model.Order], error) {
 	query := `SELECT id, user_id, number, status, accrual, uploaded_at 
 	          FROM orders WHERE status IN ('NEW', 'PROCESSING') 
@@ -164,7 +238,7 @@ func (r *orderRepository) FindPendingIterator(ctx context.Context, limit int) (I
 		var id, userID int64
 		var number string
 		var status model.OrderStatus
-		var accrual *float64
+		var accrual *model.Points
 		var uploadedAt time.Time
 		err := rows.Scan(&id, &userID, &number, &status, &accrual, &uploadedAt)
 		if err != nil {
@@ -4,6 +4,8 @@ package postgres_test
 
 import (
 	"context"
+	"errors"
+	"io"
 	"testing"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/infrastructure/datastorage/postgres"
 )
 
@@ -60,7 +63,7 @@ func TestOrderRepository_FindByNumber(t *testing.T) {
 
 	t.Run("finds existing order", func(t *testing.T) {
 		now := time.Now()
-		accrual := 100.5
+		accrual := model.MustParsePoints("100.5")
 		_, err := pool.Exec(ctx,
 			"INSERT INTO orders (user_id, number, status, accrual, uploaded_at) VALUES ($1, $2, $3, $4, $5)",
 			1, "79927398713", "PROCESSED", accrual, now,
@@ -126,6 +129,85 @@ func TestOrderRepository_FindByUserID(t *testing.T) {
 	})
 }
 
+func collectOrderNumbers(t *testing.T, it repository.Iterator[*model.Order]) []string {
+	t.Helper()
+	defer it.Close()
+
+	var numbers []string
+	for {
+		order, err := it.Next()
+		if errors.Is(err, io.EOF) {
+			return numbers
+		}
+		require.NoError(t, err)
+		numbers = append(numbers, order.Number())
+	}
+}
+
+func TestOrderRepository_FindByUserIDIterator(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewOrderRepository(pool)
+	ctx := context.Background()
+
+	base := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	for i, o := range []struct {
+		number     string
+		status     string
+		uploadedAt time.Time
+	}{
+		{"order1", "NEW", base.Add(-3 * time.Hour)},
+		{"order2", "PROCESSED", base.Add(-2 * time.Hour)},
+		{"order3", "PROCESSED", base.Add(-2 * time.Hour)},
+		{"order4", "INVALID", base},
+	} {
+		_, err := pool.Exec(ctx,
+			"INSERT INTO orders (user_id, number, status, uploaded_at) VALUES ($1, $2, $3, $4)",
+			1, o.number, o.status, o.uploadedAt,
+		)
+		require.NoError(t, err, i)
+	}
+	_, err := pool.Exec(ctx,
+		"INSERT INTO orders (user_id, number, status, uploaded_at) VALUES ($1, $2, $3, $4)",
+		2, "foreign", "NEW", base,
+	)
+	require.NoError(t, err)
+
+	t.Run("without filter returns all orders newest first", func(t *testing.T) {
+		it, err := repo.FindByUserIDIterator(ctx, 1, repository.OrderFilter{})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"order4", "order3", "order2", "order1"}, collectOrderNumbers(t, it))
+	})
+
+	t.Run("pages through orders with equal upload time", func(t *testing.T) {
+		it, err := repo.FindByUserIDIterator(ctx, 1, repository.OrderFilter{Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"order4", "order3"}, collectOrderNumbers(t, it))
+
+		order3, err := repo.FindByNumber(ctx, "order3")
+		require.NoError(t, err)
+		it, err = repo.FindByUserIDIterator(ctx, 1, repository.OrderFilter{
+			After: &repository.OrderCursor{UploadedAt: order3.UploadedAt(), ID: order3.ID()},
+			Limit: 2,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"order2", "order1"}, collectOrderNumbers(t, it))
+	})
+
+	t.Run("filters by status and upload date", func(t *testing.T) {
+		it, err := repo.FindByUserIDIterator(ctx, 1, repository.OrderFilter{Status: model.OrderStatusProcessed})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"order3", "order2"}, collectOrderNumbers(t, it))
+
+		it, err = repo.FindByUserIDIterator(ctx, 1, repository.OrderFilter{
+			From: base.Add(-2 * time.Hour),
+			To:   base,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"order3", "order2"}, collectOrderNumbers(t, it))
+	})
+}
+
 func TestOrderRepository_UpdateStatus(t *testing.T) {
 	pool := setupTestDB(t)
 	repo := postgres.NewOrderRepository(pool)
@@ -137,13 +219,14 @@ func TestOrderRepository_UpdateStatus(t *testing.T) {
 		err = repo.Create(ctx, order)
 		require.NoError(t, err)
 
-		accrual := 150.75
-		err = repo.UpdateStatus(ctx, order.ID(), model.OrderStatusProcessed, &accrual)
+		accrual := model.MustParsePoints("150.75")
+		updated, err := repo.UpdateStatus(ctx, order.ID(), model.OrderStatusProcessed, &accrual, model.OrderStatusNote{AccrualStatus: "PROCESSED"})
 
 		require.NoError(t, err)
+		assert.True(t, updated)
 
 		var status string
-		var dbAccrual *float64
+		var dbAccrual *model.Points
 		err = pool.QueryRow(ctx,
 			"SELECT status, accrual FROM orders WHERE id = $1", order.ID(),
 		).Scan(&status, &dbAccrual)
@@ -159,9 +242,10 @@ func TestOrderRepository_UpdateStatus(t *testing.T) {
 		err = repo.Create(ctx, order)
 		require.NoError(t, err)
 
-		err = repo.UpdateStatus(ctx, order.ID(), model.OrderStatusInvalid, nil)
+		updated, err := repo.UpdateStatus(ctx, order.ID(), model.OrderStatusInvalid, nil, model.OrderStatusNote{})
 
 		require.NoError(t, err)
+		assert.True(t, updated)
 
 		var status string
 		var dbAccrual *float64
@@ -172,4 +256,125 @@ func TestOrderRepository_UpdateStatus(t *testing.T) {
 		assert.Equal(t, "INVALID", status)
 		assert.Nil(t, dbAccrual)
 	})
+
+	t.Run("does not change final status", func(t *testing.T) {
+		order, err := model.NewOrder(1, "4561261212345467")
+		require.NoError(t, err)
+		err = repo.Create(ctx, order)
+		require.NoError(t, err)
+
+		accrual := model.MustParsePoints("10")
+		updated, err := repo.UpdateStatus(ctx, order.ID(), model.OrderStatusProcessed, &accrual, model.OrderStatusNote{AccrualStatus: "PROCESSED"})
+		require.NoError(t, err)
+		require.True(t, updated)
+
+		other := model.MustParsePoints("20")
+		updated, err = repo.UpdateStatus(ctx, order.ID(), model.OrderStatusProcessed, &other, model.OrderStatusNote{AccrualStatus: "PROCESSED"})
+
+		require.NoError(t, err)
+		assert.False(t, updated)
+
+		saved, err := repo.FindByID(ctx, order.ID())
+		require.NoError(t, err)
+		require.NotNil(t, saved.Accrual())
+		assert.Equal(t, accrual, *saved.Accrual())
+	})
+}
+
+func TestOrderRepository_HasPendingByUserID(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewOrderRepository(pool)
+	ctx := context.Background()
+
+	order, err := model.NewOrder(1, "79927398713")
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, order))
+
+	pending, err := repo.HasPendingByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, pending)
+
+	pending, err = repo.HasPendingByUserID(ctx, 2)
+	require.NoError(t, err)
+	assert.False(t, pending)
+
+	updated, err := repo.UpdateStatus(ctx, order.ID(), model.OrderStatusInvalid, nil, model.OrderStatusNote{})
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	pending, err = repo.HasPendingByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, pending)
+}
+
+func TestOrderRepository_FindStatusHistory(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewOrderRepository(pool)
+	ctx := context.Background()
+
+	order, err := model.NewOrder(1, "79927398713")
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, order))
+
+	_, err = repo.UpdateStatus(ctx, order.ID(), model.OrderStatusProcessing, nil, model.OrderStatusNote{AccrualStatus: "PROCESSING"})
+	require.NoError(t, err)
+	_, err = repo.UpdateStatus(ctx, order.ID(), model.OrderStatusProcessing, nil, model.OrderStatusNote{AccrualStatus: "PROCESSING"})
+	require.NoError(t, err)
+	_, err = repo.UpdateStatus(ctx, order.ID(), model.OrderStatusInvalid, nil, model.OrderStatusNote{AccrualStatus: "INVALID", Reason: "rejected by accrual system"})
+	require.NoError(t, err)
+	_, err = repo.UpdateStatus(ctx, order.ID(), model.OrderStatusProcessed, nil, model.OrderStatusNote{AccrualStatus: "PROCESSED"})
+	require.NoError(t, err)
+
+	history, err := repo.FindStatusHistory(ctx, order.ID())
+
+	require.NoError(t, err)
+	require.Len(t, history, 3, "repeated status and changes after final status are not recorded")
+	assert.Equal(t, model.OrderStatus(""), history[0].PreviousStatus)
+	assert.Equal(t, model.OrderStatusNew, history[0].Status)
+	assert.Equal(t, model.OrderStatusNew, history[1].PreviousStatus)
+	assert.Equal(t, model.OrderStatusProcessing, history[1].Status)
+	assert.Equal(t, "PROCESSING", history[1].AccrualStatus)
+	assert.Equal(t, model.OrderStatusInvalid, history[2].Status)
+	assert.Equal(t, "rejected by accrual system", history[2].Reason)
+}
+
+func TestOrderRepository_LockUser(t *testing.T) {
+	pool := setupTestDB(t)
+	uow := postgres.NewUnitOfWork(pool)
+	ctx := context.Background()
+
+	first, err := uow.Begin(ctx)
+	require.NoError(t, err)
+	defer first.Rollback(ctx)
+	require.NoError(t, first.OrderRepository().LockUser(ctx, 1))
+
+	locked := make(chan error, 1)
+	go func() {
+		second, err := uow.Begin(ctx)
+		if err != nil {
+			locked <- err
+			return
+		}
+		defer second.Rollback(ctx)
+		locked <- second.OrderRepository().LockUser(ctx, 1)
+	}()
+
+	select {
+	case err := <-locked:
+		t.Fatalf("second lock acquired while the first transaction is open: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	other, err := uow.Begin(ctx)
+	require.NoError(t, err)
+	assert.NoError(t, other.OrderRepository().LockUser(ctx, 2), "lock of another user must not wait")
+	require.NoError(t, other.Rollback(ctx))
+
+	require.NoError(t, first.Commit(ctx))
+	select {
+	case err := <-locked:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("second lock not acquired after the first transaction committed")
+	}
 }
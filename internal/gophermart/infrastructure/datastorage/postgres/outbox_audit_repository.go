@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+)
+
+type outboxAuditRepository struct {
+	querier Querier
+}
+
+func NewOutboxAuditRepository(pool *pgxpool.Pool) repository.OutboxAuditRepository {
+	return &outboxAuditRepository{querier: pool}
+}
+
+func NewOutboxAuditRepositoryTx(tx pgx.Tx) repository.OutboxAuditRepository {
+	return &outboxAuditRepository{querier: tx}
+}
+
+func (r *outboxAuditRepository) Record(ctx context.Context, entry *model.OutboxAuditEntry) error {
+	query := `INSERT INTO outbox_audit (outbox_id, action, actor_user_id, previous_status, previous_retries, previous_error) 
+	          VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+	return r.querier.QueryRow(ctx, query,
+		entry.OutboxID, entry.Action, entry.ActorUserID, entry.PreviousStatus, entry.PreviousRetries, entry.PreviousError,
+	).Scan(&entry.ID, &entry.CreatedAt)
+}
+
+func (r *outboxAuditRepository) FindByOutboxID(ctx context.Context, outboxID int64) ([]*model.OutboxAuditEntry, error) {
+	query := `SELECT id, outbox_id, action, actor_user_id, previous_status, previous_retries, previous_error, created_at 
+	          FROM outbox_audit WHERE outbox_id = $1 
+	          ORDER BY created_at ASC, id ASC`
+	rows, err := r.querier.Query(ctx, query, outboxID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRows(rows, func(rows pgx.Rows) (*model.OutboxAuditEntry, error) {
+		entry := &model.OutboxAuditEntry{}
+		err := rows.Scan(&entry.ID, &entry.OutboxID, &entry.Action, &entry.ActorUserID,
+			&entry.PreviousStatus, &entry.PreviousRetries, &entry.PreviousError, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		return entry, nil
+	})
+}
@@ -0,0 +1,196 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/infrastructure/datastorage/postgres"
+)
+
+func createDeadOutbox(t *testing.T, pool *pgxpool.Pool, repo repository.OutboxRepository, orderRepo repository.OrderRepository, number string) *model.Outbox {
+	t.Helper()
+	ctx := context.Background()
+
+	order, err := model.NewOrder(1, number)
+	require.NoError(t, err)
+	require.NoError(t, orderRepo.Create(ctx, order))
+
+	outbox := &model.Outbox{
+		OrderID:   order.ID(),
+		Status:    model.OutboxStatusPending,
+		Retries:   10,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	require.NoError(t, repo.Create(ctx, outbox))
+	leaseOutbox(t, pool, outbox.ID, "worker-1")
+	require.NoError(t, repo.MarkDead(ctx, outbox.ID, "worker-1", "accrual unavailable"))
+	return outbox
+}
+
+func TestOutboxRepository_ListAndFindByID(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewOutboxRepository(pool)
+	orderRepo := postgres.NewOrderRepository(pool)
+	ctx := context.Background()
+
+	dead := createDeadOutbox(t, pool, repo, orderRepo, "79927398713")
+
+	order, _ := model.NewOrder(1, "12345678903")
+	orderRepo.Create(ctx, order)
+	pending := &model.Outbox{
+		OrderID:   order.ID(),
+		Status:    model.OutboxStatusPending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	repo.Create(ctx, pending)
+
+	t.Run("filters by status", func(t *testing.T) {
+		outboxes, err := repo.List(ctx, repository.OutboxFilter{Status: model.OutboxStatusDead, Limit: 10})
+
+		require.NoError(t, err)
+		require.Len(t, outboxes, 1)
+		assert.Equal(t, dead.ID, outboxes[0].ID)
+		assert.Equal(t, "79927398713", outboxes[0].OrderNumber)
+		assert.Equal(t, "accrual unavailable", outboxes[0].LastError)
+	})
+
+	t.Run("filters by order number", func(t *testing.T) {
+		outboxes, err := repo.List(ctx, repository.OutboxFilter{OrderNumber: "12345678903", Limit: 10})
+
+		require.NoError(t, err)
+		require.Len(t, outboxes, 1)
+		assert.Equal(t, pending.ID, outboxes[0].ID)
+	})
+
+	t.Run("filters by age", func(t *testing.T) {
+		outboxes, err := repo.List(ctx, repository.OutboxFilter{OlderThan: time.Hour, Limit: 10})
+
+		require.NoError(t, err)
+		assert.Empty(t, outboxes)
+	})
+
+	t.Run("find by id", func(t *testing.T) {
+		outbox, err := repo.FindByID(ctx, dead.ID)
+
+		require.NoError(t, err)
+		require.NotNil(t, outbox)
+		assert.Equal(t, model.OutboxStatusDead, outbox.Status)
+
+		missing, err := repo.FindByID(ctx, 999)
+		require.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+}
+
+func TestOutboxRepository_Requeue(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewOutboxRepository(pool)
+	orderRepo := postgres.NewOrderRepository(pool)
+	auditRepo := postgres.NewOutboxAuditRepository(pool)
+	ctx := context.Background()
+
+	t.Run("requeues dead row and returns previous state", func(t *testing.T) {
+		dead := createDeadOutbox(t, pool, repo, orderRepo, "79927398713")
+
+		requeued, err := repo.Requeue(ctx, []int64{dead.ID})
+
+		require.NoError(t, err)
+		require.Len(t, requeued, 1)
+		assert.Equal(t, model.OutboxStatusDead, requeued[0].Status)
+		assert.Equal(t, 10, requeued[0].Retries)
+		assert.Equal(t, "accrual unavailable", requeued[0].LastError)
+
+		outbox, err := repo.FindByID(ctx, dead.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.OutboxStatusPending, outbox.Status)
+		assert.Equal(t, 0, outbox.Retries)
+		assert.Empty(t, outbox.LastError)
+
+		again, err := repo.Requeue(ctx, []int64{dead.ID})
+		require.NoError(t, err)
+		assert.Empty(t, again)
+	})
+
+	t.Run("requeues by status", func(t *testing.T) {
+		createDeadOutbox(t, pool, repo, orderRepo, "12345678903")
+		createDeadOutbox(t, pool, repo, orderRepo, "4532015112830366")
+
+		requeued, err := repo.RequeueByStatus(ctx, model.OutboxStatusDead)
+
+		require.NoError(t, err)
+		assert.Len(t, requeued, 2)
+
+		remaining, err := repo.List(ctx, repository.OutboxFilter{Status: model.OutboxStatusDead, Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, remaining)
+	})
+
+	t.Run("requeued review row starts a new polling period", func(t *testing.T) {
+		order, err := model.NewOrder(1, "5555555555554444")
+		require.NoError(t, err)
+		require.NoError(t, orderRepo.Create(ctx, order))
+		stale := time.Now().Add(-48 * time.Hour)
+		review := &model.Outbox{
+			OrderID:      order.ID(),
+			Status:       model.OutboxStatusInFlight,
+			PollingSince: stale,
+			CreatedAt:    stale,
+			UpdatedAt:    stale,
+		}
+		require.NoError(t, repo.Create(ctx, review))
+		leaseOutbox(t, pool, review.ID, "worker-1")
+		require.NoError(t, repo.UpdateStatus(ctx, review.ID, "worker-1", model.OutboxStatusReview))
+
+		requeued, err := repo.Requeue(ctx, []int64{review.ID})
+		require.NoError(t, err)
+		require.Len(t, requeued, 1)
+		assert.WithinDuration(t, stale, requeued[0].PollingSince, time.Second)
+
+		claimed, err := repo.ClaimBatch(ctx, "worker-1", 10, time.Minute)
+		require.NoError(t, err)
+		var polled *model.Outbox
+		for _, outbox := range claimed {
+			if outbox.ID == review.ID {
+				polled = outbox
+			}
+		}
+		require.NotNil(t, polled)
+		assert.WithinDuration(t, time.Now(), polled.PollingSince, time.Minute, "age is measured from the requeue, not the upload")
+	})
+
+	t.Run("audit trail", func(t *testing.T) {
+		outboxes, err := repo.List(ctx, repository.OutboxFilter{Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, outboxes, 1)
+
+		entry := &model.OutboxAuditEntry{
+			OutboxID:        outboxes[0].ID,
+			Action:          model.OutboxAuditActionRequeue,
+			ActorUserID:     42,
+			PreviousStatus:  model.OutboxStatusDead,
+			PreviousRetries: 10,
+			PreviousError:   "accrual unavailable",
+		}
+		err = auditRepo.Record(ctx, entry)
+		require.NoError(t, err)
+		assert.Greater(t, entry.ID, int64(0))
+
+		entries, err := auditRepo.FindByOutboxID(ctx, outboxes[0].ID)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, int64(42), entries[0].ActorUserID)
+		assert.Equal(t, model.OutboxStatusDead, entries[0].PreviousStatus)
+		assert.Equal(t, "accrual unavailable", entries[0].PreviousError)
+	})
+}
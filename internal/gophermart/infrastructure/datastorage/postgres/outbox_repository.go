@@ -2,9 +2,14 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
 )
@@ -22,15 +27,22 @@ func NewOutboxRepositoryTx(tx pgx.Tx) repository.OutboxRepository {
 }
 
 func (r *outboxRepository) Create(ctx context.Context, outbox *model.Outbox) error {
-	query := `INSERT INTO outbox (order_id, status, retries, created_at, updated_at) 
-	          VALUES ($1, $2, $3, $4, $5) RETURNING id`
-	err := r.querier.QueryRow(ctx, query, outbox.OrderID, outbox.Status, outbox.Retries, outbox.CreatedAt, outbox.UpdatedAt).Scan(&outbox.ID)
+	if outbox.NextAttemptAt.IsZero() {
+		outbox.NextAttemptAt = outbox.CreatedAt
+	}
+	if outbox.PollingSince.IsZero() {
+		outbox.PollingSince = outbox.CreatedAt
+	}
+	query := `INSERT INTO outbox (order_id, status, retries, next_attempt_at, polling_since, created_at, updated_at) 
+	          VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	err := r.querier.QueryRow(ctx, query, outbox.OrderID, outbox.Status, outbox.Retries, outbox.NextAttemptAt, outbox.PollingSince,
+		outbox.CreatedAt, outbox.UpdatedAt).Scan(&outbox.ID)
 	return err
 }
 
 func (r *outboxRepository) FindPending(ctx context.Context, limit int) ([]*model.Outbox, error) {
-	query := `SELECT id, order_id, status, retries, created_at, updated_at 
-	          FROM outbox WHERE status = 'PENDING' 
+	query := `SELECT id, order_id, status, retries, next_attempt_at, created_at, updated_at 
+	          FROM outbox WHERE status IN ('PENDING', 'IN_FLIGHT') AND next_attempt_at <= NOW() 
 	          ORDER BY created_at ASC LIMIT $1`
 	rows, err := r.querier.Query(ctx, query, limit)
 	if err != nil {
@@ -39,17 +51,56 @@ func (r *outboxRepository) FindPending(ctx context.Context, limit int) ([]*model
 
 	return scanRows(rows, func(rows pgx.Rows) (*model.Outbox, error) {
 		outbox := &model.Outbox{}
-		err := rows.Scan(&outbox.ID, &outbox.OrderID, &outbox.Status, &outbox.Retries, &outbox.CreatedAt, &outbox.UpdatedAt)
+		err := rows.Scan(&outbox.ID, &outbox.OrderID, &outbox.Status, &outbox.Retries, &outbox.NextAttemptAt, &outbox.CreatedAt, &outbox.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		return outbox, nil
+	})
+}
+
+func (r *outboxRepository) ClaimBatch(ctx context.Context, owner string, limit int, leaseTTL time.Duration) ([]*model.Outbox, error) {
+	query := `WITH claimable AS (
+	              SELECT id FROM outbox
+	              WHERE status IN ('PENDING', 'IN_FLIGHT') AND next_attempt_at <= NOW()
+	                AND (lease_expires_at IS NULL OR lease_expires_at <= NOW())
+	              ORDER BY created_at ASC
+	              LIMIT $1
+	              FOR UPDATE SKIP LOCKED
+	          )
+	          UPDATE outbox o
+	          SET lease_owner = $2, lease_expires_at = NOW() + $3::BIGINT * INTERVAL '1 millisecond', updated_at = NOW()
+	          FROM claimable c
+	          WHERE o.id = c.id
+	          RETURNING o.id, o.order_id, o.status, o.retries, o.next_attempt_at, o.lease_owner, o.lease_expires_at, o.last_error,
+	                    o.polling_since, o.created_at, o.updated_at`
+	rows, err := r.querier.Query(ctx, query, limit, owner, leaseTTL.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+
+	outboxes, err := scanRows(rows, func(rows pgx.Rows) (*model.Outbox, error) {
+		outbox := &model.Outbox{}
+		err := rows.Scan(&outbox.ID, &outbox.OrderID, &outbox.Status, &outbox.Retries, &outbox.NextAttemptAt,
+			&outbox.LeaseOwner, &outbox.LeaseExpiresAt, &outbox.LastError, &outbox.PollingSince, &outbox.CreatedAt, &outbox.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
 		return outbox, nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(outboxes, func(i, j int) bool {
+		return outboxes[i].CreatedAt.Before(outboxes[j].CreatedAt)
+	})
+	return outboxes, nil
 }
 
 func (r *outboxRepository) FindPendingIterator(ctx context.Context, limit int) (Iterator[*model.Outbox], error) {
-	query := `SELECT id, order_id, status, retries, created_at, updated_at 
-	          FROM outbox WHERE status = 'PENDING' 
+	query := `SELECT id, order_id, status, retries, next_attempt_at, created_at, updated_at 
+	          FROM outbox WHERE status IN ('PENDING', 'IN_FLIGHT') AND next_attempt_at <= NOW() 
 	          ORDER BY created_at ASC LIMIT $1`
 	rows, err := r.querier.Query(ctx, query, limit)
 	if err != nil {
@@ -58,7 +109,7 @@ func (r *outboxRepository) FindPendingIterator(ctx context.Context, limit int) (
 
 	return NewIterator(rows, func(rows pgx.Rows) (*model.Outbox, error) {
 		outbox := &model.Outbox{}
-		err := rows.Scan(&outbox.ID, &outbox.OrderID, &outbox.Status, &outbox.Retries, &outbox.CreatedAt, &outbox.UpdatedAt)
+		err := rows.Scan(&outbox.ID, &outbox.OrderID, &outbox.Status, &outbox.Retries, &outbox.NextAttemptAt, &outbox.CreatedAt, &outbox.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -66,14 +117,135 @@ func (r *outboxRepository) FindPendingIterator(ctx context.Context, limit int) (
 	}), nil
 }
 
-func (r *outboxRepository) UpdateStatus(ctx context.Context, outboxID int64, status model.OutboxStatus) error {
-	query := `UPDATE outbox SET status = $1, updated_at = NOW() WHERE id = $2`
-	_, err := r.querier.Exec(ctx, query, status, outboxID)
-	return err
+func (r *outboxRepository) UpdateStatus(ctx context.Context, outboxID int64, owner string, status model.OutboxStatus) error {
+	query := `UPDATE outbox SET status = $1, lease_owner = NULL, lease_expires_at = NULL, updated_at = NOW() 
+	          WHERE id = $2 AND lease_owner = $3`
+	tag, err := r.querier.Exec(ctx, query, status, outboxID, owner)
+	return leaseResult(tag, err)
 }
 
-func (r *outboxRepository) IncrementRetries(ctx context.Context, outboxID int64) error {
-	query := `UPDATE outbox SET retries = retries + 1, updated_at = NOW() WHERE id = $1`
-	_, err := r.querier.Exec(ctx, query, outboxID)
-	return err
+func (r *outboxRepository) ScheduleRetry(ctx context.Context, outboxID int64, owner string, delay time.Duration, lastError string) error {
+	query := `UPDATE outbox 
+	          SET retries = retries + 1, next_attempt_at = NOW() + $1::BIGINT * INTERVAL '1 millisecond', last_error = $2,
+	              lease_owner = NULL, lease_expires_at = NULL, updated_at = NOW() 
+	          WHERE id = $3 AND lease_owner = $4`
+	tag, err := r.querier.Exec(ctx, query, delay.Milliseconds(), lastError, outboxID, owner)
+	return leaseResult(tag, err)
+}
+
+func (r *outboxRepository) MarkDead(ctx context.Context, outboxID int64, owner string, lastError string) error {
+	query := `UPDATE outbox 
+	          SET status = 'DEAD', last_error = $1, lease_owner = NULL, lease_expires_at = NULL, updated_at = NOW() 
+	          WHERE id = $2 AND lease_owner = $3`
+	tag, err := r.querier.Exec(ctx, query, lastError, outboxID, owner)
+	return leaseResult(tag, err)
+}
+
+// ScheduleNextAttempt переводит запись в IN_FLIGHT и откладывает следующий опрос на delay.
+func (r *outboxRepository) ScheduleNextAttempt(ctx context.Context, outboxID int64, owner string, delay time.Duration) error {
+	query := `UPDATE outbox 
+	          SET status = 'IN_FLIGHT', next_attempt_at = NOW() + $1::BIGINT * INTERVAL '1 millisecond',
+	              lease_owner = NULL, lease_expires_at = NULL, updated_at = NOW() 
+	          WHERE id = $2 AND lease_owner = $3`
+	tag, err := r.querier.Exec(ctx, query, delay.Milliseconds(), outboxID, owner)
+	return leaseResult(tag, err)
+}
+
+func (r *outboxRepository) Postpone(ctx context.Context, outboxID int64, owner string, delay time.Duration) error {
+	query := `UPDATE outbox 
+	          SET next_attempt_at = NOW() + $1::BIGINT * INTERVAL '1 millisecond',
+	              lease_owner = NULL, lease_expires_at = NULL, updated_at = NOW() 
+	          WHERE id = $2 AND lease_owner = $3`
+	tag, err := r.querier.Exec(ctx, query, delay.Milliseconds(), outboxID, owner)
+	return leaseResult(tag, err)
+}
+
+// leaseResult сообщает о потере аренды, если запись уже не принадлежит обработчику:
+// аренда истекла и запись захватил другой экземпляр либо её перезапустили вручную.
+func leaseResult(tag pgconn.CommandTag, err error) error {
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domainerrors.ErrOutboxLeaseLost
+	}
+	return nil
+}
+
+const outboxDetailsColumns = `o.id, o.order_id, ord.number, o.status, o.retries, o.next_attempt_at,
+	COALESCE(o.lease_owner, ''), o.lease_expires_at, o.last_error, o.polling_since, o.created_at, o.updated_at`
+
+func scanOutboxDetails(row pgx.Row) (*model.Outbox, error) {
+	outbox := &model.Outbox{}
+	err := row.Scan(&outbox.ID, &outbox.OrderID, &outbox.OrderNumber, &outbox.Status, &outbox.Retries, &outbox.NextAttemptAt,
+		&outbox.LeaseOwner, &outbox.LeaseExpiresAt, &outbox.LastError, &outbox.PollingSince, &outbox.CreatedAt, &outbox.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return outbox, nil
+}
+
+func (r *outboxRepository) FindByID(ctx context.Context, outboxID int64) (*model.Outbox, error) {
+	query := `SELECT ` + outboxDetailsColumns + ` 
+	          FROM outbox o JOIN orders ord ON ord.id = o.order_id 
+	          WHERE o.id = $1`
+	outbox, err := scanOutboxDetails(r.querier.QueryRow(ctx, query, outboxID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return outbox, nil
+}
+
+func (r *outboxRepository) List(ctx context.Context, filter repository.OutboxFilter) ([]*model.Outbox, error) {
+	query := `SELECT ` + outboxDetailsColumns + ` 
+	          FROM outbox o JOIN orders ord ON ord.id = o.order_id 
+	          WHERE ($1::TEXT = '' OR o.status = $1) 
+	            AND ($2::TEXT = '' OR ord.number = $2) 
+	            AND o.created_at <= NOW() - $3::BIGINT * INTERVAL '1 millisecond' 
+	          ORDER BY o.created_at ASC, o.id ASC LIMIT $4`
+	rows, err := r.querier.Query(ctx, query, string(filter.Status), filter.OrderNumber, filter.OlderThan.Milliseconds(), filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRows(rows, func(rows pgx.Rows) (*model.Outbox, error) {
+		return scanOutboxDetails(rows)
+	})
+}
+
+func (r *outboxRepository) Requeue(ctx context.Context, ids []int64) ([]*model.Outbox, error) {
+	return r.requeue(ctx, `o.id = ANY($1::BIGINT[])`, ids)
+}
+
+func (r *outboxRepository) RequeueByStatus(ctx context.Context, status model.OutboxStatus) ([]*model.Outbox, error) {
+	return r.requeue(ctx, `o.status = $1::TEXT`, string(status))
+}
+
+// requeue блокирует подходящие записи, запоминает их состояние и возвращает их в очередь.
+// Опрос начинается заново, поэтому заказ из REVIEW снова получает ORDER_MAX_AGE на финальный статус.
+func (r *outboxRepository) requeue(ctx context.Context, condition string, arg any) ([]*model.Outbox, error) {
+	query := `WITH target AS (
+	              SELECT ` + outboxDetailsColumns + `
+	              FROM outbox o JOIN orders ord ON ord.id = o.order_id
+	              WHERE ` + condition + ` AND o.status IN ('DEAD', 'REVIEW')
+	              FOR UPDATE OF o
+	          ), requeued AS (
+	              UPDATE outbox o
+	              SET status = 'PENDING', retries = 0, next_attempt_at = NOW(), last_error = '', polling_since = NOW(),
+	                  lease_owner = NULL, lease_expires_at = NULL, updated_at = NOW()
+	              FROM target t
+	              WHERE o.id = t.id
+	          )
+	          SELECT * FROM target ORDER BY created_at ASC, id ASC`
+	rows, err := r.querier.Query(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRows(rows, func(rows pgx.Rows) (*model.Outbox, error) {
+		return scanOutboxDetails(rows)
+	})
 }
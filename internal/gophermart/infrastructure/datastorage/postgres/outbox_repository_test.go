@@ -5,12 +5,15 @@ package postgres_test
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/infrastructure/datastorage/postgres"
 )
@@ -117,6 +120,93 @@ func TestOutboxRepository_FindPending(t *testing.T) {
 	})
 }
 
+func TestOutboxRepository_ClaimBatch(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewOutboxRepository(pool)
+	ctx := context.Background()
+
+	orderRepo := postgres.NewOrderRepository(pool)
+	for i := 0; i < 4; i++ {
+		order, _ := model.NewOrder(1, fmt.Sprintf("order%d", i))
+		orderRepo.Create(ctx, order)
+		outbox := &model.Outbox{
+			OrderID:   order.ID(),
+			Status:    model.OutboxStatusPending,
+			Retries:   0,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		repo.Create(ctx, outbox)
+	}
+
+	t.Run("concurrent workers claim disjoint rows", func(t *testing.T) {
+		var wg sync.WaitGroup
+		claimed := make([][]*model.Outbox, 2)
+		for i := range claimed {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				outboxes, err := repo.ClaimBatch(ctx, fmt.Sprintf("worker-%d", i), 3, time.Minute)
+				assert.NoError(t, err)
+				claimed[i] = outboxes
+			}(i)
+		}
+		wg.Wait()
+
+		seen := make(map[int64]string)
+		for i, outboxes := range claimed {
+			for _, outbox := range outboxes {
+				owner := fmt.Sprintf("worker-%d", i)
+				assert.Equal(t, owner, outbox.LeaseOwner)
+				require.NotNil(t, outbox.LeaseExpiresAt)
+				_, duplicate := seen[outbox.ID]
+				assert.False(t, duplicate, "outbox %d claimed twice", outbox.ID)
+				seen[outbox.ID] = owner
+			}
+		}
+		assert.Len(t, seen, 4)
+
+		outboxes, err := repo.ClaimBatch(ctx, "worker-3", 10, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, outboxes)
+	})
+
+	t.Run("expired lease is reclaimed", func(t *testing.T) {
+		_, err := pool.Exec(ctx, "UPDATE outbox SET lease_expires_at = NOW() - INTERVAL '1 second'")
+		require.NoError(t, err)
+
+		outboxes, err := repo.ClaimBatch(ctx, "worker-3", 10, time.Minute)
+
+		require.NoError(t, err)
+		require.Len(t, outboxes, 4)
+		for _, outbox := range outboxes {
+			assert.Equal(t, "worker-3", outbox.LeaseOwner)
+		}
+	})
+
+	t.Run("status update releases lease", func(t *testing.T) {
+		outboxes, err := repo.FindPending(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, outboxes, 1)
+
+		err = repo.ScheduleRetry(ctx, outboxes[0].ID, "worker-3", 0, "temporary error")
+		require.NoError(t, err)
+
+		claimed, err := repo.ClaimBatch(ctx, "worker-4", 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, outboxes[0].ID, claimed[0].ID)
+	})
+}
+
+// leaseOutbox передаёт запись в аренду owner, как это сделал бы ClaimBatch.
+func leaseOutbox(t *testing.T, pool *pgxpool.Pool, outboxID int64, owner string) {
+	t.Helper()
+	_, err := pool.Exec(context.Background(),
+		"UPDATE outbox SET lease_owner = $1, lease_expires_at = NOW() + INTERVAL '1 minute' WHERE id = $2", owner, outboxID)
+	require.NoError(t, err)
+}
+
 func TestOutboxRepository_UpdateStatus(t *testing.T) {
 	pool := setupTestDB(t)
 	repo := postgres.NewOutboxRepository(pool)
@@ -136,7 +226,8 @@ func TestOutboxRepository_UpdateStatus(t *testing.T) {
 		}
 		repo.Create(ctx, outbox)
 
-		err := repo.UpdateStatus(ctx, outbox.ID, model.OutboxStatusProcessed)
+		leaseOutbox(t, pool, outbox.ID, "worker-1")
+		err := repo.UpdateStatus(ctx, outbox.ID, "worker-1", model.OutboxStatusProcessed)
 
 		require.NoError(t, err)
 
@@ -148,21 +239,22 @@ func TestOutboxRepository_UpdateStatus(t *testing.T) {
 		assert.Equal(t, "PROCESSED", status)
 	})
 
-	t.Run("updates status to FAILED", func(t *testing.T) {
+	t.Run("updates status to REVIEW", func(t *testing.T) {
 		order, _ := model.NewOrder(1, "12345678903")
 		orderRepo := postgres.NewOrderRepository(pool)
 		orderRepo.Create(ctx, order)
 
 		outbox := &model.Outbox{
 			OrderID:   order.ID(),
-			Status:    model.OutboxStatusPending,
-			Retries:   3,
+			Status:    model.OutboxStatusInFlight,
+			Retries:   0,
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		}
 		repo.Create(ctx, outbox)
 
-		err := repo.UpdateStatus(ctx, outbox.ID, model.OutboxStatusFailed)
+		leaseOutbox(t, pool, outbox.ID, "worker-1")
+		err := repo.UpdateStatus(ctx, outbox.ID, "worker-1", model.OutboxStatusReview)
 
 		require.NoError(t, err)
 
@@ -171,16 +263,16 @@ func TestOutboxRepository_UpdateStatus(t *testing.T) {
 			"SELECT status FROM outbox WHERE id = $1", outbox.ID,
 		).Scan(&status)
 		require.NoError(t, err)
-		assert.Equal(t, "FAILED", status)
+		assert.Equal(t, "REVIEW", status)
 	})
 }
 
-func TestOutboxRepository_IncrementRetries(t *testing.T) {
+func TestOutboxRepository_ScheduleRetry(t *testing.T) {
 	pool := setupTestDB(t)
 	repo := postgres.NewOutboxRepository(pool)
 	ctx := context.Background()
 
-	t.Run("increments retries counter", func(t *testing.T) {
+	t.Run("increments retries and postpones next attempt", func(t *testing.T) {
 		order, _ := model.NewOrder(1, "79927398713")
 		orderRepo := postgres.NewOrderRepository(pool)
 		orderRepo.Create(ctx, order)
@@ -194,19 +286,29 @@ func TestOutboxRepository_IncrementRetries(t *testing.T) {
 		}
 		repo.Create(ctx, outbox)
 
-		err := repo.IncrementRetries(ctx, outbox.ID)
+		leaseOutbox(t, pool, outbox.ID, "worker-1")
+		err := repo.ScheduleRetry(ctx, outbox.ID, "worker-1", time.Hour, "accrual unavailable")
 
 		require.NoError(t, err)
 
 		var retries int
+		var status, lastError string
+		var due bool
 		err = pool.QueryRow(ctx,
-			"SELECT retries FROM outbox WHERE id = $1", outbox.ID,
-		).Scan(&retries)
+			"SELECT retries, status, last_error, next_attempt_at <= NOW() FROM outbox WHERE id = $1", outbox.ID,
+		).Scan(&retries, &status, &lastError, &due)
 		require.NoError(t, err)
 		assert.Equal(t, 2, retries)
+		assert.Equal(t, "PENDING", status)
+		assert.Equal(t, "accrual unavailable", lastError)
+		assert.False(t, due)
+
+		claimed, err := repo.ClaimBatch(ctx, "worker-1", 10, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, claimed)
 	})
 
-	t.Run("multiple increments work correctly", func(t *testing.T) {
+	t.Run("multiple retries accumulate", func(t *testing.T) {
 		order, _ := model.NewOrder(1, "12345678903")
 		orderRepo := postgres.NewOrderRepository(pool)
 		orderRepo.Create(ctx, order)
@@ -220,16 +322,209 @@ func TestOutboxRepository_IncrementRetries(t *testing.T) {
 		}
 		repo.Create(ctx, outbox)
 
-		err := repo.IncrementRetries(ctx, outbox.ID)
+		leaseOutbox(t, pool, outbox.ID, "worker-1")
+		err := repo.ScheduleRetry(ctx, outbox.ID, "worker-1", 0, "first")
 		require.NoError(t, err)
-		err = repo.IncrementRetries(ctx, outbox.ID)
+		leaseOutbox(t, pool, outbox.ID, "worker-1")
+		err = repo.ScheduleRetry(ctx, outbox.ID, "worker-1", 0, "second")
 		require.NoError(t, err)
 
 		var retries int
+		var lastError string
 		err = pool.QueryRow(ctx,
-			"SELECT retries FROM outbox WHERE id = $1", outbox.ID,
-		).Scan(&retries)
+			"SELECT retries, last_error FROM outbox WHERE id = $1", outbox.ID,
+		).Scan(&retries, &lastError)
 		require.NoError(t, err)
 		assert.Equal(t, 2, retries)
+		assert.Equal(t, "second", lastError)
 	})
 }
+
+func TestOutboxRepository_MarkDead(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewOutboxRepository(pool)
+	ctx := context.Background()
+
+	order, _ := model.NewOrder(1, "79927398713")
+	orderRepo := postgres.NewOrderRepository(pool)
+	orderRepo.Create(ctx, order)
+
+	outbox := &model.Outbox{
+		OrderID:   order.ID(),
+		Status:    model.OutboxStatusPending,
+		Retries:   3,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	repo.Create(ctx, outbox)
+
+	leaseOutbox(t, pool, outbox.ID, "worker-1")
+	err := repo.MarkDead(ctx, outbox.ID, "worker-1", "accrual unavailable")
+
+	require.NoError(t, err)
+
+	var status, lastError string
+	err = pool.QueryRow(ctx,
+		"SELECT status, last_error FROM outbox WHERE id = $1", outbox.ID,
+	).Scan(&status, &lastError)
+	require.NoError(t, err)
+	assert.Equal(t, "DEAD", status)
+	assert.Equal(t, "accrual unavailable", lastError)
+
+	claimed, err := repo.ClaimBatch(ctx, "worker-1", 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+}
+
+func TestOutboxRepository_ScheduleNextAttempt(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewOutboxRepository(pool)
+	ctx := context.Background()
+
+	t.Run("in-flight outbox is returned only after next attempt time", func(t *testing.T) {
+		order, _ := model.NewOrder(1, "79927398713")
+		orderRepo := postgres.NewOrderRepository(pool)
+		orderRepo.Create(ctx, order)
+
+		outbox := &model.Outbox{
+			OrderID:   order.ID(),
+			Status:    model.OutboxStatusPending,
+			Retries:   0,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		repo.Create(ctx, outbox)
+
+		leaseOutbox(t, pool, outbox.ID, "worker-1")
+		err := repo.ScheduleNextAttempt(ctx, outbox.ID, "worker-1", time.Hour)
+		require.NoError(t, err)
+
+		var status string
+		err = pool.QueryRow(ctx,
+			"SELECT status FROM outbox WHERE id = $1", outbox.ID,
+		).Scan(&status)
+		require.NoError(t, err)
+		assert.Equal(t, "IN_FLIGHT", status)
+
+		outboxes, err := repo.FindPending(ctx, 10)
+		require.NoError(t, err)
+		assert.Empty(t, outboxes)
+
+		leaseOutbox(t, pool, outbox.ID, "worker-1")
+		err = repo.ScheduleNextAttempt(ctx, outbox.ID, "worker-1", 0)
+		require.NoError(t, err)
+
+		outboxes, err = repo.FindPending(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, outboxes, 1)
+		assert.Equal(t, outbox.ID, outboxes[0].ID)
+		assert.Equal(t, model.OutboxStatusInFlight, outboxes[0].Status)
+	})
+
+	t.Run("outbox in review is not returned", func(t *testing.T) {
+		order, _ := model.NewOrder(1, "12345678903")
+		orderRepo := postgres.NewOrderRepository(pool)
+		orderRepo.Create(ctx, order)
+
+		outbox := &model.Outbox{
+			OrderID:   order.ID(),
+			Status:    model.OutboxStatusPending,
+			Retries:   0,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		repo.Create(ctx, outbox)
+
+		leaseOutbox(t, pool, outbox.ID, "worker-1")
+		err := repo.UpdateStatus(ctx, outbox.ID, "worker-1", model.OutboxStatusReview)
+		require.NoError(t, err)
+
+		outboxes, err := repo.FindPending(ctx, 10)
+		require.NoError(t, err)
+		for _, o := range outboxes {
+			assert.NotEqual(t, outbox.ID, o.ID)
+		}
+	})
+}
+
+func TestOutboxRepository_Postpone(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewOutboxRepository(pool)
+	ctx := context.Background()
+
+	order, _ := model.NewOrder(1, "79927398713")
+	orderRepo := postgres.NewOrderRepository(pool)
+	orderRepo.Create(ctx, order)
+
+	outbox := &model.Outbox{
+		OrderID:   order.ID(),
+		Status:    model.OutboxStatusPending,
+		Retries:   2,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	repo.Create(ctx, outbox)
+
+	leaseOutbox(t, pool, outbox.ID, "worker-1")
+	err := repo.Postpone(ctx, outbox.ID, "worker-1", time.Hour)
+	require.NoError(t, err)
+
+	saved, err := repo.FindByID(ctx, outbox.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OutboxStatusPending, saved.Status)
+	assert.Equal(t, 2, saved.Retries)
+	assert.Empty(t, saved.LeaseOwner)
+	assert.Nil(t, saved.LeaseExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), saved.NextAttemptAt, time.Minute)
+
+	outboxes, err := repo.FindPending(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, outboxes)
+
+	assert.ErrorIs(t, repo.Postpone(ctx, outbox.ID, "worker-1", 0), domainerrors.ErrOutboxLeaseLost)
+}
+
+func TestOutboxRepository_LeaseLost(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewOutboxRepository(pool)
+	orderRepo := postgres.NewOrderRepository(pool)
+	ctx := context.Background()
+
+	order, _ := model.NewOrder(1, "79927398713")
+	orderRepo.Create(ctx, order)
+	outbox := &model.Outbox{
+		OrderID:   order.ID(),
+		Status:    model.OutboxStatusPending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	repo.Create(ctx, outbox)
+
+	claimed, err := repo.ClaimBatch(ctx, "worker-1", 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	// Аренда истекла, и запись перехватил другой экземпляр.
+	_, err = pool.Exec(ctx, "UPDATE outbox SET lease_expires_at = NOW() - INTERVAL '1 second' WHERE id = $1", outbox.ID)
+	require.NoError(t, err)
+	claimed, err = repo.ClaimBatch(ctx, "worker-2", 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	assert.ErrorIs(t, repo.UpdateStatus(ctx, outbox.ID, "worker-1", model.OutboxStatusProcessed), domainerrors.ErrOutboxLeaseLost)
+	assert.ErrorIs(t, repo.ScheduleRetry(ctx, outbox.ID, "worker-1", 0, "late"), domainerrors.ErrOutboxLeaseLost)
+	assert.ErrorIs(t, repo.MarkDead(ctx, outbox.ID, "worker-1", "late"), domainerrors.ErrOutboxLeaseLost)
+	assert.ErrorIs(t, repo.ScheduleNextAttempt(ctx, outbox.ID, "worker-1", 0), domainerrors.ErrOutboxLeaseLost)
+
+	var status, owner string
+	var retries int
+	err = pool.QueryRow(ctx,
+		"SELECT status, retries, lease_owner FROM outbox WHERE id = $1", outbox.ID,
+	).Scan(&status, &retries, &owner)
+	require.NoError(t, err)
+	assert.Equal(t, "PENDING", status)
+	assert.Equal(t, 0, retries)
+	assert.Equal(t, "worker-2", owner)
+
+	require.NoError(t, repo.UpdateStatus(ctx, outbox.ID, "worker-2", model.OutboxStatusProcessed))
+}
@@ -98,7 +98,7 @@ func setupTestDB(t *testing.T) *pgxpool.Pool {
 func cleanupDB(t *testing.T, pool *pgxpool.Pool) {
 	ctx := context.Background()
 
-	tables := []string{"withdrawals", "outbox", "orders", "balances", "users"}
+	tables := []string{"idempotency_keys", "api_key_grants", "api_keys", "order_status_history", "ledger_entries", "withdrawals", "outbox_audit", "outbox", "orders", "balances", "users"}
 	for _, table := range tables {
 		_, err := pool.Exec(ctx, fmt.Sprintf("TRUNCATE TABLE %s CASCADE", table))
 		if err != nil {
@@ -106,7 +106,7 @@ func cleanupDB(t *testing.T, pool *pgxpool.Pool) {
 		}
 	}
 
-	sequences := []string{"orders_id_seq", "withdrawals_id_seq", "outbox_id_seq", "users_id_seq"}
+	sequences := []string{"orders_id_seq", "withdrawals_id_seq", "outbox_id_seq", "users_id_seq", "ledger_entries_id_seq", "ledger_transaction_id_seq", "outbox_audit_id_seq", "api_keys_id_seq", "order_status_history_id_seq"}
 	for _, seq := range sequences {
 		_, err := pool.Exec(ctx, fmt.Sprintf("ALTER SEQUENCE %s RESTART WITH 1", seq))
 		if err != nil {
@@ -1,6 +1,8 @@
 package postgres
 
 import (
+	"time"
+
 	"github.com/jackc/pgx/v5"
 )
 
@@ -18,3 +20,11 @@ func scanRows[T any](
 	}
 	return results, rows.Err()
 }
+
+// nullTime превращает нулевое время в NULL, чтобы необязательные фильтры не применялись.
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
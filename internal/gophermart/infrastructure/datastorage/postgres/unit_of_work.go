@@ -2,8 +2,11 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
 )
@@ -24,6 +27,49 @@ func (uow *unitOfWork) Begin(ctx context.Context) (repository.Transaction, error
 	return &transaction{tx: tx}, nil
 }
 
+const (
+	maxTransactionAttempts = 3
+	transactionRetryDelay  = 10 * time.Millisecond
+)
+
+func (uow *unitOfWork) Do(ctx context.Context, fn func(tx repository.Transaction) error) error {
+	for attempt := 1; ; attempt++ {
+		err := uow.run(ctx, fn)
+		if err == nil || attempt == maxTransactionAttempts || !isRetryableTxError(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * transactionRetryDelay):
+		}
+	}
+}
+
+func (uow *unitOfWork) run(ctx context.Context, fn func(tx repository.Transaction) error) error {
+	tx, err := uow.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// isRetryableTxError сообщает, что транзакцию прервала база и её можно выполнить заново:
+// serialization_failure (40001) или deadlock_detected (40P01).
+func isRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
+
 type transaction struct {
 	tx pgx.Tx
 }
@@ -44,6 +90,18 @@ func (t *transaction) BalanceRepository() repository.BalanceRepository {
 	return NewBalanceRepositoryTx(t.tx)
 }
 
+func (t *transaction) LedgerRepository() repository.LedgerRepository {
+	return NewLedgerRepositoryTx(t.tx)
+}
+
+func (t *transaction) OutboxAuditRepository() repository.OutboxAuditRepository {
+	return NewOutboxAuditRepositoryTx(t.tx)
+}
+
+func (t *transaction) IdempotencyRepository() repository.IdempotencyRepository {
+	return NewIdempotencyRepositoryTx(t.tx)
+}
+
 func (t *transaction) Commit(ctx context.Context) error {
 	return t.tx.Commit(ctx)
 }
@@ -4,13 +4,16 @@ package postgres_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/infrastructure/datastorage/postgres"
 )
 
@@ -26,8 +29,8 @@ func TestUnitOfWork_BeginCommit(t *testing.T) {
 		tx, err := uow.Begin(ctx)
 		require.NoError(t, err)
 
-		balanceRepo := tx.BalanceRepository()
-		err = balanceRepo.Accrue(ctx, 1, 100.0)
+		ledgerRepo := tx.LedgerRepository()
+		err = ledgerRepo.Post(ctx, adjustmentPosting(t, 1, model.MustParsePoints("100")))
 		require.NoError(t, err)
 
 		orderRepo := tx.OrderRepository()
@@ -67,8 +70,8 @@ func TestUnitOfWork_BeginRollback(t *testing.T) {
 		tx, err := uow.Begin(ctx)
 		require.NoError(t, err)
 
-		balanceRepo := tx.BalanceRepository()
-		err = balanceRepo.Accrue(ctx, 1, 100.0)
+		ledgerRepo := tx.LedgerRepository()
+		err = ledgerRepo.Post(ctx, adjustmentPosting(t, 1, model.MustParsePoints("100")))
 		require.NoError(t, err)
 
 		orderRepo := tx.OrderRepository()
@@ -108,24 +111,24 @@ func TestUnitOfWork_TransactionIsolation(t *testing.T) {
 		require.NoError(t, err)
 
 		balanceRepo1 := tx1.BalanceRepository()
-		err = balanceRepo1.Accrue(ctx, 1, 100.0)
+		err = tx1.LedgerRepository().Post(ctx, adjustmentPosting(t, 1, model.MustParsePoints("100")))
 		require.NoError(t, err)
 
 		balance, err := balanceRepo1.GetByUserID(ctx, 1)
 		require.NoError(t, err)
-		assert.Equal(t, 100.0, balance.Current())
+		assert.Equal(t, model.MustParsePoints("100"), balance.Current())
 
 		balanceRepo2 := postgres.NewBalanceRepository(pool)
 		balance2, err := balanceRepo2.GetByUserID(ctx, 1)
 		require.NoError(t, err)
-		assert.Equal(t, 0.0, balance2.Current())
+		assert.Equal(t, model.Points(0), balance2.Current())
 
 		err = tx1.Commit(ctx)
 		require.NoError(t, err)
 
 		balance3, err := balanceRepo2.GetByUserID(ctx, 1)
 		require.NoError(t, err)
-		assert.Equal(t, 100.0, balance3.Current())
+		assert.Equal(t, model.MustParsePoints("100"), balance3.Current())
 	})
 }
 
@@ -153,6 +156,9 @@ func TestUnitOfWork_TransactionRepositories(t *testing.T) {
 		withdrawalRepo := tx.WithdrawalRepository()
 		assert.NotNil(t, withdrawalRepo)
 
+		ledgerRepo := tx.LedgerRepository()
+		assert.NotNil(t, ledgerRepo)
+
 		order, err := model.NewOrder(1, "79927398713")
 		require.NoError(t, err)
 		err = orderRepo.Create(ctx, order)
@@ -168,7 +174,9 @@ func TestUnitOfWork_TransactionRepositories(t *testing.T) {
 		err = outboxRepo.Create(ctx, outbox)
 		require.NoError(t, err)
 
-		err = balanceRepo.Accrue(ctx, 1, 50.0)
+		posting, err := model.NewAccrualPosting(1, order.ID(), model.MustParsePoints("50"))
+		require.NoError(t, err)
+		err = ledgerRepo.Post(ctx, posting)
 		require.NoError(t, err)
 
 		err = tx.Commit(ctx)
@@ -190,3 +198,68 @@ func TestUnitOfWork_TransactionRepositories(t *testing.T) {
 		assert.Equal(t, 50.0, balance)
 	})
 }
+
+func TestUnitOfWork_Do(t *testing.T) {
+	pool := setupTestDB(t)
+	uow := postgres.NewUnitOfWork(pool)
+	ctx := context.Background()
+
+	balanceOf := func(userID int64) model.Points {
+		balance, err := postgres.NewBalanceRepository(pool).GetByUserID(ctx, userID)
+		require.NoError(t, err)
+		return balance.Current()
+	}
+
+	t.Run("commits when fn succeeds", func(t *testing.T) {
+		err := uow.Do(ctx, func(tx repository.Transaction) error {
+			return tx.LedgerRepository().Post(ctx, adjustmentPosting(t, 1, model.MustParsePoints("100")))
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, model.MustParsePoints("100"), balanceOf(1))
+	})
+
+	t.Run("rolls back when fn fails", func(t *testing.T) {
+		errFailed := errors.New("failed")
+		err := uow.Do(ctx, func(tx repository.Transaction) error {
+			if err := tx.LedgerRepository().Post(ctx, adjustmentPosting(t, 2, model.MustParsePoints("100"))); err != nil {
+				return err
+			}
+			return errFailed
+		})
+
+		assert.ErrorIs(t, err, errFailed)
+		assert.Equal(t, model.Points(0), balanceOf(2))
+	})
+
+	t.Run("retries serialization failure", func(t *testing.T) {
+		attempts := 0
+		err := uow.Do(ctx, func(tx repository.Transaction) error {
+			attempts++
+			if err := tx.LedgerRepository().Post(ctx, adjustmentPosting(t, 3, model.MustParsePoints("10"))); err != nil {
+				return err
+			}
+			if attempts == 1 {
+				return &pgconn.PgError{Code: "40001"}
+			}
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		assert.Equal(t, model.MustParsePoints("10"), balanceOf(3))
+	})
+
+	t.Run("gives up after limited attempts", func(t *testing.T) {
+		attempts := 0
+		err := uow.Do(ctx, func(tx repository.Transaction) error {
+			attempts++
+			return &pgconn.PgError{Code: "40P01"}
+		})
+
+		var pgErr *pgconn.PgError
+		require.ErrorAs(t, err, &pgErr)
+		assert.Equal(t, "40P01", pgErr.Code)
+		assert.Equal(t, 3, attempts)
+	})
+}
@@ -0,0 +1,90 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/application/usecase"
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/infrastructure/datastorage/postgres"
+)
+
+// luhnNumber дописывает к base контрольную цифру по алгоритму Луна.
+func luhnNumber(base int) string {
+	digits := strconv.Itoa(base)
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return digits + strconv.Itoa((10-sum%10)%10)
+}
+
+func TestWithdrawUseCase_ConcurrentWithdrawalsDoNotOverdraw(t *testing.T) {
+	pool := setupTestDB(t)
+	ctx := context.Background()
+
+	err := postgres.NewLedgerRepository(pool).Post(ctx, adjustmentPosting(t, 1, model.MustParsePoints("100")))
+	require.NoError(t, err)
+
+	uc := usecase.NewWithdrawUseCase(
+		postgres.NewUnitOfWork(pool),
+		postgres.NewBalanceRepository(pool),
+		postgres.NewWithdrawalRepository(pool),
+		service.NewLuhnOrderNumberValidator(),
+	)
+
+	const workers = 10
+	sum := model.MustParsePoints("30")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, err := uc.Execute(ctx, usecase.WithdrawRequest{UserID: 1, Order: luhnNumber(1000 + i), Sum: sum})
+			errs <- err
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.ErrorIs(t, err, domainerrors.ErrInsufficientFunds)
+	}
+	assert.Equal(t, 3, succeeded)
+
+	balance, err := postgres.NewBalanceRepository(pool).GetByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, model.MustParsePoints("10"), balance.Current())
+	assert.Equal(t, model.MustParsePoints("90"), balance.Withdrawn())
+
+	var withdrawals int
+	err = pool.QueryRow(ctx, "SELECT COUNT(*) FROM withdrawals WHERE user_id = $1", 1).Scan(&withdrawals)
+	require.NoError(t, err)
+	assert.Equal(t, 3, withdrawals)
+}
@@ -2,10 +2,13 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
 )
@@ -28,6 +31,10 @@ func (r *withdrawalRepository) Create(ctx context.Context, withdrawal *model.Wit
 	var id int64
 	err := r.querier.QueryRow(ctx, query, withdrawal.UserID(), withdrawal.OrderNumber(), withdrawal.Sum(), withdrawal.ProcessedAt()).Scan(&id)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "uq_withdrawals_order_number" {
+			return domainerrors.ErrWithdrawalOrderExists
+		}
 		return err
 	}
 	withdrawal.SetID(id)
@@ -45,7 +52,7 @@ func (r *withdrawalRepository) FindByUserID(ctx context.Context, userID int64) (
 	return scanRows(rows, func(rows pgx.Rows) (*model.Withdrawal, error) {
 		var id, uid int64
 		var orderNumber string
-		var sum float64
+		var sum model.Points
 		var processedAt time.Time
 		err := rows.Scan(&id, &uid, &orderNumber, &sum, &processedAt)
 		if err != nil {
@@ -55,10 +62,23 @@ func (r *withdrawalRepository) FindByUserID(ctx context.Context, userID int64) (
 	})
 }
 
-func (r *withdrawalRepository) FindByUserIDIterator(ctx context.Context, userID int64) (Iterator[*model.Withdrawal], error) {
+func (r *withdrawalRepository) FindByUserIDIterator(ctx context.Context, userID int64, filter repository.WithdrawalFilter) (Iterator[*model.Withdrawal], error) {
 	query := `SELECT id, user_id, order_number, sum, processed_at 
-	          FROM withdrawals WHERE user_id = $1 ORDER BY processed_at DESC`
-	rows, err := r.querier.Query(ctx, query, userID)
+	          FROM withdrawals WHERE user_id = $1 
+	            AND ($2::TIMESTAMP IS NULL OR processed_at >= $2) 
+	            AND ($3::TIMESTAMP IS NULL OR processed_at < $3) 
+	            AND ($4::DECIMAL IS NULL OR sum >= $4) 
+	            AND ($5::DECIMAL IS NULL OR sum <= $5) 
+	            AND ($6::TIMESTAMP IS NULL OR (processed_at, id) < ($6, $7)) 
+	          ORDER BY processed_at DESC, id DESC LIMIT NULLIF($8::INT, 0)`
+	var afterProcessedAt *time.Time
+	var afterID int64
+	if filter.After != nil {
+		afterProcessedAt = &filter.After.ProcessedAt
+		afterID = filter.After.ID
+	}
+	rows, err := r.querier.Query(ctx, query, userID, nullTime(filter.From), nullTime(filter.To),
+		filter.MinSum, filter.MaxSum, afterProcessedAt, afterID, filter.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +86,7 @@ func (r *withdrawalRepository) FindByUserIDIterator(ctx context.Context, userID
 	return NewIterator(rows, func(rows pgx.Rows) (*model.Withdrawal, error) {
 		var id, uid int64
 		var orderNumber string
-		var sum float64
+		var sum model.Points
 		var processedAt time.Time
 		err := rows.Scan(&id, &uid, &orderNumber, &sum, &processedAt)
 		if err != nil {
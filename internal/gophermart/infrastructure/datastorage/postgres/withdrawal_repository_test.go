@@ -4,13 +4,17 @@ package postgres_test
 
 import (
 	"context"
+	"errors"
+	"io"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/repository"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/infrastructure/datastorage/postgres"
 )
 
@@ -20,7 +24,7 @@ func TestWithdrawalRepository_Create(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("successful create", func(t *testing.T) {
-		withdrawal, err := model.NewWithdrawal(1, "79927398713", 100.5)
+		withdrawal, err := model.NewWithdrawal(1, "79927398713", model.MustParsePoints("100.5"))
 		require.NoError(t, err)
 
 		err = repo.Create(ctx, withdrawal)
@@ -39,6 +43,15 @@ func TestWithdrawalRepository_Create(t *testing.T) {
 		assert.Equal(t, "79927398713", dbOrderNumber)
 		assert.Equal(t, 100.5, dbSum)
 	})
+
+	t.Run("order can be paid with points only once", func(t *testing.T) {
+		withdrawal, err := model.NewWithdrawal(2, "79927398713", model.MustParsePoints("10"))
+		require.NoError(t, err)
+
+		err = repo.Create(ctx, withdrawal)
+
+		assert.ErrorIs(t, err, domainerrors.ErrWithdrawalOrderExists)
+	})
 }
 
 func TestWithdrawalRepository_FindByUserID(t *testing.T) {
@@ -69,11 +82,11 @@ func TestWithdrawalRepository_FindByUserID(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, withdrawals, 3)
 		assert.Equal(t, "order2", withdrawals[0].OrderNumber())
-		assert.Equal(t, 75.0, withdrawals[0].Sum())
+		assert.Equal(t, model.MustParsePoints("75"), withdrawals[0].Sum())
 		assert.Equal(t, "order3", withdrawals[1].OrderNumber())
-		assert.Equal(t, 25.0, withdrawals[1].Sum())
+		assert.Equal(t, model.MustParsePoints("25"), withdrawals[1].Sum())
 		assert.Equal(t, "order1", withdrawals[2].OrderNumber())
-		assert.Equal(t, 50.0, withdrawals[2].Sum())
+		assert.Equal(t, model.MustParsePoints("50"), withdrawals[2].Sum())
 	})
 
 	t.Run("returns empty slice when user has no withdrawals", func(t *testing.T) {
@@ -83,3 +96,81 @@ func TestWithdrawalRepository_FindByUserID(t *testing.T) {
 		assert.Len(t, withdrawals, 0)
 	})
 }
+
+func collectWithdrawalOrders(t *testing.T, it repository.Iterator[*model.Withdrawal]) []string {
+	t.Helper()
+	defer it.Close()
+
+	var orders []string
+	for {
+		withdrawal, err := it.Next()
+		if errors.Is(err, io.EOF) {
+			return orders
+		}
+		require.NoError(t, err)
+		orders = append(orders, withdrawal.OrderNumber())
+	}
+}
+
+func TestWithdrawalRepository_FindByUserIDIterator(t *testing.T) {
+	pool := setupTestDB(t)
+	repo := postgres.NewWithdrawalRepository(pool)
+	ctx := context.Background()
+
+	base := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	ids := make(map[string]int64)
+	for _, w := range []struct {
+		userID      int64
+		order       string
+		sum         string
+		processedAt time.Time
+	}{
+		{1, "order1", "10", base.Add(-3 * time.Hour)},
+		{1, "order2", "50", base.Add(-2 * time.Hour)},
+		{1, "order3", "75.5", base.Add(-2 * time.Hour)},
+		{1, "order4", "200", base},
+		{2, "foreign", "50", base},
+	} {
+		var id int64
+		err := pool.QueryRow(ctx,
+			"INSERT INTO withdrawals (user_id, order_number, sum, processed_at) VALUES ($1, $2, $3, $4) RETURNING id",
+			w.userID, w.order, model.MustParsePoints(w.sum), w.processedAt,
+		).Scan(&id)
+		require.NoError(t, err)
+		ids[w.order] = id
+	}
+
+	t.Run("without filter returns all withdrawals newest first", func(t *testing.T) {
+		it, err := repo.FindByUserIDIterator(ctx, 1, repository.WithdrawalFilter{})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"order4", "order3", "order2", "order1"}, collectWithdrawalOrders(t, it))
+	})
+
+	t.Run("pages through withdrawals with equal processing time", func(t *testing.T) {
+		it, err := repo.FindByUserIDIterator(ctx, 1, repository.WithdrawalFilter{Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"order4", "order3"}, collectWithdrawalOrders(t, it))
+
+		it, err = repo.FindByUserIDIterator(ctx, 1, repository.WithdrawalFilter{
+			After: &repository.WithdrawalCursor{ProcessedAt: base.Add(-2 * time.Hour), ID: ids["order3"]},
+			Limit: 2,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"order2", "order1"}, collectWithdrawalOrders(t, it))
+	})
+
+	t.Run("filters by processing date and sum", func(t *testing.T) {
+		it, err := repo.FindByUserIDIterator(ctx, 1, repository.WithdrawalFilter{
+			From: base.Add(-2 * time.Hour),
+			To:   base,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"order3", "order2"}, collectWithdrawalOrders(t, it))
+
+		minSum, maxSum := model.MustParsePoints("50"), model.MustParsePoints("75.5")
+		it, err = repo.FindByUserIDIterator(ctx, 1, repository.WithdrawalFilter{MinSum: &minSum, MaxSum: &maxSum})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"order3", "order2"}, collectWithdrawalOrders(t, it))
+	})
+}
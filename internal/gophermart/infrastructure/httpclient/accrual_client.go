@@ -3,19 +3,27 @@ package httpclient
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"strconv"
 	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
 )
 
+// defaultRetryAfter — пауза после 429, если система начислений не прислала Retry-After.
+const defaultRetryAfter = 60 * time.Second
+
+var rateLimitPattern = regexp.MustCompile(`No more than (\d+) requests per minute allowed`)
+
 type AccrualClient struct {
 	baseURL string
 	client  *http.Client
+	limiter *rateLimiter
 }
 
 func NewAccrualClient(baseURL string) service.AccrualService {
@@ -24,6 +32,7 @@ func NewAccrualClient(baseURL string) service.AccrualService {
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		limiter: newRateLimiter(time.Now),
 	}
 }
 
@@ -34,6 +43,10 @@ func (c *AccrualClient) GetOrderInfo(ctx context.Context, orderNumber string) (*
 	if err != nil {
 		return nil, err
 	}
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
 	
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -43,24 +56,73 @@ func (c *AccrualClient) GetOrderInfo(ctx context.Context, orderNumber string) (*
 	
 	switch resp.StatusCode {
 	case http.StatusNoContent:
-		return nil, errors.New("order not found in accrual system")
+		return nil, domainerrors.ErrAccrualOrderNotRegistered
 	case http.StatusTooManyRequests:
-		retryAfter := resp.Header.Get("Retry-After")
-		if retryAfter != "" {
-			seconds, _ := strconv.Atoi(retryAfter)
-			return nil, fmt.Errorf("rate limited, retry after %d seconds", seconds)
-		}
-		return nil, errors.New("rate limited")
+		return nil, c.handleRateLimit(resp)
 	case http.StatusOK:
-		var accrualResp model.AccrualResponse
-		if err := json.NewDecoder(resp.Body).Decode(&accrualResp); err != nil {
+		var payload accrualPayload
+		if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
 			return nil, err
 		}
-		return &accrualResp, nil
+		return payload.toModel()
 	default:
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
 	}
 }
 
+// handleRateLimit приостанавливает все запросы клиента на время из Retry-After
+// и запоминает лимит запросов, указанный в теле ответа.
+func (c *AccrualClient) handleRateLimit(resp *http.Response) error {
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	c.limiter.Pause(retryAfter)
 
+	body, _ := io.ReadAll(resp.Body)
+	if match := rateLimitPattern.FindSubmatch(body); match != nil {
+		if limit, err := strconv.Atoi(string(match[1])); err == nil {
+			c.limiter.SetLimit(limit)
+		}
+	}
+
+	return &service.RateLimitError{RetryAfter: retryAfter}
+}
+
+// parseRetryAfter поддерживает обе формы заголовка: число секунд и HTTP-дату.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return defaultRetryAfter
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return defaultRetryAfter
+}
+
+// accrualPayload читает accrual как json.Number: система расчёта может вернуть
+// больше двух знаков после точки, такие суммы округляются до сотых.
+type accrualPayload struct {
+	Order   string       `json:"order"`
+	Status  string       `json:"status"`
+	Accrual *json.Number `json:"accrual,omitempty"`
+}
+
+func (p *accrualPayload) toModel() (*model.AccrualResponse, error) {
+	resp := &model.AccrualResponse{
+		Order:  p.Order,
+		Status: p.Status,
+	}
+	if p.Accrual != nil {
+		accrual, err := model.RoundPoints(p.Accrual.String())
+		if err != nil {
+			return nil, err
+		}
+		resp.Accrual = &accrual
+	}
+	return resp, nil
+}
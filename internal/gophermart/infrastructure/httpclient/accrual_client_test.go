@@ -12,13 +12,14 @@ import (
 	"time"
 
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestAccrualClient_GetOrderInfo(t *testing.T) {
 	t.Run("successful_response_with_accrual", func(t *testing.T) {
-		expectedAccrual := 100.5
+		expectedAccrual := model.MustParsePoints("100.5")
 		expectedOrder := "12345678903"
 		expectedStatus := "PROCESSED"
 
@@ -79,6 +80,24 @@ func TestAccrualClient_GetOrderInfo(t *testing.T) {
 		assert.Nil(t, result.Accrual)
 	})
 
+	t.Run("accrual_rounded_to_hundredths", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"order":"12345678903","status":"PROCESSED","accrual":729.985}`)
+		}))
+		defer server.Close()
+
+		client := NewAccrualClient(server.URL)
+		ctx := context.Background()
+
+		result, err := client.GetOrderInfo(ctx, "12345678903")
+
+		require.NoError(t, err)
+		require.NotNil(t, result.Accrual)
+		assert.Equal(t, model.MustParsePoints("729.99"), *result.Accrual)
+	})
+
 	t.Run("order_not_found_204", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNoContent)
@@ -115,6 +134,29 @@ func TestAccrualClient_GetOrderInfo(t *testing.T) {
 		assert.Contains(t, err.Error(), fmt.Sprintf("retry after %d seconds", expectedRetryAfter))
 	})
 
+	t.Run("rate_limited_pauses_client_and_learns_limit", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, "No more than 2 requests per minute allowed")
+		}))
+		defer server.Close()
+
+		client := NewAccrualClient(server.URL).(*AccrualClient)
+
+		_, err := client.GetOrderInfo(context.Background(), "12345678903")
+
+		var rateLimitErr *service.RateLimitError
+		require.ErrorAs(t, err, &rateLimitErr)
+		assert.Equal(t, 60*time.Second, rateLimitErr.RetryAfter)
+		assert.Equal(t, 30*time.Second, client.limiter.interval)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = client.GetOrderInfo(ctx, "12345678903")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
 	t.Run("rate_limited_without_retry_after_header", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusTooManyRequests)
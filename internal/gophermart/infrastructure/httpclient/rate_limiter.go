@@ -0,0 +1,105 @@
+package httpclient
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter — token bucket, общий для всех запросов к системе начислений.
+// Пока лимит не известен, запросы не ограничиваются; после 429 все запросы
+// приостанавливаются до окончания паузы.
+type rateLimiter struct {
+	mu          sync.Mutex
+	now         func() time.Time
+	interval    time.Duration
+	tokens      float64
+	lastRefill  time.Time
+	pausedUntil time.Time
+}
+
+func newRateLimiter(now func() time.Time) *rateLimiter {
+	return &rateLimiter{now: now}
+}
+
+// Wait блокирует вызов до момента, когда запрос разрешён лимитом.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve забирает токен и возвращает ноль либо возвращает время, через которое
+// стоит попробовать снова.
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.interval == 0 {
+		return 0
+	}
+
+	l.refill(now)
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) * float64(l.interval))
+}
+
+func (l *rateLimiter) refill(now time.Time) {
+	if l.lastRefill.IsZero() {
+		l.tokens = 1
+		l.lastRefill = now
+		return
+	}
+	if now.Before(l.lastRefill) {
+		return
+	}
+	l.tokens += float64(now.Sub(l.lastRefill)) / float64(l.interval)
+	if l.tokens > 1 {
+		l.tokens = 1
+	}
+	l.lastRefill = now
+}
+
+// Pause приостанавливает все запросы на d. Более короткая пауза не сокращает уже действующую.
+func (l *rateLimiter) Pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	until := l.now().Add(d)
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	l.tokens = 1
+	l.lastRefill = l.pausedUntil
+}
+
+// SetLimit задаёт допустимое число запросов в минуту. Запросы распределяются
+// равномерно, без всплесков.
+func (l *rateLimiter) SetLimit(requestsPerMinute int) {
+	if requestsPerMinute <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.interval = time.Minute / time.Duration(requestsPerMinute)
+}
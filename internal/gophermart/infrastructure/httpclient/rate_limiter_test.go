@@ -0,0 +1,113 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestRateLimiter_UnlimitedByDefault(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	limiter := newRateLimiter(clock.Now)
+
+	for i := 0; i < 100; i++ {
+		if delay := limiter.reserve(); delay != 0 {
+			t.Fatalf("reserve() #%d = %v, want 0", i, delay)
+		}
+	}
+}
+
+func TestRateLimiter_SetLimit(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	limiter := newRateLimiter(clock.Now)
+	limiter.SetLimit(60)
+
+	if delay := limiter.reserve(); delay != 0 {
+		t.Fatalf("first reserve() = %v, want 0", delay)
+	}
+	if delay := limiter.reserve(); delay != time.Second {
+		t.Fatalf("second reserve() = %v, want %v", delay, time.Second)
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if delay := limiter.reserve(); delay != 500*time.Millisecond {
+		t.Fatalf("reserve() after 500ms = %v, want 500ms", delay)
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if delay := limiter.reserve(); delay != 0 {
+		t.Fatalf("reserve() after 1s = %v, want 0", delay)
+	}
+
+	clock.Advance(time.Hour)
+	limiter.reserve()
+	if delay := limiter.reserve(); delay == 0 {
+		t.Fatal("tokens must not accumulate beyond a single request")
+	}
+}
+
+func TestRateLimiter_Pause(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	limiter := newRateLimiter(clock.Now)
+
+	limiter.Pause(60 * time.Second)
+	limiter.Pause(10 * time.Second)
+
+	if delay := limiter.reserve(); delay != 60*time.Second {
+		t.Fatalf("reserve() during pause = %v, want 60s", delay)
+	}
+
+	clock.Advance(60 * time.Second)
+	if delay := limiter.reserve(); delay != 0 {
+		t.Fatalf("reserve() after pause = %v, want 0", delay)
+	}
+}
+
+func TestRateLimiter_WaitRespectsContext(t *testing.T) {
+	limiter := newRateLimiter(time.Now)
+	limiter.Pause(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Wait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"seconds", "30", 30 * time.Second},
+		{"http date", now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{"date in the past", now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"empty", "", defaultRetryAfter},
+		{"garbage", "soon", defaultRetryAfter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
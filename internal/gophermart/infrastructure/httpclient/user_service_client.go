@@ -3,10 +3,12 @@ package httpclient
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
 )
 
 type UserServiceClient struct {
@@ -24,13 +26,17 @@ func NewUserServiceClient(baseURL string) *UserServiceClient {
 }
 
 type Claims struct {
-	UserID int64  `json:"user_id"`
-	Login  string `json:"login"`
+	UserID    int64     `json:"user_id"`
+	Login     string    `json:"login"`
+	Roles     []string  `json:"roles"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 type ValidateResponse struct {
-	UserID int64  `json:"user_id"`
-	Login  string `json:"login"`
+	UserID    int64      `json:"user_id"`
+	Login     string     `json:"login"`
+	Roles     []string   `json:"roles"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
 func (c *UserServiceClient) ValidateToken(ctx context.Context, token string) (*Claims, error) {
@@ -50,7 +56,7 @@ func (c *UserServiceClient) ValidateToken(ctx context.Context, token string) (*C
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusUnauthorized {
-		return nil, errors.New("invalid token")
+		return nil, domainerrors.ErrInvalidToken
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -62,9 +68,27 @@ func (c *UserServiceClient) ValidateToken(ctx context.Context, token string) (*C
 		return nil, err
 	}
 
-	return &Claims{
+	claims := &Claims{
 		UserID: validateResp.UserID,
 		Login:  validateResp.Login,
-	}, nil
+		Roles:  validateResp.Roles,
+	}
+	if validateResp.ExpiresAt != nil {
+		claims.ExpiresAt = *validateResp.ExpiresAt
+	}
+	return claims, nil
 }
 
+// Verify проверяет токен через метод /api/auth/validate сервиса пользователей.
+func (c *UserServiceClient) Verify(ctx context.Context, token string) (*service.TokenClaims, error) {
+	claims, err := c.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return &service.TokenClaims{
+		UserID:    claims.UserID,
+		Login:     claims.Login,
+		Roles:     claims.Roles,
+		ExpiresAt: claims.ExpiresAt,
+	}, nil
+}
@@ -19,6 +19,7 @@ func TestUserServiceClient_ValidateToken(t *testing.T) {
 	t.Run("successful_validation", func(t *testing.T) {
 		expectedUserID := int64(123)
 		expectedLogin := "testuser"
+		expectedExpiresAt := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
 
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			assert.Equal(t, http.MethodPost, r.Method)
@@ -35,8 +36,9 @@ func TestUserServiceClient_ValidateToken(t *testing.T) {
 			w.WriteHeader(http.StatusOK)
 
 			response := ValidateResponse{
-				UserID: expectedUserID,
-				Login:  expectedLogin,
+				UserID:    expectedUserID,
+				Login:     expectedLogin,
+				ExpiresAt: &expectedExpiresAt,
 			}
 			json.NewEncoder(w).Encode(response)
 		}))
@@ -51,6 +53,7 @@ func TestUserServiceClient_ValidateToken(t *testing.T) {
 		require.NotNil(t, claims)
 		assert.Equal(t, expectedUserID, claims.UserID)
 		assert.Equal(t, expectedLogin, claims.Login)
+		assert.True(t, expectedExpiresAt.Equal(claims.ExpiresAt))
 	})
 
 	t.Run("invalid_token_401", func(t *testing.T) {
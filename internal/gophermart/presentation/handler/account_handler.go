@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/application/usecase"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/presentation/middleware"
+)
+
+type AccountHandler struct {
+	exportUseCase *usecase.ExportPersonalDataUseCase
+	deleteUseCase *usecase.DeleteAccountUseCase
+}
+
+func NewAccountHandler(exportUseCase *usecase.ExportPersonalDataUseCase, deleteUseCase *usecase.DeleteAccountUseCase) *AccountHandler {
+	return &AccountHandler{
+		exportUseCase: exportUseCase,
+		deleteUseCase: deleteUseCase,
+	}
+}
+
+// Export отдаёт данные пользователя одним JSON-документом или, при format=zip,
+// архивом с отдельным файлом на каждый раздел.
+func (h *AccountHandler) Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "zip" {
+		http.Error(w, "format must be json or zip", http.StatusBadRequest)
+		return
+	}
+
+	export, err := h.exportUseCase.Execute(r.Context(), usecase.ExportPersonalDataRequest{UserID: userID})
+	if err != nil {
+		writeError(w, "export personal data", err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	if format == "zip" {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", `attachment; filename="personal-data.zip"`)
+		w.WriteHeader(http.StatusOK)
+		if err := writeExportArchive(w, export); err != nil {
+			log.Printf("write personal data archive error: %v", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="personal-data.json"`)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(export)
+}
+
+func writeExportArchive(w http.ResponseWriter, export *usecase.PersonalDataExport) error {
+	archive := zip.NewWriter(w)
+	sections := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", export.Profile},
+		{"balance.json", export.Balance},
+		{"orders.json", export.Orders},
+		{"withdrawals.json", export.Withdrawals},
+		{"balance_history.json", export.BalanceHistory},
+	}
+	for _, section := range sections {
+		file, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     section.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(section.data); err != nil {
+			return fmt.Errorf("%s: %w", section.name, err)
+		}
+	}
+	return archive.Close()
+}
+
+func (h *AccountHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.deleteUseCase.Execute(r.Context(), usecase.DeleteAccountRequest{UserID: userID}); err != nil {
+		writeError(w, "delete account", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
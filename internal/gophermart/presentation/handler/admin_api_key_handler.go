@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/application/usecase"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/presentation/middleware"
+)
+
+type AdminAPIKeyHandler struct {
+	issueAPIKeyUseCase  *usecase.IssueAPIKeyUseCase
+	listAPIKeysUseCase  *usecase.ListAPIKeysUseCase
+	revokeAPIKeyUseCase *usecase.RevokeAPIKeyUseCase
+}
+
+func NewAdminAPIKeyHandler(
+	issueAPIKeyUseCase *usecase.IssueAPIKeyUseCase,
+	listAPIKeysUseCase *usecase.ListAPIKeysUseCase,
+	revokeAPIKeyUseCase *usecase.RevokeAPIKeyUseCase,
+) *AdminAPIKeyHandler {
+	return &AdminAPIKeyHandler{
+		issueAPIKeyUseCase:  issueAPIKeyUseCase,
+		listAPIKeysUseCase:  listAPIKeysUseCase,
+		revokeAPIKeyUseCase: revokeAPIKeyUseCase,
+	}
+}
+
+// Issue принимает {"name": "...", "owner": "...", "scopes": ["orders:write"], "expires_in": "720h"}.
+// Ключ возвращается в ответе один раз и больше нигде не показывается.
+func (h *AdminAPIKeyHandler) Issue(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Name      string              `json:"name"`
+		Owner     string              `json:"owner"`
+		Scopes    []model.APIKeyScope `json:"scopes"`
+		ExpiresIn string              `json:"expires_in"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+
+	var ttl time.Duration
+	if req.ExpiresIn != "" {
+		d, err := time.ParseDuration(req.ExpiresIn)
+		if err != nil || d <= 0 {
+			http.Error(w, "invalid expires_in", http.StatusBadRequest)
+			return
+		}
+		ttl = d
+	}
+
+	resp, err := h.issueAPIKeyUseCase.Execute(r.Context(), usecase.IssueAPIKeyRequest{
+		ActorUserID: userID,
+		Name:        req.Name,
+		Owner:       req.Owner,
+		Scopes:      req.Scopes,
+		TTL:         ttl,
+	})
+	if err != nil {
+		writeError(w, "issue api key", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *AdminAPIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.listAPIKeysUseCase.Execute(r.Context())
+	if err != nil {
+		writeError(w, "list api keys", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(keys)
+}
+
+func (h *AdminAPIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid api key id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.revokeAPIKeyUseCase.Execute(r.Context(), usecase.RevokeAPIKeyRequest{ID: id}); err != nil {
+		writeError(w, "revoke api key", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
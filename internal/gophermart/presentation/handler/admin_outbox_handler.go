@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/application/usecase"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/presentation/middleware"
+)
+
+type AdminOutboxHandler struct {
+	listOutboxUseCase    *usecase.ListOutboxUseCase
+	getOutboxUseCase     *usecase.GetOutboxUseCase
+	requeueOutboxUseCase *usecase.RequeueOutboxUseCase
+}
+
+func NewAdminOutboxHandler(
+	listOutboxUseCase *usecase.ListOutboxUseCase,
+	getOutboxUseCase *usecase.GetOutboxUseCase,
+	requeueOutboxUseCase *usecase.RequeueOutboxUseCase,
+) *AdminOutboxHandler {
+	return &AdminOutboxHandler{
+		listOutboxUseCase:    listOutboxUseCase,
+		getOutboxUseCase:     getOutboxUseCase,
+		requeueOutboxUseCase: requeueOutboxUseCase,
+	}
+}
+
+// List поддерживает параметры status, order (номер заказа), older_than (например, 1h) и limit.
+func (h *AdminOutboxHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := usecase.ListOutboxRequest{
+		Status:      model.OutboxStatus(strings.ToUpper(query.Get("status"))),
+		OrderNumber: query.Get("order"),
+	}
+
+	if olderThan := query.Get("older_than"); olderThan != "" {
+		d, err := time.ParseDuration(olderThan)
+		if err != nil || d < 0 {
+			http.Error(w, "invalid older_than", http.StatusBadRequest)
+			return
+		}
+		req.OlderThan = d
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		req.Limit = n
+	}
+
+	outboxes, err := h.listOutboxUseCase.Execute(r.Context(), req)
+	if err != nil {
+		writeError(w, "list outbox", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(outboxes)
+}
+
+func (h *AdminOutboxHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid outbox id", http.StatusBadRequest)
+		return
+	}
+
+	outbox, err := h.getOutboxUseCase.Execute(r.Context(), usecase.GetOutboxRequest{ID: id})
+	if err != nil {
+		writeError(w, "get outbox", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(outbox)
+}
+
+func (h *AdminOutboxHandler) Requeue(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid outbox id", http.StatusBadRequest)
+		return
+	}
+
+	h.requeue(w, r, usecase.RequeueOutboxRequest{IDs: []int64{id}})
+}
+
+// RequeueBulk принимает {"ids": [...]} или {"status": "DEAD"}.
+func (h *AdminOutboxHandler) RequeueBulk(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		IDs    []int64 `json:"ids"`
+		Status string  `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+
+	h.requeue(w, r, usecase.RequeueOutboxRequest{
+		IDs:    req.IDs,
+		Status: model.OutboxStatus(strings.ToUpper(req.Status)),
+	})
+}
+
+func (h *AdminOutboxHandler) requeue(w http.ResponseWriter, r *http.Request, req usecase.RequeueOutboxRequest) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	req.ActorUserID = userID
+
+	resp, err := h.requeueOutboxUseCase.Execute(r.Context(), req)
+	if err != nil {
+		writeError(w, "requeue outbox", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
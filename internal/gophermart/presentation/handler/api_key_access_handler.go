@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/application/usecase"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/presentation/middleware"
+)
+
+// APIKeyAccessHandler управляет согласием пользователя на запросы ключа внешней системы
+// от его имени. Доступен только по токену пользователя, не по API-ключу.
+type APIKeyAccessHandler struct {
+	grantAccessUseCase  *usecase.GrantAPIKeyAccessUseCase
+	revokeAccessUseCase *usecase.RevokeAPIKeyAccessUseCase
+}
+
+func NewAPIKeyAccessHandler(
+	grantAccessUseCase *usecase.GrantAPIKeyAccessUseCase,
+	revokeAccessUseCase *usecase.RevokeAPIKeyAccessUseCase,
+) *APIKeyAccessHandler {
+	return &APIKeyAccessHandler{
+		grantAccessUseCase:  grantAccessUseCase,
+		revokeAccessUseCase: revokeAccessUseCase,
+	}
+}
+
+func (h *APIKeyAccessHandler) Grant(w http.ResponseWriter, r *http.Request) {
+	userID, keyID, ok := apiKeyAccessParams(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.grantAccessUseCase.Execute(r.Context(), usecase.GrantAPIKeyAccessRequest{UserID: userID, KeyID: keyID})
+	if err != nil {
+		writeError(w, "grant api key access", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIKeyAccessHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID, keyID, ok := apiKeyAccessParams(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.revokeAccessUseCase.Execute(r.Context(), usecase.RevokeAPIKeyAccessRequest{UserID: userID, KeyID: keyID})
+	if err != nil {
+		writeError(w, "revoke api key access", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func apiKeyAccessParams(w http.ResponseWriter, r *http.Request) (userID, keyID int64, ok bool) {
+	userID, ok = middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return 0, 0, false
+	}
+
+	keyID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid api key id", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return userID, keyID, true
+}
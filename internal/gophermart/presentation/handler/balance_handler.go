@@ -2,13 +2,19 @@ package handler
 
 import (
 	"encoding/json"
-	"log"
 	"net/http"
 
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/application/usecase"
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/presentation/middleware"
 )
 
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
 type BalanceHandler struct {
 	getBalanceUseCase *usecase.GetBalanceUseCase
 	withdrawUseCase   *usecase.WithdrawUseCase
@@ -40,7 +46,7 @@ func (h *BalanceHandler) Get(w http.ResponseWriter, r *http.Request) {
 		UserID: userID,
 	})
 	if err != nil {
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		writeError(w, "get balance", err)
 		return
 	}
 
@@ -49,6 +55,8 @@ func (h *BalanceHandler) Get(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(balance)
 }
 
+// Withdraw списывает баллы. С заголовком Idempotency-Key повтор запроса, например после таймаута,
+// не списывает баллы второй раз: клиент получает исходный ответ с заголовком Idempotent-Replayed.
 func (h *BalanceHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -62,33 +70,32 @@ func (h *BalanceHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Order string  `json:"order"`
-		Sum   float64 `json:"sum"`
+		Order string       `json:"order"`
+		Sum   model.Points `json:"sum"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if _, ok := domainerrors.As(err); ok {
+			writeError(w, "withdraw", err)
+			return
+		}
 		http.Error(w, "invalid request format", http.StatusBadRequest)
 		return
 	}
 
-	_, err := h.withdrawUseCase.Execute(r.Context(), usecase.WithdrawRequest{
-		UserID: userID,
-		Order:  req.Order,
-		Sum:    req.Sum,
+	resp, err := h.withdrawUseCase.Execute(r.Context(), usecase.WithdrawRequest{
+		UserID:         userID,
+		Order:          req.Order,
+		Sum:            req.Sum,
+		IdempotencyKey: r.Header.Get(idempotencyKeyHeader),
 	})
 	if err != nil {
-		if err.Error() == "invalid order number format" {
-			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
-			return
-		}
-		if err.Error() == "insufficient funds" {
-			http.Error(w, err.Error(), http.StatusPaymentRequired)
-			return
-		}
-		log.Printf("withdraw error: %v", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		writeError(w, "withdraw", err)
 		return
 	}
 
+	if resp.Replayed {
+		w.Header().Set(idempotentReplayedHeader, "true")
+	}
 	w.WriteHeader(http.StatusOK)
 }
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+)
+
+var statusByKind = map[domainerrors.Kind]int{
+	domainerrors.KindInvalidInput:      http.StatusBadRequest,
+	domainerrors.KindValidation:        http.StatusUnprocessableEntity,
+	domainerrors.KindUnauthorized:      http.StatusUnauthorized,
+	domainerrors.KindForbidden:         http.StatusForbidden,
+	domainerrors.KindNotFound:          http.StatusNotFound,
+	domainerrors.KindConflict:          http.StatusConflict,
+	domainerrors.KindInsufficientFunds: http.StatusPaymentRequired,
+	domainerrors.KindRateLimited:       http.StatusTooManyRequests,
+	domainerrors.KindInternal:          http.StatusInternalServerError,
+}
+
+// HTTPStatus возвращает код ответа для ошибки по её категории.
+func HTTPStatus(err error) int {
+	if status, ok := statusByKind[domainerrors.KindOf(err)]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// writeError отвечает клиенту на ошибку use case. Клиент видит только текст ошибки домена,
+// внутренние ошибки логируются и отдаются как 500 без подробностей.
+func writeError(w http.ResponseWriter, op string, err error) {
+	status := HTTPStatus(err)
+	domainErr, ok := domainerrors.As(err)
+	if !ok || status == http.StatusInternalServerError {
+		log.Printf("%s error: %v", op, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	http.Error(w, domainErr.Error(), status)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+)
+
+func TestHTTPStatus_EveryRegisteredErrorIsMapped(t *testing.T) {
+	// Ошибки модели регистрируются при инициализации пакета model.
+	_ = model.ErrInvalidPoints
+
+	registered := domainerrors.Registered()
+	assert.NotEmpty(t, registered)
+
+	for _, err := range registered {
+		status, ok := statusByKind[err.Kind()]
+		assert.True(t, ok, "no HTTP status for kind %q of error %q", err.Kind(), err)
+		if err.Kind() != domainerrors.KindInternal {
+			assert.NotEqual(t, http.StatusInternalServerError, status, "error %q", err)
+		}
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"invalid order number", domainerrors.ErrInvalidOrderNumber, http.StatusUnprocessableEntity},
+		{"wrapped conflict", fmt.Errorf("upload order: %w", domainerrors.ErrOrderOwnedByAnotherUser), http.StatusConflict},
+		{"insufficient funds", domainerrors.ErrInsufficientFunds, http.StatusPaymentRequired},
+		{"points precision", model.ErrPointsPrecision, http.StatusUnprocessableEntity},
+		{"not found", domainerrors.ErrOutboxNotFound, http.StatusNotFound},
+		{"unknown error", fmt.Errorf("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HTTPStatus(tt.err))
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	t.Run("domain error message is returned without wrapping context", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		writeError(rec, "withdraw", fmt.Errorf("withdraw from user 1: %w", domainerrors.ErrInsufficientFunds))
+
+		assert.Equal(t, http.StatusPaymentRequired, rec.Code)
+		assert.Equal(t, "insufficient funds\n", rec.Body.String())
+	})
+
+	t.Run("internal error details are hidden", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		writeError(rec, "withdraw", fmt.Errorf("connection refused"))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, "internal server error\n", rec.Body.String())
+	})
+}
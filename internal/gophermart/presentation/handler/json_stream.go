@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// writeJSONArray кодирует JSON-массив по мере того, как next отдаёт элементы, не собирая их
+// в срез; next сообщает о конце io.EOF. Результат совпадает с json.Encoder.Encode для среза.
+func writeJSONArray[T any](w io.Writer, first T, next func() (T, error)) error {
+	bw := bufio.NewWriter(w)
+	if err := bw.WriteByte('['); err != nil {
+		return err
+	}
+
+	item := first
+	for i := 0; ; i++ {
+		if i > 0 {
+			if err := bw.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if _, err := bw.Write(data); err != nil {
+			return err
+		}
+
+		item, err = next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := bw.WriteString("]\n"); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestWriteJSONArray(t *testing.T) {
+	type item struct {
+		Name string `json:"name"`
+		Note string `json:"note,omitempty"`
+	}
+
+	tests := []struct {
+		name  string
+		items []item
+	}{
+		{name: "single item", items: []item{{Name: "a"}}},
+		{name: "several items", items: []item{{Name: "a"}, {Name: "b", Note: "<html>"}, {Name: "c"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rest := tt.items[1:]
+			next := func() (item, error) {
+				if len(rest) == 0 {
+					return item{}, io.EOF
+				}
+				it := rest[0]
+				rest = rest[1:]
+				return it, nil
+			}
+
+			var got, want bytes.Buffer
+			if err := writeJSONArray(&got, tt.items[0], next); err != nil {
+				t.Fatalf("writeJSONArray: %v", err)
+			}
+			if err := json.NewEncoder(&want).Encode(tt.items); err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if got.String() != want.String() {
+				t.Errorf("got %q, want %q", got.String(), want.String())
+			}
+		})
+	}
+}
+
+func TestWriteJSONArray_NextError(t *testing.T) {
+	errLost := errors.New("connection lost")
+	next := func() (int, error) { return 0, errLost }
+
+	if err := writeJSONArray(io.Discard, 1, next); !errors.Is(err, errLost) {
+		t.Errorf("err = %v, want %v", err, errLost)
+	}
+}
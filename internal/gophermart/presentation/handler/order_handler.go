@@ -4,23 +4,30 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
 
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/application/usecase"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/presentation/middleware"
 )
 
 type OrderHandler struct {
 	uploadOrderUseCase *usecase.UploadOrderUseCase
 	getOrdersUseCase   *usecase.GetOrdersUseCase
+	getOrderUseCase    *usecase.GetOrderUseCase
 }
 
 func NewOrderHandler(
 	uploadOrderUseCase *usecase.UploadOrderUseCase,
 	getOrdersUseCase *usecase.GetOrdersUseCase,
+	getOrderUseCase *usecase.GetOrderUseCase,
 ) *OrderHandler {
 	return &OrderHandler{
 		uploadOrderUseCase: uploadOrderUseCase,
 		getOrdersUseCase:   getOrdersUseCase,
+		getOrderUseCase:    getOrderUseCase,
 	}
 }
 
@@ -54,15 +61,7 @@ func (h *OrderHandler) Upload(w http.ResponseWriter, r *http.Request) {
 		Number: orderNumber,
 	})
 	if err != nil {
-		if err.Error() == "invalid order number format" {
-			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
-			return
-		}
-		if err.Error() == "order number already exists for another user" {
-			http.Error(w, err.Error(), http.StatusConflict)
-			return
-		}
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		writeError(w, "upload order", err)
 		return
 	}
 
@@ -86,11 +85,17 @@ func (h *OrderHandler) GetList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	query := r.URL.Query()
+	if hasAnyParam(query, "limit", "cursor", "status", "from", "to") {
+		h.getPage(w, r, userID)
+		return
+	}
+
 	orders, err := h.getOrdersUseCase.Execute(r.Context(), usecase.GetOrdersRequest{
 		UserID: userID,
 	})
 	if err != nil {
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		writeError(w, "get orders", err)
 		return
 	}
 
@@ -103,3 +108,66 @@ func (h *OrderHandler) GetList(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(orders)
 }
+
+// Get отдаёт заказ с историей смены статусов и, пока статус не финальный, состоянием опроса системы начислений.
+func (h *OrderHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	order, err := h.getOrderUseCase.Execute(r.Context(), usecase.GetOrderRequest{
+		UserID: userID,
+		Number: chi.URLParam(r, "number"),
+	})
+	if err != nil {
+		writeError(w, "get order", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(order)
+}
+
+// getPage отдаёт страницу заказов. Поддерживаются параметры limit, cursor, status,
+// from и to (дата загрузки); курсор следующей страницы передаётся в заголовках Link и X-Next-Cursor.
+func (h *OrderHandler) getPage(w http.ResponseWriter, r *http.Request, userID int64) {
+	query := r.URL.Query()
+	req := usecase.GetOrdersPageRequest{
+		UserID: userID,
+		Status: model.OrderStatus(strings.ToUpper(query.Get("status"))),
+		Cursor: query.Get("cursor"),
+	}
+
+	var err error
+	if req.Limit, err = parseLimitParam(query); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.From, err = parseDateParam(query, "from", false); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.To, err = parseDateParam(query, "to", true); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.getOrdersUseCase.ExecutePage(r.Context(), req)
+	if err != nil {
+		writeError(w, "get orders", err)
+		return
+	}
+
+	if len(page.Orders) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	setNextPage(w, r, page.NextCursor)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(page.Orders)
+}
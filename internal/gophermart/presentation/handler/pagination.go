@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+)
+
+const (
+	nextCursorHeader = "X-Next-Cursor"
+	dateLayout       = "2006-01-02"
+)
+
+// hasAnyParam сообщает, передан ли хотя бы один из параметров запроса. Без параметров
+// списки отдаются целиком, как описано в спецификации.
+func hasAnyParam(query url.Values, names ...string) bool {
+	for _, name := range names {
+		if query.Has(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseLimitParam(query url.Values) (int, error) {
+	value := query.Get("limit")
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid limit")
+	}
+	return n, nil
+}
+
+// parseDateParam принимает RFC 3339 или дату YYYY-MM-DD. Для верхней границы (upper)
+// дата без времени означает конец этого дня, чтобы to=2024-01-31 включало 31 января.
+func parseDateParam(query url.Values, name string, upper bool) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s", name)
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+func parsePointsParam(query url.Values, name string) (*model.Points, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	points, err := model.ParsePoints(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", name)
+	}
+	return &points, nil
+}
+
+// setNextPage передаёт курсор следующей страницы в X-Next-Cursor и в Link с rel="next",
+// сохраняя остальные параметры запроса.
+func setNextPage(w http.ResponseWriter, r *http.Request, cursor string) {
+	if cursor == "" {
+		return
+	}
+	query := r.URL.Query()
+	query.Set("cursor", cursor)
+	next := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	w.Header().Set(nextCursorHeader, cursor)
+	w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", next.String()))
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+)
+
+func TestParseDateParam(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		upper   bool
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "empty", value: ""},
+		{name: "rfc3339", value: "2024-01-31T10:00:00+03:00", want: time.Date(2024, 1, 31, 7, 0, 0, 0, time.UTC)},
+		{name: "date as lower bound", value: "2024-01-31", want: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "date as upper bound includes the day", value: "2024-01-31", upper: true, want: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "invalid", value: "31.01.2024", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDateParam(url.Values{"from": {tt.value}}, "from", tt.upper)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePointsParam(t *testing.T) {
+	got, err := parsePointsParam(url.Values{"min_sum": {"10.5"}}, "min_sum")
+	if err != nil || got == nil || *got != model.MustParsePoints("10.5") {
+		t.Errorf("got %v, %v; want 10.50", got, err)
+	}
+
+	got, err = parsePointsParam(url.Values{}, "min_sum")
+	if err != nil || got != nil {
+		t.Errorf("empty value: got %v, %v; want nil", got, err)
+	}
+
+	if _, err = parsePointsParam(url.Values{"min_sum": {"ten"}}, "min_sum"); err == nil {
+		t.Error("expected error for malformed value")
+	}
+}
+
+func TestSetNextPage(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/user/orders?limit=2&status=NEW&cursor=old", nil)
+	w := httptest.NewRecorder()
+
+	setNextPage(w, r, "next")
+
+	if got := w.Header().Get(nextCursorHeader); got != "next" {
+		t.Errorf("%s = %q, want %q", nextCursorHeader, got, "next")
+	}
+	want := `</api/user/orders?cursor=next&limit=2&status=NEW>; rel="next"`
+	if got := w.Header().Get("Link"); got != want {
+		t.Errorf("Link = %q, want %q", got, want)
+	}
+
+	w = httptest.NewRecorder()
+	setNextPage(w, r, "")
+	if len(w.Header()) != 0 {
+		t.Errorf("headers set for last page: %v", w.Header())
+	}
+}
@@ -2,7 +2,11 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"log"
 	"net/http"
+
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/application/usecase"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/presentation/middleware"
 )
@@ -17,6 +21,10 @@ func NewWithdrawalHandler(getWithdrawalsUseCase *usecase.GetWithdrawalsUseCase)
 	}
 }
 
+// GetList отдаёт списания пользователя от новых к старым. Поддерживаются параметры from и to
+// (дата списания), min_sum и max_sum, а также limit и cursor. Без limit список пишется
+// в ответ потоком прямо из базы; страница с limit собирается целиком, чтобы до тела
+// передать курсор следующей страницы в заголовках Link и X-Next-Cursor.
 func (h *WithdrawalHandler) GetList(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -29,22 +37,78 @@ func (h *WithdrawalHandler) GetList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	withdrawals, err := h.getWithdrawalsUseCase.Execute(r.Context(), usecase.GetWithdrawalsRequest{
+	query := r.URL.Query()
+	req := usecase.GetWithdrawalsRequest{
 		UserID: userID,
-	})
+		Cursor: query.Get("cursor"),
+	}
+
+	var err error
+	if req.Limit, err = parseLimitParam(query); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.From, err = parseDateParam(query, "from", false); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.To, err = parseDateParam(query, "to", true); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.MinSum, err = parsePointsParam(query, "min_sum"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.MaxSum, err = parsePointsParam(query, "max_sum"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stream, err := h.getWithdrawalsUseCase.Execute(r.Context(), req)
 	if err != nil {
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		writeError(w, "get withdrawals", err)
 		return
 	}
+	defer stream.Close()
 
-	if len(withdrawals) == 0 {
+	first, err := stream.Next()
+	if errors.Is(err, io.EOF) {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
+	if err != nil {
+		writeError(w, "get withdrawals", err)
+		return
+	}
+
+	if req.Limit > 0 {
+		page := []*usecase.WithdrawalResponse{first}
+		for {
+			withdrawal, err := stream.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				writeError(w, "get withdrawals", err)
+				return
+			}
+			page = append(page, withdrawal)
+		}
+
+		setNextPage(w, r, stream.NextCursor())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(page)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(withdrawals)
+	if err := writeJSONArray(w, first, stream.Next); err != nil {
+		// Статус уже отправлен: обрываем соединение, чтобы клиент не принял
+		// усечённый список за полный.
+		log.Printf("get withdrawals: stream interrupted: %v", err)
+		panic(http.ErrAbortHandler)
+	}
 }
-
-
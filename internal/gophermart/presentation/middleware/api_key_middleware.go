@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/application/usecase"
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+)
+
+const (
+	APIKeyHeader      = "X-API-Key"
+	ActingUserHeader  = "X-Acting-User-ID"
+	actingUserQueryID = "acting_user_id"
+)
+
+type APIKeyAuthenticator interface {
+	Execute(ctx context.Context, req usecase.AuthenticateAPIKeyRequest) (*usecase.APIKeyPrincipal, error)
+}
+
+// AllowAPIKey разрешает маршруту, помимо токена пользователя, запросы внешних систем по X-API-Key.
+// Такой запрос выполняется от имени пользователя из X-Acting-User-ID (или параметра acting_user_id),
+// ключ должен иметь scope. Ролей у запроса по ключу нет, поэтому административные маршруты ему недоступны.
+func (m *AuthMiddleware) AllowAPIKey(scope model.APIKeyScope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		jwtHandler := m.Handle(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(APIKeyHeader)
+			if key == "" || m.apiKeys == nil {
+				jwtHandler.ServeHTTP(w, r)
+				return
+			}
+
+			actingUserID, err := actingUserFromRequest(r)
+			if err != nil {
+				http.Error(w, "invalid acting user id", http.StatusBadRequest)
+				return
+			}
+
+			principal, err := m.apiKeys.Execute(r.Context(), usecase.AuthenticateAPIKeyRequest{
+				Key:          key,
+				Scope:        scope,
+				ActingUserID: actingUserID,
+			})
+			if err != nil {
+				writeAPIKeyError(w, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), userIDKey, principal.ActingUserID)
+			ctx = context.WithValue(ctx, apiKeyIDKey, principal.KeyID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func actingUserFromRequest(r *http.Request) (int64, error) {
+	value := r.Header.Get(ActingUserHeader)
+	if value == "" {
+		value = r.URL.Query().Get(actingUserQueryID)
+	}
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+func writeAPIKeyError(w http.ResponseWriter, err error) {
+	switch domainerrors.KindOf(err) {
+	case domainerrors.KindUnauthorized:
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	case domainerrors.KindForbidden:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case domainerrors.KindInvalidInput:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("api key authentication error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+// GetAPIKeyID возвращает ID ключа, если запрос выполнен по API-ключу.
+func GetAPIKeyID(ctx context.Context) (int64, bool) {
+	keyID, ok := ctx.Value(apiKeyIDKey).(int64)
+	return keyID, ok
+}
@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/application/usecase"
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
+)
+
+type stubTokenVerifier struct{}
+
+func (stubTokenVerifier) Verify(_ context.Context, token string) (*service.TokenClaims, error) {
+	if token != "valid" {
+		return nil, domainerrors.ErrInvalidToken
+	}
+	return &service.TokenClaims{UserID: 1, Roles: []string{service.RoleUser}}, nil
+}
+
+type stubAPIKeyAuthenticator struct {
+	got usecase.AuthenticateAPIKeyRequest
+}
+
+func (s *stubAPIKeyAuthenticator) Execute(_ context.Context, req usecase.AuthenticateAPIKeyRequest) (*usecase.APIKeyPrincipal, error) {
+	s.got = req
+	switch {
+	case req.Key != "gm_valid":
+		return nil, domainerrors.ErrInvalidAPIKey
+	case req.Scope != model.APIKeyScopeOrdersWrite:
+		return nil, domainerrors.ErrAPIKeyScopeDenied
+	case req.ActingUserID == 0:
+		return nil, domainerrors.ErrActingUserRequired
+	}
+	return &usecase.APIKeyPrincipal{KeyID: 9, Owner: "shop", ActingUserID: req.ActingUserID}, nil
+}
+
+func TestAuthMiddleware_AllowAPIKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		scope      model.APIKeyScope
+		headers    map[string]string
+		target     string
+		wantStatus int
+		wantUserID int64
+		wantKeyID  int64
+	}{
+		{
+			name:       "bearer token",
+			scope:      model.APIKeyScopeOrdersWrite,
+			headers:    map[string]string{"Authorization": "Bearer valid"},
+			wantStatus: http.StatusOK,
+			wantUserID: 1,
+		},
+		{
+			name:       "api key with acting user header",
+			scope:      model.APIKeyScopeOrdersWrite,
+			headers:    map[string]string{APIKeyHeader: "gm_valid", ActingUserHeader: "42"},
+			wantStatus: http.StatusOK,
+			wantUserID: 42,
+			wantKeyID:  9,
+		},
+		{
+			name:       "api key with acting user query parameter",
+			scope:      model.APIKeyScopeOrdersWrite,
+			headers:    map[string]string{APIKeyHeader: "gm_valid"},
+			target:     "/api/user/orders?acting_user_id=43",
+			wantStatus: http.StatusOK,
+			wantUserID: 43,
+			wantKeyID:  9,
+		},
+		{
+			name:       "invalid api key",
+			scope:      model.APIKeyScopeOrdersWrite,
+			headers:    map[string]string{APIKeyHeader: "gm_other", ActingUserHeader: "42"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "scope denied",
+			scope:      model.APIKeyScopeBalanceRead,
+			headers:    map[string]string{APIKeyHeader: "gm_valid", ActingUserHeader: "42"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "missing acting user",
+			scope:      model.APIKeyScopeOrdersWrite,
+			headers:    map[string]string{APIKeyHeader: "gm_valid"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "malformed acting user",
+			scope:      model.APIKeyScopeOrdersWrite,
+			headers:    map[string]string{APIKeyHeader: "gm_valid", ActingUserHeader: "abc"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "no credentials",
+			scope:      model.APIKeyScopeOrdersWrite,
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewAuthMiddleware(stubTokenVerifier{}, &stubAPIKeyAuthenticator{})
+
+			var gotUserID, gotKeyID int64
+			handler := m.AllowAPIKey(tt.scope)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUserID, _ = GetUserID(r.Context())
+				gotKeyID, _ = GetAPIKeyID(r.Context())
+				if _, ok := GetAPIKeyID(r.Context()); ok && len(GetRoles(r.Context())) > 0 {
+					t.Error("api key request must not carry roles")
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			target := tt.target
+			if target == "" {
+				target = "/api/user/orders"
+			}
+			req := httptest.NewRequest(http.MethodPost, target, nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if gotUserID != tt.wantUserID {
+				t.Errorf("user id = %d, want %d", gotUserID, tt.wantUserID)
+			}
+			if gotKeyID != tt.wantKeyID {
+				t.Errorf("api key id = %d, want %d", gotKeyID, tt.wantKeyID)
+			}
+		})
+	}
+}
@@ -2,25 +2,34 @@ package middleware
 
 import (
 	"context"
+	"errors"
+	"log"
 	"net/http"
 	"strings"
 
-	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/infrastructure/httpclient"
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
 )
 
 type AuthMiddleware struct {
-	userServiceClient *httpclient.UserServiceClient
+	tokenVerifier service.TokenVerifier
+	apiKeys       APIKeyAuthenticator
 }
 
-func NewAuthMiddleware(userServiceClient *httpclient.UserServiceClient) *AuthMiddleware {
+func NewAuthMiddleware(tokenVerifier service.TokenVerifier, apiKeys APIKeyAuthenticator) *AuthMiddleware {
 	return &AuthMiddleware{
-		userServiceClient: userServiceClient,
+		tokenVerifier: tokenVerifier,
+		apiKeys:       apiKeys,
 	}
 }
 
 type contextKey string
 
-const userIDKey contextKey = "userID"
+const (
+	userIDKey   contextKey = "userID"
+	rolesKey    contextKey = "roles"
+	apiKeyIDKey contextKey = "apiKeyID"
+)
 
 func (m *AuthMiddleware) Handle(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -30,13 +39,17 @@ func (m *AuthMiddleware) Handle(next http.Handler) http.Handler {
 			return
 		}
 
-		claims, err := m.userServiceClient.ValidateToken(r.Context(), token)
+		claims, err := m.tokenVerifier.Verify(r.Context(), token)
 		if err != nil {
+			if !errors.Is(err, domainerrors.ErrInvalidToken) {
+				log.Printf("token verification error: %v", err)
+			}
 			http.Error(w, "unauthorized", http.StatusUnauthorized)
 			return
 		}
 
 		ctx := context.WithValue(r.Context(), userIDKey, claims.UserID)
+		ctx = context.WithValue(ctx, rolesKey, claims.Roles)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -60,3 +73,9 @@ func GetUserID(ctx context.Context) (int64, bool) {
 	return userID, ok
 }
 
+// GetRoles возвращает роли пользователя из claims токена.
+func GetRoles(ctx context.Context) []string {
+	roles, _ := ctx.Value(rolesKey).([]string)
+	return roles
+}
+
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/gophermart/domain/service"
+)
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     int64
+		roles      []string
+		wantStatus int
+	}{
+		{name: "admin", userID: 1, roles: []string{service.RoleUser, service.RoleAdmin}, wantStatus: http.StatusOK},
+		{name: "support", userID: 1, roles: []string{service.RoleUser, service.RoleSupport}, wantStatus: http.StatusOK},
+		{name: "plain user", userID: 1, roles: []string{service.RoleUser}, wantStatus: http.StatusForbidden},
+		{name: "token without roles", userID: 1, wantStatus: http.StatusForbidden},
+		{name: "unauthenticated", wantStatus: http.StatusUnauthorized},
+	}
+
+	handler := RequireRole(service.RoleSupport, service.RoleAdmin)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/outbox", nil)
+			if tt.userID != 0 {
+				ctx := context.WithValue(req.Context(), userIDKey, tt.userID)
+				ctx = context.WithValue(ctx, rolesKey, tt.roles)
+				req = req.WithContext(ctx)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
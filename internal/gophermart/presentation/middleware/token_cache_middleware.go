@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// TokenCacheInvalidator — кэш результатов проверки токенов, из которого можно удалить
+// токены пользователя.
+type TokenCacheInvalidator interface {
+	// ForgetUser удаляет из кэша токены пользователя, которому принадлежит token.
+	ForgetUser(token string)
+}
+
+// InvalidateTokenCache подключается к маршрутам, отзывающим токены (выход, смена пароля):
+// после успешного ответа токены пользователя из заголовка Authorization удаляются из кэша,
+// и следующий запрос с ними проверяется заново.
+func InvalidateTokenCache(cache TokenCacheInvalidator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := extractTokenFromHeader(r)
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if token != "" && (status == 0 || status >= 200 && status < 300) {
+				cache.ForgetUser(token)
+			}
+		})
+	}
+}
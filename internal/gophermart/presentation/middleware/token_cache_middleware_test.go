@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeTokenCache struct {
+	forgotten []string
+}
+
+func (c *fakeTokenCache) ForgetUser(token string) {
+	c.forgotten = append(c.forgotten, token)
+}
+
+func TestInvalidateTokenCache(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+		wantForgotten bool
+	}{
+		{name: "successful logout", authorization: "Bearer token", status: http.StatusNoContent, wantForgotten: true},
+		{name: "failed logout", authorization: "Bearer token", status: http.StatusUnauthorized},
+		{name: "without token", status: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &fakeTokenCache{}
+			handler := InvalidateTokenCache(cache)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/logout", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if forgotten := len(cache.forgotten) == 1 && cache.forgotten[0] == "token"; forgotten != tt.wantForgotten {
+				t.Errorf("forgotten = %v, want token forgotten: %v", cache.forgotten, tt.wantForgotten)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type ChangePasswordUseCase struct {
+	userRepo         repository.UserRepository
+	validateToken    *ValidateTokenUseCase
+	revokedTokenRepo repository.RevokedTokenRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	tokenIssuer      *TokenIssuer
+	passwordPolicy   service.PasswordPolicy
+	now              func() time.Time
+}
+
+func NewChangePasswordUseCase(
+	userRepo repository.UserRepository,
+	validateToken *ValidateTokenUseCase,
+	revokedTokenRepo repository.RevokedTokenRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	tokenIssuer *TokenIssuer,
+	passwordPolicy service.PasswordPolicy,
+) *ChangePasswordUseCase {
+	return &ChangePasswordUseCase{
+		userRepo:         userRepo,
+		validateToken:    validateToken,
+		revokedTokenRepo: revokedTokenRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		tokenIssuer:      tokenIssuer,
+		passwordPolicy:   passwordPolicy,
+		now:              time.Now,
+	}
+}
+
+type ChangePasswordRequest struct {
+	AccessToken string
+	OldPassword string
+	NewPassword string
+}
+
+// ChangePasswordResponse — новая пара токенов для текущего клиента: все прежние сессии отозваны.
+type ChangePasswordResponse struct {
+	Token        string
+	RefreshToken string
+}
+
+func (uc *ChangePasswordUseCase) Execute(ctx context.Context, req ChangePasswordRequest) (*ChangePasswordResponse, error) {
+	validated, err := uc.validateToken.Execute(ctx, ValidateTokenRequest{Token: req.AccessToken})
+	if err != nil {
+		return nil, err
+	}
+	if req.OldPassword == "" {
+		return nil, errors.ErrPasswordRequired
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, validated.Claims.UserID)
+	if err != nil {
+		if errors.Is(err, errors.ErrUserNotFound) {
+			return nil, errors.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.OldPassword)); err != nil {
+		return nil, errors.ErrInvalidCredentials
+	}
+	if req.NewPassword == req.OldPassword {
+		return nil, errors.ErrSamePassword
+	}
+	if err := uc.passwordPolicy.Validate(req.NewPassword, user.Login); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.userRepo.UpdatePassword(ctx, user.ID, string(hashedPassword)); err != nil {
+		return nil, err
+	}
+
+	if err := revokeAllSessions(ctx, uc.revokedTokenRepo, uc.refreshTokenRepo, user.ID, uc.now()); err != nil {
+		return nil, err
+	}
+
+	pair, err := uc.tokenIssuer.Issue(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &ChangePasswordResponse{Token: pair.AccessToken, RefreshToken: pair.RefreshToken}, nil
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+)
+
+func TestChangePasswordUseCase_Execute(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("Old-password-1"), bcrypt.MinCost)
+	user := &model.User{ID: 1, Login: "testuser", PasswordHash: string(hashedPassword)}
+	issuedAt := time.Now().Add(-time.Minute)
+	claims := &model.Claims{
+		UserID:           1,
+		Login:            "testuser",
+		RegisteredClaims: jwt.RegisteredClaims{ID: "jti-1", IssuedAt: jwt.NewNumericDate(issuedAt)},
+	}
+	now := time.Date(2024, 1, 1, 12, 0, 30, 500_000_700, time.UTC)
+	policy := service.PasswordPolicy{MinLength: 8, MinClasses: 2, RejectCommon: true}
+
+	tests := []struct {
+		name        string
+		oldPassword string
+		newPassword string
+		setupMocks  func(*MockUserRepository, *MockRevokedTokenRepository, *MockRefreshTokenRepository, *MockJWTService)
+		wantErr     error
+	}{
+		{
+			name:        "changes password and revokes other sessions",
+			oldPassword: "Old-password-1",
+			newPassword: "New-password-2",
+			setupMocks: func(userRepo *MockUserRepository, revokedRepo *MockRevokedTokenRepository, refreshRepo *MockRefreshTokenRepository, jwtService *MockJWTService) {
+				userRepo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+				userRepo.On("UpdatePassword", mock.Anything, int64(1), mock.MatchedBy(func(hash string) bool {
+					return bcrypt.CompareHashAndPassword([]byte(hash), []byte("New-password-2")) == nil
+				})).Return(nil)
+				revokedRepo.On("RevokeAllForUser", mock.Anything, int64(1), now.Truncate(model.TokenTimePrecision)).Return(nil)
+				refreshRepo.On("RevokeAllForUser", mock.Anything, int64(1)).Return(nil)
+				refreshRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+				jwtService.On("GenerateToken", int64(1), "testuser", mock.Anything).Return("new-token", nil)
+			},
+		},
+		{
+			name:        "wrong current password",
+			oldPassword: "Wrong-password-1",
+			newPassword: "New-password-2",
+			setupMocks: func(userRepo *MockUserRepository, _ *MockRevokedTokenRepository, _ *MockRefreshTokenRepository, _ *MockJWTService) {
+				userRepo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+			},
+			wantErr: domainerrors.ErrInvalidCredentials,
+		},
+		{
+			name:        "same password",
+			oldPassword: "Old-password-1",
+			newPassword: "Old-password-1",
+			setupMocks: func(userRepo *MockUserRepository, _ *MockRevokedTokenRepository, _ *MockRefreshTokenRepository, _ *MockJWTService) {
+				userRepo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+			},
+			wantErr: domainerrors.ErrSamePassword,
+		},
+		{
+			name:        "weak new password",
+			oldPassword: "Old-password-1",
+			newPassword: "qwerty123",
+			setupMocks: func(userRepo *MockUserRepository, _ *MockRevokedTokenRepository, _ *MockRefreshTokenRepository, _ *MockJWTService) {
+				userRepo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+			},
+			wantErr: domainerrors.ErrWeakPassword,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			revokedRepo := new(MockRevokedTokenRepository)
+			refreshRepo := new(MockRefreshTokenRepository)
+			jwtService := new(MockJWTService)
+			jwtService.On("ValidateToken", "access").Return(claims, nil)
+			revokedRepo.On("IsRevoked", mock.Anything, "jti-1", int64(1), issuedAt.Truncate(time.Second)).Return(false, nil)
+			tt.setupMocks(userRepo, revokedRepo, refreshRepo, jwtService)
+
+			uc := NewChangePasswordUseCase(
+				userRepo,
+				NewValidateTokenUseCase(jwtService, revokedRepo),
+				revokedRepo,
+				refreshRepo,
+				NewTokenIssuer(jwtService, refreshRepo, time.Hour),
+				policy,
+			)
+			uc.now = func() time.Time { return now }
+
+			resp, err := uc.Execute(context.Background(), ChangePasswordRequest{
+				AccessToken: "access",
+				OldPassword: tt.oldPassword,
+				NewPassword: tt.newPassword,
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				userRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "new-token", resp.Token)
+			assert.NotEmpty(t, resp.RefreshToken)
+			userRepo.AssertExpectations(t)
+			revokedRepo.AssertExpectations(t)
+			refreshRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestChangePasswordUseCase_RevokedToken(t *testing.T) {
+	jwtService := new(MockJWTService)
+	revokedRepo := new(MockRevokedTokenRepository)
+	userRepo := new(MockUserRepository)
+	jwtService.On("ValidateToken", "access").Return(&model.Claims{UserID: 1}, nil)
+	revokedRepo.On("IsRevoked", mock.Anything, mock.Anything, int64(1), mock.Anything).Return(true, nil)
+
+	uc := NewChangePasswordUseCase(userRepo, NewValidateTokenUseCase(jwtService, revokedRepo), revokedRepo,
+		new(MockRefreshTokenRepository), nil, service.PasswordPolicy{})
+
+	_, err := uc.Execute(context.Background(), ChangePasswordRequest{AccessToken: "access", OldPassword: "a", NewPassword: "b"})
+
+	assert.ErrorIs(t, err, domainerrors.ErrTokenRevoked)
+	userRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type ConfirmPasswordResetUseCase struct {
+	userRepo         repository.UserRepository
+	resetTokenRepo   repository.PasswordResetTokenRepository
+	revokedTokenRepo repository.RevokedTokenRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	passwordPolicy   service.PasswordPolicy
+	now              func() time.Time
+}
+
+func NewConfirmPasswordResetUseCase(
+	userRepo repository.UserRepository,
+	resetTokenRepo repository.PasswordResetTokenRepository,
+	revokedTokenRepo repository.RevokedTokenRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	passwordPolicy service.PasswordPolicy,
+) *ConfirmPasswordResetUseCase {
+	return &ConfirmPasswordResetUseCase{
+		userRepo:         userRepo,
+		resetTokenRepo:   resetTokenRepo,
+		revokedTokenRepo: revokedTokenRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		passwordPolicy:   passwordPolicy,
+		now:              time.Now,
+	}
+}
+
+type ConfirmPasswordResetRequest struct {
+	Token       string
+	NewPassword string
+}
+
+// Execute устанавливает новый пароль по токену сброса и завершает все сессии пользователя.
+func (uc *ConfirmPasswordResetUseCase) Execute(ctx context.Context, req ConfirmPasswordResetRequest) error {
+	if req.Token == "" {
+		return errors.ErrInvalidResetToken
+	}
+
+	now := uc.now()
+	resetToken, err := uc.resetTokenRepo.FindByHash(ctx, HashToken(req.Token))
+	if err != nil {
+		return err
+	}
+	if resetToken.UsedAt != nil || resetToken.IsExpired(now) {
+		return errors.ErrInvalidResetToken
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, resetToken.UserID)
+	if err != nil {
+		if errors.Is(err, errors.ErrUserNotFound) {
+			return errors.ErrInvalidResetToken
+		}
+		return err
+	}
+
+	// Пароль проверяется до использования токена, чтобы слабый пароль не сжигал ссылку.
+	if err := uc.passwordPolicy.Validate(req.NewPassword, user.Login); err != nil {
+		return err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	redeemed, err := uc.resetTokenRepo.Redeem(ctx, resetToken.ID, string(hashedPassword), now)
+	if err != nil {
+		if errors.Is(err, errors.ErrUserNotFound) {
+			return errors.ErrInvalidResetToken
+		}
+		return err
+	}
+	if !redeemed {
+		return errors.ErrInvalidResetToken
+	}
+
+	return revokeAllSessions(ctx, uc.revokedTokenRepo, uc.refreshTokenRepo, user.ID, now)
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+)
+
+type DeleteAccountUseCase struct {
+	userRepo         repository.UserRepository
+	revokedTokenRepo repository.RevokedTokenRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	now              func() time.Time
+}
+
+func NewDeleteAccountUseCase(
+	userRepo repository.UserRepository,
+	revokedTokenRepo repository.RevokedTokenRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+) *DeleteAccountUseCase {
+	return &DeleteAccountUseCase{
+		userRepo:         userRepo,
+		revokedTokenRepo: revokedTokenRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		now:              time.Now,
+	}
+}
+
+type DeleteAccountRequest struct {
+	UserID int64
+}
+
+// Execute отзывает все токены пользователя и обезличивает его учётную запись.
+// Сессии отзываются первыми, чтобы выданные токены не пережили удаление при сбое.
+func (uc *DeleteAccountUseCase) Execute(ctx context.Context, req DeleteAccountRequest) error {
+	if err := revokeAllSessions(ctx, uc.revokedTokenRepo, uc.refreshTokenRepo, req.UserID, uc.now()); err != nil {
+		return err
+	}
+	return uc.userRepo.Anonymize(ctx, req.UserID)
+}
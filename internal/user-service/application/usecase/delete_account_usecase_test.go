@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+)
+
+func TestDeleteAccountUseCase_Execute(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 30, 500_000_700, time.UTC)
+
+	tests := []struct {
+		name       string
+		setupMocks func(*MockUserRepository, *MockRevokedTokenRepository, *MockRefreshTokenRepository)
+		wantErr    error
+	}{
+		{
+			name: "revokes sessions and anonymizes user",
+			setupMocks: func(userRepo *MockUserRepository, revokedRepo *MockRevokedTokenRepository, refreshRepo *MockRefreshTokenRepository) {
+				revokedRepo.On("RevokeAllForUser", mock.Anything, int64(1), now.Truncate(model.TokenTimePrecision)).Return(nil)
+				refreshRepo.On("RevokeAllForUser", mock.Anything, int64(1)).Return(nil)
+				userRepo.On("Anonymize", mock.Anything, int64(1)).Return(nil)
+			},
+		},
+		{
+			name: "user already deleted",
+			setupMocks: func(userRepo *MockUserRepository, revokedRepo *MockRevokedTokenRepository, refreshRepo *MockRefreshTokenRepository) {
+				revokedRepo.On("RevokeAllForUser", mock.Anything, int64(1), mock.Anything).Return(nil)
+				refreshRepo.On("RevokeAllForUser", mock.Anything, int64(1)).Return(nil)
+				userRepo.On("Anonymize", mock.Anything, int64(1)).Return(domainerrors.ErrUserNotFound)
+			},
+			wantErr: domainerrors.ErrUserNotFound,
+		},
+		{
+			name: "revocation failure keeps user data",
+			setupMocks: func(_ *MockUserRepository, revokedRepo *MockRevokedTokenRepository, _ *MockRefreshTokenRepository) {
+				revokedRepo.On("RevokeAllForUser", mock.Anything, int64(1), mock.Anything).Return(errors.New("db error"))
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			revokedRepo := new(MockRevokedTokenRepository)
+			refreshRepo := new(MockRefreshTokenRepository)
+			tt.setupMocks(userRepo, revokedRepo, refreshRepo)
+
+			uc := NewDeleteAccountUseCase(userRepo, revokedRepo, refreshRepo)
+			uc.now = func() time.Time { return now }
+
+			err := uc.Execute(context.Background(), DeleteAccountRequest{UserID: 1})
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+			userRepo.AssertExpectations(t)
+			revokedRepo.AssertExpectations(t)
+			refreshRepo.AssertExpectations(t)
+		})
+	}
+}
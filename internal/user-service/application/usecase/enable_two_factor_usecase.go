@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+)
+
+type EnableTwoFactorUseCase struct {
+	validateToken *ValidateTokenUseCase
+	twoFactorRepo repository.TwoFactorRepository
+	cipher        service.SecretCipher
+	totp          service.TOTP
+	now           func() time.Time
+}
+
+func NewEnableTwoFactorUseCase(
+	validateToken *ValidateTokenUseCase,
+	twoFactorRepo repository.TwoFactorRepository,
+	cipher service.SecretCipher,
+	totp service.TOTP,
+) *EnableTwoFactorUseCase {
+	return &EnableTwoFactorUseCase{
+		validateToken: validateToken,
+		twoFactorRepo: twoFactorRepo,
+		cipher:        cipher,
+		totp:          totp,
+		now:           time.Now,
+	}
+}
+
+type EnableTwoFactorRequest struct {
+	AccessToken string
+	Code        string
+}
+
+// EnableTwoFactorResponse — одноразовые резервные коды. Они показываются только один раз,
+// в БД хранятся их хэши.
+type EnableTwoFactorResponse struct {
+	RecoveryCodes []string
+}
+
+func (uc *EnableTwoFactorUseCase) Execute(ctx context.Context, req EnableTwoFactorRequest) (*EnableTwoFactorResponse, error) {
+	validated, err := uc.validateToken.Execute(ctx, ValidateTokenRequest{Token: req.AccessToken})
+	if err != nil {
+		return nil, err
+	}
+	if uc.cipher == nil {
+		return nil, errors.ErrTwoFactorUnavailable
+	}
+
+	userID := validated.Claims.UserID
+	twoFactor, err := uc.twoFactorRepo.Find(ctx, userID)
+	if err != nil {
+		if errors.Is(err, errors.ErrUserNotFound) {
+			return nil, errors.ErrInvalidToken
+		}
+		return nil, err
+	}
+	if twoFactor.Enabled {
+		return nil, errors.ErrTwoFactorEnabled
+	}
+	if twoFactor.Secret == nil {
+		return nil, errors.ErrTwoFactorNotStarted
+	}
+
+	secret, err := uc.cipher.Decrypt(twoFactor.Secret, twoFactorAssociatedData(userID))
+	if err != nil {
+		return nil, err
+	}
+	step, ok := uc.totp.Verify(secret, req.Code, uc.now())
+	if !ok {
+		return nil, errors.ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.twoFactorRepo.Enable(ctx, userID, twoFactor.Secret, hashes, step); err != nil {
+		return nil, err
+	}
+	return &EnableTwoFactorResponse{RecoveryCodes: codes}, nil
+}
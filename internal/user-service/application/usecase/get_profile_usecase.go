@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+)
+
+type GetProfileUseCase struct {
+	validateToken *ValidateTokenUseCase
+	userRepo      repository.UserRepository
+	balances      service.BalanceProvider
+}
+
+// NewGetProfileUseCase создаёт сценарий чтения профиля; balances == nil означает,
+// что сервис запущен без gophermart и баланс в профиль не попадает.
+func NewGetProfileUseCase(
+	validateToken *ValidateTokenUseCase,
+	userRepo repository.UserRepository,
+	balances service.BalanceProvider,
+) *GetProfileUseCase {
+	return &GetProfileUseCase{
+		validateToken: validateToken,
+		userRepo:      userRepo,
+		balances:      balances,
+	}
+}
+
+type GetProfileRequest struct {
+	AccessToken string
+}
+
+func (uc *GetProfileUseCase) Execute(ctx context.Context, req GetProfileRequest) (*Profile, error) {
+	validated, err := uc.validateToken.Execute(ctx, ValidateTokenRequest{Token: req.AccessToken})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, validated.Claims.UserID)
+	if err != nil {
+		if errors.Is(err, errors.ErrUserNotFound) {
+			return nil, errors.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	profile := newProfile(user)
+	if uc.balances != nil {
+		// Недоступный баланс не мешает показать профиль.
+		balance, err := uc.balances.GetBalanceSummary(ctx, user.ID)
+		if err != nil {
+			log.Printf("failed to load balance for user %d: %v", user.ID, err)
+		} else {
+			profile.Balance = balance
+		}
+	}
+	return profile, nil
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+)
+
+type GrantRoleUseCase struct {
+	userRepo repository.UserRepository
+}
+
+func NewGrantRoleUseCase(userRepo repository.UserRepository) *GrantRoleUseCase {
+	return &GrantRoleUseCase{
+		userRepo: userRepo,
+	}
+}
+
+type GrantRoleRequest struct {
+	Login string
+	Role  model.Role
+}
+
+// Execute выдаёт пользователю роль. Роль попадает в claims только новых токенов,
+// поэтому пользователю нужно войти заново или обновить токен.
+func (uc *GrantRoleUseCase) Execute(ctx context.Context, req GrantRoleRequest) error {
+	if req.Login == "" {
+		return errors.ErrLoginRequired
+	}
+	if !req.Role.Valid() {
+		return errors.ErrUnknownRole
+	}
+
+	user, err := uc.userRepo.FindByLogin(ctx, req.Login)
+	if err != nil {
+		return err
+	}
+	return uc.userRepo.AddRole(ctx, user.ID, req.Role)
+}
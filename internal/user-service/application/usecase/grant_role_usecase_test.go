@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+)
+
+func TestGrantRoleUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        GrantRoleRequest
+		setupMocks func(*MockUserRepository)
+		wantErr    error
+	}{
+		{
+			name: "grants admin role",
+			req:  GrantRoleRequest{Login: "testuser", Role: model.RoleAdmin},
+			setupMocks: func(userRepo *MockUserRepository) {
+				userRepo.On("FindByLogin", mock.Anything, "testuser").Return(&model.User{ID: 1, Login: "testuser"}, nil)
+				userRepo.On("AddRole", mock.Anything, int64(1), model.RoleAdmin).Return(nil)
+			},
+		},
+		{
+			name:       "empty login",
+			req:        GrantRoleRequest{Role: model.RoleAdmin},
+			setupMocks: func(*MockUserRepository) {},
+			wantErr:    domainerrors.ErrLoginRequired,
+		},
+		{
+			name:       "unknown role",
+			req:        GrantRoleRequest{Login: "testuser", Role: "root"},
+			setupMocks: func(*MockUserRepository) {},
+			wantErr:    domainerrors.ErrUnknownRole,
+		},
+		{
+			name: "user not found",
+			req:  GrantRoleRequest{Login: "missing", Role: model.RoleSupport},
+			setupMocks: func(userRepo *MockUserRepository) {
+				userRepo.On("FindByLogin", mock.Anything, "missing").Return(nil, domainerrors.ErrUserNotFound)
+			},
+			wantErr: domainerrors.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			tt.setupMocks(userRepo)
+
+			err := NewGrantRoleUseCase(userRepo).Execute(context.Background(), tt.req)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "got %v, want %v", err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+)
+
+// LoginThrottlePolicy задаёт защиту от подбора пароля.
+type LoginThrottlePolicy struct {
+	// MaxFailures — число неудачных попыток для логина, после которого вход блокируется на Lockout;
+	// ноль отключает блокировку.
+	MaxFailures int
+	// MaxIPFailures — то же для IP-адреса клиента; порог выше, чтобы не блокировать клиентов за NAT.
+	MaxIPFailures int
+	// Lockout — длительность блокировки; за это же время без неудач счётчик сбрасывается.
+	Lockout time.Duration
+	// BaseDelay — пауза после второй неудачи для логина, удваивается с каждой следующей.
+	BaseDelay time.Duration
+}
+
+// LoginThrottle считает неудачные попытки входа по логину и по IP-адресу.
+// Состояние хранится в БД, поэтому блокировка действует на всех репликах.
+type LoginThrottle struct {
+	attemptRepo repository.LoginAttemptRepository
+	policy      LoginThrottlePolicy
+	now         func() time.Time
+}
+
+func NewLoginThrottle(attemptRepo repository.LoginAttemptRepository, policy LoginThrottlePolicy) *LoginThrottle {
+	return &LoginThrottle{
+		attemptRepo: attemptRepo,
+		policy:      policy,
+		now:         time.Now,
+	}
+}
+
+// LoginTry — попытка входа, уже учтённая в счётчиках логина и IP-адреса.
+type LoginTry struct {
+	attempts []*model.LoginAttempt
+}
+
+// Acquire учитывает попытку до проверки пароля или кода: счётчик увеличивается одним
+// атомарным запросом, поэтому параллельные попытки не проходят мимо порога все сразу.
+// Если логин или IP-адрес заблокирован либо попытка превышает порог, возвращается
+// *errors.LoginLockedError. Учтённую попытку завершает RecordFailure, RecordSuccess или Release.
+func (t *LoginThrottle) Acquire(ctx context.Context, login, ip string) (*LoginTry, error) {
+	now := t.now()
+	try := &LoginTry{}
+	var retryAfter time.Duration
+	for _, key := range t.keys(login, ip) {
+		attempt, err := t.attemptRepo.RecordAttempt(ctx, key.scope, key.subject, now, now.Add(-t.policy.Lockout))
+		if err != nil {
+			return nil, err
+		}
+		if wait := attempt.RetryAfter(now); wait > 0 {
+			retryAfter = max(retryAfter, wait)
+			continue
+		}
+		if t.exceeded(key.scope, attempt.Failures) {
+			if err := t.attemptRepo.Lock(ctx, key.scope, key.subject, now.Add(t.policy.Lockout)); err != nil {
+				return nil, err
+			}
+			retryAfter = max(retryAfter, t.policy.Lockout)
+			continue
+		}
+		try.attempts = append(try.attempts, attempt)
+	}
+
+	if retryAfter > 0 {
+		// Попытка до пароля не дошла, поэтому уже учтённые счётчики возвращаются.
+		if err := t.Release(ctx, try); err != nil {
+			return nil, err
+		}
+		return nil, &errors.LoginLockedError{RetryAfter: retryAfter}
+	}
+	return try, nil
+}
+
+// RecordFailure замедляет или блокирует вход после неудачной попытки.
+func (t *LoginThrottle) RecordFailure(ctx context.Context, try *LoginTry) error {
+	now := t.now()
+	for _, attempt := range try.attempts {
+		if delay := t.delay(attempt.Scope, attempt.Failures); delay > 0 {
+			if err := t.attemptRepo.Lock(ctx, attempt.Scope, attempt.Subject, now.Add(delay)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RecordSuccess сбрасывает счётчик логина. У IP-адреса снимается только эта попытка:
+// иначе успешный вход в свой аккаунт обнулял бы подбор паролей к чужим.
+func (t *LoginThrottle) RecordSuccess(ctx context.Context, try *LoginTry) error {
+	for _, attempt := range try.attempts {
+		var err error
+		if attempt.Scope == model.LoginAttemptScopeLogin {
+			err = t.attemptRepo.Reset(ctx, attempt.Scope, attempt.Subject)
+		} else {
+			err = t.attemptRepo.Release(ctx, attempt.Scope, attempt.Subject)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Release снимает попытку со всех счётчиков, не считая её ни неудачной, ни успешной.
+func (t *LoginThrottle) Release(ctx context.Context, try *LoginTry) error {
+	for _, attempt := range try.attempts {
+		if err := t.attemptRepo.Release(ctx, attempt.Scope, attempt.Subject); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exceeded сообщает, что failures-я попытка подряд выходит за порог блокировки.
+func (t *LoginThrottle) exceeded(scope model.LoginAttemptScope, failures int) bool {
+	limit := t.policy.MaxFailures
+	if scope == model.LoginAttemptScopeIP {
+		limit = t.policy.MaxIPFailures
+	}
+	return limit > 0 && failures > limit
+}
+
+// delay возвращает паузу перед следующей попыткой после failures неудач подряд.
+func (t *LoginThrottle) delay(scope model.LoginAttemptScope, failures int) time.Duration {
+	if scope == model.LoginAttemptScopeIP {
+		if t.policy.MaxIPFailures > 0 && failures >= t.policy.MaxIPFailures {
+			return t.policy.Lockout
+		}
+		return 0
+	}
+
+	if t.policy.MaxFailures > 0 && failures >= t.policy.MaxFailures {
+		return t.policy.Lockout
+	}
+	// Первая ошибка (опечатка) не замедляет вход.
+	if failures < 2 || t.policy.BaseDelay <= 0 {
+		return 0
+	}
+	delay := t.policy.BaseDelay
+	for i := 2; i < failures && delay < t.policy.Lockout; i++ {
+		delay *= 2
+	}
+	if delay > t.policy.Lockout {
+		delay = t.policy.Lockout
+	}
+	return delay
+}
+
+type throttleKey struct {
+	scope   model.LoginAttemptScope
+	subject string
+}
+
+func (t *LoginThrottle) keys(login, ip string) []throttleKey {
+	keys := []throttleKey{{scope: model.LoginAttemptScopeLogin, subject: login}}
+	if ip != "" {
+		keys = append(keys, throttleKey{scope: model.LoginAttemptScopeIP, subject: ip})
+	}
+	return keys
+}
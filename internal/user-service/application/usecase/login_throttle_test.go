@@ -0,0 +1,316 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+)
+
+var testThrottlePolicy = LoginThrottlePolicy{
+	MaxFailures:   5,
+	MaxIPFailures: 20,
+	Lockout:       15 * time.Minute,
+	BaseDelay:     time.Second,
+}
+
+func newTestThrottle(repo repository.LoginAttemptRepository, now time.Time) *LoginThrottle {
+	throttle := NewLoginThrottle(repo, testThrottlePolicy)
+	throttle.now = func() time.Time { return now }
+	return throttle
+}
+
+func TestLoginThrottle_Delay(t *testing.T) {
+	throttle := NewLoginThrottle(nil, testThrottlePolicy)
+
+	tests := []struct {
+		scope    model.LoginAttemptScope
+		failures int
+		want     time.Duration
+	}{
+		{model.LoginAttemptScopeLogin, 1, 0},
+		{model.LoginAttemptScopeLogin, 2, time.Second},
+		{model.LoginAttemptScopeLogin, 3, 2 * time.Second},
+		{model.LoginAttemptScopeLogin, 4, 4 * time.Second},
+		{model.LoginAttemptScopeLogin, 5, 15 * time.Minute},
+		{model.LoginAttemptScopeIP, 19, 0},
+		{model.LoginAttemptScopeIP, 20, 15 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, throttle.delay(tt.scope, tt.failures), "%s after %d failures", tt.scope, tt.failures)
+	}
+
+	disabled := NewLoginThrottle(nil, LoginThrottlePolicy{Lockout: time.Minute})
+	assert.Zero(t, disabled.delay(model.LoginAttemptScopeLogin, 100))
+	assert.Zero(t, disabled.delay(model.LoginAttemptScopeIP, 100))
+}
+
+func TestLoginThrottle_Acquire(t *testing.T) {
+	now := time.Now()
+	resetBefore := now.Add(-testThrottlePolicy.Lockout)
+	lockedUntil := now.Add(10 * time.Minute)
+
+	t.Run("locked_ip_blocks_any_login", func(t *testing.T) {
+		repo := new(MockLoginAttemptRepository)
+		repo.On("RecordAttempt", mock.Anything, model.LoginAttemptScopeLogin, "user", now, resetBefore).
+			Return(&model.LoginAttempt{Scope: model.LoginAttemptScopeLogin, Subject: "user", Failures: 1}, nil)
+		repo.On("RecordAttempt", mock.Anything, model.LoginAttemptScopeIP, "10.0.0.1", now, resetBefore).
+			Return(&model.LoginAttempt{Failures: 20, LockedUntil: &lockedUntil}, nil)
+		repo.On("Release", mock.Anything, model.LoginAttemptScopeLogin, "user").Return(nil)
+
+		try, err := newTestThrottle(repo, now).Acquire(context.Background(), "user", "10.0.0.1")
+
+		var lockedErr *domainerrors.LoginLockedError
+		require.ErrorAs(t, err, &lockedErr)
+		assert.ErrorIs(t, err, domainerrors.ErrTooManyLoginAttempts)
+		assert.Equal(t, 10*time.Minute, lockedErr.RetryAfter)
+		assert.Nil(t, try)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("attempt_over_threshold_locks", func(t *testing.T) {
+		repo := new(MockLoginAttemptRepository)
+		repo.On("RecordAttempt", mock.Anything, model.LoginAttemptScopeLogin, "user", now, resetBefore).
+			Return(&model.LoginAttempt{Scope: model.LoginAttemptScopeLogin, Subject: "user", Failures: 6}, nil)
+		repo.On("Lock", mock.Anything, model.LoginAttemptScopeLogin, "user", now.Add(15*time.Minute)).Return(nil)
+
+		_, err := newTestThrottle(repo, now).Acquire(context.Background(), "user", "")
+
+		var lockedErr *domainerrors.LoginLockedError
+		require.ErrorAs(t, err, &lockedErr)
+		assert.Equal(t, 15*time.Minute, lockedErr.RetryAfter)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("attempt_at_threshold_is_allowed", func(t *testing.T) {
+		repo := new(MockLoginAttemptRepository)
+		repo.On("RecordAttempt", mock.Anything, model.LoginAttemptScopeLogin, "user", now, resetBefore).
+			Return(&model.LoginAttempt{Scope: model.LoginAttemptScopeLogin, Subject: "user", Failures: 5}, nil)
+
+		try, err := newTestThrottle(repo, now).Acquire(context.Background(), "user", "")
+
+		require.NoError(t, err)
+		assert.NotNil(t, try)
+		repo.AssertExpectations(t)
+	})
+}
+
+// memoryLoginAttempts — потокобезопасное хранилище счётчиков в памяти для проверки гонок.
+type memoryLoginAttempts struct {
+	mu       sync.Mutex
+	attempts map[model.LoginAttemptScope]map[string]*model.LoginAttempt
+}
+
+func newMemoryLoginAttempts() *memoryLoginAttempts {
+	return &memoryLoginAttempts{attempts: make(map[model.LoginAttemptScope]map[string]*model.LoginAttempt)}
+}
+
+func (m *memoryLoginAttempts) get(scope model.LoginAttemptScope, subject string) *model.LoginAttempt {
+	if m.attempts[scope] == nil {
+		m.attempts[scope] = make(map[string]*model.LoginAttempt)
+	}
+	if m.attempts[scope][subject] == nil {
+		m.attempts[scope][subject] = &model.LoginAttempt{Scope: scope, Subject: subject}
+	}
+	return m.attempts[scope][subject]
+}
+
+func (m *memoryLoginAttempts) Find(_ context.Context, scope model.LoginAttemptScope, subject string) (*model.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	attempt := *m.get(scope, subject)
+	return &attempt, nil
+}
+
+func (m *memoryLoginAttempts) RecordAttempt(_ context.Context, scope model.LoginAttemptScope, subject string, now, _ time.Time) (*model.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	attempt := m.get(scope, subject)
+	if attempt.RetryAfter(now) == 0 {
+		attempt.Failures++
+		attempt.LastFailureAt = now
+	}
+	result := *attempt
+	return &result, nil
+}
+
+func (m *memoryLoginAttempts) Release(_ context.Context, scope model.LoginAttemptScope, subject string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if attempt := m.get(scope, subject); attempt.Failures > 0 {
+		attempt.Failures--
+	}
+	return nil
+}
+
+func (m *memoryLoginAttempts) Lock(_ context.Context, scope model.LoginAttemptScope, subject string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	attempt := m.get(scope, subject)
+	if attempt.LockedUntil == nil || until.After(*attempt.LockedUntil) {
+		attempt.LockedUntil = &until
+	}
+	return nil
+}
+
+func (m *memoryLoginAttempts) Reset(_ context.Context, scope model.LoginAttemptScope, subject string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.attempts[scope], subject)
+	return nil
+}
+
+func TestLoginUseCase_Throttle(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	user := &model.User{ID: 1, Login: "testuser", PasswordHash: string(hashedPassword)}
+	now := time.Now()
+	resetBefore := now.Add(-testThrottlePolicy.Lockout)
+	loginAttempt := func(failures int) *model.LoginAttempt {
+		return &model.LoginAttempt{Scope: model.LoginAttemptScopeLogin, Subject: "testuser", Failures: failures}
+	}
+	ipAttempt := func(failures int) *model.LoginAttempt {
+		return &model.LoginAttempt{Scope: model.LoginAttemptScopeIP, Subject: "10.0.0.1", Failures: failures}
+	}
+
+	newUseCase := func(userRepo *MockUserRepository, attempts repository.LoginAttemptRepository, jwtService *MockJWTService) *LoginUseCase {
+		refreshTokenRepo := new(MockRefreshTokenRepository)
+		refreshTokenRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+		return NewLoginUseCase(userRepo, NewTokenIssuer(jwtService, refreshTokenRepo, time.Hour), newTestThrottle(attempts, now), nil)
+	}
+
+	t.Run("wrong_password_locks_account_at_threshold", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		attempts := new(MockLoginAttemptRepository)
+		userRepo.On("FindByLogin", mock.Anything, "testuser").Return(user, nil)
+		attempts.On("RecordAttempt", mock.Anything, model.LoginAttemptScopeLogin, "testuser", now, resetBefore).
+			Return(loginAttempt(5), nil)
+		attempts.On("RecordAttempt", mock.Anything, model.LoginAttemptScopeIP, "10.0.0.1", now, resetBefore).
+			Return(ipAttempt(1), nil)
+		attempts.On("Lock", mock.Anything, model.LoginAttemptScopeLogin, "testuser", now.Add(15*time.Minute)).Return(nil)
+
+		_, err := newUseCase(userRepo, attempts, new(MockJWTService)).Execute(context.Background(), LoginRequest{
+			Login: "testuser", Password: "wrong", ClientIP: "10.0.0.1",
+		})
+
+		assert.ErrorIs(t, err, domainerrors.ErrInvalidCredentials)
+		attempts.AssertExpectations(t)
+	})
+
+	t.Run("unknown_login_is_counted_like_existing_one", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		attempts := new(MockLoginAttemptRepository)
+		userRepo.On("FindByLogin", mock.Anything, "ghost").Return(nil, domainerrors.ErrUserNotFound)
+		attempts.On("RecordAttempt", mock.Anything, model.LoginAttemptScopeLogin, "ghost", now, resetBefore).
+			Return(&model.LoginAttempt{Scope: model.LoginAttemptScopeLogin, Subject: "ghost", Failures: 1}, nil)
+
+		_, err := newUseCase(userRepo, attempts, new(MockJWTService)).Execute(context.Background(), LoginRequest{
+			Login: "ghost", Password: "password123",
+		})
+
+		assert.ErrorIs(t, err, domainerrors.ErrInvalidCredentials)
+		attempts.AssertExpectations(t)
+		attempts.AssertNotCalled(t, "Release", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("locked_account_is_rejected_before_password_check", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		attempts := new(MockLoginAttemptRepository)
+		lockedUntil := now.Add(90 * time.Second)
+		locked := loginAttempt(5)
+		locked.LockedUntil = &lockedUntil
+		attempts.On("RecordAttempt", mock.Anything, model.LoginAttemptScopeLogin, "testuser", now, resetBefore).
+			Return(locked, nil)
+
+		_, err := newUseCase(userRepo, attempts, new(MockJWTService)).Execute(context.Background(), LoginRequest{
+			Login: "testuser", Password: "password123",
+		})
+
+		var lockedErr *domainerrors.LoginLockedError
+		require.ErrorAs(t, err, &lockedErr)
+		assert.Equal(t, 90*time.Second, lockedErr.RetryAfter)
+		userRepo.AssertNotCalled(t, "FindByLogin", mock.Anything, mock.Anything)
+	})
+
+	t.Run("successful_login_resets_login_counter", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		attempts := new(MockLoginAttemptRepository)
+		jwtService := new(MockJWTService)
+		userRepo.On("FindByLogin", mock.Anything, "testuser").Return(user, nil)
+		attempts.On("RecordAttempt", mock.Anything, model.LoginAttemptScopeLogin, "testuser", now, resetBefore).
+			Return(loginAttempt(1), nil)
+		attempts.On("RecordAttempt", mock.Anything, model.LoginAttemptScopeIP, "10.0.0.1", now, resetBefore).
+			Return(ipAttempt(3), nil)
+		attempts.On("Reset", mock.Anything, model.LoginAttemptScopeLogin, "testuser").Return(nil)
+		attempts.On("Release", mock.Anything, model.LoginAttemptScopeIP, "10.0.0.1").Return(nil)
+		jwtService.On("GenerateToken", int64(1), "testuser", mock.Anything).Return("token", nil)
+
+		resp, err := newUseCase(userRepo, attempts, jwtService).Execute(context.Background(), LoginRequest{
+			Login: "testuser", Password: "password123", ClientIP: "10.0.0.1",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "token", resp.Token)
+		attempts.AssertExpectations(t)
+		attempts.AssertNotCalled(t, "Reset", mock.Anything, model.LoginAttemptScopeIP, mock.Anything)
+	})
+
+	t.Run("parallel_wrong_passwords_stop_at_threshold", func(t *testing.T) {
+		const parallel = 50
+		userRepo := new(MockUserRepository)
+		userRepo.On("FindByLogin", mock.Anything, "testuser").Return(user, nil)
+		attempts := newMemoryLoginAttempts()
+		uc := newUseCase(userRepo, attempts, new(MockJWTService))
+
+		var (
+			wg      sync.WaitGroup
+			checked atomic.Int32
+			locked  atomic.Int32
+			start   = make(chan struct{})
+		)
+		for i := 0; i < parallel; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				_, err := uc.Execute(context.Background(), LoginRequest{Login: "testuser", Password: "wrong", ClientIP: "10.0.0.1"})
+				switch {
+				case domainerrors.Is(err, domainerrors.ErrInvalidCredentials):
+					checked.Add(1)
+				case domainerrors.Is(err, domainerrors.ErrTooManyLoginAttempts):
+					locked.Add(1)
+				}
+			}()
+		}
+		close(start)
+		wg.Wait()
+
+		assert.Positive(t, checked.Load())
+		assert.LessOrEqual(t, int(checked.Load()), testThrottlePolicy.MaxFailures, "only attempts within the limit reach bcrypt")
+		assert.Equal(t, int32(parallel), checked.Load()+locked.Load())
+	})
+}
+
+func TestUnlockLoginUseCase_Execute(t *testing.T) {
+	attempts := new(MockLoginAttemptRepository)
+	attempts.On("Reset", mock.Anything, model.LoginAttemptScopeLogin, "testuser").Return(nil)
+	attempts.On("Reset", mock.Anything, model.LoginAttemptScopeIP, "10.0.0.1").Return(nil)
+	uc := NewUnlockLoginUseCase(attempts)
+
+	err := uc.Execute(context.Background(), UnlockLoginRequest{Login: "testuser", IP: "10.0.0.1"})
+	require.NoError(t, err)
+	attempts.AssertExpectations(t)
+
+	err = uc.Execute(context.Background(), UnlockLoginRequest{})
+	assert.ErrorIs(t, err, domainerrors.ErrUnlockTargetRequired)
+}
@@ -5,29 +5,44 @@ import (
 
 	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
-	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type LoginUseCase struct {
-	userRepo   repository.UserRepository
-	jwtService service.JWTService
+	userRepo    repository.UserRepository
+	tokenIssuer *TokenIssuer
+	throttle    *LoginThrottle
+	challenges  *TwoFactorChallenges
 }
 
-func NewLoginUseCase(userRepo repository.UserRepository, jwtService service.JWTService) *LoginUseCase {
+// NewLoginUseCase создаёт сценарий входа; throttle == nil отключает защиту от подбора пароля,
+// challenges == nil — второй фактор.
+func NewLoginUseCase(
+	userRepo repository.UserRepository,
+	tokenIssuer *TokenIssuer,
+	throttle *LoginThrottle,
+	challenges *TwoFactorChallenges,
+) *LoginUseCase {
 	return &LoginUseCase{
-		userRepo:   userRepo,
-		jwtService: jwtService,
+		userRepo:    userRepo,
+		tokenIssuer: tokenIssuer,
+		throttle:    throttle,
+		challenges:  challenges,
 	}
 }
 
 type LoginRequest struct {
 	Login    string
 	Password string
+	ClientIP string
 }
 
+// LoginResponse содержит пару токенов либо, если у пользователя включена 2FA, только
+// ChallengeToken, который обменивается на токены вместе с кодом.
 type LoginResponse struct {
-	Token string
+	Token          string
+	RefreshToken   string
+	ChallengeToken string
 }
 
 func (uc *LoginUseCase) Execute(ctx context.Context, req LoginRequest) (*LoginResponse, error) {
@@ -35,23 +50,72 @@ func (uc *LoginUseCase) Execute(ctx context.Context, req LoginRequest) (*LoginRe
 		return nil, errors.ErrLoginRequired
 	}
 
+	var try *LoginTry
+	if uc.throttle != nil {
+		var err error
+		if try, err = uc.throttle.Acquire(ctx, req.Login, req.ClientIP); err != nil {
+			return nil, err
+		}
+	}
+
 	user, err := uc.userRepo.FindByLogin(ctx, req.Login)
 	if err != nil {
 		if errors.Is(err, errors.ErrUserNotFound) {
-			return nil, errors.ErrInvalidCredentials
+			return nil, uc.failed(ctx, try)
 		}
-		return nil, err
+		return nil, uc.release(ctx, try, err)
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password))
 	if err != nil {
-		return nil, errors.ErrInvalidCredentials
+		return nil, uc.failed(ctx, try)
+	}
+
+	if uc.challenges != nil {
+		challenge, err := uc.challenges.Issue(ctx, user.ID)
+		if err != nil {
+			return nil, uc.release(ctx, try, err)
+		}
+		if challenge != "" {
+			// Счётчик логина сбрасывается только после кода, но верный пароль неудачей не считается.
+			if err := uc.release(ctx, try, nil); err != nil {
+				return nil, err
+			}
+			return &LoginResponse{ChallengeToken: challenge}, nil
+		}
 	}
 
-	token, err := uc.jwtService.GenerateToken(user.ID, user.Login)
+	if try != nil {
+		if err := uc.throttle.RecordSuccess(ctx, try); err != nil {
+			return nil, err
+		}
+	}
+
+	pair, err := uc.tokenIssuer.Issue(ctx, user)
 	if err != nil {
 		return nil, err
 	}
 
-	return &LoginResponse{Token: token}, nil
+	return &LoginResponse{Token: pair.AccessToken, RefreshToken: pair.RefreshToken}, nil
+}
+
+// failed учитывает неудачную попытку. Попытки для несуществующих логинов считаются так же,
+// как для существующих, чтобы по блокировке нельзя было узнать, зарегистрирован ли логин.
+func (uc *LoginUseCase) failed(ctx context.Context, try *LoginTry) error {
+	if try != nil {
+		if err := uc.throttle.RecordFailure(ctx, try); err != nil {
+			return err
+		}
+	}
+	return errors.ErrInvalidCredentials
+}
+
+// release снимает попытку, прерванную не из-за неверного пароля, и возвращает err.
+func (uc *LoginUseCase) release(ctx context.Context, try *LoginTry, err error) error {
+	if try != nil {
+		if releaseErr := uc.throttle.Release(ctx, try); releaseErr != nil && err == nil {
+			return releaseErr
+		}
+	}
+	return err
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -37,7 +38,7 @@ func TestLoginUseCase_Execute(t *testing.T) {
 					PasswordHash: string(hashedPassword),
 				}
 				userRepo.On("FindByLogin", mock.Anything, "testuser").Return(user, nil)
-				jwtService.On("GenerateToken", int64(1), "testuser").Return("test-token", nil)
+				jwtService.On("GenerateToken", int64(1), "testuser", mock.Anything).Return("test-token", nil)
 			},
 			want:    &LoginResponse{Token: "test-token"},
 			wantErr: false,
@@ -122,7 +123,7 @@ func TestLoginUseCase_Execute(t *testing.T) {
 					PasswordHash: string(hashedPassword),
 				}
 				userRepo.On("FindByLogin", mock.Anything, "testuser").Return(user, nil)
-				jwtService.On("GenerateToken", int64(1), "testuser").Return("", errors.New("jwt error"))
+				jwtService.On("GenerateToken", int64(1), "testuser", mock.Anything).Return("", errors.New("jwt error"))
 			},
 			want:    nil,
 			wantErr: true,
@@ -133,9 +134,11 @@ func TestLoginUseCase_Execute(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			userRepo := new(MockUserRepository)
 			jwtService := new(MockJWTService)
+			refreshTokenRepo := new(MockRefreshTokenRepository)
+			refreshTokenRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
 			tt.setupMocks(userRepo, jwtService)
 
-			uc := NewLoginUseCase(userRepo, jwtService)
+			uc := NewLoginUseCase(userRepo, NewTokenIssuer(jwtService, refreshTokenRepo, time.Hour), nil, nil)
 			got, err := uc.Execute(context.Background(), tt.req)
 
 			if tt.wantErr {
@@ -146,7 +149,8 @@ func TestLoginUseCase_Execute(t *testing.T) {
 				}
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.want, got)
+				assert.Equal(t, tt.want.Token, got.Token)
+				assert.NotEmpty(t, got.RefreshToken)
 			}
 
 			userRepo.AssertExpectations(t)
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+)
+
+type LogoutUseCase struct {
+	jwtService       service.JWTService
+	revokedTokenRepo repository.RevokedTokenRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	now              func() time.Time
+}
+
+func NewLogoutUseCase(
+	jwtService service.JWTService,
+	revokedTokenRepo repository.RevokedTokenRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+) *LogoutUseCase {
+	return &LogoutUseCase{
+		jwtService:       jwtService,
+		revokedTokenRepo: revokedTokenRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		now:              time.Now,
+	}
+}
+
+type LogoutRequest struct {
+	AccessToken  string
+	RefreshToken string
+	// AllSessions отзывает все токены пользователя, а не только предъявленные.
+	AllSessions bool
+}
+
+func (uc *LogoutUseCase) Execute(ctx context.Context, req LogoutRequest) error {
+	if req.AccessToken == "" {
+		return errors.ErrTokenRequired
+	}
+
+	claims, err := uc.jwtService.ValidateToken(req.AccessToken)
+	if err != nil {
+		return errors.ErrInvalidToken
+	}
+
+	if req.AllSessions {
+		return revokeAllSessions(ctx, uc.revokedTokenRepo, uc.refreshTokenRepo, claims.UserID, uc.now())
+	}
+
+	// Токены, выпущенные до появления jti, отозвать по отдельности нельзя:
+	// они истекут через JWT_EXPIRY.
+	if claims.ID != "" && claims.ExpiresAt != nil {
+		if err := uc.revokedTokenRepo.Revoke(ctx, claims.ID, claims.UserID, claims.ExpiresAt.Time); err != nil {
+			return err
+		}
+	}
+
+	if req.RefreshToken == "" {
+		return nil
+	}
+	token, err := uc.refreshTokenRepo.FindByHash(ctx, HashToken(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, errors.ErrInvalidRefreshToken) {
+			return nil
+		}
+		return err
+	}
+	if token.UserID != claims.UserID {
+		return errors.ErrInvalidRefreshToken
+	}
+	return uc.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID)
+}
+
+// revokeAllSessions отзывает все access- и refresh-токены пользователя, выпущенные до now.
+func revokeAllSessions(
+	ctx context.Context,
+	revokedTokenRepo repository.RevokedTokenRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	userID int64,
+	now time.Time,
+) error {
+	// iat хранится с точностью model.TokenTimePrecision, поэтому граница округляется вниз до неё:
+	// иначе токен, выпущенный сразу после отзыва, например при смене пароля, оказался бы отозван.
+	if err := revokedTokenRepo.RevokeAllForUser(ctx, userID, now.Truncate(model.TokenTimePrecision)); err != nil {
+		return err
+	}
+	return refreshTokenRepo.RevokeAllForUser(ctx, userID)
+}
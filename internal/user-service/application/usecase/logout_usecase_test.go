@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+)
+
+func TestLogoutUseCase_Execute(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 30, 500_000_700, time.UTC)
+	expiresAt := now.Add(20 * time.Minute)
+	claims := &model.Claims{
+		UserID: 1,
+		Login:  "testuser",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti-1",
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	tests := []struct {
+		name       string
+		req        LogoutRequest
+		setupMocks func(*MockJWTService, *MockRevokedTokenRepository, *MockRefreshTokenRepository)
+		wantErr    error
+	}{
+		{
+			name: "revokes access token until expiry",
+			req:  LogoutRequest{AccessToken: "access"},
+			setupMocks: func(jwtService *MockJWTService, revokedRepo *MockRevokedTokenRepository, _ *MockRefreshTokenRepository) {
+				jwtService.On("ValidateToken", "access").Return(claims, nil)
+				revokedRepo.On("Revoke", mock.Anything, "jti-1", int64(1), expiresAt.Truncate(time.Second)).Return(nil)
+			},
+		},
+		{
+			name: "revokes refresh token family",
+			req:  LogoutRequest{AccessToken: "access", RefreshToken: "refresh"},
+			setupMocks: func(jwtService *MockJWTService, revokedRepo *MockRevokedTokenRepository, refreshRepo *MockRefreshTokenRepository) {
+				jwtService.On("ValidateToken", "access").Return(claims, nil)
+				revokedRepo.On("Revoke", mock.Anything, "jti-1", int64(1), mock.Anything).Return(nil)
+				refreshRepo.On("FindByHash", mock.Anything, HashToken("refresh")).Return(&model.RefreshToken{UserID: 1, FamilyID: "family"}, nil)
+				refreshRepo.On("RevokeFamily", mock.Anything, "family").Return(nil)
+			},
+		},
+		{
+			name: "refresh token of another user",
+			req:  LogoutRequest{AccessToken: "access", RefreshToken: "refresh"},
+			setupMocks: func(jwtService *MockJWTService, revokedRepo *MockRevokedTokenRepository, refreshRepo *MockRefreshTokenRepository) {
+				jwtService.On("ValidateToken", "access").Return(claims, nil)
+				revokedRepo.On("Revoke", mock.Anything, "jti-1", int64(1), mock.Anything).Return(nil)
+				refreshRepo.On("FindByHash", mock.Anything, mock.Anything).Return(&model.RefreshToken{UserID: 2, FamilyID: "family"}, nil)
+			},
+			wantErr: domainerrors.ErrInvalidRefreshToken,
+		},
+		{
+			name: "all sessions",
+			req:  LogoutRequest{AccessToken: "access", AllSessions: true},
+			setupMocks: func(jwtService *MockJWTService, revokedRepo *MockRevokedTokenRepository, refreshRepo *MockRefreshTokenRepository) {
+				jwtService.On("ValidateToken", "access").Return(claims, nil)
+				revokedRepo.On("RevokeAllForUser", mock.Anything, int64(1), now.Truncate(model.TokenTimePrecision)).Return(nil)
+				refreshRepo.On("RevokeAllForUser", mock.Anything, int64(1)).Return(nil)
+			},
+		},
+		{
+			name:       "missing token",
+			req:        LogoutRequest{},
+			setupMocks: func(*MockJWTService, *MockRevokedTokenRepository, *MockRefreshTokenRepository) {},
+			wantErr:    domainerrors.ErrTokenRequired,
+		},
+		{
+			name: "invalid token",
+			req:  LogoutRequest{AccessToken: "bad"},
+			setupMocks: func(jwtService *MockJWTService, _ *MockRevokedTokenRepository, _ *MockRefreshTokenRepository) {
+				jwtService.On("ValidateToken", "bad").Return(nil, errors.New("signature is invalid"))
+			},
+			wantErr: domainerrors.ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jwtService := new(MockJWTService)
+			revokedRepo := new(MockRevokedTokenRepository)
+			refreshRepo := new(MockRefreshTokenRepository)
+			tt.setupMocks(jwtService, revokedRepo, refreshRepo)
+
+			uc := NewLogoutUseCase(jwtService, revokedRepo, refreshRepo)
+			uc.now = func() time.Time { return now }
+
+			err := uc.Execute(context.Background(), tt.req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			jwtService.AssertExpectations(t)
+			revokedRepo.AssertExpectations(t)
+			refreshRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 
@@ -25,17 +26,45 @@ func (m *MockUserRepository) FindByLogin(ctx context.Context, login string) (*mo
 	return args.Get(0).(*model.User), args.Error(1)
 }
 
+func (m *MockUserRepository) FindByID(ctx context.Context, id int64) (*model.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
 func (m *MockUserRepository) ExistsByLogin(ctx context.Context, login string) (bool, error) {
 	args := m.Called(ctx, login)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, userID int64, passwordHash string) error {
+	args := m.Called(ctx, userID, passwordHash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, user *model.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Anonymize(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) AddRole(ctx context.Context, userID int64, role model.Role) error {
+	args := m.Called(ctx, userID, role)
+	return args.Error(0)
+}
+
 type MockJWTService struct {
 	mock.Mock
 }
 
-func (m *MockJWTService) GenerateToken(userID int64, login string) (string, error) {
-	args := m.Called(userID, login)
+func (m *MockJWTService) GenerateToken(userID int64, login string, roles []model.Role) (string, error) {
+	args := m.Called(userID, login, roles)
 	return args.String(0), args.Error(1)
 }
 
@@ -46,3 +75,179 @@ func (m *MockJWTService) ValidateToken(tokenString string) (*model.Claims, error
 	}
 	return args.Get(0).(*model.Claims), args.Error(1)
 }
+
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Create(ctx context.Context, token *model.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) MarkRotated(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	args := m.Called(ctx, familyID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+type MockRevokedTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRevokedTokenRepository) Revoke(ctx context.Context, jti string, userID int64, expiresAt time.Time) error {
+	args := m.Called(ctx, jti, userID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockRevokedTokenRepository) RevokeAllForUser(ctx context.Context, userID int64, before time.Time) error {
+	args := m.Called(ctx, userID, before)
+	return args.Error(0)
+}
+
+func (m *MockRevokedTokenRepository) IsRevoked(ctx context.Context, jti string, userID int64, issuedAt time.Time) (bool, error) {
+	args := m.Called(ctx, jti, userID, issuedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockLoginAttemptRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginAttemptRepository) Find(ctx context.Context, scope model.LoginAttemptScope, subject string) (*model.LoginAttempt, error) {
+	args := m.Called(ctx, scope, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.LoginAttempt), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) RecordAttempt(ctx context.Context, scope model.LoginAttemptScope, subject string, now, resetBefore time.Time) (*model.LoginAttempt, error) {
+	args := m.Called(ctx, scope, subject, now, resetBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.LoginAttempt), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) Release(ctx context.Context, scope model.LoginAttemptScope, subject string) error {
+	args := m.Called(ctx, scope, subject)
+	return args.Error(0)
+}
+
+func (m *MockLoginAttemptRepository) Lock(ctx context.Context, scope model.LoginAttemptScope, subject string, until time.Time) error {
+	args := m.Called(ctx, scope, subject, until)
+	return args.Error(0)
+}
+
+func (m *MockLoginAttemptRepository) Reset(ctx context.Context, scope model.LoginAttemptScope, subject string) error {
+	args := m.Called(ctx, scope, subject)
+	return args.Error(0)
+}
+
+type MockPasswordResetTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetTokenRepository) Create(ctx context.Context, token *model.PasswordResetToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*model.PasswordResetToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetTokenRepository) Redeem(ctx context.Context, id int64, passwordHash string, now time.Time) (bool, error) {
+	args := m.Called(ctx, id, passwordHash, now)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockPasswordResetNotifier struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetNotifier) SendPasswordReset(ctx context.Context, user *model.User, token string, expiresAt time.Time) error {
+	args := m.Called(ctx, user, token, expiresAt)
+	return args.Error(0)
+}
+
+type MockTwoFactorRepository struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorRepository) Find(ctx context.Context, userID int64) (*model.TwoFactor, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TwoFactor), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) SetPendingSecret(ctx context.Context, userID int64, secret []byte) error {
+	args := m.Called(ctx, userID, secret)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) Enable(ctx context.Context, userID int64, secret []byte, recoveryCodeHashes []string, step int64) error {
+	args := m.Called(ctx, userID, secret, recoveryCodeHashes, step)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) UseStep(ctx context.Context, userID int64, step int64) (bool, error) {
+	args := m.Called(ctx, userID, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error) {
+	args := m.Called(ctx, userID, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockTwoFactorChallengeRepository struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorChallengeRepository) Create(ctx context.Context, challenge *model.TwoFactorChallenge) error {
+	args := m.Called(ctx, challenge)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorChallengeRepository) FindByHash(ctx context.Context, tokenHash string) (*model.TwoFactorChallenge, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TwoFactorChallenge), args.Error(1)
+}
+
+func (m *MockTwoFactorChallengeRepository) RecordFailure(ctx context.Context, id int64) (int, error) {
+	args := m.Called(ctx, id)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTwoFactorChallengeRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+)
+
+func TestRequestPasswordResetUseCase_Execute(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	user := &model.User{ID: 1, Login: "testuser"}
+
+	t.Run("issues token and notifies user", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		resetRepo := new(MockPasswordResetTokenRepository)
+		notifier := new(MockPasswordResetNotifier)
+		userRepo.On("FindByLogin", mock.Anything, "testuser").Return(user, nil)
+
+		var stored *model.PasswordResetToken
+		resetRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*model.PasswordResetToken)
+		}).Return(nil)
+		var sent string
+		notifier.On("SendPasswordReset", mock.Anything, user, mock.Anything, now.Add(30*time.Minute)).Run(func(args mock.Arguments) {
+			sent = args.String(2)
+		}).Return(nil)
+
+		uc := NewRequestPasswordResetUseCase(userRepo, resetRepo, notifier, 30*time.Minute)
+		uc.now = func() time.Time { return now }
+
+		err := uc.Execute(context.Background(), RequestPasswordResetRequest{Login: "testuser"})
+
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+		assert.NotEmpty(t, sent)
+		assert.Equal(t, HashToken(sent), stored.TokenHash, "only the hash of the token is stored")
+		assert.Equal(t, int64(1), stored.UserID)
+	})
+
+	t.Run("unknown login is not revealed", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		resetRepo := new(MockPasswordResetTokenRepository)
+		notifier := new(MockPasswordResetNotifier)
+		userRepo.On("FindByLogin", mock.Anything, "ghost").Return(nil, domainerrors.ErrUserNotFound)
+
+		err := NewRequestPasswordResetUseCase(userRepo, resetRepo, notifier, time.Minute).
+			Execute(context.Background(), RequestPasswordResetRequest{Login: "ghost"})
+
+		assert.NoError(t, err)
+		notifier.AssertNotCalled(t, "SendPasswordReset", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestConfirmPasswordResetUseCase_Execute(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	user := &model.User{ID: 1, Login: "testuser"}
+	validToken := &model.PasswordResetToken{ID: 7, UserID: 1, ExpiresAt: now.Add(time.Minute)}
+	usedAt := now.Add(-time.Second)
+	policy := service.PasswordPolicy{MinLength: 8, MinClasses: 2, RejectCommon: true}
+	errDatabase := errors.New("db error")
+
+	tests := []struct {
+		name       string
+		password   string
+		setupMocks func(*MockUserRepository, *MockPasswordResetTokenRepository, *MockRevokedTokenRepository, *MockRefreshTokenRepository)
+		wantErr    error
+	}{
+		{
+			name:     "sets password and ends all sessions",
+			password: "New-password-2",
+			setupMocks: func(userRepo *MockUserRepository, resetRepo *MockPasswordResetTokenRepository, revokedRepo *MockRevokedTokenRepository, refreshRepo *MockRefreshTokenRepository) {
+				resetRepo.On("FindByHash", mock.Anything, HashToken("reset")).Return(validToken, nil)
+				userRepo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+				resetRepo.On("Redeem", mock.Anything, int64(7), mock.MatchedBy(func(hash string) bool {
+					return bcrypt.CompareHashAndPassword([]byte(hash), []byte("New-password-2")) == nil
+				}), now).Return(true, nil)
+				revokedRepo.On("RevokeAllForUser", mock.Anything, int64(1), now).Return(nil)
+				refreshRepo.On("RevokeAllForUser", mock.Anything, int64(1)).Return(nil)
+			},
+		},
+		{
+			name:     "used token",
+			password: "New-password-2",
+			setupMocks: func(_ *MockUserRepository, resetRepo *MockPasswordResetTokenRepository, _ *MockRevokedTokenRepository, _ *MockRefreshTokenRepository) {
+				resetRepo.On("FindByHash", mock.Anything, mock.Anything).Return(&model.PasswordResetToken{ID: 7, UserID: 1, ExpiresAt: now.Add(time.Minute), UsedAt: &usedAt}, nil)
+			},
+			wantErr: domainerrors.ErrInvalidResetToken,
+		},
+		{
+			name:     "expired token",
+			password: "New-password-2",
+			setupMocks: func(_ *MockUserRepository, resetRepo *MockPasswordResetTokenRepository, _ *MockRevokedTokenRepository, _ *MockRefreshTokenRepository) {
+				resetRepo.On("FindByHash", mock.Anything, mock.Anything).Return(&model.PasswordResetToken{ID: 7, UserID: 1, ExpiresAt: now}, nil)
+			},
+			wantErr: domainerrors.ErrInvalidResetToken,
+		},
+		{
+			name:     "token consumed concurrently",
+			password: "New-password-2",
+			setupMocks: func(userRepo *MockUserRepository, resetRepo *MockPasswordResetTokenRepository, _ *MockRevokedTokenRepository, _ *MockRefreshTokenRepository) {
+				resetRepo.On("FindByHash", mock.Anything, mock.Anything).Return(validToken, nil)
+				userRepo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+				resetRepo.On("Redeem", mock.Anything, int64(7), mock.Anything, now).Return(false, nil)
+			},
+			wantErr: domainerrors.ErrInvalidResetToken,
+		},
+		{
+			name:     "weak password keeps token usable",
+			password: "12345678",
+			setupMocks: func(userRepo *MockUserRepository, resetRepo *MockPasswordResetTokenRepository, _ *MockRevokedTokenRepository, _ *MockRefreshTokenRepository) {
+				resetRepo.On("FindByHash", mock.Anything, mock.Anything).Return(validToken, nil)
+				userRepo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+			},
+			wantErr: domainerrors.ErrWeakPassword,
+		},
+		{
+			name:     "failed password update surfaces error",
+			password: "New-password-2",
+			setupMocks: func(userRepo *MockUserRepository, resetRepo *MockPasswordResetTokenRepository, _ *MockRevokedTokenRepository, _ *MockRefreshTokenRepository) {
+				resetRepo.On("FindByHash", mock.Anything, mock.Anything).Return(validToken, nil)
+				userRepo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+				resetRepo.On("Redeem", mock.Anything, int64(7), mock.Anything, now).Return(false, errDatabase)
+			},
+			wantErr: errDatabase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			resetRepo := new(MockPasswordResetTokenRepository)
+			revokedRepo := new(MockRevokedTokenRepository)
+			refreshRepo := new(MockRefreshTokenRepository)
+			tt.setupMocks(userRepo, resetRepo, revokedRepo, refreshRepo)
+
+			uc := NewConfirmPasswordResetUseCase(userRepo, resetRepo, revokedRepo, refreshRepo, policy)
+			uc.now = func() time.Time { return now }
+
+			err := uc.Execute(context.Background(), ConfirmPasswordResetRequest{Token: "reset", NewPassword: tt.password})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				revokedRepo.AssertNotCalled(t, "RevokeAllForUser", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+			userRepo.AssertExpectations(t)
+			resetRepo.AssertExpectations(t)
+			revokedRepo.AssertExpectations(t)
+			refreshRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+)
+
+const (
+	maxNameLength  = 100
+	maxEmailLength = 254
+)
+
+// Profile — данные профиля, которые пользователь видит и может менять.
+type Profile struct {
+	Login        string
+	FirstName    string
+	LastName     string
+	Email        string
+	RegisteredAt time.Time
+	// Balance равен nil, если баланс недоступен.
+	Balance *model.BalanceSummary
+}
+
+func newProfile(user *model.User) *Profile {
+	return &Profile{
+		Login:        user.Login,
+		FirstName:    user.FirstName,
+		LastName:     user.LastName,
+		Email:        user.Email,
+		RegisteredAt: user.CreatedAt,
+	}
+}
+
+// ProfileUpdate — изменяемые поля профиля; nil означает, что поле не меняется.
+type ProfileUpdate struct {
+	FirstName *string
+	LastName  *string
+	Email     *string
+}
+
+// apply проверяет изменения и применяет их к пользователю. Пустой email удаляет адрес.
+func (u ProfileUpdate) apply(user *model.User) error {
+	var violations []string
+
+	if u.FirstName != nil {
+		name, violation := normalizeName("first_name", *u.FirstName)
+		if violation != "" {
+			violations = append(violations, violation)
+		}
+		user.FirstName = name
+	}
+	if u.LastName != nil {
+		name, violation := normalizeName("last_name", *u.LastName)
+		if violation != "" {
+			violations = append(violations, violation)
+		}
+		user.LastName = name
+	}
+	if u.Email != nil {
+		email, violation := normalizeEmail(*u.Email)
+		if violation != "" {
+			violations = append(violations, violation)
+		}
+		user.Email = email
+	}
+
+	if len(violations) > 0 {
+		return &errors.ProfileValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func normalizeName(field, value string) (string, string) {
+	value = strings.TrimSpace(value)
+	if !utf8.ValidString(value) || utf8.RuneCountInString(value) > maxNameLength {
+		return value, fmt.Sprintf("%s must be at most %d characters long", field, maxNameLength)
+	}
+	if strings.ContainsFunc(value, func(r rune) bool { return r < ' ' }) {
+		return value, fmt.Sprintf("%s must not contain control characters", field)
+	}
+	return value, ""
+}
+
+func normalizeEmail(value string) (string, string) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return "", ""
+	}
+	if len(value) > maxEmailLength {
+		return value, fmt.Sprintf("email must be at most %d characters long", maxEmailLength)
+	}
+	address, err := mail.ParseAddress(value)
+	if err != nil || address.Address != value {
+		return value, "email is not a valid address"
+	}
+	return value, ""
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+)
+
+type MockBalanceProvider struct {
+	mock.Mock
+}
+
+func (m *MockBalanceProvider) GetBalanceSummary(ctx context.Context, userID int64) (*model.BalanceSummary, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BalanceSummary), args.Error(1)
+}
+
+func newProfileValidateToken() *ValidateTokenUseCase {
+	jwtService := new(MockJWTService)
+	revokedRepo := new(MockRevokedTokenRepository)
+	jwtService.On("ValidateToken", "access").Return(&model.Claims{UserID: 1, Login: "testuser"}, nil)
+	jwtService.On("ValidateToken", mock.Anything).Return(nil, errors.New("bad token"))
+	revokedRepo.On("IsRevoked", mock.Anything, mock.Anything, int64(1), mock.Anything).Return(false, nil)
+	return NewValidateTokenUseCase(jwtService, revokedRepo)
+}
+
+func TestGetProfileUseCase_Execute(t *testing.T) {
+	registeredAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	user := &model.User{ID: 1, Login: "testuser", FirstName: "Test", LastName: "User", Email: "test@example.com", CreatedAt: registeredAt}
+
+	t.Run("returns profile with balance", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		balances := new(MockBalanceProvider)
+		userRepo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+		balances.On("GetBalanceSummary", mock.Anything, int64(1)).Return(&model.BalanceSummary{Current: "500.50", Withdrawn: "42.00"}, nil)
+
+		profile, err := NewGetProfileUseCase(newProfileValidateToken(), userRepo, balances).
+			Execute(context.Background(), GetProfileRequest{AccessToken: "access"})
+
+		require.NoError(t, err)
+		assert.Equal(t, &Profile{
+			Login:        "testuser",
+			FirstName:    "Test",
+			LastName:     "User",
+			Email:        "test@example.com",
+			RegisteredAt: registeredAt,
+			Balance:      &model.BalanceSummary{Current: "500.50", Withdrawn: "42.00"},
+		}, profile)
+	})
+
+	t.Run("balance failure does not hide profile", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		balances := new(MockBalanceProvider)
+		userRepo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+		balances.On("GetBalanceSummary", mock.Anything, int64(1)).Return(nil, errors.New("db down"))
+
+		profile, err := NewGetProfileUseCase(newProfileValidateToken(), userRepo, balances).
+			Execute(context.Background(), GetProfileRequest{AccessToken: "access"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "testuser", profile.Login)
+		assert.Nil(t, profile.Balance)
+	})
+
+	t.Run("without balance provider", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		userRepo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+
+		profile, err := NewGetProfileUseCase(newProfileValidateToken(), userRepo, nil).
+			Execute(context.Background(), GetProfileRequest{AccessToken: "access"})
+
+		require.NoError(t, err)
+		assert.Nil(t, profile.Balance)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+
+		_, err := NewGetProfileUseCase(newProfileValidateToken(), userRepo, nil).
+			Execute(context.Background(), GetProfileRequest{AccessToken: "bad"})
+
+		assert.ErrorIs(t, err, domainerrors.ErrInvalidToken)
+		userRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateProfileUseCase_Execute(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name       string
+		update     ProfileUpdate
+		setupMocks func(*MockUserRepository)
+		want       *Profile
+		wantErr    error
+	}{
+		{
+			name:   "updates given fields only",
+			update: ProfileUpdate{FirstName: ptr("  Jane "), Email: ptr("Jane@Example.com")},
+			setupMocks: func(userRepo *MockUserRepository) {
+				userRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+					return u.FirstName == "Jane" && u.LastName == "User" && u.Email == "jane@example.com"
+				})).Return(nil)
+			},
+			want: &Profile{Login: "testuser", FirstName: "Jane", LastName: "User", Email: "jane@example.com"},
+		},
+		{
+			name:   "empty email removes address",
+			update: ProfileUpdate{Email: ptr("")},
+			setupMocks: func(userRepo *MockUserRepository) {
+				userRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+					return u.Email == ""
+				})).Return(nil)
+			},
+			want: &Profile{Login: "testuser", FirstName: "Test", LastName: "User"},
+		},
+		{
+			name:       "invalid email",
+			update:     ProfileUpdate{Email: ptr("Jane <jane@example.com>")},
+			setupMocks: func(*MockUserRepository) {},
+			wantErr:    domainerrors.ErrInvalidProfile,
+		},
+		{
+			name:       "name too long",
+			update:     ProfileUpdate{LastName: ptr(string(make([]byte, maxNameLength+1)))},
+			setupMocks: func(*MockUserRepository) {},
+			wantErr:    domainerrors.ErrInvalidProfile,
+		},
+		{
+			name:   "email taken",
+			update: ProfileUpdate{Email: ptr("taken@example.com")},
+			setupMocks: func(userRepo *MockUserRepository) {
+				userRepo.On("Update", mock.Anything, mock.Anything).Return(domainerrors.ErrEmailAlreadyExists)
+			},
+			wantErr: domainerrors.ErrEmailAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			userRepo.On("FindByID", mock.Anything, int64(1)).Return(&model.User{ID: 1, Login: "testuser", FirstName: "Test", LastName: "User", Email: "old@example.com"}, nil)
+			tt.setupMocks(userRepo)
+
+			profile, err := NewUpdateProfileUseCase(newProfileValidateToken(), userRepo).
+				Execute(context.Background(), UpdateProfileRequest{AccessToken: "access", Update: tt.update})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, profile)
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+)
+
+type RefreshTokenUseCase struct {
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	tokenIssuer      *TokenIssuer
+}
+
+func NewRefreshTokenUseCase(
+	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	tokenIssuer *TokenIssuer,
+) *RefreshTokenUseCase {
+	return &RefreshTokenUseCase{
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		tokenIssuer:      tokenIssuer,
+	}
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string
+}
+
+type RefreshTokenResponse struct {
+	Token        string
+	RefreshToken string
+}
+
+// Execute обменивает refresh-токен на новую пару токенов. Каждый refresh-токен
+// действует один раз: повторное предъявление использованного токена означает его
+// утечку, поэтому всё семейство токенов отзывается.
+func (uc *RefreshTokenUseCase) Execute(ctx context.Context, req RefreshTokenRequest) (*RefreshTokenResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, errors.ErrRefreshTokenRequired
+	}
+
+	token, err := uc.refreshTokenRepo.FindByHash(ctx, HashToken(req.RefreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if token.RevokedAt != nil || token.IsExpired(uc.tokenIssuer.now()) {
+		return nil, errors.ErrInvalidRefreshToken
+	}
+	if token.RotatedAt != nil {
+		return nil, uc.revokeFamily(ctx, token.FamilyID, token.UserID)
+	}
+
+	rotated, err := uc.refreshTokenRepo.MarkRotated(ctx, token.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Токен успели использовать параллельно.
+		return nil, uc.revokeFamily(ctx, token.FamilyID, token.UserID)
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	pair, err := uc.tokenIssuer.issueInFamily(ctx, user, token.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RefreshTokenResponse{Token: pair.AccessToken, RefreshToken: pair.RefreshToken}, nil
+}
+
+func (uc *RefreshTokenUseCase) revokeFamily(ctx context.Context, familyID string, userID int64) error {
+	log.Printf("refresh token reuse detected for user %d, revoking token family", userID)
+	if err := uc.refreshTokenRepo.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+	return errors.ErrRefreshTokenReused
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+)
+
+func TestRefreshTokenUseCase_Execute(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	user := &model.User{ID: 1, Login: "testuser"}
+	rotatedAt := now.Add(-time.Minute)
+	revokedAt := now.Add(-time.Minute)
+	dbErr := errors.New("database error")
+
+	activeToken := func() *model.RefreshToken {
+		return &model.RefreshToken{ID: 10, UserID: 1, FamilyID: "family", ExpiresAt: now.Add(time.Hour)}
+	}
+
+	tests := []struct {
+		name       string
+		req        RefreshTokenRequest
+		setupMocks func(*MockUserRepository, *MockJWTService, *MockRefreshTokenRepository)
+		wantErr    error
+	}{
+		{
+			name: "rotates token within family",
+			req:  RefreshTokenRequest{RefreshToken: "refresh"},
+			setupMocks: func(userRepo *MockUserRepository, jwtService *MockJWTService, repo *MockRefreshTokenRepository) {
+				repo.On("FindByHash", mock.Anything, HashToken("refresh")).Return(activeToken(), nil)
+				repo.On("MarkRotated", mock.Anything, int64(10)).Return(true, nil)
+				userRepo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+				jwtService.On("GenerateToken", int64(1), "testuser", mock.Anything).Return("new-access", nil)
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(token *model.RefreshToken) bool {
+					return token.FamilyID == "family" && token.UserID == 1 && token.ExpiresAt.Equal(now.Add(time.Hour*24))
+				})).Return(nil)
+			},
+		},
+		{
+			name:       "empty token",
+			req:        RefreshTokenRequest{},
+			setupMocks: func(*MockUserRepository, *MockJWTService, *MockRefreshTokenRepository) {},
+			wantErr:    domainerrors.ErrRefreshTokenRequired,
+		},
+		{
+			name: "unknown token",
+			req:  RefreshTokenRequest{RefreshToken: "unknown"},
+			setupMocks: func(_ *MockUserRepository, _ *MockJWTService, repo *MockRefreshTokenRepository) {
+				repo.On("FindByHash", mock.Anything, HashToken("unknown")).Return(nil, domainerrors.ErrInvalidRefreshToken)
+			},
+			wantErr: domainerrors.ErrInvalidRefreshToken,
+		},
+		{
+			name: "expired token",
+			req:  RefreshTokenRequest{RefreshToken: "refresh"},
+			setupMocks: func(_ *MockUserRepository, _ *MockJWTService, repo *MockRefreshTokenRepository) {
+				token := activeToken()
+				token.ExpiresAt = now
+				repo.On("FindByHash", mock.Anything, mock.Anything).Return(token, nil)
+			},
+			wantErr: domainerrors.ErrInvalidRefreshToken,
+		},
+		{
+			name: "revoked token",
+			req:  RefreshTokenRequest{RefreshToken: "refresh"},
+			setupMocks: func(_ *MockUserRepository, _ *MockJWTService, repo *MockRefreshTokenRepository) {
+				token := activeToken()
+				token.RevokedAt = &revokedAt
+				repo.On("FindByHash", mock.Anything, mock.Anything).Return(token, nil)
+			},
+			wantErr: domainerrors.ErrInvalidRefreshToken,
+		},
+		{
+			name: "reuse of rotated token revokes family",
+			req:  RefreshTokenRequest{RefreshToken: "refresh"},
+			setupMocks: func(_ *MockUserRepository, _ *MockJWTService, repo *MockRefreshTokenRepository) {
+				token := activeToken()
+				token.RotatedAt = &rotatedAt
+				repo.On("FindByHash", mock.Anything, mock.Anything).Return(token, nil)
+				repo.On("RevokeFamily", mock.Anything, "family").Return(nil)
+			},
+			wantErr: domainerrors.ErrRefreshTokenReused,
+		},
+		{
+			name: "concurrent rotation revokes family",
+			req:  RefreshTokenRequest{RefreshToken: "refresh"},
+			setupMocks: func(_ *MockUserRepository, _ *MockJWTService, repo *MockRefreshTokenRepository) {
+				repo.On("FindByHash", mock.Anything, mock.Anything).Return(activeToken(), nil)
+				repo.On("MarkRotated", mock.Anything, int64(10)).Return(false, nil)
+				repo.On("RevokeFamily", mock.Anything, "family").Return(nil)
+			},
+			wantErr: domainerrors.ErrRefreshTokenReused,
+		},
+		{
+			name: "revoke family error",
+			req:  RefreshTokenRequest{RefreshToken: "refresh"},
+			setupMocks: func(_ *MockUserRepository, _ *MockJWTService, repo *MockRefreshTokenRepository) {
+				token := activeToken()
+				token.RotatedAt = &rotatedAt
+				repo.On("FindByHash", mock.Anything, mock.Anything).Return(token, nil)
+				repo.On("RevokeFamily", mock.Anything, "family").Return(dbErr)
+			},
+			wantErr: dbErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			jwtService := new(MockJWTService)
+			refreshTokenRepo := new(MockRefreshTokenRepository)
+			tt.setupMocks(userRepo, jwtService, refreshTokenRepo)
+
+			issuer := NewTokenIssuer(jwtService, refreshTokenRepo, 24*time.Hour)
+			issuer.now = func() time.Time { return now }
+			uc := NewRefreshTokenUseCase(userRepo, refreshTokenRepo, issuer)
+
+			got, err := uc.Execute(context.Background(), tt.req)
+
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.Nil(t, got)
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "new-access", got.Token)
+				assert.NotEmpty(t, got.RefreshToken)
+				assert.NotEqual(t, "refresh", got.RefreshToken)
+			}
+
+			userRepo.AssertExpectations(t)
+			jwtService.AssertExpectations(t)
+			refreshTokenRepo.AssertExpectations(t)
+		})
+	}
+}
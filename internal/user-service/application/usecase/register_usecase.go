@@ -11,14 +11,16 @@ import (
 )
 
 type RegisterUseCase struct {
-	userRepo repository.UserRepository
-	jwtService service.JWTService
+	userRepo       repository.UserRepository
+	tokenIssuer    *TokenIssuer
+	passwordPolicy service.PasswordPolicy
 }
 
-func NewRegisterUseCase(userRepo repository.UserRepository, jwtService service.JWTService) *RegisterUseCase {
+func NewRegisterUseCase(userRepo repository.UserRepository, tokenIssuer *TokenIssuer, passwordPolicy service.PasswordPolicy) *RegisterUseCase {
 	return &RegisterUseCase{
-		userRepo:  userRepo,
-		jwtService: jwtService,
+		userRepo:       userRepo,
+		tokenIssuer:    tokenIssuer,
+		passwordPolicy: passwordPolicy,
 	}
 }
 
@@ -30,13 +32,17 @@ type RegisterRequest struct {
 }
 
 type RegisterResponse struct {
-	Token string
+	Token        string
+	RefreshToken string
 }
 
 func (uc *RegisterUseCase) Execute(ctx context.Context, req RegisterRequest) (*RegisterResponse, error) {
 	if req.Login == "" || req.Password == "" {
 		return nil, errors.ErrLoginRequired
 	}
+	if err := uc.passwordPolicy.Validate(req.Password, req.Login); err != nil {
+		return nil, err
+	}
 
 	exists, err := uc.userRepo.ExistsByLogin(ctx, req.Login)
 	if err != nil {
@@ -64,11 +70,11 @@ func (uc *RegisterUseCase) Execute(ctx context.Context, req RegisterRequest) (*R
 		return nil, err
 	}
 
-	token, err := uc.jwtService.GenerateToken(user.ID, user.Login)
+	pair, err := uc.tokenIssuer.Issue(ctx, user)
 	if err != nil {
 		return nil, err
 	}
 
-	return &RegisterResponse{Token: token}, nil
+	return &RegisterResponse{Token: pair.AccessToken, RefreshToken: pair.RefreshToken}, nil
 }
 
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
 )
 
 func TestRegisterUseCase_Execute(t *testing.T) {
@@ -34,7 +36,7 @@ func TestRegisterUseCase_Execute(t *testing.T) {
 				userRepo.On("Create", mock.Anything, mock.MatchedBy(func(u *model.User) bool {
 					return u.Login == "testuser" && u.FirstName == "Test" && u.LastName == "User" && u.PasswordHash != ""
 				})).Return(nil)
-				jwtService.On("GenerateToken", mock.Anything, "testuser").Return("test-token", nil)
+				jwtService.On("GenerateToken", mock.Anything, "testuser", mock.Anything).Return("test-token", nil)
 			},
 			want:    &RegisterResponse{Token: "test-token"},
 			wantErr: false,
@@ -110,7 +112,7 @@ func TestRegisterUseCase_Execute(t *testing.T) {
 			setupMocks: func(userRepo *MockUserRepository, jwtService *MockJWTService) {
 				userRepo.On("ExistsByLogin", mock.Anything, "testuser").Return(false, nil)
 				userRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
-				jwtService.On("GenerateToken", mock.Anything, "testuser").Return("", errors.New("jwt error"))
+				jwtService.On("GenerateToken", mock.Anything, "testuser", mock.Anything).Return("", errors.New("jwt error"))
 			},
 			want:    nil,
 			wantErr: true,
@@ -121,9 +123,11 @@ func TestRegisterUseCase_Execute(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			userRepo := new(MockUserRepository)
 			jwtService := new(MockJWTService)
+			refreshTokenRepo := new(MockRefreshTokenRepository)
+			refreshTokenRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
 			tt.setupMocks(userRepo, jwtService)
 
-			uc := NewRegisterUseCase(userRepo, jwtService)
+			uc := NewRegisterUseCase(userRepo, NewTokenIssuer(jwtService, refreshTokenRepo, time.Hour), service.PasswordPolicy{})
 			got, err := uc.Execute(context.Background(), tt.req)
 
 			if tt.wantErr {
@@ -134,7 +138,8 @@ func TestRegisterUseCase_Execute(t *testing.T) {
 				}
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.want, got)
+				assert.Equal(t, tt.want.Token, got.Token)
+				assert.NotEmpty(t, got.RefreshToken)
 			}
 
 			userRepo.AssertExpectations(t)
@@ -142,3 +147,14 @@ func TestRegisterUseCase_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterUseCase_PasswordPolicy(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	policy := service.PasswordPolicy{MinLength: 8, MinClasses: 2, RejectCommon: true}
+	uc := NewRegisterUseCase(userRepo, NewTokenIssuer(new(MockJWTService), new(MockRefreshTokenRepository), time.Hour), policy)
+
+	_, err := uc.Execute(context.Background(), RegisterRequest{Login: "testuser", Password: "password123"})
+
+	assert.ErrorIs(t, err, domainerrors.ErrWeakPassword)
+	userRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+)
+
+const resetTokenBytes = 32
+
+type RequestPasswordResetUseCase struct {
+	userRepo       repository.UserRepository
+	resetTokenRepo repository.PasswordResetTokenRepository
+	notifier       service.PasswordResetNotifier
+	tokenTTL       time.Duration
+	now            func() time.Time
+}
+
+func NewRequestPasswordResetUseCase(
+	userRepo repository.UserRepository,
+	resetTokenRepo repository.PasswordResetTokenRepository,
+	notifier service.PasswordResetNotifier,
+	tokenTTL time.Duration,
+) *RequestPasswordResetUseCase {
+	return &RequestPasswordResetUseCase{
+		userRepo:       userRepo,
+		resetTokenRepo: resetTokenRepo,
+		notifier:       notifier,
+		tokenTTL:       tokenTTL,
+		now:            time.Now,
+	}
+}
+
+type RequestPasswordResetRequest struct {
+	Login string
+}
+
+// Execute выпускает токен сброса пароля и отправляет его пользователю. Для неизвестного
+// логина ошибка не возвращается, чтобы по ответу нельзя было проверить, зарегистрирован ли логин.
+func (uc *RequestPasswordResetUseCase) Execute(ctx context.Context, req RequestPasswordResetRequest) error {
+	if req.Login == "" {
+		return errors.ErrLoginRequired
+	}
+
+	user, err := uc.userRepo.FindByLogin(ctx, req.Login)
+	if err != nil {
+		if errors.Is(err, errors.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := randomString(resetTokenBytes)
+	if err != nil {
+		return err
+	}
+
+	now := uc.now()
+	resetToken := &model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: HashToken(token),
+		ExpiresAt: now.Add(uc.tokenTTL),
+		CreatedAt: now,
+	}
+	if err := uc.resetTokenRepo.Create(ctx, resetToken); err != nil {
+		return err
+	}
+
+	return uc.notifier.SendPasswordReset(ctx, user, token, resetToken.ExpiresAt)
+}
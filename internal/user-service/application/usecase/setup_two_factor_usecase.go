@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+)
+
+type SetupTwoFactorUseCase struct {
+	validateToken *ValidateTokenUseCase
+	twoFactorRepo repository.TwoFactorRepository
+	cipher        service.SecretCipher
+	totp          service.TOTP
+}
+
+// NewSetupTwoFactorUseCase создаёт сценарий начала настройки 2FA; cipher == nil означает,
+// что ключ шифрования секретов не задан и 2FA недоступна.
+func NewSetupTwoFactorUseCase(
+	validateToken *ValidateTokenUseCase,
+	twoFactorRepo repository.TwoFactorRepository,
+	cipher service.SecretCipher,
+	totp service.TOTP,
+) *SetupTwoFactorUseCase {
+	return &SetupTwoFactorUseCase{
+		validateToken: validateToken,
+		twoFactorRepo: twoFactorRepo,
+		cipher:        cipher,
+		totp:          totp,
+	}
+}
+
+type SetupTwoFactorRequest struct {
+	AccessToken string
+}
+
+// SetupTwoFactorResponse — секрет для ручного ввода и otpauth:// ссылка для QR-кода.
+// 2FA включается только после подтверждения первого кода.
+type SetupTwoFactorResponse struct {
+	Secret string
+	URI    string
+}
+
+func (uc *SetupTwoFactorUseCase) Execute(ctx context.Context, req SetupTwoFactorRequest) (*SetupTwoFactorResponse, error) {
+	validated, err := uc.validateToken.Execute(ctx, ValidateTokenRequest{Token: req.AccessToken})
+	if err != nil {
+		return nil, err
+	}
+	if uc.cipher == nil {
+		return nil, errors.ErrTwoFactorUnavailable
+	}
+
+	userID := validated.Claims.UserID
+	twoFactor, err := uc.twoFactorRepo.Find(ctx, userID)
+	if err != nil {
+		if errors.Is(err, errors.ErrUserNotFound) {
+			return nil, errors.ErrInvalidToken
+		}
+		return nil, err
+	}
+	if twoFactor.Enabled {
+		return nil, errors.ErrTwoFactorEnabled
+	}
+
+	secret, err := service.NewTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := uc.cipher.Encrypt(secret, twoFactorAssociatedData(userID))
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.twoFactorRepo.SetPendingSecret(ctx, userID, encrypted); err != nil {
+		return nil, err
+	}
+
+	return &SetupTwoFactorResponse{
+		Secret: service.EncodeTOTPSecret(secret),
+		URI:    uc.totp.URI(validated.Claims.Login, secret),
+	}, nil
+}
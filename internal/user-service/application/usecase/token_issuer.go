@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+)
+
+const (
+	refreshTokenBytes = 32
+	familyIDBytes     = 16
+)
+
+// TokenIssuer выпускает пару токенов: короткоживущий access-токен JWT
+// и непрозрачный refresh-токен, хэш которого сохраняется в БД.
+type TokenIssuer struct {
+	jwtService       service.JWTService
+	refreshTokenRepo repository.RefreshTokenRepository
+	refreshTTL       time.Duration
+	now              func() time.Time
+}
+
+func NewTokenIssuer(jwtService service.JWTService, refreshTokenRepo repository.RefreshTokenRepository, refreshTTL time.Duration) *TokenIssuer {
+	return &TokenIssuer{
+		jwtService:       jwtService,
+		refreshTokenRepo: refreshTokenRepo,
+		refreshTTL:       refreshTTL,
+		now:              time.Now,
+	}
+}
+
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+// Issue выпускает пару токенов и открывает новое семейство refresh-токенов.
+func (i *TokenIssuer) Issue(ctx context.Context, user *model.User) (*TokenPair, error) {
+	familyID, err := randomString(familyIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	return i.issueInFamily(ctx, user, familyID)
+}
+
+func (i *TokenIssuer) issueInFamily(ctx context.Context, user *model.User, familyID string) (*TokenPair, error) {
+	accessToken, err := i.jwtService.GenerateToken(user.ID, user.Login, user.Roles)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomString(refreshTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	now := i.now()
+	err = i.refreshTokenRepo.Create(ctx, &model.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: HashToken(refreshToken),
+		ExpiresAt: now.Add(i.refreshTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// HashToken — хэш непрозрачного токена (refresh, сброс пароля), который хранится в БД вместо самого токена.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
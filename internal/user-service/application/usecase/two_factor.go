@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+)
+
+const (
+	challengeTokenBytes = 32
+	recoveryCodeCount   = 10
+	// Резервный код — 6 случайных байт, 10 символов base32.
+	recoveryCodeBytes  = 6
+	recoveryCodeLength = 10
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TwoFactorChallenges выдаёт токен второго шага входа пользователям с включённой 2FA.
+type TwoFactorChallenges struct {
+	twoFactorRepo repository.TwoFactorRepository
+	challengeRepo repository.TwoFactorChallengeRepository
+	ttl           time.Duration
+	now           func() time.Time
+}
+
+func NewTwoFactorChallenges(
+	twoFactorRepo repository.TwoFactorRepository,
+	challengeRepo repository.TwoFactorChallengeRepository,
+	ttl time.Duration,
+) *TwoFactorChallenges {
+	return &TwoFactorChallenges{
+		twoFactorRepo: twoFactorRepo,
+		challengeRepo: challengeRepo,
+		ttl:           ttl,
+		now:           time.Now,
+	}
+}
+
+// Issue возвращает токен второго шага или пустую строку, если 2FA у пользователя не включена.
+func (c *TwoFactorChallenges) Issue(ctx context.Context, userID int64) (string, error) {
+	twoFactor, err := c.twoFactorRepo.Find(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if !twoFactor.Enabled {
+		return "", nil
+	}
+
+	token, err := randomString(challengeTokenBytes)
+	if err != nil {
+		return "", err
+	}
+	now := c.now()
+	challenge := &model.TwoFactorChallenge{
+		UserID:    userID,
+		TokenHash: HashToken(token),
+		ExpiresAt: now.Add(c.ttl),
+		CreatedAt: now,
+	}
+	if err := c.challengeRepo.Create(ctx, challenge); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// newRecoveryCodes возвращает резервные коды для пользователя и их хэши для хранения.
+func newRecoveryCodes() (codes []string, hashes []string, err error) {
+	buf := make([]byte, recoveryCodeBytes)
+	for i := 0; i < recoveryCodeCount; i++ {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(buf))
+		codes = append(codes, code[:recoveryCodeLength/2]+"-"+code[recoveryCodeLength/2:])
+		hashes = append(hashes, HashToken(code))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode приводит введённый код к виду, от которого считался хэш;
+// пустая строка — введённое не похоже на резервный код.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(code) != recoveryCodeLength {
+		return ""
+	}
+	return code
+}
+
+// twoFactorAssociatedData привязывает зашифрованный секрет к пользователю.
+func twoFactorAssociatedData(userID int64) []byte {
+	return []byte("totp:" + strconv.FormatInt(userID, 10))
+}
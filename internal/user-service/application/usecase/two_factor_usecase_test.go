@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+)
+
+// prefixCipher — обратимое «шифрование» для тестов, проверяющее associatedData.
+type prefixCipher struct{}
+
+func (prefixCipher) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	return append(append([]byte{}, associatedData...), plaintext...), nil
+}
+
+func (prefixCipher) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	if !strings.HasPrefix(string(ciphertext), string(associatedData)) {
+		return nil, assert.AnError
+	}
+	return ciphertext[len(associatedData):], nil
+}
+
+var (
+	testTOTP       = service.TOTP{Issuer: "Gophermart", Skew: 1}
+	testTOTPSecret = []byte("12345678901234567890")
+)
+
+func newTestValidateToken(userID int64, login string) *ValidateTokenUseCase {
+	jwtService := new(MockJWTService)
+	revokedRepo := new(MockRevokedTokenRepository)
+	jwtService.On("ValidateToken", "access").Return(&model.Claims{UserID: userID, Login: login}, nil)
+	revokedRepo.On("IsRevoked", mock.Anything, mock.Anything, userID, mock.Anything).Return(false, nil)
+	return NewValidateTokenUseCase(jwtService, revokedRepo)
+}
+
+func encryptedTestSecret(userID int64) []byte {
+	encrypted, _ := prefixCipher{}.Encrypt(testTOTPSecret, twoFactorAssociatedData(userID))
+	return encrypted
+}
+
+func TestLoginUseCase_TwoFactorChallenge(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	user := &model.User{ID: 1, Login: "testuser", PasswordHash: string(hashedPassword)}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	userRepo := new(MockUserRepository)
+	twoFactorRepo := new(MockTwoFactorRepository)
+	challengeRepo := new(MockTwoFactorChallengeRepository)
+	attempts := new(MockLoginAttemptRepository)
+	jwtService := new(MockJWTService)
+	userRepo.On("FindByLogin", mock.Anything, "testuser").Return(user, nil)
+	attempts.On("RecordAttempt", mock.Anything, model.LoginAttemptScopeLogin, "testuser", now, now.Add(-testThrottlePolicy.Lockout)).
+		Return(&model.LoginAttempt{Scope: model.LoginAttemptScopeLogin, Subject: "testuser", Failures: 1}, nil)
+	attempts.On("Release", mock.Anything, model.LoginAttemptScopeLogin, "testuser").Return(nil)
+	twoFactorRepo.On("Find", mock.Anything, int64(1)).Return(&model.TwoFactor{UserID: 1, Enabled: true}, nil)
+	var stored *model.TwoFactorChallenge
+	challengeRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*model.TwoFactorChallenge)
+	}).Return(nil)
+
+	challenges := NewTwoFactorChallenges(twoFactorRepo, challengeRepo, 5*time.Minute)
+	challenges.now = func() time.Time { return now }
+	uc := NewLoginUseCase(userRepo, NewTokenIssuer(jwtService, new(MockRefreshTokenRepository), time.Hour),
+		newTestThrottle(attempts, now), challenges)
+
+	resp, err := uc.Execute(context.Background(), LoginRequest{Login: "testuser", Password: "password123"})
+
+	require.NoError(t, err)
+	assert.Empty(t, resp.Token, "tokens are issued only after the second factor")
+	assert.NotEmpty(t, resp.ChallengeToken)
+	require.NotNil(t, stored)
+	assert.Equal(t, HashToken(resp.ChallengeToken), stored.TokenHash)
+	assert.Equal(t, now.Add(5*time.Minute), stored.ExpiresAt)
+	jwtService.AssertNotCalled(t, "GenerateToken", mock.Anything, mock.Anything)
+	attempts.AssertExpectations(t)
+	attempts.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSetupTwoFactorUseCase_Execute(t *testing.T) {
+	t.Run("stores_encrypted_pending_secret", func(t *testing.T) {
+		twoFactorRepo := new(MockTwoFactorRepository)
+		twoFactorRepo.On("Find", mock.Anything, int64(1)).Return(&model.TwoFactor{UserID: 1}, nil)
+		var stored []byte
+		twoFactorRepo.On("SetPendingSecret", mock.Anything, int64(1), mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(2).([]byte)
+		}).Return(nil)
+
+		resp, err := NewSetupTwoFactorUseCase(newTestValidateToken(1, "testuser"), twoFactorRepo, prefixCipher{}, testTOTP).
+			Execute(context.Background(), SetupTwoFactorRequest{AccessToken: "access"})
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(resp.URI, "otpauth://totp/Gophermart:testuser?"))
+		secret, err := prefixCipher{}.Decrypt(stored, twoFactorAssociatedData(1))
+		require.NoError(t, err)
+		assert.Equal(t, resp.Secret, service.EncodeTOTPSecret(secret))
+	})
+
+	t.Run("already_enabled", func(t *testing.T) {
+		twoFactorRepo := new(MockTwoFactorRepository)
+		twoFactorRepo.On("Find", mock.Anything, int64(1)).Return(&model.TwoFactor{UserID: 1, Enabled: true}, nil)
+
+		_, err := NewSetupTwoFactorUseCase(newTestValidateToken(1, "testuser"), twoFactorRepo, prefixCipher{}, testTOTP).
+			Execute(context.Background(), SetupTwoFactorRequest{AccessToken: "access"})
+
+		assert.ErrorIs(t, err, domainerrors.ErrTwoFactorEnabled)
+	})
+
+	t.Run("no_encryption_key", func(t *testing.T) {
+		_, err := NewSetupTwoFactorUseCase(newTestValidateToken(1, "testuser"), new(MockTwoFactorRepository), nil, testTOTP).
+			Execute(context.Background(), SetupTwoFactorRequest{AccessToken: "access"})
+
+		assert.ErrorIs(t, err, domainerrors.ErrTwoFactorUnavailable)
+	})
+}
+
+func TestEnableTwoFactorUseCase_Execute(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := testTOTP.Step(now)
+	pending := &model.TwoFactor{UserID: 1, Secret: encryptedTestSecret(1)}
+
+	newUseCase := func(twoFactorRepo *MockTwoFactorRepository) *EnableTwoFactorUseCase {
+		uc := NewEnableTwoFactorUseCase(newTestValidateToken(1, "testuser"), twoFactorRepo, prefixCipher{}, testTOTP)
+		uc.now = func() time.Time { return now }
+		return uc
+	}
+
+	t.Run("first_code_enables_and_returns_recovery_codes", func(t *testing.T) {
+		twoFactorRepo := new(MockTwoFactorRepository)
+		twoFactorRepo.On("Find", mock.Anything, int64(1)).Return(pending, nil)
+		var hashes []string
+		twoFactorRepo.On("Enable", mock.Anything, int64(1), pending.Secret, mock.Anything, step).Run(func(args mock.Arguments) {
+			hashes = args.Get(3).([]string)
+		}).Return(nil)
+
+		resp, err := newUseCase(twoFactorRepo).Execute(context.Background(), EnableTwoFactorRequest{
+			AccessToken: "access",
+			Code:        testTOTP.Code(testTOTPSecret, step),
+		})
+
+		require.NoError(t, err)
+		require.Len(t, resp.RecoveryCodes, recoveryCodeCount)
+		require.Len(t, hashes, recoveryCodeCount)
+		for i, code := range resp.RecoveryCodes {
+			assert.Equal(t, hashes[i], HashToken(normalizeRecoveryCode(code)))
+		}
+	})
+
+	t.Run("wrong_code", func(t *testing.T) {
+		twoFactorRepo := new(MockTwoFactorRepository)
+		twoFactorRepo.On("Find", mock.Anything, int64(1)).Return(pending, nil)
+
+		_, err := newUseCase(twoFactorRepo).Execute(context.Background(), EnableTwoFactorRequest{AccessToken: "access", Code: "000000"})
+
+		assert.ErrorIs(t, err, domainerrors.ErrInvalidTwoFactorCode)
+		twoFactorRepo.AssertNotCalled(t, "Enable", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("setup_not_started", func(t *testing.T) {
+		twoFactorRepo := new(MockTwoFactorRepository)
+		twoFactorRepo.On("Find", mock.Anything, int64(1)).Return(&model.TwoFactor{UserID: 1}, nil)
+
+		_, err := newUseCase(twoFactorRepo).Execute(context.Background(), EnableTwoFactorRequest{AccessToken: "access", Code: "123456"})
+
+		assert.ErrorIs(t, err, domainerrors.ErrTwoFactorNotStarted)
+	})
+}
+
+func TestVerifyTwoFactorUseCase_Execute(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := testTOTP.Step(now)
+	user := &model.User{ID: 1, Login: "testuser"}
+	enabled := &model.TwoFactor{UserID: 1, Secret: encryptedTestSecret(1), Enabled: true}
+	challenge := &model.TwoFactorChallenge{ID: 3, UserID: 1, ExpiresAt: now.Add(time.Minute)}
+	resetBefore := now.Add(-testThrottlePolicy.Lockout)
+	loginAttempt := func(failures int) *model.LoginAttempt {
+		return &model.LoginAttempt{Scope: model.LoginAttemptScopeLogin, Subject: "testuser", Failures: failures}
+	}
+
+	type mocks struct {
+		twoFactorRepo *MockTwoFactorRepository
+		challengeRepo *MockTwoFactorChallengeRepository
+		attempts      *MockLoginAttemptRepository
+		jwtService    *MockJWTService
+	}
+
+	tests := []struct {
+		name       string
+		code       string
+		setupMocks func(m mocks)
+		wantErr    error
+	}{
+		{
+			name: "valid_code_issues_tokens",
+			code: testTOTP.Code(testTOTPSecret, step),
+			setupMocks: func(m mocks) {
+				m.twoFactorRepo.On("UseStep", mock.Anything, int64(1), step).Return(true, nil)
+				m.challengeRepo.On("Delete", mock.Anything, int64(3)).Return(true, nil)
+				m.attempts.On("RecordAttempt", mock.Anything, model.LoginAttemptScopeLogin, "testuser", now, resetBefore).
+					Return(loginAttempt(1), nil)
+				m.attempts.On("Reset", mock.Anything, model.LoginAttemptScopeLogin, "testuser").Return(nil)
+				m.jwtService.On("GenerateToken", int64(1), "testuser", mock.Anything).Return("token", nil)
+			},
+		},
+		{
+			name: "recovery_code_issues_tokens",
+			code: "ABCDE-FGHIJ",
+			setupMocks: func(m mocks) {
+				m.twoFactorRepo.On("UseRecoveryCode", mock.Anything, int64(1), HashToken("abcdefghij")).Return(true, nil)
+				m.challengeRepo.On("Delete", mock.Anything, int64(3)).Return(true, nil)
+				m.attempts.On("RecordAttempt", mock.Anything, model.LoginAttemptScopeLogin, "testuser", now, resetBefore).
+					Return(loginAttempt(1), nil)
+				m.attempts.On("Reset", mock.Anything, model.LoginAttemptScopeLogin, "testuser").Return(nil)
+				m.jwtService.On("GenerateToken", int64(1), "testuser", mock.Anything).Return("token", nil)
+			},
+		},
+		{
+			name: "replayed_code_is_counted_as_failure",
+			code: testTOTP.Code(testTOTPSecret, step),
+			setupMocks: func(m mocks) {
+				m.twoFactorRepo.On("UseStep", mock.Anything, int64(1), step).Return(false, nil)
+				m.challengeRepo.On("RecordFailure", mock.Anything, int64(3)).Return(1, nil)
+				m.attempts.On("RecordAttempt", mock.Anything, model.LoginAttemptScopeLogin, "testuser", now, resetBefore).
+					Return(loginAttempt(1), nil)
+			},
+			wantErr: domainerrors.ErrInvalidTwoFactorCode,
+		},
+		{
+			name: "challenge_is_dropped_after_max_attempts",
+			code: "000000",
+			setupMocks: func(m mocks) {
+				m.challengeRepo.On("RecordFailure", mock.Anything, int64(3)).Return(maxChallengeAttempts, nil)
+				m.challengeRepo.On("Delete", mock.Anything, int64(3)).Return(true, nil)
+				m.attempts.On("RecordAttempt", mock.Anything, model.LoginAttemptScopeLogin, "testuser", now, resetBefore).
+					Return(loginAttempt(2), nil)
+				m.attempts.On("Lock", mock.Anything, model.LoginAttemptScopeLogin, "testuser", mock.Anything).Return(nil)
+			},
+			wantErr: domainerrors.ErrInvalidTwoFactorCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks{
+				twoFactorRepo: new(MockTwoFactorRepository),
+				challengeRepo: new(MockTwoFactorChallengeRepository),
+				attempts:      new(MockLoginAttemptRepository),
+				jwtService:    new(MockJWTService),
+			}
+			userRepo := new(MockUserRepository)
+			userRepo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+			refreshRepo := new(MockRefreshTokenRepository)
+			refreshRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+			m.challengeRepo.On("FindByHash", mock.Anything, HashToken("challenge")).Return(challenge, nil)
+			m.twoFactorRepo.On("Find", mock.Anything, int64(1)).Return(enabled, nil)
+			tt.setupMocks(m)
+
+			uc := NewVerifyTwoFactorUseCase(userRepo, m.twoFactorRepo, m.challengeRepo, prefixCipher{}, testTOTP,
+				NewTokenIssuer(m.jwtService, refreshRepo, time.Hour), newTestThrottle(m.attempts, now))
+			uc.now = func() time.Time { return now }
+
+			resp, err := uc.Execute(context.Background(), VerifyTwoFactorRequest{ChallengeToken: "challenge", Code: tt.code})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				m.jwtService.AssertNotCalled(t, "GenerateToken", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "token", resp.Token)
+			}
+			m.twoFactorRepo.AssertExpectations(t)
+			m.challengeRepo.AssertExpectations(t)
+			m.attempts.AssertExpectations(t)
+		})
+	}
+}
+
+func TestVerifyTwoFactorUseCase_ExpiredChallenge(t *testing.T) {
+	now := time.Now()
+	challengeRepo := new(MockTwoFactorChallengeRepository)
+	challengeRepo.On("FindByHash", mock.Anything, mock.Anything).
+		Return(&model.TwoFactorChallenge{ID: 3, UserID: 1, ExpiresAt: now.Add(-time.Second)}, nil)
+	userRepo := new(MockUserRepository)
+
+	uc := NewVerifyTwoFactorUseCase(userRepo, new(MockTwoFactorRepository), challengeRepo, prefixCipher{}, testTOTP, nil, nil)
+	uc.now = func() time.Time { return now }
+
+	_, err := uc.Execute(context.Background(), VerifyTwoFactorRequest{ChallengeToken: "challenge", Code: "123456"})
+
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidChallenge)
+	userRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+)
+
+type UnlockLoginUseCase struct {
+	attemptRepo repository.LoginAttemptRepository
+}
+
+func NewUnlockLoginUseCase(attemptRepo repository.LoginAttemptRepository) *UnlockLoginUseCase {
+	return &UnlockLoginUseCase{
+		attemptRepo: attemptRepo,
+	}
+}
+
+type UnlockLoginRequest struct {
+	Login string
+	IP    string
+}
+
+// Execute снимает блокировку и обнуляет счётчик неудачных попыток для логина и/или IP-адреса.
+func (uc *UnlockLoginUseCase) Execute(ctx context.Context, req UnlockLoginRequest) error {
+	if req.Login == "" && req.IP == "" {
+		return errors.ErrUnlockTargetRequired
+	}
+	if req.Login != "" {
+		if err := uc.attemptRepo.Reset(ctx, model.LoginAttemptScopeLogin, req.Login); err != nil {
+			return err
+		}
+	}
+	if req.IP != "" {
+		if err := uc.attemptRepo.Reset(ctx, model.LoginAttemptScopeIP, req.IP); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+)
+
+type UpdateProfileUseCase struct {
+	validateToken *ValidateTokenUseCase
+	userRepo      repository.UserRepository
+}
+
+func NewUpdateProfileUseCase(validateToken *ValidateTokenUseCase, userRepo repository.UserRepository) *UpdateProfileUseCase {
+	return &UpdateProfileUseCase{
+		validateToken: validateToken,
+		userRepo:      userRepo,
+	}
+}
+
+type UpdateProfileRequest struct {
+	AccessToken string
+	Update      ProfileUpdate
+}
+
+// Execute применяет частичное изменение профиля и возвращает профиль без баланса.
+func (uc *UpdateProfileUseCase) Execute(ctx context.Context, req UpdateProfileRequest) (*Profile, error) {
+	validated, err := uc.validateToken.Execute(ctx, ValidateTokenRequest{Token: req.AccessToken})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, validated.Claims.UserID)
+	if err != nil {
+		if errors.Is(err, errors.ErrUserNotFound) {
+			return nil, errors.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if err := req.Update.apply(user); err != nil {
+		return nil, err
+	}
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return newProfile(user), nil
+}
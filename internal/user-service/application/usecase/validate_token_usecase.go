@@ -1,18 +1,24 @@
 package usecase
 
 import (
+	"context"
+	"time"
+
 	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
 )
 
 type ValidateTokenUseCase struct {
-	jwtService service.JWTService
+	jwtService       service.JWTService
+	revokedTokenRepo repository.RevokedTokenRepository
 }
 
-func NewValidateTokenUseCase(jwtService service.JWTService) *ValidateTokenUseCase {
+func NewValidateTokenUseCase(jwtService service.JWTService, revokedTokenRepo repository.RevokedTokenRepository) *ValidateTokenUseCase {
 	return &ValidateTokenUseCase{
-		jwtService: jwtService,
+		jwtService:       jwtService,
+		revokedTokenRepo: revokedTokenRepo,
 	}
 }
 
@@ -24,7 +30,7 @@ type ValidateTokenResponse struct {
 	Claims *model.Claims
 }
 
-func (uc *ValidateTokenUseCase) Execute(req ValidateTokenRequest) (*ValidateTokenResponse, error) {
+func (uc *ValidateTokenUseCase) Execute(ctx context.Context, req ValidateTokenRequest) (*ValidateTokenResponse, error) {
 	if req.Token == "" {
 		return nil, errors.ErrTokenRequired
 	}
@@ -34,6 +40,17 @@ func (uc *ValidateTokenUseCase) Execute(req ValidateTokenRequest) (*ValidateToke
 		return nil, errors.ErrInvalidToken
 	}
 
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+	revoked, err := uc.revokedTokenRepo.IsRevoked(ctx, claims.ID, claims.UserID, issuedAt)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, errors.ErrTokenRevoked
+	}
+
 	return &ValidateTokenResponse{Claims: claims}, nil
 }
-
@@ -1,11 +1,13 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
-	_ "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/mock"
 
 	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
@@ -15,7 +17,7 @@ func TestValidateTokenUseCase_Execute(t *testing.T) {
 	tests := []struct {
 		name       string
 		req        ValidateTokenRequest
-		setupMocks func(*MockJWTService)
+		setupMocks func(*MockJWTService, *MockRevokedTokenRepository)
 		want       *ValidateTokenResponse
 		wantErr    bool
 		errType    error
@@ -25,12 +27,13 @@ func TestValidateTokenUseCase_Execute(t *testing.T) {
 			req: ValidateTokenRequest{
 				Token: "valid-token",
 			},
-			setupMocks: func(jwtService *MockJWTService) {
+			setupMocks: func(jwtService *MockJWTService, revokedRepo *MockRevokedTokenRepository) {
 				claims := &model.Claims{
 					UserID: 1,
 					Login:  "testuser",
 				}
 				jwtService.On("ValidateToken", "valid-token").Return(claims, nil)
+				revokedRepo.On("IsRevoked", mock.Anything, "", int64(1), time.Time{}).Return(false, nil)
 			},
 			want: &ValidateTokenResponse{
 				Claims: &model.Claims{
@@ -45,7 +48,7 @@ func TestValidateTokenUseCase_Execute(t *testing.T) {
 			req: ValidateTokenRequest{
 				Token: "",
 			},
-			setupMocks: func(jwtService *MockJWTService) {
+			setupMocks: func(jwtService *MockJWTService, revokedRepo *MockRevokedTokenRepository) {
 			},
 			want:    nil,
 			wantErr: true,
@@ -56,7 +59,7 @@ func TestValidateTokenUseCase_Execute(t *testing.T) {
 			req: ValidateTokenRequest{
 				Token: "invalid-token",
 			},
-			setupMocks: func(jwtService *MockJWTService) {
+			setupMocks: func(jwtService *MockJWTService, revokedRepo *MockRevokedTokenRepository) {
 				jwtService.On("ValidateToken", "invalid-token").Return(nil, errors.New("invalid token"))
 			},
 			want:    nil,
@@ -68,22 +71,50 @@ func TestValidateTokenUseCase_Execute(t *testing.T) {
 			req: ValidateTokenRequest{
 				Token: "token",
 			},
-			setupMocks: func(jwtService *MockJWTService) {
+			setupMocks: func(jwtService *MockJWTService, revokedRepo *MockRevokedTokenRepository) {
 				jwtService.On("ValidateToken", "token").Return(nil, errors.New("jwt error"))
 			},
 			want:    nil,
 			wantErr: true,
 			errType: domainerrors.ErrInvalidToken,
 		},
+		{
+			name: "revoked token",
+			req: ValidateTokenRequest{
+				Token: "revoked-token",
+			},
+			setupMocks: func(jwtService *MockJWTService, revokedRepo *MockRevokedTokenRepository) {
+				claims := &model.Claims{UserID: 1, Login: "testuser"}
+				claims.ID = "jti-1"
+				jwtService.On("ValidateToken", "revoked-token").Return(claims, nil)
+				revokedRepo.On("IsRevoked", mock.Anything, "jti-1", int64(1), time.Time{}).Return(true, nil)
+			},
+			want:    nil,
+			wantErr: true,
+			errType: domainerrors.ErrTokenRevoked,
+		},
+		{
+			name: "revocation store error",
+			req: ValidateTokenRequest{
+				Token: "token",
+			},
+			setupMocks: func(jwtService *MockJWTService, revokedRepo *MockRevokedTokenRepository) {
+				jwtService.On("ValidateToken", "token").Return(&model.Claims{UserID: 1}, nil)
+				revokedRepo.On("IsRevoked", mock.Anything, "", int64(1), time.Time{}).Return(false, errors.New("database error"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			jwtService := new(MockJWTService)
-			tt.setupMocks(jwtService)
+			revokedRepo := new(MockRevokedTokenRepository)
+			tt.setupMocks(jwtService, revokedRepo)
 
-			uc := NewValidateTokenUseCase(jwtService)
-			got, err := uc.Execute(tt.req)
+			uc := NewValidateTokenUseCase(jwtService, revokedRepo)
+			got, err := uc.Execute(context.Background(), tt.req)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -97,6 +128,7 @@ func TestValidateTokenUseCase_Execute(t *testing.T) {
 			}
 
 			jwtService.AssertExpectations(t)
+			revokedRepo.AssertExpectations(t)
 		})
 	}
 }
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+)
+
+// maxChallengeAttempts — число неверных кодов, после которого нужно снова ввести пароль.
+const maxChallengeAttempts = 5
+
+// VerifyTwoFactorUseCase — второй шаг входа: обмен challenge-токена и кода TOTP
+// или резервного кода на пару токенов.
+type VerifyTwoFactorUseCase struct {
+	userRepo      repository.UserRepository
+	twoFactorRepo repository.TwoFactorRepository
+	challengeRepo repository.TwoFactorChallengeRepository
+	cipher        service.SecretCipher
+	totp          service.TOTP
+	tokenIssuer   *TokenIssuer
+	throttle      *LoginThrottle
+	now           func() time.Time
+}
+
+func NewVerifyTwoFactorUseCase(
+	userRepo repository.UserRepository,
+	twoFactorRepo repository.TwoFactorRepository,
+	challengeRepo repository.TwoFactorChallengeRepository,
+	cipher service.SecretCipher,
+	totp service.TOTP,
+	tokenIssuer *TokenIssuer,
+	throttle *LoginThrottle,
+) *VerifyTwoFactorUseCase {
+	return &VerifyTwoFactorUseCase{
+		userRepo:      userRepo,
+		twoFactorRepo: twoFactorRepo,
+		challengeRepo: challengeRepo,
+		cipher:        cipher,
+		totp:          totp,
+		tokenIssuer:   tokenIssuer,
+		throttle:      throttle,
+		now:           time.Now,
+	}
+}
+
+type VerifyTwoFactorRequest struct {
+	ChallengeToken string
+	Code           string
+	ClientIP       string
+}
+
+func (uc *VerifyTwoFactorUseCase) Execute(ctx context.Context, req VerifyTwoFactorRequest) (*LoginResponse, error) {
+	if req.ChallengeToken == "" {
+		return nil, errors.ErrInvalidChallenge
+	}
+	if req.Code == "" {
+		return nil, errors.ErrInvalidTwoFactorCode
+	}
+
+	challenge, err := uc.challengeRepo.FindByHash(ctx, HashToken(req.ChallengeToken))
+	if err != nil {
+		return nil, err
+	}
+	if challenge.IsExpired(uc.now()) {
+		return nil, errors.ErrInvalidChallenge
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, errors.ErrUserNotFound) {
+			return nil, errors.ErrInvalidChallenge
+		}
+		return nil, err
+	}
+	var try *LoginTry
+	if uc.throttle != nil {
+		if try, err = uc.throttle.Acquire(ctx, user.Login, req.ClientIP); err != nil {
+			return nil, err
+		}
+	}
+
+	twoFactor, err := uc.twoFactorRepo.Find(ctx, user.ID)
+	if err != nil {
+		return nil, uc.release(ctx, try, err)
+	}
+	if !twoFactor.Enabled {
+		return nil, uc.release(ctx, try, errors.ErrInvalidChallenge)
+	}
+
+	ok, err := uc.checkCode(ctx, twoFactor, req.Code)
+	if err != nil {
+		return nil, uc.release(ctx, try, err)
+	}
+	if !ok {
+		return nil, uc.failed(ctx, challenge, try)
+	}
+
+	deleted, err := uc.challengeRepo.Delete(ctx, challenge.ID)
+	if err != nil {
+		return nil, uc.release(ctx, try, err)
+	}
+	if !deleted {
+		return nil, uc.release(ctx, try, errors.ErrInvalidChallenge)
+	}
+
+	// Счётчик неудач логина сбрасывается только здесь, а не после пароля:
+	// иначе повторный ввод пароля обнулял бы подбор кода.
+	if try != nil {
+		if err := uc.throttle.RecordSuccess(ctx, try); err != nil {
+			return nil, err
+		}
+	}
+
+	pair, err := uc.tokenIssuer.Issue(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResponse{Token: pair.AccessToken, RefreshToken: pair.RefreshToken}, nil
+}
+
+// checkCode принимает код TOTP, который ещё не использовался, или неизрасходованный резервный код.
+func (uc *VerifyTwoFactorUseCase) checkCode(ctx context.Context, twoFactor *model.TwoFactor, code string) (bool, error) {
+	if recoveryCode := normalizeRecoveryCode(code); recoveryCode != "" {
+		return uc.twoFactorRepo.UseRecoveryCode(ctx, twoFactor.UserID, HashToken(recoveryCode))
+	}
+
+	if uc.cipher == nil {
+		return false, errors.ErrTwoFactorUnavailable
+	}
+	secret, err := uc.cipher.Decrypt(twoFactor.Secret, twoFactorAssociatedData(twoFactor.UserID))
+	if err != nil {
+		return false, err
+	}
+	step, ok := uc.totp.Verify(secret, code, uc.now())
+	if !ok {
+		return false, nil
+	}
+	return uc.twoFactorRepo.UseStep(ctx, twoFactor.UserID, step)
+}
+
+// failed учитывает неверный код в challenge и в защите от подбора пароля.
+func (uc *VerifyTwoFactorUseCase) failed(ctx context.Context, challenge *model.TwoFactorChallenge, try *LoginTry) error {
+	attempts, err := uc.challengeRepo.RecordFailure(ctx, challenge.ID)
+	if err != nil {
+		return err
+	}
+	if attempts >= maxChallengeAttempts {
+		if _, err := uc.challengeRepo.Delete(ctx, challenge.ID); err != nil {
+			return err
+		}
+	}
+	if try != nil {
+		if err := uc.throttle.RecordFailure(ctx, try); err != nil {
+			return err
+		}
+	}
+	return errors.ErrInvalidTwoFactorCode
+}
+
+// release снимает попытку, прерванную не из-за неверного кода, и возвращает err.
+func (uc *VerifyTwoFactorUseCase) release(ctx context.Context, try *LoginTry, err error) error {
+	if try != nil {
+		if releaseErr := uc.throttle.Release(ctx, try); releaseErr != nil && err == nil {
+			return releaseErr
+		}
+	}
+	return err
+}
@@ -2,7 +2,11 @@ package bootstrap
 
 import (
 	"os"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
 )
 
 type Config struct {
@@ -10,6 +14,23 @@ type Config struct {
 	DatabaseURI string
 	JWTSecret   string
 	JWTExpiry   time.Duration
+	RefreshTTL  time.Duration
+	// JWTSigningKeyFile — PEM-файл закрытого ключа RSA или Ed25519. Если не задан,
+	// токены подписываются HS256 секретом JWTSecret.
+	JWTSigningKeyFile       string
+	JWTVerificationKeyFiles []string
+	LoginMaxFailures        int
+	LoginIPMaxFailures      int
+	LoginLockout            time.Duration
+	LoginFailureDelay       time.Duration
+	PasswordPolicy          service.PasswordPolicy
+	PasswordResetTTL        time.Duration
+	// PasswordResetNotifyFile — файл, куда пишутся токены сброса пароля; если не задан, токены пишутся в лог.
+	PasswordResetNotifyFile string
+	// TOTPEncryptionKey — ключ AES-256 в base64 для шифрования секретов TOTP; без него 2FA недоступна.
+	TOTPEncryptionKey     string
+	TOTPIssuer            string
+	TwoFactorChallengeTTL time.Duration
 }
 
 func ConfigLoad() *Config {
@@ -26,6 +47,32 @@ func ConfigLoad() *Config {
 	}
 	cfg.JWTExpiry = expiry
 
+	refreshTTL, err := time.ParseDuration(getEnv("REFRESH_TOKEN_TTL", "720h"))
+	if err != nil || refreshTTL <= 0 {
+		refreshTTL = 30 * 24 * time.Hour
+	}
+	cfg.RefreshTTL = refreshTTL
+
+	cfg.JWTSigningKeyFile = getEnv("JWT_SIGNING_KEY_FILE", "")
+	cfg.JWTVerificationKeyFiles = splitList(getEnv("JWT_VERIFICATION_KEY_FILES", ""))
+
+	cfg.LoginMaxFailures = getEnvInt("LOGIN_MAX_FAILURES", 5)
+	cfg.LoginIPMaxFailures = getEnvInt("LOGIN_IP_MAX_FAILURES", 20)
+	cfg.LoginLockout = getEnvDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute)
+	cfg.LoginFailureDelay = getEnvDuration("LOGIN_FAILURE_DELAY", time.Second)
+
+	cfg.PasswordPolicy = service.PasswordPolicy{
+		MinLength:    getEnvInt("PASSWORD_MIN_LENGTH", 8),
+		MinClasses:   getEnvInt("PASSWORD_MIN_CLASSES", 2),
+		RejectCommon: getEnv("PASSWORD_REJECT_COMMON", "true") != "false",
+	}
+	cfg.PasswordResetTTL = getEnvDuration("PASSWORD_RESET_TTL", 30*time.Minute)
+	cfg.PasswordResetNotifyFile = getEnv("PASSWORD_RESET_NOTIFY_FILE", "")
+
+	cfg.TOTPEncryptionKey = getEnv("TOTP_ENCRYPTION_KEY", "")
+	cfg.TOTPIssuer = getEnv("TOTP_ISSUER", "Gophermart")
+	cfg.TwoFactorChallengeTTL = getEnvDuration("TWO_FACTOR_CHALLENGE_TTL", 5*time.Minute)
+
 	return cfg
 }
 
@@ -35,3 +82,29 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -1,6 +1,11 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
 
 var (
 	ErrLoginRequired        = errors.New("login and password are required")
@@ -9,10 +14,76 @@ var (
 	ErrInvalidCredentials   = errors.New("invalid login or password")
 	ErrTokenRequired        = errors.New("token is required")
 	ErrInvalidToken         = errors.New("invalid token")
+	ErrTokenRevoked         = errors.New("token has been revoked")
+	ErrRefreshTokenRequired = errors.New("refresh token is required")
+	ErrInvalidRefreshToken  = errors.New("invalid refresh token")
+	ErrRefreshTokenReused   = errors.New("refresh token reuse detected")
+	ErrTooManyLoginAttempts = errors.New("too many login attempts")
+	ErrUnlockTargetRequired = errors.New("login or ip is required")
+	ErrWeakPassword         = errors.New("password does not meet policy")
+	ErrSamePassword         = errors.New("new password must differ from the current one")
+	ErrPasswordRequired     = errors.New("password is required")
+	ErrInvalidResetToken    = errors.New("invalid or expired password reset token")
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotStarted  = errors.New("two-factor setup has not been started")
+	ErrTwoFactorUnavailable = errors.New("two-factor authentication is not configured")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	ErrInvalidChallenge     = errors.New("invalid or expired two-factor challenge")
+	ErrUnknownRole          = errors.New("unknown role")
+	ErrInvalidProfile       = errors.New("invalid profile")
+	ErrEmailAlreadyExists   = errors.New("email already exists")
 )
 
+// PasswordPolicyError перечисляет требования политики паролей, которым пароль не соответствует.
+type PasswordPolicyError struct {
+	Violations []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrWeakPassword, strings.Join(e.Violations, "; "))
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrWeakPassword
+}
+
+// ProfileValidationError перечисляет поля профиля, не прошедшие проверку.
+type ProfileValidationError struct {
+	Violations []string
+}
+
+func (e *ProfileValidationError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrInvalidProfile, strings.Join(e.Violations, "; "))
+}
+
+func (e *ProfileValidationError) Unwrap() error {
+	return ErrInvalidProfile
+}
+
+// LoginLockedError — вход временно запрещён после серии неудачных попыток.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("%v, retry after %d seconds", ErrTooManyLoginAttempts, RetryAfterSeconds(e.RetryAfter))
+}
+
+func (e *LoginLockedError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
+
+// RetryAfterSeconds округляет паузу вверх до целых секунд для заголовка Retry-After.
+func RetryAfterSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
 func Is(err, target error) bool {
 	return errors.Is(err, target)
 }
 
+func As(err error, target any) bool {
+	return errors.As(err, target)
+}
+
 
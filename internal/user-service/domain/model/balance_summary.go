@@ -0,0 +1,8 @@
+package model
+
+// BalanceSummary — баланс пользователя в системе лояльности. Суммы — десятичные строки
+// с двумя знаками после точки, чтобы не терять точность при передаче между сервисами.
+type BalanceSummary struct {
+	Current   string
+	Withdrawn string
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// LoginAttemptScope — по какому признаку считаются неудачные попытки входа.
+type LoginAttemptScope string
+
+const (
+	LoginAttemptScopeLogin LoginAttemptScope = "LOGIN"
+	LoginAttemptScopeIP    LoginAttemptScope = "IP"
+)
+
+// LoginAttempt — счётчик неудачных попыток входа для логина или IP-адреса клиента.
+type LoginAttempt struct {
+	Scope         LoginAttemptScope
+	Subject       string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+}
+
+// RetryAfter возвращает, сколько осталось ждать до следующей попытки; ноль — вход разрешён.
+func (a *LoginAttempt) RetryAfter(now time.Time) time.Duration {
+	if a.LockedUntil == nil || !now.Before(*a.LockedUntil) {
+		return 0
+	}
+	return a.LockedUntil.Sub(now)
+}
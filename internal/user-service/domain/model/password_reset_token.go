@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// PasswordResetToken — одноразовый токен сброса пароля. В БД хранится только хэш токена.
+type PasswordResetToken struct {
+	ID        int64
+	UserID    int64
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
+
+func (t *PasswordResetToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
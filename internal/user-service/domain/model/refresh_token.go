@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// RefreshToken — непрозрачный токен обновления. В БД хранится только хэш токена.
+// Токены, выпущенные друг из друга при ротации, образуют семейство с общим FamilyID.
+type RefreshToken struct {
+	ID        int64
+	UserID    int64
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+}
+
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,36 @@
+package model
+
+// Role определяет, к каким группам маршрутов у пользователя есть доступ.
+// Каждый пользователь имеет роль user; support и admin выдаются отдельно.
+type Role string
+
+const (
+	RoleUser    Role = "user"
+	RoleSupport Role = "support"
+	RoleAdmin   Role = "admin"
+)
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleUser, RoleSupport, RoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+func RoleStrings(roles []Role) []string {
+	values := make([]string, 0, len(roles))
+	for _, role := range roles {
+		values = append(values, string(role))
+	}
+	return values
+}
+
+func ParseRoles(values []string) []Role {
+	roles := make([]Role, 0, len(values))
+	for _, value := range values {
+		roles = append(roles, Role(value))
+	}
+	return roles
+}
@@ -1,10 +1,19 @@
 package model
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenTimePrecision — точность времени в claims токена. iat сравнивается с моментом отзыва
+// всех сессий пользователя, и при секундной точности токен, выпущенный в ту же секунду
+// до отзыва, оставался бы действительным. Микросекунды — точность TIMESTAMP в PostgreSQL.
+const TokenTimePrecision = time.Microsecond
 
 type Claims struct {
 	UserID int64
 	Login  string
+	Roles  []Role `json:",omitempty"`
 	jwt.RegisteredClaims
 }
-
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// TwoFactor — настройки TOTP пользователя из таблицы users. Secret зашифрован;
+// LastStep — шаг последнего принятого кода, чтобы код нельзя было использовать повторно.
+type TwoFactor struct {
+	UserID   int64
+	Secret   []byte
+	Enabled  bool
+	LastStep int64
+}
+
+// TwoFactorChallenge выдаётся после проверки пароля, если у пользователя включена 2FA,
+// и обменивается вместе с кодом на токены. В БД хранится только хэш токена.
+type TwoFactorChallenge struct {
+	ID        int64
+	UserID    int64
+	TokenHash string
+	Attempts  int
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+func (c *TwoFactorChallenge) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
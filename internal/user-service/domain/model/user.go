@@ -8,6 +8,8 @@ type User struct {
 	PasswordHash string
 	FirstName    string
 	LastName     string
+	Email        string
+	Roles        []Role
 	CreatedAt    time.Time
 }
 
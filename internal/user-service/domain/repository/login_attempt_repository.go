@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+)
+
+type LoginAttemptRepository interface {
+	// Find возвращает счётчик попыток; если неудачных попыток не было, счётчик пустой.
+	Find(ctx context.Context, scope model.LoginAttemptScope, subject string) (*model.LoginAttempt, error)
+	// RecordAttempt атомарно увеличивает счётчик и возвращает его новое значение. Если предыдущая
+	// попытка была раньше resetBefore, счётчик начинается заново. Пока субъект заблокирован,
+	// счётчик не меняется, а возвращается текущая блокировка.
+	RecordAttempt(ctx context.Context, scope model.LoginAttemptScope, subject string, now, resetBefore time.Time) (*model.LoginAttempt, error)
+	// Release снимает со счётчика попытку, учтённую RecordAttempt, но не оказавшуюся неудачной.
+	Release(ctx context.Context, scope model.LoginAttemptScope, subject string) error
+	Lock(ctx context.Context, scope model.LoginAttemptScope, subject string, until time.Time) error
+	Reset(ctx context.Context, scope model.LoginAttemptScope, subject string) error
+}
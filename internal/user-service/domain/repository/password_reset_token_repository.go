@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+)
+
+type PasswordResetTokenRepository interface {
+	// Create сохраняет токен и отменяет ранее выданные неиспользованные токены пользователя.
+	Create(ctx context.Context, token *model.PasswordResetToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*model.PasswordResetToken, error)
+	// Redeem в одной транзакции помечает токен использованным и устанавливает владельцу
+	// новый хеш пароля; false — токен уже использован или истёк, пароль не меняется.
+	Redeem(ctx context.Context, id int64, passwordHash string, now time.Time) (bool, error)
+}
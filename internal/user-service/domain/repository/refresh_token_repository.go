@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *model.RefreshToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
+	// MarkRotated помечает токен использованным. Возвращает false, если токен уже был
+	// использован или отозван, — так повторное использование обнаруживается атомарно.
+	MarkRotated(ctx context.Context, id int64) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID int64) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// RevokedTokenRepository хранит отозванные access-токены до истечения их срока действия.
+type RevokedTokenRepository interface {
+	// Revoke отзывает токен с идентификатором jti. Запись хранится до expiresAt,
+	// после этого токен отклоняется по сроку действия.
+	Revoke(ctx context.Context, jti string, userID int64, expiresAt time.Time) error
+	// RevokeAllForUser отзывает все токены пользователя, выпущенные до before.
+	RevokeAllForUser(ctx context.Context, userID int64, before time.Time) error
+	IsRevoked(ctx context.Context, jti string, userID int64, issuedAt time.Time) (bool, error)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+)
+
+type TwoFactorRepository interface {
+	Find(ctx context.Context, userID int64) (*model.TwoFactor, error)
+	// SetPendingSecret сохраняет новый секрет, пока 2FA не включена; прежний незавершённый секрет заменяется.
+	SetPendingSecret(ctx context.Context, userID int64, secret []byte) error
+	// Enable включает 2FA, если сохранённый секрет всё ещё равен secret, по которому проверен первый код.
+	Enable(ctx context.Context, userID int64, secret []byte, recoveryCodeHashes []string, step int64) error
+	// UseStep запоминает шаг принятого кода; false — код этого или более позднего шага уже использован.
+	UseStep(ctx context.Context, userID int64, step int64) (bool, error)
+	// UseRecoveryCode удаляет резервный код; false — такого кода нет.
+	UseRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error)
+}
+
+type TwoFactorChallengeRepository interface {
+	// Create сохраняет challenge и удаляет истёкшие.
+	Create(ctx context.Context, challenge *model.TwoFactorChallenge) error
+	FindByHash(ctx context.Context, tokenHash string) (*model.TwoFactorChallenge, error)
+	// RecordFailure увеличивает число неверных кодов и возвращает его.
+	RecordFailure(ctx context.Context, id int64) (int, error)
+	// Delete удаляет challenge; false — он уже использован параллельным запросом.
+	Delete(ctx context.Context, id int64) (bool, error)
+}
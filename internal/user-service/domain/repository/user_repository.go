@@ -8,7 +8,16 @@ import (
 type UserRepository interface {
 	Create(ctx context.Context, user *model.User) error
 	FindByLogin(ctx context.Context, login string) (*model.User, error)
+	FindByID(ctx context.Context, id int64) (*model.User, error)
 	ExistsByLogin(ctx context.Context, login string) (bool, error)
+	UpdatePassword(ctx context.Context, userID int64, passwordHash string) error
+	// Update сохраняет имя, фамилию и email пользователя.
+	Update(ctx context.Context, user *model.User) error
+	// Anonymize удаляет персональные данные пользователя, оставляя строку для финансовых записей.
+	// После этого пользователь не находится ни по логину, ни по идентификатору.
+	Anonymize(ctx context.Context, userID int64) error
+	// AddRole выдаёт роль; повторная выдача не меняет список ролей.
+	AddRole(ctx context.Context, userID int64, role model.Role) error
 }
 
 
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+)
+
+// BalanceProvider возвращает баланс пользователя из системы лояльности gophermart.
+type BalanceProvider interface {
+	GetBalanceSummary(ctx context.Context, userID int64) (*model.BalanceSummary, error)
+}
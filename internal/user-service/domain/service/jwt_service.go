@@ -3,8 +3,12 @@ package service
 import "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
 
 type JWTService interface {
-	GenerateToken(userID int64, login string) (string, error)
+	GenerateToken(userID int64, login string, roles []model.Role) (string, error)
 	ValidateToken(tokenString string) (*model.Claims, error)
 }
 
-
+// PublicKeyProvider отдаёт открытые ключи, которыми сторонние сервисы
+// проверяют подпись токенов без доступа к секрету.
+type PublicKeyProvider interface {
+	PublicKeys() model.JWKSet
+}
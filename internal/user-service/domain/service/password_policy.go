@@ -0,0 +1,93 @@
+package service
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+)
+
+// bcrypt учитывает только первые 72 байта пароля, более длинные пароли отклоняются.
+const maxPasswordBytes = 72
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var commonPasswords = parseCommonPasswords(commonPasswordsFile)
+
+// PasswordPolicy — требования к паролю. Нулевое значение принимает любой непустой пароль.
+type PasswordPolicy struct {
+	MinLength int
+	// MinClasses — сколько классов символов (строчные, прописные, цифры, прочие) должно быть в пароле.
+	MinClasses   int
+	RejectCommon bool
+}
+
+// Validate возвращает *errors.PasswordPolicyError со списком нарушенных требований.
+func (p PasswordPolicy) Validate(password, login string) error {
+	if password == "" {
+		return errors.ErrPasswordRequired
+	}
+
+	var violations []string
+	if utf8.RuneCountInString(password) < p.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters long", p.MinLength))
+	}
+	if len(password) > maxPasswordBytes {
+		violations = append(violations, fmt.Sprintf("must be at most %d bytes long", maxPasswordBytes))
+	}
+	if classes := characterClasses(password); classes < p.MinClasses {
+		violations = append(violations, fmt.Sprintf(
+			"must contain at least %d of: lowercase letters, uppercase letters, digits, symbols", p.MinClasses))
+	}
+	if p.RejectCommon && commonPasswords[strings.ToLower(password)] {
+		violations = append(violations, "is too common")
+	}
+	if login != "" && strings.EqualFold(password, login) {
+		violations = append(violations, "must not match the login")
+	}
+
+	if len(violations) > 0 {
+		return &errors.PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	classes := 0
+	for _, present := range []bool{lower, upper, digit, other} {
+		if present {
+			classes++
+		}
+	}
+	return classes
+}
+
+func parseCommonPasswords(data string) map[string]bool {
+	passwords := make(map[string]bool)
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords[strings.ToLower(line)] = true
+	}
+	return passwords
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	policy := PasswordPolicy{MinLength: 8, MinClasses: 3, RejectCommon: true}
+
+	tests := []struct {
+		name      string
+		password  string
+		login     string
+		violation string
+	}{
+		{"strong", "Correct-horse-7", "user", ""},
+		{"unicode counted in characters", "Пароль-надёжный1", "user", ""},
+		{"too short", "Ab1!", "user", "at least 8 characters"},
+		{"too few classes", "onlylowercaseletters", "user", "at least 3 of"},
+		{"common", "P@ssw0rd", "user", "too common"},
+		{"matches login", "User.Name-1", "user.name-1", "must not match the login"},
+		{"longer than bcrypt limit", "Aa1!" + strings.Repeat("x", 70), "user", "at most 72 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.password, tt.login)
+			if tt.violation == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			var policyErr *domainerrors.PasswordPolicyError
+			if !errors.As(err, &policyErr) || !errors.Is(err, domainerrors.ErrWeakPassword) {
+				t.Fatalf("Validate() error = %v, want %v", err, domainerrors.ErrWeakPassword)
+			}
+			if !strings.Contains(err.Error(), tt.violation) {
+				t.Errorf("Validate() error = %q, want it to mention %q", err, tt.violation)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicy_ZeroValue(t *testing.T) {
+	var policy PasswordPolicy
+
+	if err := policy.Validate("123456", "user"); err != nil {
+		t.Errorf("Validate() error = %v, zero policy must accept any non-empty password", err)
+	}
+	if err := policy.Validate("", "user"); !errors.Is(err, domainerrors.ErrPasswordRequired) {
+		t.Errorf("Validate() error = %v, want %v", err, domainerrors.ErrPasswordRequired)
+	}
+}
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+)
+
+// PasswordResetNotifier доставляет пользователю токен сброса пароля.
+type PasswordResetNotifier interface {
+	SendPasswordReset(ctx context.Context, user *model.User, token string, expiresAt time.Time) error
+}
@@ -0,0 +1,8 @@
+package service
+
+// SecretCipher шифрует секреты для хранения в БД. associatedData привязывает
+// шифртекст к владельцу: секрет, скопированный в чужую запись, не расшифруется.
+type SecretCipher interface {
+	Encrypt(plaintext, associatedData []byte) ([]byte, error)
+	Decrypt(ciphertext, associatedData []byte) ([]byte, error)
+}
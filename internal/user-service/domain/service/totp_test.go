@@ -0,0 +1,56 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTOTP_Code(t *testing.T) {
+	// Тестовые векторы RFC 6238 (SHA1), младшие 6 цифр.
+	secret := []byte("12345678901234567890")
+	totp := TOTP{}
+
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, totp.Code(secret, totp.Step(time.Unix(tt.unix, 0))), "time %d", tt.unix)
+	}
+}
+
+func TestTOTP_Verify(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	now := time.Unix(1111111111, 0)
+	totp := TOTP{Skew: 1}
+	current := totp.Step(now)
+
+	step, ok := totp.Verify(secret, totp.Code(secret, current-1), now)
+	assert.True(t, ok, "code of the previous step is accepted")
+	assert.Equal(t, current-1, step)
+
+	_, ok = totp.Verify(secret, totp.Code(secret, current-2), now)
+	assert.False(t, ok, "code outside of skew is rejected")
+
+	_, ok = totp.Verify(secret, "12345", now)
+	assert.False(t, ok)
+}
+
+func TestTOTP_URI(t *testing.T) {
+	uri := TOTP{Issuer: "Gophermart"}.URI("user@example", []byte("12345678901234567890"))
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Gophermart:user@example?"), uri)
+	assert.Contains(t, uri, "secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+	assert.Contains(t, uri, "issuer=Gophermart")
+	assert.Contains(t, uri, "digits=6")
+	assert.Contains(t, uri, "period=30")
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+)
+
+type loginAttemptRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewLoginAttemptRepository(pool *pgxpool.Pool) repository.LoginAttemptRepository {
+	return &loginAttemptRepository{pool: pool}
+}
+
+func (r *loginAttemptRepository) Find(ctx context.Context, scope model.LoginAttemptScope, subject string) (*model.LoginAttempt, error) {
+	query := `SELECT failures, last_failure_at, locked_until FROM login_attempts
+	          WHERE scope = $1 AND subject = $2`
+	attempt := &model.LoginAttempt{Scope: scope, Subject: subject}
+	err := r.pool.QueryRow(ctx, query, scope, subject).Scan(
+		&attempt.Failures,
+		&attempt.LastFailureAt,
+		&attempt.LockedUntil,
+	)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+func (r *loginAttemptRepository) RecordAttempt(
+	ctx context.Context,
+	scope model.LoginAttemptScope,
+	subject string,
+	now, resetBefore time.Time,
+) (*model.LoginAttempt, error) {
+	query := `INSERT INTO login_attempts (scope, subject, failures, last_failure_at)
+	          VALUES ($1, $2, 1, $3)
+	          ON CONFLICT (scope, subject) DO UPDATE SET
+	              failures = CASE WHEN login_attempts.last_failure_at < $4 THEN 1
+	                              ELSE login_attempts.failures + 1 END,
+	              last_failure_at = EXCLUDED.last_failure_at
+	          WHERE login_attempts.locked_until IS NULL OR login_attempts.locked_until <= $3
+	          RETURNING failures, last_failure_at, locked_until`
+	attempt := &model.LoginAttempt{Scope: scope, Subject: subject}
+	err := r.pool.QueryRow(ctx, query, scope, subject, now, resetBefore).Scan(
+		&attempt.Failures,
+		&attempt.LastFailureAt,
+		&attempt.LockedUntil,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Строка не обновилась: субъект заблокирован.
+		return r.Find(ctx, scope, subject)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Счётчики, которые уже сбросились бы при следующей попытке, хранить не нужно.
+	_, err = r.pool.Exec(ctx,
+		`DELETE FROM login_attempts
+		 WHERE last_failure_at < $1 AND (locked_until IS NULL OR locked_until < $2)`,
+		resetBefore, now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+func (r *loginAttemptRepository) Release(ctx context.Context, scope model.LoginAttemptScope, subject string) error {
+	query := `UPDATE login_attempts SET failures = failures - 1
+	          WHERE scope = $1 AND subject = $2 AND failures > 0`
+	_, err := r.pool.Exec(ctx, query, scope, subject)
+	return err
+}
+
+func (r *loginAttemptRepository) Lock(ctx context.Context, scope model.LoginAttemptScope, subject string, until time.Time) error {
+	query := `UPDATE login_attempts SET locked_until = GREATEST(COALESCE(locked_until, $3), $3)
+	          WHERE scope = $1 AND subject = $2`
+	_, err := r.pool.Exec(ctx, query, scope, subject, until)
+	return err
+}
+
+func (r *loginAttemptRepository) Reset(ctx context.Context, scope model.LoginAttemptScope, subject string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM login_attempts WHERE scope = $1 AND subject = $2`, scope, subject)
+	return err
+}
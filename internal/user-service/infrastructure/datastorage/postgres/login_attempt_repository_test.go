@@ -0,0 +1,135 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/infrastructure/datastorage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginAttemptRepository(t *testing.T) {
+	repo := postgres.NewLoginAttemptRepository(testPool)
+	ctx := context.Background()
+	scope := model.LoginAttemptScopeLogin
+
+	t.Run("unknown_subject_has_no_failures", func(t *testing.T) {
+		setupTestDB(t)
+
+		attempt, err := repo.Find(ctx, scope, "user")
+		require.NoError(t, err)
+		assert.Zero(t, attempt.Failures)
+		assert.Nil(t, attempt.LockedUntil)
+	})
+
+	t.Run("failures_accumulate_within_window", func(t *testing.T) {
+		setupTestDB(t)
+		now := time.Now().Truncate(time.Microsecond)
+
+		for i := 1; i <= 3; i++ {
+			attempt, err := repo.RecordAttempt(ctx, scope, "user", now, now.Add(-time.Hour))
+			require.NoError(t, err)
+			assert.Equal(t, i, attempt.Failures)
+		}
+
+		other, err := repo.RecordAttempt(ctx, model.LoginAttemptScopeIP, "user", now, now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, other.Failures, "scopes are counted separately")
+	})
+
+	t.Run("counter_restarts_after_window", func(t *testing.T) {
+		setupTestDB(t)
+		start := time.Now().Add(-2 * time.Hour)
+
+		_, err := repo.RecordAttempt(ctx, scope, "user", start, start.Add(-time.Hour))
+		require.NoError(t, err)
+		_, err = repo.RecordAttempt(ctx, scope, "user", start, start.Add(-time.Hour))
+		require.NoError(t, err)
+
+		now := time.Now()
+		attempt, err := repo.RecordAttempt(ctx, scope, "user", now, now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, attempt.Failures)
+	})
+
+	t.Run("lock_keeps_latest_deadline_and_reset_clears_it", func(t *testing.T) {
+		setupTestDB(t)
+		now := time.Now()
+		_, err := repo.RecordAttempt(ctx, scope, "user", now, now.Add(-time.Hour))
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Lock(ctx, scope, "user", now.Add(15*time.Minute)))
+		require.NoError(t, repo.Lock(ctx, scope, "user", now.Add(time.Second)))
+
+		attempt, err := repo.Find(ctx, scope, "user")
+		require.NoError(t, err)
+		require.NotNil(t, attempt.LockedUntil)
+		assert.WithinDuration(t, now.Add(15*time.Minute), *attempt.LockedUntil, time.Second)
+
+		require.NoError(t, repo.Reset(ctx, scope, "user"))
+		attempt, err = repo.Find(ctx, scope, "user")
+		require.NoError(t, err)
+		assert.Zero(t, attempt.Failures)
+		assert.Nil(t, attempt.LockedUntil)
+	})
+	t.Run("locked_subject_is_not_counted", func(t *testing.T) {
+		setupTestDB(t)
+		now := time.Now().Truncate(time.Microsecond)
+		_, err := repo.RecordAttempt(ctx, scope, "user", now, now.Add(-time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, repo.Lock(ctx, scope, "user", now.Add(time.Minute)))
+
+		attempt, err := repo.RecordAttempt(ctx, scope, "user", now, now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, attempt.Failures)
+		require.NotNil(t, attempt.LockedUntil)
+		assert.Positive(t, attempt.RetryAfter(now))
+	})
+
+	t.Run("release_takes_back_one_attempt", func(t *testing.T) {
+		setupTestDB(t)
+		now := time.Now()
+		for i := 0; i < 2; i++ {
+			_, err := repo.RecordAttempt(ctx, scope, "user", now, now.Add(-time.Hour))
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, repo.Release(ctx, scope, "user"))
+		attempt, err := repo.Find(ctx, scope, "user")
+		require.NoError(t, err)
+		assert.Equal(t, 1, attempt.Failures)
+	})
+
+	t.Run("parallel_attempts_get_distinct_counts", func(t *testing.T) {
+		setupTestDB(t)
+		now := time.Now()
+		const parallel = 10
+
+		counts := make(chan int, parallel)
+		var wg sync.WaitGroup
+		for i := 0; i < parallel; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				attempt, err := repo.RecordAttempt(ctx, scope, "user", now, now.Add(-time.Hour))
+				if assert.NoError(t, err) {
+					counts <- attempt.Failures
+				}
+			}()
+		}
+		wg.Wait()
+		close(counts)
+
+		seen := make(map[int]bool)
+		for count := range counts {
+			seen[count] = true
+		}
+		assert.Len(t, seen, parallel)
+	})
+}
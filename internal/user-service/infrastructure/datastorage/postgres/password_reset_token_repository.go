@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+)
+
+type passwordResetTokenRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPasswordResetTokenRepository(pool *pgxpool.Pool) repository.PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{pool: pool}
+}
+
+func (r *passwordResetTokenRepository) Create(ctx context.Context, token *model.PasswordResetToken) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Действует только последняя выданная ссылка; старые токены и истёкшие записи удаляются.
+	_, err = tx.Exec(ctx,
+		`DELETE FROM password_reset_tokens WHERE user_id = $1 OR expires_at < $2`,
+		token.UserID, token.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO password_reset_tokens (user_id, token_hash, expires_at, created_at)
+	          VALUES ($1, $2, $3, $4) RETURNING id`
+	err = tx.QueryRow(ctx, query, token.UserID, token.TokenHash, token.ExpiresAt, token.CreatedAt).Scan(&token.ID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *passwordResetTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*model.PasswordResetToken, error) {
+	query := `SELECT id, user_id, token_hash, expires_at, created_at, used_at
+	          FROM password_reset_tokens WHERE token_hash = $1`
+	token := &model.PasswordResetToken{}
+	err := r.pool.QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&token.UsedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainerrors.ErrInvalidResetToken
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+func (r *passwordResetTokenRepository) Redeem(ctx context.Context, id int64, passwordHash string, now time.Time) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Ссылка считается использованной, только если пароль действительно сменился.
+	var userID int64
+	err = tx.QueryRow(ctx,
+		`UPDATE password_reset_tokens SET used_at = $2
+		 WHERE id = $1 AND used_at IS NULL AND expires_at > $2
+		 RETURNING user_id`,
+		id, now,
+	).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE users SET password_hash = $1 WHERE id = $2`, passwordHash, userID)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, domainerrors.ErrUserNotFound
+	}
+	return true, tx.Commit(ctx)
+}
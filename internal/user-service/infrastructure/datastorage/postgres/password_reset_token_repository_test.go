@@ -0,0 +1,86 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/infrastructure/datastorage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordResetTokenRepository(t *testing.T) {
+	repo := postgres.NewPasswordResetTokenRepository(testPool)
+	ctx := context.Background()
+
+	newToken := func(userID int64, hash string, now time.Time) *model.PasswordResetToken {
+		return &model.PasswordResetToken{UserID: userID, TokenHash: hash, ExpiresAt: now.Add(30 * time.Minute), CreatedAt: now}
+	}
+
+	t.Run("token_can_be_used_once", func(t *testing.T) {
+		setupTestDB(t)
+		user := createTestUser(t)
+		now := time.Now()
+
+		token := newToken(user.ID, "hash-1", now)
+		require.NoError(t, repo.Create(ctx, token))
+		assert.NotZero(t, token.ID)
+
+		found, err := repo.FindByHash(ctx, "hash-1")
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, found.UserID)
+		assert.Nil(t, found.UsedAt)
+
+		used, err := repo.Redeem(ctx, token.ID, "new-hash", now)
+		require.NoError(t, err)
+		assert.True(t, used)
+
+		updated, err := postgres.NewUserRepository(testPool).FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "new-hash", updated.PasswordHash)
+
+		used, err = repo.Redeem(ctx, token.ID, "other-hash", now)
+		require.NoError(t, err)
+		assert.False(t, used)
+
+		updated, err = postgres.NewUserRepository(testPool).FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "new-hash", updated.PasswordHash)
+	})
+
+	t.Run("new_token_replaces_previous_one", func(t *testing.T) {
+		setupTestDB(t)
+		user := createTestUser(t)
+		now := time.Now()
+
+		require.NoError(t, repo.Create(ctx, newToken(user.ID, "old", now)))
+		require.NoError(t, repo.Create(ctx, newToken(user.ID, "new", now)))
+
+		_, err := repo.FindByHash(ctx, "old")
+		assert.ErrorIs(t, err, domainerrors.ErrInvalidResetToken)
+		_, err = repo.FindByHash(ctx, "new")
+		assert.NoError(t, err)
+	})
+
+	t.Run("expired_token_is_not_consumed", func(t *testing.T) {
+		setupTestDB(t)
+		user := createTestUser(t)
+		now := time.Now()
+
+		token := newToken(user.ID, "hash-1", now)
+		require.NoError(t, repo.Create(ctx, token))
+
+		used, err := repo.Redeem(ctx, token.ID, "new-hash", token.ExpiresAt)
+		require.NoError(t, err)
+		assert.False(t, used)
+
+		unchanged, err := postgres.NewUserRepository(testPool).FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "hash", unchanged.PasswordHash)
+	})
+}
@@ -103,6 +103,11 @@ func cleanupDB(t *testing.T, pool *pgxpool.Pool) {
 		t.Logf("failed to truncate users: %v", err)
 	}
 
+	_, err = pool.Exec(ctx, "TRUNCATE TABLE revoked_tokens, login_attempts")
+	if err != nil {
+		t.Logf("failed to truncate token and login tables: %v", err)
+	}
+
 	_, err = pool.Exec(ctx, "ALTER SEQUENCE users_id_seq RESTART WITH 1")
 	if err != nil {
 		t.Logf("failed to reset sequence users_id_seq: %v", err)
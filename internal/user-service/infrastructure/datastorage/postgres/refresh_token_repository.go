@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+)
+
+type refreshTokenRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRefreshTokenRepository(pool *pgxpool.Pool) repository.RefreshTokenRepository {
+	return &refreshTokenRepository{pool: pool}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *model.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at, created_at)
+	          VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	return r.pool.QueryRow(ctx, query,
+		token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt, token.CreatedAt,
+	).Scan(&token.ID)
+}
+
+func (r *refreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	query := `SELECT id, user_id, family_id, token_hash, expires_at, created_at, rotated_at, revoked_at
+	          FROM refresh_tokens WHERE token_hash = $1`
+	token := &model.RefreshToken{}
+	err := r.pool.QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&token.RotatedAt,
+		&token.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainerrors.ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+func (r *refreshTokenRepository) MarkRotated(ctx context.Context, id int64) (bool, error) {
+	query := `UPDATE refresh_tokens SET rotated_at = NOW()
+	          WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL`
+	tag, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`
+	_, err := r.pool.Exec(ctx, query, familyID)
+	return err
+}
+
+func (r *refreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int64) error {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`
+	_, err := r.pool.Exec(ctx, query, userID)
+	return err
+}
@@ -0,0 +1,97 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/infrastructure/datastorage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestUser(t *testing.T) *model.User {
+	t.Helper()
+	user := &model.User{Login: "refresh_user", PasswordHash: "hash", CreatedAt: time.Now()}
+	require.NoError(t, postgres.NewUserRepository(testPool).Create(context.Background(), user))
+	return user
+}
+
+func TestRefreshTokenRepository(t *testing.T) {
+	repo := postgres.NewRefreshTokenRepository(testPool)
+	ctx := context.Background()
+
+	t.Run("creates_and_finds_token_by_hash", func(t *testing.T) {
+		setupTestDB(t)
+		user := createTestUser(t)
+		token := &model.RefreshToken{
+			UserID:    user.ID,
+			FamilyID:  "family",
+			TokenHash: "hash-1",
+			ExpiresAt: time.Now().Add(time.Hour),
+			CreatedAt: time.Now(),
+		}
+
+		require.NoError(t, repo.Create(ctx, token))
+		assert.NotZero(t, token.ID)
+
+		found, err := repo.FindByHash(ctx, "hash-1")
+		require.NoError(t, err)
+		assert.Equal(t, token.ID, found.ID)
+		assert.Equal(t, "family", found.FamilyID)
+		assert.Nil(t, found.RotatedAt)
+		assert.Nil(t, found.RevokedAt)
+	})
+
+	t.Run("returns_error_for_unknown_hash", func(t *testing.T) {
+		setupTestDB(t)
+
+		_, err := repo.FindByHash(ctx, "missing")
+		assert.ErrorIs(t, err, errors.ErrInvalidRefreshToken)
+	})
+
+	t.Run("token_can_be_rotated_once", func(t *testing.T) {
+		setupTestDB(t)
+		user := createTestUser(t)
+		token := &model.RefreshToken{UserID: user.ID, FamilyID: "family", TokenHash: "hash-1", ExpiresAt: time.Now().Add(time.Hour), CreatedAt: time.Now()}
+		require.NoError(t, repo.Create(ctx, token))
+
+		rotated, err := repo.MarkRotated(ctx, token.ID)
+		require.NoError(t, err)
+		assert.True(t, rotated)
+
+		rotated, err = repo.MarkRotated(ctx, token.ID)
+		require.NoError(t, err)
+		assert.False(t, rotated)
+	})
+
+	t.Run("revoke_family_revokes_all_tokens_in_family", func(t *testing.T) {
+		setupTestDB(t)
+		user := createTestUser(t)
+		for _, tok := range []*model.RefreshToken{
+			{UserID: user.ID, FamilyID: "family", TokenHash: "hash-1", ExpiresAt: time.Now().Add(time.Hour), CreatedAt: time.Now()},
+			{UserID: user.ID, FamilyID: "family", TokenHash: "hash-2", ExpiresAt: time.Now().Add(time.Hour), CreatedAt: time.Now()},
+			{UserID: user.ID, FamilyID: "other", TokenHash: "hash-3", ExpiresAt: time.Now().Add(time.Hour), CreatedAt: time.Now()},
+		} {
+			require.NoError(t, repo.Create(ctx, tok))
+		}
+
+		require.NoError(t, repo.RevokeFamily(ctx, "family"))
+
+		for hash, wantRevoked := range map[string]bool{"hash-1": true, "hash-2": true, "hash-3": false} {
+			found, err := repo.FindByHash(ctx, hash)
+			require.NoError(t, err)
+			assert.Equal(t, wantRevoked, found.RevokedAt != nil, hash)
+		}
+
+		second, err := repo.FindByHash(ctx, "hash-2")
+		require.NoError(t, err)
+		rotated, err := repo.MarkRotated(ctx, second.ID)
+		require.NoError(t, err)
+		assert.False(t, rotated)
+	})
+}
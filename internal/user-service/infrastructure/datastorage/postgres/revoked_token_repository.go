@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+)
+
+type revokedTokenRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRevokedTokenRepository(pool *pgxpool.Pool) repository.RevokedTokenRepository {
+	return &revokedTokenRepository{pool: pool}
+}
+
+func (r *revokedTokenRepository) Revoke(ctx context.Context, jti string, userID int64, expiresAt time.Time) error {
+	query := `INSERT INTO revoked_tokens (jti, user_id, expires_at) VALUES ($1, $2, $3)
+	          ON CONFLICT (jti) DO NOTHING`
+	if _, err := r.pool.Exec(ctx, query, jti, userID, expiresAt); err != nil {
+		return err
+	}
+
+	// Истёкшие токены отклоняются по сроку действия, хранить их больше не нужно.
+	_, err := r.pool.Exec(ctx, `DELETE FROM revoked_tokens WHERE expires_at < $1`, time.Now())
+	return err
+}
+
+func (r *revokedTokenRepository) RevokeAllForUser(ctx context.Context, userID int64, before time.Time) error {
+	query := `INSERT INTO user_token_revocations (user_id, revoked_before) VALUES ($1, $2)
+	          ON CONFLICT (user_id) DO UPDATE
+	          SET revoked_before = GREATEST(user_token_revocations.revoked_before, EXCLUDED.revoked_before)`
+	_, err := r.pool.Exec(ctx, query, userID, before)
+	return err
+}
+
+func (r *revokedTokenRepository) IsRevoked(ctx context.Context, jti string, userID int64, issuedAt time.Time) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1)
+	          OR EXISTS(SELECT 1 FROM user_token_revocations WHERE user_id = $2 AND revoked_before > $3)`
+	var revoked bool
+	if err := r.pool.QueryRow(ctx, query, jti, userID, issuedAt).Scan(&revoked); err != nil {
+		return false, err
+	}
+	return revoked, nil
+}
@@ -0,0 +1,79 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/infrastructure/datastorage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokedTokenRepository(t *testing.T) {
+	repo := postgres.NewRevokedTokenRepository(testPool)
+	ctx := context.Background()
+
+	t.Run("revoked_jti_is_reported_as_revoked", func(t *testing.T) {
+		setupTestDB(t)
+		user := createTestUser(t)
+		issuedAt := time.Now().Add(-time.Minute)
+
+		require.NoError(t, repo.Revoke(ctx, "jti-1", user.ID, time.Now().Add(time.Hour)))
+		require.NoError(t, repo.Revoke(ctx, "jti-1", user.ID, time.Now().Add(time.Hour)))
+
+		revoked, err := repo.IsRevoked(ctx, "jti-1", user.ID, issuedAt)
+		require.NoError(t, err)
+		assert.True(t, revoked)
+
+		revoked, err = repo.IsRevoked(ctx, "jti-2", user.ID, issuedAt)
+		require.NoError(t, err)
+		assert.False(t, revoked)
+	})
+
+	t.Run("expired_revocations_are_purged", func(t *testing.T) {
+		setupTestDB(t)
+		user := createTestUser(t)
+
+		require.NoError(t, repo.Revoke(ctx, "expired", user.ID, time.Now().Add(-time.Hour)))
+		require.NoError(t, repo.Revoke(ctx, "active", user.ID, time.Now().Add(time.Hour)))
+
+		var count int
+		require.NoError(t, testPool.QueryRow(ctx, "SELECT COUNT(*) FROM revoked_tokens").Scan(&count))
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("revoke_all_rejects_tokens_issued_before_cutoff", func(t *testing.T) {
+		setupTestDB(t)
+		user := createTestUser(t)
+		cutoff := time.Now().Truncate(time.Second)
+
+		require.NoError(t, repo.RevokeAllForUser(ctx, user.ID, cutoff))
+		require.NoError(t, repo.RevokeAllForUser(ctx, user.ID, cutoff.Add(-time.Hour)))
+
+		revoked, err := repo.IsRevoked(ctx, "old", user.ID, cutoff.Add(-time.Minute))
+		require.NoError(t, err)
+		assert.True(t, revoked)
+
+		revoked, err = repo.IsRevoked(ctx, "new", user.ID, cutoff)
+		require.NoError(t, err)
+		assert.False(t, revoked)
+	})
+	t.Run("revoke_all_rejects_token_issued_earlier_in_the_same_second", func(t *testing.T) {
+		setupTestDB(t)
+		user := createTestUser(t)
+		cutoff := time.Date(2024, 1, 1, 12, 0, 30, 500_000_000, time.UTC)
+
+		require.NoError(t, repo.RevokeAllForUser(ctx, user.ID, cutoff))
+
+		revoked, err := repo.IsRevoked(ctx, "before", user.ID, cutoff.Add(-time.Millisecond))
+		require.NoError(t, err)
+		assert.True(t, revoked)
+
+		revoked, err = repo.IsRevoked(ctx, "after", user.ID, cutoff.Add(time.Microsecond))
+		require.NoError(t, err)
+		assert.False(t, revoked)
+	})
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
+)
+
+type twoFactorRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewTwoFactorRepository(pool *pgxpool.Pool) repository.TwoFactorRepository {
+	return &twoFactorRepository{pool: pool}
+}
+
+func (r *twoFactorRepository) Find(ctx context.Context, userID int64) (*model.TwoFactor, error) {
+	query := `SELECT id, totp_secret, totp_enabled, totp_last_step FROM users WHERE id = $1`
+	twoFactor := &model.TwoFactor{}
+	err := r.pool.QueryRow(ctx, query, userID).Scan(
+		&twoFactor.UserID,
+		&twoFactor.Secret,
+		&twoFactor.Enabled,
+		&twoFactor.LastStep,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainerrors.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return twoFactor, nil
+}
+
+func (r *twoFactorRepository) SetPendingSecret(ctx context.Context, userID int64, secret []byte) error {
+	query := `UPDATE users SET totp_secret = $2, totp_last_step = 0, totp_recovery_codes = '{}'
+	          WHERE id = $1 AND NOT totp_enabled`
+	tag, err := r.pool.Exec(ctx, query, userID, secret)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domainerrors.ErrTwoFactorEnabled
+	}
+	return nil
+}
+
+func (r *twoFactorRepository) Enable(ctx context.Context, userID int64, secret []byte, recoveryCodeHashes []string, step int64) error {
+	query := `UPDATE users SET totp_enabled = TRUE, totp_recovery_codes = $3, totp_last_step = $4
+	          WHERE id = $1 AND NOT totp_enabled AND totp_secret = $2`
+	tag, err := r.pool.Exec(ctx, query, userID, secret, recoveryCodeHashes, step)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		// Параллельный запрос уже включил 2FA или начал настройку заново с другим секретом.
+		return domainerrors.ErrTwoFactorNotStarted
+	}
+	return nil
+}
+
+func (r *twoFactorRepository) UseStep(ctx context.Context, userID int64, step int64) (bool, error) {
+	query := `UPDATE users SET totp_last_step = $2
+	          WHERE id = $1 AND totp_enabled AND totp_last_step < $2`
+	tag, err := r.pool.Exec(ctx, query, userID, step)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error) {
+	query := `UPDATE users SET totp_recovery_codes = array_remove(totp_recovery_codes, $2)
+	          WHERE id = $1 AND totp_enabled AND $2 = ANY(totp_recovery_codes)`
+	tag, err := r.pool.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+type twoFactorChallengeRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewTwoFactorChallengeRepository(pool *pgxpool.Pool) repository.TwoFactorChallengeRepository {
+	return &twoFactorChallengeRepository{pool: pool}
+}
+
+func (r *twoFactorChallengeRepository) Create(ctx context.Context, challenge *model.TwoFactorChallenge) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM two_factor_challenges WHERE expires_at < $1`, challenge.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO two_factor_challenges (user_id, token_hash, expires_at, created_at)
+	          VALUES ($1, $2, $3, $4) RETURNING id`
+	return r.pool.QueryRow(ctx, query, challenge.UserID, challenge.TokenHash, challenge.ExpiresAt, challenge.CreatedAt).
+		Scan(&challenge.ID)
+}
+
+func (r *twoFactorChallengeRepository) FindByHash(ctx context.Context, tokenHash string) (*model.TwoFactorChallenge, error) {
+	query := `SELECT id, user_id, token_hash, attempts, expires_at, created_at
+	          FROM two_factor_challenges WHERE token_hash = $1`
+	challenge := &model.TwoFactorChallenge{}
+	err := r.pool.QueryRow(ctx, query, tokenHash).Scan(
+		&challenge.ID,
+		&challenge.UserID,
+		&challenge.TokenHash,
+		&challenge.Attempts,
+		&challenge.ExpiresAt,
+		&challenge.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainerrors.ErrInvalidChallenge
+		}
+		return nil, err
+	}
+	return challenge, nil
+}
+
+func (r *twoFactorChallengeRepository) RecordFailure(ctx context.Context, id int64) (int, error) {
+	query := `UPDATE two_factor_challenges SET attempts = attempts + 1 WHERE id = $1 RETURNING attempts`
+	var attempts int
+	if err := r.pool.QueryRow(ctx, query, id).Scan(&attempts); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainerrors.ErrInvalidChallenge
+		}
+		return 0, err
+	}
+	return attempts, nil
+}
+
+func (r *twoFactorChallengeRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM two_factor_challenges WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
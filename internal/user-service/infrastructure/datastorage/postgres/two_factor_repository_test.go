@@ -0,0 +1,102 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainerrors "github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/infrastructure/datastorage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactorRepository(t *testing.T) {
+	repo := postgres.NewTwoFactorRepository(testPool)
+	ctx := context.Background()
+
+	t.Run("enable_requires_verified_secret", func(t *testing.T) {
+		setupTestDB(t)
+		user := createTestUser(t)
+
+		twoFactor, err := repo.Find(ctx, user.ID)
+		require.NoError(t, err)
+		assert.False(t, twoFactor.Enabled)
+		assert.Nil(t, twoFactor.Secret)
+
+		require.NoError(t, repo.SetPendingSecret(ctx, user.ID, []byte("first")))
+		require.NoError(t, repo.SetPendingSecret(ctx, user.ID, []byte("second")))
+
+		err = repo.Enable(ctx, user.ID, []byte("first"), []string{"hash"}, 10)
+		assert.ErrorIs(t, err, domainerrors.ErrTwoFactorNotStarted, "secret was replaced after the code was checked")
+
+		require.NoError(t, repo.Enable(ctx, user.ID, []byte("second"), []string{"hash"}, 10))
+		twoFactor, err = repo.Find(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, twoFactor.Enabled)
+		assert.Equal(t, []byte("second"), twoFactor.Secret)
+		assert.Equal(t, int64(10), twoFactor.LastStep)
+
+		err = repo.SetPendingSecret(ctx, user.ID, []byte("third"))
+		assert.ErrorIs(t, err, domainerrors.ErrTwoFactorEnabled)
+	})
+
+	t.Run("codes_are_single_use", func(t *testing.T) {
+		setupTestDB(t)
+		user := createTestUser(t)
+		require.NoError(t, repo.SetPendingSecret(ctx, user.ID, []byte("secret")))
+		require.NoError(t, repo.Enable(ctx, user.ID, []byte("secret"), []string{"a", "b"}, 10))
+
+		used, err := repo.UseStep(ctx, user.ID, 10)
+		require.NoError(t, err)
+		assert.False(t, used, "code of the enabling step must not be reused")
+
+		used, err = repo.UseStep(ctx, user.ID, 11)
+		require.NoError(t, err)
+		assert.True(t, used)
+
+		used, err = repo.UseRecoveryCode(ctx, user.ID, "a")
+		require.NoError(t, err)
+		assert.True(t, used)
+
+		used, err = repo.UseRecoveryCode(ctx, user.ID, "a")
+		require.NoError(t, err)
+		assert.False(t, used)
+	})
+}
+
+func TestTwoFactorChallengeRepository(t *testing.T) {
+	repo := postgres.NewTwoFactorChallengeRepository(testPool)
+	ctx := context.Background()
+
+	setupTestDB(t)
+	user := createTestUser(t)
+	now := time.Now()
+
+	expired := &model.TwoFactorChallenge{UserID: user.ID, TokenHash: "expired", ExpiresAt: now.Add(-time.Minute), CreatedAt: now.Add(-time.Hour)}
+	require.NoError(t, repo.Create(ctx, expired))
+	challenge := &model.TwoFactorChallenge{UserID: user.ID, TokenHash: "active", ExpiresAt: now.Add(5 * time.Minute), CreatedAt: now}
+	require.NoError(t, repo.Create(ctx, challenge))
+
+	_, err := repo.FindByHash(ctx, "expired")
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidChallenge)
+
+	attempts, err := repo.RecordFailure(ctx, challenge.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempts)
+
+	found, err := repo.FindByHash(ctx, "active")
+	require.NoError(t, err)
+	assert.Equal(t, 1, found.Attempts)
+
+	deleted, err := repo.Delete(ctx, challenge.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = repo.Delete(ctx, challenge.ID)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
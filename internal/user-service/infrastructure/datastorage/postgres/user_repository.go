@@ -11,6 +11,9 @@ import (
 	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/repository"
 )
 
+// usersEmailConstraint — уникальный индекс по email, отличает повтор email от повтора логина.
+const usersEmailConstraint = "users_email_key"
+
 type userRepository struct {
 	pool *pgxpool.Pool
 }
@@ -20,27 +23,35 @@ func NewUserRepository(pool *pgxpool.Pool) repository.UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *model.User) error {
-	query := `INSERT INTO users (login, password_hash, first_name, last_name, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`
-	err := r.pool.QueryRow(ctx, query, user.Login, user.PasswordHash, user.FirstName, user.LastName, user.CreatedAt).Scan(&user.ID)
+	query := `INSERT INTO users (login, password_hash, first_name, last_name, email, created_at) VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6) RETURNING id, roles`
+	var roles []string
+	err := r.pool.QueryRow(ctx, query, user.Login, user.PasswordHash, user.FirstName, user.LastName, user.Email, user.CreatedAt).Scan(&user.ID, &roles)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			if pgErr.ConstraintName == usersEmailConstraint {
+				return domainerrors.ErrEmailAlreadyExists
+			}
 			return domainerrors.ErrLoginAlreadyExists
 		}
 		return err
 	}
+	user.Roles = model.ParseRoles(roles)
 	return nil
 }
 
 func (r *userRepository) FindByLogin(ctx context.Context, login string) (*model.User, error) {
-	query := `SELECT id, login, password_hash, first_name, last_name, created_at FROM users WHERE login = $1`
+	query := `SELECT id, login, password_hash, first_name, last_name, COALESCE(email, ''), roles, created_at FROM users WHERE login = $1 AND deleted_at IS NULL`
 	user := &model.User{}
+	var roles []string
 	err := r.pool.QueryRow(ctx, query, login).Scan(
 		&user.ID,
 		&user.Login,
 		&user.PasswordHash,
 		&user.FirstName,
 		&user.LastName,
+		&user.Email,
+		&roles,
 		&user.CreatedAt,
 	)
 	if err != nil {
@@ -49,6 +60,31 @@ func (r *userRepository) FindByLogin(ctx context.Context, login string) (*model.
 		}
 		return nil, err
 	}
+	user.Roles = model.ParseRoles(roles)
+	return user, nil
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id int64) (*model.User, error) {
+	query := `SELECT id, login, password_hash, first_name, last_name, COALESCE(email, ''), roles, created_at FROM users WHERE id = $1 AND deleted_at IS NULL`
+	user := &model.User{}
+	var roles []string
+	err := r.pool.QueryRow(ctx, query, id).Scan(
+		&user.ID,
+		&user.Login,
+		&user.PasswordHash,
+		&user.FirstName,
+		&user.LastName,
+		&user.Email,
+		&roles,
+		&user.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainerrors.ErrUserNotFound
+		}
+		return nil, err
+	}
+	user.Roles = model.ParseRoles(roles)
 	return user, nil
 }
 
@@ -62,3 +98,78 @@ func (r *userRepository) ExistsByLogin(ctx context.Context, login string) (bool,
 	return exists, nil
 }
 
+func (r *userRepository) UpdatePassword(ctx context.Context, userID int64, passwordHash string) error {
+	query := `UPDATE users SET password_hash = $1 WHERE id = $2`
+	tag, err := r.pool.Exec(ctx, query, passwordHash, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domainerrors.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) Update(ctx context.Context, user *model.User) error {
+	query := `UPDATE users SET first_name = $1, last_name = $2, email = NULLIF($3, '') WHERE id = $4`
+	tag, err := r.pool.Exec(ctx, query, user.FirstName, user.LastName, user.Email, user.ID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == usersEmailConstraint {
+			return domainerrors.ErrEmailAlreadyExists
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domainerrors.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) AddRole(ctx context.Context, userID int64, role model.Role) error {
+	query := `UPDATE users SET roles = CASE WHEN $2 = ANY(roles) THEN roles ELSE array_append(roles, $2) END
+	          WHERE id = $1`
+	tag, err := r.pool.Exec(ctx, query, userID, string(role))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domainerrors.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) Anonymize(ctx context.Context, userID int64) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Логин заменяется случайным псевдонимом: прежний логин освобождается, а заказы
+	// и списания остаются привязаны к идентификатору без персональных данных.
+	var login string
+	query := `UPDATE users SET login = 'deleted-' || gen_random_uuid(), password_hash = '', first_name = '', last_name = '',
+	          email = NULL, roles = '{user}', totp_secret = NULL, totp_enabled = FALSE, totp_recovery_codes = '{}', deleted_at = NOW()
+	          FROM (SELECT id, login FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE) AS previous
+	          WHERE users.id = previous.id
+	          RETURNING previous.login`
+	if err := tx.QueryRow(ctx, query, userID).Scan(&login); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainerrors.ErrUserNotFound
+		}
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM login_attempts WHERE scope = $1 AND subject = $2`, string(model.LoginAttemptScopeLogin), login); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM password_reset_tokens WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM two_factor_challenges WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -274,3 +274,125 @@ func TestUserRepository_RoundTrip(t *testing.T) {
 		assert.True(t, exists)
 	})
 }
+
+func TestUserRepository_AddRole(t *testing.T) {
+	setupTestDB(t)
+	repo := postgres.NewUserRepository(testPool)
+	ctx := context.Background()
+
+	t.Run("new_user_has_user_role", func(t *testing.T) {
+		setupTestDB(t)
+		user := &model.User{Login: "roles", PasswordHash: "hash", CreatedAt: time.Now()}
+		require.NoError(t, repo.Create(ctx, user))
+		assert.Equal(t, []model.Role{model.RoleUser}, user.Roles)
+	})
+
+	t.Run("grants_role_once", func(t *testing.T) {
+		setupTestDB(t)
+		user := &model.User{Login: "admin", PasswordHash: "hash", CreatedAt: time.Now()}
+		require.NoError(t, repo.Create(ctx, user))
+
+		require.NoError(t, repo.AddRole(ctx, user.ID, model.RoleAdmin))
+		require.NoError(t, repo.AddRole(ctx, user.ID, model.RoleAdmin))
+
+		found, err := repo.FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []model.Role{model.RoleUser, model.RoleAdmin}, found.Roles)
+	})
+
+	t.Run("returns_error_when_user_not_found", func(t *testing.T) {
+		setupTestDB(t)
+		err := repo.AddRole(ctx, 999, model.RoleAdmin)
+		assert.True(t, errors.Is(err, errors.ErrUserNotFound))
+	})
+}
+
+func TestUserRepository_Update(t *testing.T) {
+	setupTestDB(t)
+	repo := postgres.NewUserRepository(testPool)
+	ctx := context.Background()
+
+	t.Run("updates_profile_fields", func(t *testing.T) {
+		setupTestDB(t)
+		user := &model.User{Login: "profile", PasswordHash: "hash", FirstName: "Old", CreatedAt: time.Now()}
+		require.NoError(t, repo.Create(ctx, user))
+
+		user.FirstName = "New"
+		user.LastName = "Name"
+		user.Email = "profile@example.com"
+		require.NoError(t, repo.Update(ctx, user))
+
+		found, err := repo.FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "New", found.FirstName)
+		assert.Equal(t, "Name", found.LastName)
+		assert.Equal(t, "profile@example.com", found.Email)
+
+		user.Email = ""
+		require.NoError(t, repo.Update(ctx, user))
+		found, err = repo.FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Empty(t, found.Email)
+	})
+
+	t.Run("rejects_duplicate_email", func(t *testing.T) {
+		setupTestDB(t)
+		first := &model.User{Login: "first", PasswordHash: "hash", Email: "same@example.com", CreatedAt: time.Now()}
+		require.NoError(t, repo.Create(ctx, first))
+		second := &model.User{Login: "second", PasswordHash: "hash", CreatedAt: time.Now()}
+		require.NoError(t, repo.Create(ctx, second))
+
+		second.Email = "same@example.com"
+		err := repo.Update(ctx, second)
+		assert.True(t, errors.Is(err, errors.ErrEmailAlreadyExists))
+	})
+
+	t.Run("returns_error_when_user_not_found", func(t *testing.T) {
+		setupTestDB(t)
+		err := repo.Update(ctx, &model.User{ID: 999})
+		assert.True(t, errors.Is(err, errors.ErrUserNotFound))
+	})
+}
+
+func TestUserRepository_Anonymize(t *testing.T) {
+	setupTestDB(t)
+	repo := postgres.NewUserRepository(testPool)
+	ctx := context.Background()
+
+	t.Run("removes_personal_data", func(t *testing.T) {
+		setupTestDB(t)
+		user := &model.User{Login: "gdpr", PasswordHash: "hash", FirstName: "John", LastName: "Doe", Email: "gdpr@example.com", CreatedAt: time.Now()}
+		require.NoError(t, repo.Create(ctx, user))
+
+		require.NoError(t, repo.Anonymize(ctx, user.ID))
+
+		_, err := repo.FindByID(ctx, user.ID)
+		assert.True(t, errors.Is(err, errors.ErrUserNotFound))
+		_, err = repo.FindByLogin(ctx, "gdpr")
+		assert.True(t, errors.Is(err, errors.ErrUserNotFound))
+
+		var login, firstName, lastName string
+		var email *string
+		err = testPool.QueryRow(ctx, `SELECT login, first_name, last_name, email FROM users WHERE id = $1`, user.ID).
+			Scan(&login, &firstName, &lastName, &email)
+		require.NoError(t, err)
+		assert.NotEqual(t, "gdpr", login)
+		assert.Empty(t, firstName)
+		assert.Empty(t, lastName)
+		assert.Nil(t, email)
+
+		exists, err := repo.ExistsByLogin(ctx, "gdpr")
+		require.NoError(t, err)
+		assert.False(t, exists, "login must be free for a new registration")
+	})
+
+	t.Run("returns_error_when_already_deleted", func(t *testing.T) {
+		setupTestDB(t)
+		user := &model.User{Login: "twice", PasswordHash: "hash", CreatedAt: time.Now()}
+		require.NoError(t, repo.Create(ctx, user))
+		require.NoError(t, repo.Anonymize(ctx, user.ID))
+
+		err := repo.Anonymize(ctx, user.ID)
+		assert.True(t, errors.Is(err, errors.ErrUserNotFound))
+	})
+}
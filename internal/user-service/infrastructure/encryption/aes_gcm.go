@@ -0,0 +1,57 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+)
+
+const keySize = 32
+
+var (
+	ErrInvalidKey          = errors.New("encryption key must be 32 bytes encoded in base64")
+	ErrMalformedCiphertext = errors.New("malformed ciphertext")
+)
+
+type aesGCMCipher struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCMCipher создаёт шифр AES-256-GCM. Ключ передаётся в base64, например
+// результат `openssl rand -base64 32`.
+func NewAESGCMCipher(encodedKey string) (service.SecretCipher, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil || len(key) != keySize {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &aesGCMCipher{aead: aead}, nil
+}
+
+// Encrypt возвращает nonce, за которым следует шифртекст с тегом аутентификации.
+func (c *aesGCMCipher) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, associatedData), nil
+}
+
+func (c *aesGCMCipher) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	nonceSize := c.aead.NonceSize()
+	if len(ciphertext) < nonceSize+c.aead.Overhead() {
+		return nil, ErrMalformedCiphertext
+	}
+	return c.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], associatedData)
+}
@@ -0,0 +1,50 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKey(t *testing.T) string {
+	t.Helper()
+	key := make([]byte, keySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestAESGCMCipher(t *testing.T) {
+	c, err := NewAESGCMCipher(newTestKey(t))
+	require.NoError(t, err)
+
+	ciphertext, err := c.Encrypt([]byte("secret"), []byte("user:1"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), "secret")
+
+	plaintext, err := c.Decrypt(ciphertext, []byte("user:1"))
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(plaintext))
+
+	_, err = c.Decrypt(ciphertext, []byte("user:2"))
+	assert.Error(t, err, "ciphertext is bound to its owner")
+
+	other, err := NewAESGCMCipher(newTestKey(t))
+	require.NoError(t, err)
+	_, err = other.Decrypt(ciphertext, []byte("user:1"))
+	assert.Error(t, err)
+
+	_, err = c.Decrypt([]byte{1, 2, 3}, nil)
+	assert.ErrorIs(t, err, ErrMalformedCiphertext)
+}
+
+func TestNewAESGCMCipher_InvalidKey(t *testing.T) {
+	_, err := NewAESGCMCipher("not base64!")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = NewAESGCMCipher(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
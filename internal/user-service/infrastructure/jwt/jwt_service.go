@@ -1,7 +1,10 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"time"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
@@ -9,38 +12,59 @@ import (
 )
 
 type jwtService struct {
-	secretKey []byte
-	expiry    time.Duration
+	keys   *KeySet
+	expiry time.Duration
 }
 
+// NewJWTService подписывает токены HS256 с секретом secretKey. Собрать такой набор ключей
+// нельзя только при ошибке в коде, поэтому ошибка приводит к панике.
 func NewJWTService(secretKey string, expiry time.Duration) service.JWTService {
+	keys, err := NewKeySet(NewHMACKey(secretKey))
+	if err != nil {
+		panic(fmt.Sprintf("jwt: build hmac key set: %v", err))
+	}
+	return NewJWTServiceWithKeys(keys, expiry)
+}
+
+// NewJWTServiceWithKeys подписывает токены ключом подписи keys и проверяет ключами набора.
+// Конструктор задаёт точность времени в claims model.TokenTimePrecision.
+func NewJWTServiceWithKeys(keys *KeySet, expiry time.Duration) service.JWTService {
+	// Точность задаётся в библиотеке глобально и действует и при выпуске, и при разборе токена.
+	jwt.TimePrecision = model.TokenTimePrecision
 	return &jwtService{
-		secretKey: []byte(secretKey),
-		expiry:    expiry,
+		keys:   keys,
+		expiry: expiry,
 	}
 }
 
-func (s *jwtService) GenerateToken(userID int64, login string) (string, error) {
+func (s *jwtService) GenerateToken(userID int64, login string, roles []model.Role) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
 	claims := &model.Claims{
 		UserID: userID,
 		Login:  login,
+		Roles:  roles,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.secretKey)
+	signing := s.keys.SigningKey()
+	token := jwt.NewWithClaims(signing.method, claims)
+	if signing.id != "" {
+		token.Header["kid"] = signing.id
+	}
+	return token.SignedString(signing.signKey)
 }
 
 func (s *jwtService) ValidateToken(tokenString string) (*model.Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &model.Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return s.secretKey, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &model.Claims{}, s.keys.keyFunc,
+		jwt.WithValidMethods(s.keys.algorithms()))
 
 	if err != nil {
 		return nil, err
@@ -53,4 +77,11 @@ func (s *jwtService) ValidateToken(tokenString string) (*model.Claims, error) {
 	return nil, errors.New("invalid token")
 }
 
-
+// newTokenID возвращает идентификатор токена (jti), по которому токен можно отозвать.
+func newTokenID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
 )
 
 func TestNewJWTService(t *testing.T) {
@@ -77,7 +79,7 @@ func TestJWTService_GenerateToken(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			token, err := service.GenerateToken(tt.userID, tt.login)
+			token, err := service.GenerateToken(tt.userID, tt.login, nil)
 			tt.check(t, token, err)
 
 			if err == nil {
@@ -98,7 +100,7 @@ func TestJWTService_GenerateToken_Claims(t *testing.T) {
 	userID := int64(123)
 	login := "testuser"
 
-	token, err := service.GenerateToken(userID, login)
+	token, err := service.GenerateToken(userID, login, nil)
 	require.NoError(t, err)
 
 	claims, err := service.ValidateToken(token)
@@ -119,6 +121,37 @@ func TestJWTService_GenerateToken_Claims(t *testing.T) {
 	assert.True(t, expiresAt.Sub(issuedAt) <= expiry+time.Second, "token expiry should be approximately equal to configured expiry")
 }
 
+func TestJWTService_GenerateToken_SubSecondIssuedAt(t *testing.T) {
+	service := NewJWTService("test-secret-key", time.Hour)
+	before := time.Now().Truncate(model.TokenTimePrecision)
+
+	token, err := service.GenerateToken(123, "testuser", nil)
+	require.NoError(t, err)
+	claims, err := service.ValidateToken(token)
+	require.NoError(t, err)
+
+	require.NotNil(t, claims.IssuedAt)
+	assert.False(t, claims.IssuedAt.Time.Before(before), "iat must not be rounded down to the second")
+}
+
+func TestJWTService_GenerateToken_Roles(t *testing.T) {
+	service := NewJWTService("test-secret-key", time.Hour)
+
+	token, err := service.GenerateToken(123, "testuser", []model.Role{model.RoleUser, model.RoleAdmin})
+	require.NoError(t, err)
+
+	claims, err := service.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Role{model.RoleUser, model.RoleAdmin}, claims.Roles)
+
+	token, err = service.GenerateToken(123, "testuser", nil)
+	require.NoError(t, err)
+
+	claims, err = service.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Empty(t, claims.Roles)
+}
+
 func TestJWTService_ValidateToken(t *testing.T) {
 	secretKey := "test-secret-key"
 	expiry := 24 * time.Hour
@@ -128,7 +161,7 @@ func TestJWTService_ValidateToken(t *testing.T) {
 		userID := int64(123)
 		login := "testuser"
 
-		token, err := service.GenerateToken(userID, login)
+		token, err := service.GenerateToken(userID, login, nil)
 		require.NoError(t, err)
 
 		claims, err := service.ValidateToken(token)
@@ -156,7 +189,7 @@ func TestJWTService_ValidateToken(t *testing.T) {
 		userID := int64(123)
 		login := "testuser"
 
-		token, err := wrongService.GenerateToken(userID, login)
+		token, err := wrongService.GenerateToken(userID, login, nil)
 		require.NoError(t, err)
 
 		claims, err := service.ValidateToken(token)
@@ -171,7 +204,7 @@ func TestJWTService_ValidateToken(t *testing.T) {
 		userID := int64(123)
 		login := "testuser"
 
-		token, err := expiredService.GenerateToken(userID, login)
+		token, err := expiredService.GenerateToken(userID, login, nil)
 		require.NoError(t, err)
 
 		time.Sleep(100 * time.Millisecond)
@@ -240,7 +273,7 @@ func TestJWTService_GenerateAndValidate_RoundTrip(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			token, err := service.GenerateToken(tt.userID, tt.login)
+			token, err := service.GenerateToken(tt.userID, tt.login, nil)
 			require.NoError(t, err)
 			assert.NotEmpty(t, token)
 
@@ -269,7 +302,7 @@ func TestJWTService_DifferentExpiryTimes(t *testing.T) {
 			userID := int64(123)
 			login := "testuser"
 
-			token, err := service.GenerateToken(userID, login)
+			token, err := service.GenerateToken(userID, login, nil)
 			require.NoError(t, err)
 
 			claims, err := service.ValidateToken(token)
@@ -305,7 +338,7 @@ func TestJWTService_DifferentSecretKeys(t *testing.T) {
 			userID := int64(123)
 			login := "testuser"
 
-			token, err := service.GenerateToken(userID, login)
+			token, err := service.GenerateToken(userID, login, nil)
 			require.NoError(t, err)
 
 			claims, err := service.ValidateToken(token)
@@ -0,0 +1,245 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+)
+
+const minRSAKeyBits = 2048
+
+var (
+	ErrNoSigningKey      = errors.New("signing key must contain a private key")
+	ErrDuplicateKeyID    = errors.New("duplicate key id")
+	ErrUnsupportedKey    = errors.New("unsupported key type")
+	ErrRSAKeyTooShort    = fmt.Errorf("rsa key must be at least %d bits", minRSAKeyBits)
+	ErrInvalidPEM        = errors.New("no PEM block found")
+	ErrUnknownKeyID      = errors.New("unknown key id")
+	ErrAlgorithmMismatch = errors.New("token algorithm does not match key")
+)
+
+// Key — ключ подписи или проверки токенов. Асимметричные ключи публикуются в JWKS
+// и идентифицируются kid — отпечатком открытого ключа по RFC 7638.
+type Key struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	jwk       *model.JWK
+}
+
+func (k *Key) ID() string {
+	return k.id
+}
+
+func (k *Key) Algorithm() string {
+	return k.method.Alg()
+}
+
+// NewHMACKey — симметричный ключ HS256 на основе JWT_SECRET. Такой ключ не имеет kid
+// и не публикуется: проверить подпись может только владелец секрета.
+func NewHMACKey(secret string) *Key {
+	return &Key{
+		method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}
+}
+
+// ParsePEMKey разбирает закрытый (PKCS#1, PKCS#8) или открытый (PKIX, PKCS#1) ключ RSA либо Ed25519.
+// Из закрытого ключа получается ключ подписи, из открытого — только проверки.
+func ParsePEMKey(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidPEM
+	}
+
+	var (
+		parsed interface{}
+		err    error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: PEM block %q", ErrUnsupportedKey, block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		return newRSAKey(key, &key.PublicKey)
+	case *rsa.PublicKey:
+		return newRSAKey(nil, key)
+	case ed25519.PrivateKey:
+		return newEd25519Key(key, key.Public().(ed25519.PublicKey)), nil
+	case ed25519.PublicKey:
+		return newEd25519Key(nil, key), nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKey, parsed)
+	}
+}
+
+func LoadPEMKeyFile(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ParsePEMKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+func newRSAKey(private *rsa.PrivateKey, public *rsa.PublicKey) (*Key, error) {
+	if public.N.BitLen() < minRSAKeyBits {
+		return nil, ErrRSAKeyTooShort
+	}
+	jwk := &model.JWK{
+		Kty: "RSA",
+		Use: "sig",
+		Alg: jwt.SigningMethodRS256.Alg(),
+		N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+	}
+	jwk.Kid = thumbprint(map[string]string{"e": jwk.E, "kty": jwk.Kty, "n": jwk.N})
+
+	key := &Key{id: jwk.Kid, method: jwt.SigningMethodRS256, verifyKey: public, jwk: jwk}
+	if private != nil {
+		key.signKey = private
+	}
+	return key, nil
+}
+
+func newEd25519Key(private ed25519.PrivateKey, public ed25519.PublicKey) *Key {
+	jwk := &model.JWK{
+		Kty: "OKP",
+		Use: "sig",
+		Alg: jwt.SigningMethodEdDSA.Alg(),
+		Crv: "Ed25519",
+		X:   base64.RawURLEncoding.EncodeToString(public),
+	}
+	jwk.Kid = thumbprint(map[string]string{"crv": jwk.Crv, "kty": jwk.Kty, "x": jwk.X})
+
+	key := &Key{id: jwk.Kid, method: jwt.SigningMethodEdDSA, verifyKey: public, jwk: jwk}
+	if private != nil {
+		key.signKey = private
+	}
+	return key
+}
+
+// thumbprint вычисляет отпечаток JWK по RFC 7638: SHA-256 от обязательных полей,
+// сериализованных в лексикографическом порядке (json.Marshal сортирует ключи map).
+func thumbprint(members map[string]string) string {
+	data, _ := json.Marshal(members)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// KeySet — ключ, которым подписываются новые токены, и ключи, которыми они проверяются.
+// Для ротации новый ключ становится ключом подписи, а прежний остаётся в наборе
+// проверки, пока не истекут выпущенные им токены.
+type KeySet struct {
+	signing *Key
+	keys    []*Key
+	byID    map[string]*Key
+}
+
+func NewKeySet(signing *Key, verification ...*Key) (*KeySet, error) {
+	if signing == nil || signing.signKey == nil {
+		return nil, ErrNoSigningKey
+	}
+
+	set := &KeySet{signing: signing, byID: make(map[string]*Key)}
+	for _, key := range append([]*Key{signing}, verification...) {
+		if _, ok := set.byID[key.id]; ok {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateKeyID, key.id)
+		}
+		set.byID[key.id] = key
+		set.keys = append(set.keys, key)
+	}
+	return set, nil
+}
+
+// LoadKeySet собирает набор ключей из конфигурации. Без файла ключа подписи
+// используется HS256 с секретом — прежнее поведение сервиса.
+func LoadKeySet(secret, signingKeyFile string, verificationKeyFiles []string) (*KeySet, error) {
+	if signingKeyFile == "" {
+		return NewKeySet(NewHMACKey(secret))
+	}
+
+	signing, err := LoadPEMKeyFile(signingKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	verification := make([]*Key, 0, len(verificationKeyFiles))
+	for _, path := range verificationKeyFiles {
+		key, err := LoadPEMKeyFile(path)
+		if err != nil {
+			return nil, err
+		}
+		verification = append(verification, key)
+	}
+	return NewKeySet(signing, verification...)
+}
+
+func (s *KeySet) SigningKey() *Key {
+	return s.signing
+}
+
+// PublicKeys возвращает открытые ключи набора; симметричный ключ не публикуется.
+func (s *KeySet) PublicKeys() model.JWKSet {
+	set := model.JWKSet{Keys: []model.JWK{}}
+	for _, key := range s.keys {
+		if key.jwk != nil {
+			set.Keys = append(set.Keys, *key.jwk)
+		}
+	}
+	return set
+}
+
+// keyFunc выбирает ключ проверки по kid из заголовка токена. Алгоритм токена обязан
+// совпадать с алгоритмом ключа, иначе открытый ключ RSA можно было бы выдать за секрет HS256.
+func (s *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.byID[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, ErrAlgorithmMismatch
+	}
+	return key.verifyKey, nil
+}
+
+func (s *KeySet) algorithms() []string {
+	algs := make([]string, 0, len(s.keys))
+	seen := make(map[string]bool)
+	for _, key := range s.keys {
+		if alg := key.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			algs = append(algs, alg)
+		}
+	}
+	return algs
+}
@@ -0,0 +1,224 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rsaPEM(t *testing.T, bits int) (private, public []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	require.NoError(t, err)
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})
+}
+
+func ed25519PEM(t *testing.T) (private, public []byte) {
+	t.Helper()
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	priv, err := x509.MarshalPKCS8PrivateKey(privKey)
+	require.NoError(t, err)
+	pub, err := x509.MarshalPKIXPublicKey(pubKey)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: priv}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})
+}
+
+func mustParseKey(t *testing.T, data []byte) *Key {
+	t.Helper()
+	key, err := ParsePEMKey(data)
+	require.NoError(t, err)
+	return key
+}
+
+func mustKeySet(t *testing.T, signing *Key, verification ...*Key) *KeySet {
+	t.Helper()
+	keys, err := NewKeySet(signing, verification...)
+	require.NoError(t, err)
+	return keys
+}
+
+func TestParsePEMKey(t *testing.T) {
+	rsaPrivate, rsaPublic := rsaPEM(t, 2048)
+	edPrivate, edPublic := ed25519PEM(t)
+
+	t.Run("rsa_private_and_public_share_kid", func(t *testing.T) {
+		private := mustParseKey(t, rsaPrivate)
+		public := mustParseKey(t, rsaPublic)
+
+		assert.Equal(t, "RS256", private.Algorithm())
+		assert.NotEmpty(t, private.ID())
+		assert.Equal(t, private.ID(), public.ID())
+	})
+
+	t.Run("ed25519_private_and_public_share_kid", func(t *testing.T) {
+		private := mustParseKey(t, edPrivate)
+		public := mustParseKey(t, edPublic)
+
+		assert.Equal(t, "EdDSA", private.Algorithm())
+		assert.Equal(t, private.ID(), public.ID())
+	})
+
+	t.Run("short_rsa_key_is_rejected", func(t *testing.T) {
+		short, _ := rsaPEM(t, 1024)
+
+		_, err := ParsePEMKey(short)
+		assert.ErrorIs(t, err, ErrRSAKeyTooShort)
+	})
+
+	t.Run("garbage_is_rejected", func(t *testing.T) {
+		_, err := ParsePEMKey([]byte("not a key"))
+		assert.ErrorIs(t, err, ErrInvalidPEM)
+
+		_, err = ParsePEMKey(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{1}}))
+		assert.ErrorIs(t, err, ErrUnsupportedKey)
+	})
+}
+
+func TestNewKeySet(t *testing.T) {
+	rsaPrivate, rsaPublic := rsaPEM(t, 2048)
+
+	_, err := NewKeySet(mustParseKey(t, rsaPublic))
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+
+	_, err = NewKeySet(mustParseKey(t, rsaPrivate), mustParseKey(t, rsaPublic))
+	assert.ErrorIs(t, err, ErrDuplicateKeyID)
+}
+
+func TestJWTServiceWithKeys_SignsWithKid(t *testing.T) {
+	for name, generate := range map[string]func(*testing.T) ([]byte, []byte){
+		"rs256": func(t *testing.T) ([]byte, []byte) { return rsaPEM(t, 2048) },
+		"eddsa": ed25519PEM,
+	} {
+		t.Run(name, func(t *testing.T) {
+			private, public := generate(t)
+			signing := mustParseKey(t, private)
+			service := NewJWTServiceWithKeys(mustKeySet(t, signing), time.Hour)
+
+			token, err := service.GenerateToken(42, "user", nil)
+			require.NoError(t, err)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &model.Claims{})
+			require.NoError(t, err)
+			assert.Equal(t, signing.ID(), parsed.Header["kid"])
+			assert.Equal(t, signing.Algorithm(), parsed.Method.Alg())
+
+			claims, err := service.ValidateToken(token)
+			require.NoError(t, err)
+			assert.Equal(t, int64(42), claims.UserID)
+
+			// Сторонний сервис проверяет токен одним открытым ключом.
+			verifier := mustParseKey(t, public)
+			verified, err := jwt.ParseWithClaims(token, &model.Claims{}, func(*jwt.Token) (interface{}, error) {
+				return verifier.verifyKey, nil
+			})
+			require.NoError(t, err)
+			assert.True(t, verified.Valid)
+		})
+	}
+}
+
+func TestJWTServiceWithKeys_Rotation(t *testing.T) {
+	oldPrivate, oldPublic := rsaPEM(t, 2048)
+	newPrivate, _ := ed25519PEM(t)
+
+	oldService := NewJWTServiceWithKeys(mustKeySet(t, mustParseKey(t, oldPrivate)), time.Hour)
+	oldToken, err := oldService.GenerateToken(1, "user", nil)
+	require.NoError(t, err)
+
+	rotated := NewJWTServiceWithKeys(mustKeySet(t, mustParseKey(t, newPrivate), mustParseKey(t, oldPublic)), time.Hour)
+	_, err = rotated.ValidateToken(oldToken)
+	assert.NoError(t, err, "token signed by previous key must stay valid during rotation")
+
+	newToken, err := rotated.GenerateToken(1, "user", nil)
+	require.NoError(t, err)
+	_, err = rotated.ValidateToken(newToken)
+	assert.NoError(t, err)
+
+	retired := NewJWTServiceWithKeys(mustKeySet(t, mustParseKey(t, newPrivate)), time.Hour)
+	_, err = retired.ValidateToken(oldToken)
+	assert.Error(t, err, "token signed by a removed key must be rejected")
+
+	legacy := NewJWTService("secret", time.Hour)
+	legacyToken, err := legacy.GenerateToken(1, "user", nil)
+	require.NoError(t, err)
+	_, err = rotated.ValidateToken(legacyToken)
+	assert.Error(t, err, "HS256 token must not be accepted by an asymmetric key set")
+}
+
+func TestJWTServiceWithKeys_RejectsAlgorithmConfusion(t *testing.T) {
+	private, public := rsaPEM(t, 2048)
+	signing := mustParseKey(t, private)
+	service := NewJWTServiceWithKeys(mustKeySet(t, signing), time.Hour)
+
+	// Токен HS256, подписанный открытым ключом как секретом, с kid ключа RSA.
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &model.Claims{
+		UserID:           1,
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	})
+	forged.Header["kid"] = signing.ID()
+	tokenString, err := forged.SignedString(public)
+	require.NoError(t, err)
+
+	_, err = service.ValidateToken(tokenString)
+	assert.Error(t, err)
+}
+
+func TestKeySet_PublicKeys(t *testing.T) {
+	rsaPrivate, _ := rsaPEM(t, 2048)
+	_, edPublic := ed25519PEM(t)
+
+	keys := mustKeySet(t, mustParseKey(t, rsaPrivate), mustParseKey(t, edPublic))
+	set := keys.PublicKeys()
+
+	require.Len(t, set.Keys, 2)
+	assert.Equal(t, "RSA", set.Keys[0].Kty)
+	assert.Equal(t, "RS256", set.Keys[0].Alg)
+	assert.Equal(t, "AQAB", set.Keys[0].E)
+	assert.Equal(t, "OKP", set.Keys[1].Kty)
+	assert.Equal(t, "Ed25519", set.Keys[1].Crv)
+	for _, jwk := range set.Keys {
+		assert.Equal(t, "sig", jwk.Use)
+		assert.NotEmpty(t, jwk.Kid)
+	}
+
+	hmac := mustKeySet(t, NewHMACKey("secret"))
+	assert.Empty(t, hmac.PublicKeys().Keys, "symmetric secret must never be published")
+}
+
+func TestLoadKeySet(t *testing.T) {
+	dir := t.TempDir()
+	signingPrivate, _ := ed25519PEM(t)
+	_, previousPublic := rsaPEM(t, 2048)
+	signingPath := filepath.Join(dir, "signing.pem")
+	previousPath := filepath.Join(dir, "previous.pem")
+	require.NoError(t, os.WriteFile(signingPath, signingPrivate, 0o600))
+	require.NoError(t, os.WriteFile(previousPath, previousPublic, 0o600))
+
+	keys, err := LoadKeySet("secret", signingPath, []string{previousPath})
+	require.NoError(t, err)
+	assert.Equal(t, "EdDSA", keys.SigningKey().Algorithm())
+	assert.Len(t, keys.PublicKeys().Keys, 2)
+
+	keys, err = LoadKeySet("secret", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "HS256", keys.SigningKey().Algorithm())
+
+	_, err = LoadKeySet("secret", filepath.Join(dir, "missing.pem"), nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,65 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+)
+
+// FileNotifier дописывает уведомления в файл по одному JSON-объекту на строку —
+// заменитель почтовой рассылки для локального запуска и тестов.
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileNotifier(path string) service.PasswordResetNotifier {
+	return &FileNotifier{path: path}
+}
+
+type fileNotification struct {
+	Kind      string    `json:"kind"`
+	UserID    int64     `json:"user_id"`
+	Login     string    `json:"login"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (n *FileNotifier) SendPasswordReset(_ context.Context, user *model.User, token string, expiresAt time.Time) error {
+	data, err := json.Marshal(fileNotification{
+		Kind:      "password_reset",
+		UserID:    user.ID,
+		Login:     user.Login,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// NewPasswordResetNotifier выбирает заменитель рассылки: файл, если путь задан, иначе лог.
+func NewPasswordResetNotifier(path string) service.PasswordResetNotifier {
+	if path != "" {
+		return NewFileNotifier(path)
+	}
+	return NewLogNotifier()
+}
@@ -0,0 +1,24 @@
+package notifier
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+)
+
+// LogNotifier пишет токен сброса пароля в лог. Предназначен только для локальной разработки:
+// любой, у кого есть доступ к логам, сможет сменить пароль пользователя.
+type LogNotifier struct{}
+
+func NewLogNotifier() service.PasswordResetNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) SendPasswordReset(_ context.Context, user *model.User, token string, expiresAt time.Time) error {
+	log.Printf("password reset requested: user_id=%d login=%q token=%s expires_at=%s",
+		user.ID, user.Login, token, expiresAt.Format(time.RFC3339))
+	return nil
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/application/usecase"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+)
+
+type ChangePasswordHandler struct {
+	changePasswordUseCase *usecase.ChangePasswordUseCase
+}
+
+func NewChangePasswordHandler(changePasswordUseCase *usecase.ChangePasswordUseCase) *ChangePasswordHandler {
+	return &ChangePasswordHandler{
+		changePasswordUseCase: changePasswordUseCase,
+	}
+}
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+func (h *ChangePasswordHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	accessToken, ok := bearerToken(r)
+	if !ok {
+		http.Error(w, "authorization header required", http.StatusUnauthorized)
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.changePasswordUseCase.Execute(r.Context(), usecase.ChangePasswordRequest{
+		AccessToken: accessToken,
+		OldPassword: req.OldPassword,
+		NewPassword: req.NewPassword,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, errors.ErrTokenRequired), errors.Is(err, errors.ErrInvalidToken), errors.Is(err, errors.ErrTokenRevoked):
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case errors.Is(err, errors.ErrInvalidCredentials):
+			http.Error(w, "current password is incorrect", http.StatusForbidden)
+		case errors.Is(err, errors.ErrWeakPassword), errors.Is(err, errors.ErrSamePassword), errors.Is(err, errors.ErrPasswordRequired):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.Printf("change password error: %v", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Authorization", "Bearer "+resp.Token)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"token": resp.Token, "refresh_token": resp.RefreshToken})
+}
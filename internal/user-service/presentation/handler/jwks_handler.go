@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/service"
+)
+
+type JWKSHandler struct {
+	keys service.PublicKeyProvider
+}
+
+func NewJWKSHandler(keys service.PublicKeyProvider) *JWKSHandler {
+	return &JWKSHandler{
+		keys: keys,
+	}
+}
+
+func (h *JWKSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Набор ключей меняется только при ротации, поэтому клиентам разрешено кэшировать ответ.
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.keys.PublicKeys())
+}
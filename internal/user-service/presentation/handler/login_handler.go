@@ -2,7 +2,9 @@ package handler
 
 import (
 	"encoding/json"
+	"net"
 	"net/http"
+	"strconv"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/application/usecase"
 	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
 )
@@ -37,8 +39,12 @@ func (h *LoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	resp, err := h.loginUseCase.Execute(r.Context(), usecase.LoginRequest{
 		Login:    req.Login,
 		Password: req.Password,
+		ClientIP: clientIP(r),
 	})
 	if err != nil {
+		if writeLoginLocked(w, err) {
+			return
+		}
 		if errors.Is(err, errors.ErrInvalidCredentials) {
 			http.Error(w, err.Error(), http.StatusUnauthorized)
 			return
@@ -51,9 +57,38 @@ func (h *LoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Пароль верен, но нужен второй фактор: токены выдаются в POST /api/user/login/2fa.
+	if resp.ChallengeToken != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]any{"two_factor_required": true, "challenge_token": resp.ChallengeToken})
+		return
+	}
+
 	w.Header().Set("Authorization", "Bearer "+resp.Token)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"token": resp.Token})
+	json.NewEncoder(w).Encode(map[string]string{"token": resp.Token, "refresh_token": resp.RefreshToken})
+}
+
+
+// clientIP возвращает адрес клиента из соединения. За обратным прокси адрес
+// из X-Forwarded-For нужно подставлять в RemoteAddr на уровне роутера.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
 }
 
+// writeLoginLocked отвечает 429 с Retry-After, если вход временно заблокирован.
+func writeLoginLocked(w http.ResponseWriter, err error) bool {
+	var lockedErr *errors.LoginLockedError
+	if !errors.As(err, &lockedErr) {
+		return false
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(errors.RetryAfterSeconds(lockedErr.RetryAfter)))
+	http.Error(w, errors.ErrTooManyLoginAttempts.Error(), http.StatusTooManyRequests)
+	return true
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/application/usecase"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+)
+
+type LogoutHandler struct {
+	logoutUseCase *usecase.LogoutUseCase
+}
+
+func NewLogoutHandler(logoutUseCase *usecase.LogoutUseCase) *LogoutHandler {
+	return &LogoutHandler{
+		logoutUseCase: logoutUseCase,
+	}
+}
+
+// LogoutRequest — необязательное тело запроса: refresh-токен текущей сессии
+// и флаг выхода на всех устройствах.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	All          bool   `json:"all"`
+}
+
+func (h *LogoutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	accessToken, ok := bearerToken(r)
+	if !ok {
+		http.Error(w, "authorization header required", http.StatusUnauthorized)
+		return
+	}
+
+	var req LogoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+
+	err := h.logoutUseCase.Execute(r.Context(), usecase.LogoutRequest{
+		AccessToken:  accessToken,
+		RefreshToken: req.RefreshToken,
+		AllSessions:  req.All,
+	})
+	if err != nil {
+		if errors.Is(err, errors.ErrTokenRequired) || errors.Is(err, errors.ErrInvalidToken) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if errors.Is(err, errors.ErrInvalidRefreshToken) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("logout error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
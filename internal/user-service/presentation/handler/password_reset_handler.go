@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/application/usecase"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+)
+
+type RequestPasswordResetHandler struct {
+	requestPasswordResetUseCase *usecase.RequestPasswordResetUseCase
+}
+
+func NewRequestPasswordResetHandler(requestPasswordResetUseCase *usecase.RequestPasswordResetUseCase) *RequestPasswordResetHandler {
+	return &RequestPasswordResetHandler{
+		requestPasswordResetUseCase: requestPasswordResetUseCase,
+	}
+}
+
+type RequestPasswordResetRequest struct {
+	Login string `json:"login"`
+}
+
+// ServeHTTP отвечает 202 независимо от того, существует ли логин.
+func (h *RequestPasswordResetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RequestPasswordResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+
+	err := h.requestPasswordResetUseCase.Execute(r.Context(), usecase.RequestPasswordResetRequest{Login: req.Login})
+	if err != nil {
+		if errors.Is(err, errors.ErrLoginRequired) {
+			http.Error(w, "login is required", http.StatusBadRequest)
+			return
+		}
+		log.Printf("password reset request error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+type ConfirmPasswordResetHandler struct {
+	confirmPasswordResetUseCase *usecase.ConfirmPasswordResetUseCase
+}
+
+func NewConfirmPasswordResetHandler(confirmPasswordResetUseCase *usecase.ConfirmPasswordResetUseCase) *ConfirmPasswordResetHandler {
+	return &ConfirmPasswordResetHandler{
+		confirmPasswordResetUseCase: confirmPasswordResetUseCase,
+	}
+}
+
+type ConfirmPasswordResetRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+func (h *ConfirmPasswordResetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ConfirmPasswordResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+
+	err := h.confirmPasswordResetUseCase.Execute(r.Context(), usecase.ConfirmPasswordResetRequest{
+		Token:       req.Token,
+		NewPassword: req.NewPassword,
+	})
+	if err != nil {
+		if errors.Is(err, errors.ErrInvalidResetToken) || errors.Is(err, errors.ErrWeakPassword) || errors.Is(err, errors.ErrPasswordRequired) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("password reset confirm error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/application/usecase"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+)
+
+type ProfileResponse struct {
+	Login        string           `json:"login"`
+	FirstName    string           `json:"first_name"`
+	LastName     string           `json:"last_name"`
+	Email        string           `json:"email,omitempty"`
+	RegisteredAt string           `json:"registered_at"`
+	Balance      *BalanceResponse `json:"balance,omitempty"`
+}
+
+type BalanceResponse struct {
+	Current   json.Number `json:"current"`
+	Withdrawn json.Number `json:"withdrawn"`
+}
+
+type GetProfileHandler struct {
+	getProfileUseCase *usecase.GetProfileUseCase
+}
+
+func NewGetProfileHandler(getProfileUseCase *usecase.GetProfileUseCase) *GetProfileHandler {
+	return &GetProfileHandler{
+		getProfileUseCase: getProfileUseCase,
+	}
+}
+
+func (h *GetProfileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	accessToken, ok := bearerToken(r)
+	if !ok {
+		http.Error(w, "authorization header required", http.StatusUnauthorized)
+		return
+	}
+
+	profile, err := h.getProfileUseCase.Execute(r.Context(), usecase.GetProfileRequest{AccessToken: accessToken})
+	if err != nil {
+		writeProfileError(w, "get profile", err)
+		return
+	}
+
+	writeProfile(w, profile)
+}
+
+type UpdateProfileHandler struct {
+	updateProfileUseCase *usecase.UpdateProfileUseCase
+}
+
+func NewUpdateProfileHandler(updateProfileUseCase *usecase.UpdateProfileUseCase) *UpdateProfileHandler {
+	return &UpdateProfileHandler{
+		updateProfileUseCase: updateProfileUseCase,
+	}
+}
+
+// UpdateProfileRequest — частичное изменение профиля: отсутствующие поля не меняются,
+// пустой email удаляет адрес.
+type UpdateProfileRequest struct {
+	FirstName *string `json:"first_name"`
+	LastName  *string `json:"last_name"`
+	Email     *string `json:"email"`
+}
+
+func (h *UpdateProfileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	accessToken, ok := bearerToken(r)
+	if !ok {
+		http.Error(w, "authorization header required", http.StatusUnauthorized)
+		return
+	}
+
+	var req UpdateProfileRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.updateProfileUseCase.Execute(r.Context(), usecase.UpdateProfileRequest{
+		AccessToken: accessToken,
+		Update: usecase.ProfileUpdate{
+			FirstName: req.FirstName,
+			LastName:  req.LastName,
+			Email:     req.Email,
+		},
+	})
+	if err != nil {
+		writeProfileError(w, "update profile", err)
+		return
+	}
+
+	writeProfile(w, profile)
+}
+
+func writeProfile(w http.ResponseWriter, profile *usecase.Profile) {
+	resp := ProfileResponse{
+		Login:        profile.Login,
+		FirstName:    profile.FirstName,
+		LastName:     profile.LastName,
+		Email:        profile.Email,
+		RegisteredAt: profile.RegisteredAt.Format(time.RFC3339),
+	}
+	if profile.Balance != nil {
+		resp.Balance = &BalanceResponse{
+			Current:   json.Number(profile.Balance.Current),
+			Withdrawn: json.Number(profile.Balance.Withdrawn),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func writeProfileError(w http.ResponseWriter, operation string, err error) {
+	switch {
+	case errors.Is(err, errors.ErrTokenRequired), errors.Is(err, errors.ErrInvalidToken), errors.Is(err, errors.ErrTokenRevoked):
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, errors.ErrInvalidProfile):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, errors.ErrEmailAlreadyExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("%s error: %v", operation, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
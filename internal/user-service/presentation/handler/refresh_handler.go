@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/application/usecase"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+)
+
+type RefreshHandler struct {
+	refreshTokenUseCase *usecase.RefreshTokenUseCase
+}
+
+func NewRefreshHandler(refreshTokenUseCase *usecase.RefreshTokenUseCase) *RefreshHandler {
+	return &RefreshHandler{
+		refreshTokenUseCase: refreshTokenUseCase,
+	}
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (h *RefreshHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.refreshTokenUseCase.Execute(r.Context(), usecase.RefreshTokenRequest{
+		RefreshToken: req.RefreshToken,
+	})
+	if err != nil {
+		if errors.Is(err, errors.ErrRefreshTokenRequired) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, errors.ErrInvalidRefreshToken) || errors.Is(err, errors.ErrRefreshTokenReused) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		log.Printf("refresh token error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Authorization", "Bearer "+resp.Token)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(map[string]string{"token": resp.Token, "refresh_token": resp.RefreshToken}); err != nil {
+		log.Printf("failed to encode response: %v", err)
+	}
+}
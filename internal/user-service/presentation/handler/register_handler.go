@@ -48,7 +48,7 @@ func (h *RegisterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusConflict)
 			return
 		}
-		if errors.Is(err, errors.ErrLoginRequired) {
+		if errors.Is(err, errors.ErrLoginRequired) || errors.Is(err, errors.ErrWeakPassword) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
@@ -60,7 +60,7 @@ func (h *RegisterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Authorization", "Bearer "+resp.Token)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(map[string]string{"token": resp.Token}); err != nil {
+	if err := json.NewEncoder(w).Encode(map[string]string{"token": resp.Token, "refresh_token": resp.RefreshToken}); err != nil {
 		log.Printf("failed to encode response: %v", err)
 	}
 }
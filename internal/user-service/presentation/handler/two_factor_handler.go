@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/application/usecase"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+)
+
+type SetupTwoFactorHandler struct {
+	setupTwoFactorUseCase *usecase.SetupTwoFactorUseCase
+}
+
+func NewSetupTwoFactorHandler(setupTwoFactorUseCase *usecase.SetupTwoFactorUseCase) *SetupTwoFactorHandler {
+	return &SetupTwoFactorHandler{
+		setupTwoFactorUseCase: setupTwoFactorUseCase,
+	}
+}
+
+func (h *SetupTwoFactorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	accessToken, ok := bearerToken(r)
+	if !ok {
+		http.Error(w, "authorization header required", http.StatusUnauthorized)
+		return
+	}
+
+	resp, err := h.setupTwoFactorUseCase.Execute(r.Context(), usecase.SetupTwoFactorRequest{AccessToken: accessToken})
+	if err != nil {
+		writeTwoFactorError(w, "two-factor setup", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"secret": resp.Secret, "otpauth_uri": resp.URI})
+}
+
+type EnableTwoFactorHandler struct {
+	enableTwoFactorUseCase *usecase.EnableTwoFactorUseCase
+}
+
+func NewEnableTwoFactorHandler(enableTwoFactorUseCase *usecase.EnableTwoFactorUseCase) *EnableTwoFactorHandler {
+	return &EnableTwoFactorHandler{
+		enableTwoFactorUseCase: enableTwoFactorUseCase,
+	}
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+func (h *EnableTwoFactorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	accessToken, ok := bearerToken(r)
+	if !ok {
+		http.Error(w, "authorization header required", http.StatusUnauthorized)
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.enableTwoFactorUseCase.Execute(r.Context(), usecase.EnableTwoFactorRequest{
+		AccessToken: accessToken,
+		Code:        req.Code,
+	})
+	if err != nil {
+		writeTwoFactorError(w, "two-factor enable", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string][]string{"recovery_codes": resp.RecoveryCodes})
+}
+
+type VerifyTwoFactorHandler struct {
+	verifyTwoFactorUseCase *usecase.VerifyTwoFactorUseCase
+}
+
+func NewVerifyTwoFactorHandler(verifyTwoFactorUseCase *usecase.VerifyTwoFactorUseCase) *VerifyTwoFactorHandler {
+	return &VerifyTwoFactorHandler{
+		verifyTwoFactorUseCase: verifyTwoFactorUseCase,
+	}
+}
+
+type VerifyTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+func (h *VerifyTwoFactorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req VerifyTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.verifyTwoFactorUseCase.Execute(r.Context(), usecase.VerifyTwoFactorRequest{
+		ChallengeToken: req.ChallengeToken,
+		Code:           req.Code,
+		ClientIP:       clientIP(r),
+	})
+	if err != nil {
+		if writeLoginLocked(w, err) {
+			return
+		}
+		if errors.Is(err, errors.ErrInvalidTwoFactorCode) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		writeTwoFactorError(w, "two-factor login", err)
+		return
+	}
+
+	w.Header().Set("Authorization", "Bearer "+resp.Token)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"token": resp.Token, "refresh_token": resp.RefreshToken})
+}
+
+func writeTwoFactorError(w http.ResponseWriter, operation string, err error) {
+	switch {
+	case errors.Is(err, errors.ErrTokenRequired), errors.Is(err, errors.ErrInvalidToken), errors.Is(err, errors.ErrTokenRevoked),
+		errors.Is(err, errors.ErrInvalidChallenge):
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, errors.ErrTwoFactorEnabled):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, errors.ErrTwoFactorNotStarted), errors.Is(err, errors.ErrInvalidTwoFactorCode):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, errors.ErrTwoFactorUnavailable):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		log.Printf("%s error: %v", operation, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	authHeader := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return "", false
+	}
+	return strings.TrimPrefix(authHeader, "Bearer "), true
+}
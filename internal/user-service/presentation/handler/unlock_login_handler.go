@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/application/usecase"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+)
+
+// UnlockLoginHandler снимает блокировку входа. Доступ к нему ограничивается
+// на уровне роутера: обработчик монтируется только под административный маршрут.
+type UnlockLoginHandler struct {
+	unlockLoginUseCase *usecase.UnlockLoginUseCase
+}
+
+func NewUnlockLoginHandler(unlockLoginUseCase *usecase.UnlockLoginUseCase) *UnlockLoginHandler {
+	return &UnlockLoginHandler{
+		unlockLoginUseCase: unlockLoginUseCase,
+	}
+}
+
+type UnlockLoginRequest struct {
+	Login string `json:"login"`
+	IP    string `json:"ip"`
+}
+
+func (h *UnlockLoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req UnlockLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+
+	err := h.unlockLoginUseCase.Execute(r.Context(), usecase.UnlockLoginRequest{
+		Login: req.Login,
+		IP:    req.IP,
+	})
+	if err != nil {
+		if errors.Is(err, errors.ErrUnlockTargetRequired) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("unlock login error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("login lockout cleared: login=%q ip=%q", req.Login, req.IP)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,10 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/application/usecase"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/errors"
+	"github.com/sirajDeveloper/loyalty-points-service/internal/user-service/domain/model"
 )
 
 type ValidateHandler struct {
@@ -23,8 +27,10 @@ type ValidateRequest struct {
 }
 
 type ValidateResponse struct {
-	UserID int64  `json:"user_id"`
-	Login  string `json:"login"`
+	UserID    int64      `json:"user_id"`
+	Login     string     `json:"login"`
+	Roles     []string   `json:"roles,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
 func (h *ValidateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -52,18 +58,29 @@ func (h *ValidateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.validateUseCase.Execute(usecase.ValidateTokenRequest{
+	resp, err := h.validateUseCase.Execute(r.Context(), usecase.ValidateTokenRequest{
 		Token: token,
 	})
 	if err != nil {
-		http.Error(w, "invalid token", http.StatusUnauthorized)
+		if errors.Is(err, errors.ErrInvalidToken) || errors.Is(err, errors.ErrTokenRevoked) {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		log.Printf("validate token error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ValidateResponse{
+	validateResp := ValidateResponse{
 		UserID: resp.Claims.UserID,
 		Login:  resp.Claims.Login,
-	})
+		Roles:  model.RoleStrings(resp.Claims.Roles),
+	}
+	if resp.Claims.ExpiresAt != nil {
+		validateResp.ExpiresAt = &resp.Claims.ExpiresAt.Time
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(validateResp)
 }
@@ -0,0 +1,13 @@
+DROP TRIGGER IF EXISTS ledger_entries_balanced ON ledger_entries;
+DROP FUNCTION IF EXISTS ledger_entries_check_balanced();
+
+DROP TRIGGER IF EXISTS ledger_entries_append_only ON ledger_entries;
+DROP FUNCTION IF EXISTS ledger_entries_forbid_change();
+
+DROP INDEX IF EXISTS idx_ledger_entries_withdrawal_id;
+DROP INDEX IF EXISTS idx_ledger_entries_order_id;
+DROP INDEX IF EXISTS idx_ledger_entries_transaction_id;
+DROP INDEX IF EXISTS idx_ledger_entries_user_id;
+DROP TABLE IF EXISTS ledger_entries;
+
+DROP SEQUENCE IF EXISTS ledger_transaction_id_seq;
@@ -0,0 +1,96 @@
+CREATE SEQUENCE IF NOT EXISTS ledger_transaction_id_seq;
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id BIGINT NOT NULL,
+    kind VARCHAR NOT NULL CHECK (kind IN ('ACCRUAL', 'WITHDRAWAL', 'REVERSAL', 'ADJUSTMENT')),
+    account VARCHAR NOT NULL CHECK (account IN ('USER', 'ACCRUAL_SOURCE', 'REDEMPTION', 'ADJUSTMENT')),
+    user_id BIGINT NOT NULL,
+    amount DECIMAL(12,2) NOT NULL CHECK (amount <> 0),
+    order_id BIGINT REFERENCES orders(id),
+    withdrawal_id BIGINT REFERENCES withdrawals(id),
+    reversal_of BIGINT,
+    description VARCHAR,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_user_id ON ledger_entries(user_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_transaction_id ON ledger_entries(transaction_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_order_id ON ledger_entries(order_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_withdrawal_id ON ledger_entries(withdrawal_id);
+
+-- Журнал только дописывается: исправления вносятся сторнирующими и корректирующими проводками.
+CREATE OR REPLACE FUNCTION ledger_entries_forbid_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger_entries is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ledger_entries_append_only
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION ledger_entries_forbid_change();
+
+-- Сумма проводок одной операции должна быть равна нулю; проверяется при фиксации транзакции.
+CREATE OR REPLACE FUNCTION ledger_entries_check_balanced() RETURNS trigger AS $$
+BEGIN
+    IF (SELECT SUM(amount) FROM ledger_entries WHERE transaction_id = NEW.transaction_id) <> 0 THEN
+        RAISE EXCEPTION 'ledger transaction % is unbalanced', NEW.transaction_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER ledger_entries_balanced
+    AFTER INSERT ON ledger_entries
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION ledger_entries_check_balanced();
+
+-- Перенос истории: начисления по обработанным заказам и списания.
+WITH src AS (
+    SELECT id, user_id, accrual, uploaded_at, nextval('ledger_transaction_id_seq') AS transaction_id
+    FROM orders
+    WHERE status = 'PROCESSED' AND accrual > 0
+)
+INSERT INTO ledger_entries (transaction_id, kind, account, user_id, amount, order_id, description, created_at)
+SELECT transaction_id, 'ACCRUAL', 'USER', user_id, accrual, id, 'backfill', uploaded_at FROM src
+UNION ALL
+SELECT transaction_id, 'ACCRUAL', 'ACCRUAL_SOURCE', user_id, -accrual, id, 'backfill', uploaded_at FROM src;
+
+WITH src AS (
+    SELECT id, user_id, sum, processed_at, nextval('ledger_transaction_id_seq') AS transaction_id
+    FROM withdrawals
+    WHERE sum > 0
+)
+INSERT INTO ledger_entries (transaction_id, kind, account, user_id, amount, withdrawal_id, description, created_at)
+SELECT transaction_id, 'WITHDRAWAL', 'USER', user_id, -sum, id, 'backfill', processed_at FROM src
+UNION ALL
+SELECT transaction_id, 'WITHDRAWAL', 'REDEMPTION', user_id, sum, id, 'backfill', processed_at FROM src;
+
+-- Расхождение между балансом и перенесённой историей фиксируется корректировкой,
+-- чтобы текущие балансы пользователей не изменились.
+WITH ledger AS (
+    SELECT user_id, SUM(amount) AS current
+    FROM ledger_entries
+    WHERE account = 'USER'
+    GROUP BY user_id
+), src AS (
+    SELECT b.user_id, b.current - COALESCE(l.current, 0) AS residual, nextval('ledger_transaction_id_seq') AS transaction_id
+    FROM balances b LEFT JOIN ledger l ON l.user_id = b.user_id
+    WHERE b.current - COALESCE(l.current, 0) <> 0
+)
+INSERT INTO ledger_entries (transaction_id, kind, account, user_id, amount, description)
+SELECT transaction_id, 'ADJUSTMENT', 'USER', user_id, residual, 'backfill residual' FROM src
+UNION ALL
+SELECT transaction_id, 'ADJUSTMENT', 'ADJUSTMENT', user_id, -residual, 'backfill residual' FROM src;
+
+WITH totals AS (
+    SELECT user_id,
+           COALESCE(SUM(amount) FILTER (WHERE account = 'USER'), 0) AS current,
+           COALESCE(SUM(amount) FILTER (WHERE account = 'REDEMPTION'), 0) AS withdrawn
+    FROM ledger_entries
+    GROUP BY user_id
+)
+INSERT INTO balances (user_id, current, withdrawn)
+SELECT user_id, current, withdrawn FROM totals
+ON CONFLICT (user_id)
+DO UPDATE SET current = EXCLUDED.current, withdrawn = EXCLUDED.withdrawn;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS uq_ledger_entries_order_accrual;
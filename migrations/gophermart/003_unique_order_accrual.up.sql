@@ -0,0 +1,4 @@
+-- Заказ может быть зачислен на баланс пользователя только один раз.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_ledger_entries_order_accrual
+    ON ledger_entries(order_id)
+    WHERE kind = 'ACCRUAL' AND account = 'USER';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_outbox_next_attempt_at;
+
+UPDATE outbox SET status = 'PENDING' WHERE status IN ('IN_FLIGHT', 'REVIEW');
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS next_attempt_at;
@@ -0,0 +1,13 @@
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+-- Заказы, для которых outbox был закрыт до получения финального статуса, снова ставятся в опрос.
+UPDATE outbox o
+SET status = 'IN_FLIGHT', next_attempt_at = NOW(), updated_at = NOW()
+FROM orders ord
+WHERE ord.id = o.order_id
+  AND o.status = 'PROCESSED'
+  AND ord.status IN ('NEW', 'PROCESSING');
+
+CREATE INDEX IF NOT EXISTS idx_outbox_next_attempt_at
+    ON outbox(next_attempt_at)
+    WHERE status IN ('PENDING', 'IN_FLIGHT');
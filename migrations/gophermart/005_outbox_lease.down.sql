@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_outbox_claimable;
+CREATE INDEX IF NOT EXISTS idx_outbox_next_attempt_at
+    ON outbox(next_attempt_at)
+    WHERE status IN ('PENDING', 'IN_FLIGHT');
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS lease_expires_at;
+ALTER TABLE outbox DROP COLUMN IF EXISTS lease_owner;
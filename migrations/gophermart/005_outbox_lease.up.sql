@@ -0,0 +1,7 @@
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS lease_owner VARCHAR;
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMP;
+
+DROP INDEX IF EXISTS idx_outbox_next_attempt_at;
+CREATE INDEX IF NOT EXISTS idx_outbox_claimable
+    ON outbox(next_attempt_at, lease_expires_at)
+    WHERE status IN ('PENDING', 'IN_FLIGHT');
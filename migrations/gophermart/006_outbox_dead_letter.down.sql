@@ -0,0 +1,3 @@
+UPDATE outbox SET status = 'FAILED' WHERE status = 'DEAD';
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS last_error;
@@ -0,0 +1,4 @@
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS last_error TEXT NOT NULL DEFAULT '';
+
+-- FAILED заменён статусом DEAD: такие записи больше не обрабатываются, но остаются в таблице для разбора.
+UPDATE outbox SET status = 'DEAD' WHERE status = 'FAILED';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_outbox_status_created_at;
+DROP TABLE IF EXISTS outbox_audit;
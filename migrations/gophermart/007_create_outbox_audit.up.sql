@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS outbox_audit (
+    id BIGSERIAL PRIMARY KEY,
+    outbox_id BIGINT NOT NULL REFERENCES outbox(id),
+    action VARCHAR NOT NULL,
+    actor_user_id BIGINT NOT NULL,
+    previous_status VARCHAR NOT NULL,
+    previous_retries INT NOT NULL,
+    previous_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_audit_outbox_id ON outbox_audit(outbox_id);
+CREATE INDEX IF NOT EXISTS idx_outbox_status_created_at ON outbox(status, created_at);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti VARCHAR PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+-- Токены пользователя, выпущенные раньше revoked_before, считаются отозванными («выйти на всех устройствах»).
+CREATE TABLE IF NOT EXISTS user_token_revocations (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    revoked_before TIMESTAMP NOT NULL
+);
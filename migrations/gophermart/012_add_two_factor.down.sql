@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS two_factor_challenges;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS totp_recovery_codes,
+    DROP COLUMN IF EXISTS totp_last_step,
+    DROP COLUMN IF EXISTS totp_enabled,
+    DROP COLUMN IF EXISTS totp_secret;
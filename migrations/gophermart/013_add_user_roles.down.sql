@@ -0,0 +1,5 @@
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_roles_known;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS roles;
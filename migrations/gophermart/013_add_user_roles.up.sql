@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS roles TEXT[] NOT NULL DEFAULT '{user}';
+
+ALTER TABLE users
+    ADD CONSTRAINT users_roles_known CHECK (roles <@ ARRAY['user', 'support', 'admin']::TEXT[]);
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_email_key;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS email;
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS email VARCHAR;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_email_key UNIQUE (email);
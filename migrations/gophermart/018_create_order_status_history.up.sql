@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL REFERENCES orders(id),
+    previous_status VARCHAR,
+    status VARCHAR NOT NULL,
+    accrual DECIMAL(10,2),
+    accrual_status VARCHAR NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id ON order_status_history(order_id, created_at);
+
+-- Для заказов, загруженных до появления истории, известны только загрузка и текущий статус.
+INSERT INTO order_status_history (order_id, previous_status, status, created_at)
+SELECT id, NULL, 'NEW', uploaded_at FROM orders;
+
+INSERT INTO order_status_history (order_id, previous_status, status, accrual, reason, created_at)
+SELECT id, 'NEW', status, accrual, 'backfill', uploaded_at FROM orders WHERE status <> 'NEW';
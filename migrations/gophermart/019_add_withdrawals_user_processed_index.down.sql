@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_withdrawals_user_processed_at;
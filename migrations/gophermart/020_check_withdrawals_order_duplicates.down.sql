@@ -0,0 +1,2 @@
+-- Миграция только проверяет данные, откатывать нечего.
+SELECT 1;
@@ -0,0 +1,29 @@
+-- Перед уникальным индексом по withdrawals.order_number (миграция 023) проверяем, что повторных
+-- списаний по одному заказу нет. Дубли — это баллы, списанные дважды, поэтому миграция их не
+-- удаляет, а останавливается с перечнем: лишние списания сторнируются вручную (REVERSAL в
+-- ledger_entries), номер заказа у них помечается, например '<номер>-dup-<id>', и миграция
+-- запускается снова.
+DO $$
+DECLARE
+    report TEXT;
+    total  INT;
+BEGIN
+    SELECT COUNT(*),
+           string_agg(format('order %s: withdrawals %s (users %s)', order_number, ids, users), E'\n' ORDER BY order_number)
+    INTO total, report
+    FROM (
+        SELECT order_number,
+               string_agg(id::TEXT, ', ' ORDER BY id) AS ids,
+               string_agg(DISTINCT user_id::TEXT, ', ') AS users
+        FROM withdrawals
+        GROUP BY order_number
+        HAVING COUNT(*) > 1
+    ) duplicates;
+
+    IF total > 0 THEN
+        RAISE EXCEPTION 'withdrawals contain % order numbers paid more than once', total
+            USING DETAIL = report,
+                  HINT = 'reverse the extra withdrawals in ledger_entries, rename their order_number and rerun the migration';
+    END IF;
+END
+$$;
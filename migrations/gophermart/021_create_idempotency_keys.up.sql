@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id BIGINT NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    operation VARCHAR NOT NULL,
+    request_hash VARCHAR NOT NULL,
+    response JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, key)
+);
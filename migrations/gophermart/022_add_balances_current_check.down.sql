@@ -0,0 +1 @@
+ALTER TABLE balances DROP CONSTRAINT IF EXISTS balances_current_non_negative;
@@ -0,0 +1,5 @@
+-- NOT VALID: ограничение действует для всех новых записей, но не проверяет строки, ушедшие в минус
+-- до его появления, и не держит эксклюзивную блокировку таблицы на время проверки. Старые строки
+-- проверяются отдельно в миграции 024 перед VALIDATE CONSTRAINT.
+ALTER TABLE balances
+    ADD CONSTRAINT balances_current_non_negative CHECK (current >= 0) NOT VALID;
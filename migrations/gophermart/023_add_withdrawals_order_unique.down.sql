@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS uq_withdrawals_order_number;
@@ -0,0 +1,3 @@
+-- Отдельная миграция без других команд: CREATE INDEX CONCURRENTLY нельзя выполнять в транзакции.
+-- Если построение прервётся, останется невалидный индекс; его нужно удалить перед повторным запуском.
+CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS uq_withdrawals_order_number ON withdrawals(order_number);
@@ -0,0 +1,2 @@
+-- Подтверждение ограничения не откатывается: само ограничение удаляет откат миграции 022.
+SELECT 1;
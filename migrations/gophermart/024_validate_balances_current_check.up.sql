@@ -0,0 +1,24 @@
+-- Отрицательные балансы остались с тех пор, как списания не блокировали строку баланса.
+-- Миграция их не исправляет сама: нужна корректирующая проводка (ADJUSTMENT в ledger_entries)
+-- на сумму долга, решение о которой принимает поддержка. Поэтому при их наличии миграция
+-- останавливается с перечнем, а после корректировки запускается снова.
+DO $$
+DECLARE
+    report TEXT;
+    total  INT;
+BEGIN
+    SELECT COUNT(*), string_agg(format('user %s: current %s', user_id, current), E'\n' ORDER BY user_id)
+    INTO total, report
+    FROM balances
+    WHERE current < 0;
+
+    IF total > 0 THEN
+        RAISE EXCEPTION 'balances contain % negative rows', total
+            USING DETAIL = report,
+                  HINT = 'post ADJUSTMENT entries to bring these balances to zero and rerun the migration';
+    END IF;
+END
+$$;
+
+-- VALIDATE берёт SHARE UPDATE EXCLUSIVE и не мешает записи в balances во время проверки.
+ALTER TABLE balances VALIDATE CONSTRAINT balances_current_non_negative;
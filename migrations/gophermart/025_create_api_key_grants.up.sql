@@ -0,0 +1,10 @@
+-- Пользователи, разрешившие ключу действовать от их имени. Ключ без разрешения
+-- пользователя не может выполнять запросы за него, даже если у ключа есть нужный scope.
+CREATE TABLE IF NOT EXISTS api_key_grants (
+    api_key_id BIGINT NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (api_key_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_key_grants_user_id ON api_key_grants(user_id);
@@ -0,0 +1,12 @@
+-- Срок опроса заказа (ORDER_MAX_AGE) отсчитывается от polling_since, а не от загрузки заказа:
+-- ручной перезапуск записи из REVIEW сбрасывает его и даёт заказу новый срок.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS polling_since TIMESTAMP;
+
+UPDATE outbox o
+SET polling_since = ord.uploaded_at
+FROM orders ord
+WHERE ord.id = o.order_id
+  AND o.polling_since IS NULL;
+
+ALTER TABLE outbox ALTER COLUMN polling_since SET DEFAULT NOW();
+ALTER TABLE outbox ALTER COLUMN polling_since SET NOT NULL;